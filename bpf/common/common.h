@@ -149,7 +149,7 @@ typedef struct tcp_req {
     u32 resp_len;
     u32 lb_req_bytes;
     u32 lb_res_bytes;
-    u8 _pad2[4];
+    u32 conn_err; // socket error (errno) of the failed connection events
     unsigned char buf[k_tcp_max_len];
     unsigned char rbuf[k_tcp_res_len];
     // we need this to filter traces from unsolicited processes that share the executable
@@ -702,7 +702,7 @@ int BPF_KPROBE(obi_kprobe_tcp_close, struct sock *sk, long timeout) {
 
             if (ct && !ct->established && !ct->failed) {
                 dbg_print_http_connection_info(&info.conn);
-                failed_to_connect_event(&info, orig_dport, ct->ts);
+                failed_to_connect_event(&info, sk, orig_dport, ct->ts);
             }
         }
         bpf_map_delete_elem(&cp_support_connect_info, &info);
@@ -753,7 +753,7 @@ int BPF_KPROBE(obi_kprobe_sock_def_error_report, struct sock *sk) {
         if (args) {
             if (!args->failed) {
                 dbg_print_http_connection_info(&info.conn);
-                failed_to_connect_event(&info, orig_dport, args->ts);
+                failed_to_connect_event(&info, sk, orig_dport, args->ts);
                 // mark the args and cp_support_info as failed so we don't duplicate the event
                 cp_support_data_t *cp_data = bpf_map_lookup_elem(&cp_support_connect_info, &info);
                 if (cp_data) {
@@ -765,7 +765,7 @@ int BPF_KPROBE(obi_kprobe_sock_def_error_report, struct sock *sk) {
             conn_pid_t *conn_pid = bpf_map_lookup_elem(&sock_pids, &info.conn);
             if (conn_pid && conn_pid->id == id) {
                 dbg_print_http_connection_info(&info.conn);
-                failed_to_connect_event(&info, orig_dport, conn_pid->ts);
+                failed_to_connect_event(&info, sk, orig_dport, conn_pid->ts);
             }
         }
     }
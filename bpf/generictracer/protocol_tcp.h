@@ -5,6 +5,7 @@
 
 #include <bpfcore/vmlinux.h>
 #include <bpfcore/bpf_helpers.h>
+#include <bpfcore/bpf_core_read.h>
 
 #include <common/common.h>
 #include <common/connection_info.h>
@@ -151,8 +152,10 @@ static __always_inline int tcp_send_large_buffer(tcp_req_t *req,
     return 0;
 }
 
-static __always_inline void
-failed_to_connect_event(pid_connection_info_t *pid_conn, u16 orig_dport, u64 connect_ts) {
+static __always_inline void failed_to_connect_event(pid_connection_info_t *pid_conn,
+                                                    struct sock *sk,
+                                                    u16 orig_dport,
+                                                    u64 connect_ts) {
     tcp_req_t *req = bpf_ringbuf_reserve(&events, sizeof(tcp_req_t), 0);
     if (req) {
         req->flags = EVENT_FAILED_CONNECT;
@@ -163,6 +166,7 @@ failed_to_connect_event(pid_connection_info_t *pid_conn, u16 orig_dport, u64 con
         req->start_monotime_ns = connect_ts;
         req->end_monotime_ns = bpf_ktime_get_ns();
         req->resp_len = 0;
+        req->conn_err = (u32)BPF_CORE_READ(sk, sk_err);
         req->len = 0;
         req->req_len = req->len;
         req->extra_id = extra_runtime_id();
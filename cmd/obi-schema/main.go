@@ -17,6 +17,7 @@ import (
 	"go/parser"
 	"go/token"
 	"os"
+	"path"
 	"path/filepath"
 	"reflect"
 	"sort"
@@ -302,6 +303,7 @@ func main() {
 		ExpandedStruct:             true,
 		FieldNameTag:               "yaml",
 		Mapper:                     g.customMapper(),
+		Namer:                      configTypeNamer,
 	}
 	if err := reflector.AddGoComments("go.opentelemetry.io/obi", "./", jsonschema.WithFullComment()); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not add Go comments: %v\n", err)
@@ -674,6 +676,17 @@ func normalizeDescriptions(schema *jsonschema.Schema) {
 // jsonSchemaerType is the reflect.Type for the jsonSchemaer interface.
 var jsonSchemaerType = reflect.TypeOf((*jsonSchemaer)(nil)).Elem()
 
+// configTypeNamer prefixes the name of the Config types from other packages with their
+// package name (e.g. SloConfig), as their definitions would otherwise collide with the
+// root obi.Config. It returns an empty name for the rest of types, to keep their default name.
+func configTypeNamer(t reflect.Type) string {
+	if t.Name() != "Config" || t.PkgPath() == reflect.TypeOf(obi.Config{}).PkgPath() {
+		return ""
+	}
+	pkg := path.Base(t.PkgPath())
+	return strings.ToUpper(pkg[:1]) + pkg[1:] + t.Name()
+}
+
 // customMapper returns a mapper function that handles types the default reflector cannot process
 // and provides enum values for string-typed constants.
 func (g *SchemaGenerator) customMapper() func(reflect.Type) *jsonschema.Schema {
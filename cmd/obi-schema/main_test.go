@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
 	"go.opentelemetry.io/obi/pkg/obi"
 )
 
@@ -313,6 +314,12 @@ func TestCustomMapper(t *testing.T) {
 	})
 }
 
+func TestConfigTypeNamer(t *testing.T) {
+	assert.Empty(t, configTypeNamer(reflect.TypeOf(obi.Config{})))
+	assert.Empty(t, configTypeNamer(reflect.TypeOf(time.Duration(0))))
+	assert.Equal(t, "OtlpfileConfig", configTypeNamer(reflect.TypeOf(otlpfile.Config{})))
+}
+
 func TestExtractEnums(t *testing.T) {
 	g := NewSchemaGenerator()
 
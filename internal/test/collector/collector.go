@@ -215,6 +215,13 @@ func (tc *TestCollector) metricEvent(writer http.ResponseWriter, body []byte) {
 							mr.Attributes[k] = v.AsString()
 							return true
 						})
+						for _, ex := range hdp.Exemplars().All() {
+							mr.Exemplars = append(mr.Exemplars, ExemplarRecord{
+								TraceID:  ex.TraceID().String(),
+								SpanID:   ex.SpanID().String(),
+								FloatVal: ex.DoubleValue(),
+							})
+						}
 						tc.Records() <- mr
 					}
 				case pmetric.MetricTypeGauge:
@@ -252,6 +259,14 @@ type MetricRecord struct {
 	IntVal             int64
 	FloatVal           float64
 	Count              int
+	// Exemplars of histogram data points
+	Exemplars []ExemplarRecord
+}
+
+type ExemplarRecord struct {
+	TraceID  string
+	SpanID   string
+	FloatVal float64
 }
 
 type TraceRecord struct {
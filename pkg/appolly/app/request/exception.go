@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package request // import "go.opentelemetry.io/obi/pkg/appolly/app/request"
+
+import (
+	"net/http"
+	"strconv"
+
+	grpccodes "google.golang.org/grpc/codes"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
+)
+
+// Exception describes the error carried by a span, to be reported as an exception span event.
+type Exception struct {
+	Type    string
+	Message string
+	// Code is the protocol or vendor-specific error code (e.g. SQL state or gRPC status),
+	// as a semantic convention attribute. It is invalid if the error has no code.
+	Code attribute.KeyValue
+}
+
+// SpanException returns the Exception carried by the span, or false if the span didn't fail
+// or the error is not known.
+func SpanException(span *Span) (Exception, bool) {
+	if SpanStatusCode(span) != StatusCodeError {
+		return Exception{}, false
+	}
+	switch span.Type {
+	case EventTypeSQLClient, EventTypeSQLServer:
+		if span.SQLError == nil {
+			return Exception{}, false
+		}
+		code := span.SQLError.SQLState
+		if code == "" && span.SQLError.Code != 0 {
+			code = strconv.FormatUint(uint64(span.SQLError.Code), 10)
+		}
+		return Exception{
+			Type:    "SQLError",
+			Message: span.SQLError.Message,
+			Code:    optionalCode(semconv.DBResponseStatusCodeKey, code),
+		}, true
+	case EventTypeRedisClient, EventTypeRedisServer, EventTypeMongoClient, EventTypeCouchbaseClient,
+		EventTypeMemcachedClient, EventTypeMemcachedServer:
+		return dbException(span)
+	case EventTypeGRPC, EventTypeGRPCClient:
+		return Exception{
+			Type:    "GRPCError",
+			Message: grpccodes.Code(span.Status).String(),
+			Code:    semconv.RPCGRPCStatusCodeKey.Int(span.Status),
+		}, true
+	case EventTypeHTTPClient:
+		if span.SubType == HTTPSubtypeSQLPP {
+			return dbException(span)
+		}
+		if exc, ok := genAIException(span); ok {
+			return exc, true
+		}
+		return httpException(span)
+	case EventTypeHTTP:
+		return httpException(span)
+	}
+	return Exception{}, false
+}
+
+func dbException(span *Span) (Exception, bool) {
+	if span.DBError.ErrorCode == "" && span.DBError.Description == "" {
+		return Exception{}, false
+	}
+	return Exception{
+		Type:    "DBError",
+		Message: span.DBError.Description,
+		Code:    optionalCode(semconv.DBResponseStatusCodeKey, span.DBError.ErrorCode),
+	}, true
+}
+
+func genAIException(span *Span) (Exception, bool) {
+	switch {
+	case span.GenAI == nil:
+		return Exception{}, false
+	case span.GenAI.OpenAI != nil && span.GenAI.OpenAI.Error.Type != "":
+		return Exception{
+			Type:    span.GenAI.OpenAI.Error.Type,
+			Message: span.GenAI.OpenAI.Error.Message,
+			Code:    semconv.ErrorTypeKey.String(span.GenAI.OpenAI.Error.Type),
+		}, true
+	case span.GenAI.Anthropic != nil && span.GenAI.Anthropic.Output.Error != nil &&
+		span.GenAI.Anthropic.Output.Error.Type != "":
+		return Exception{
+			Type:    span.GenAI.Anthropic.Output.Error.Type,
+			Message: span.GenAI.Anthropic.Output.Error.Message,
+			Code:    semconv.ErrorTypeKey.String(span.GenAI.Anthropic.Output.Error.Type),
+		}, true
+	}
+	return Exception{}, false
+}
+
+// httpException only reports server errors (5xx). If the payload was captured, the message
+// contains the beginning of the response body.
+func httpException(span *Span) (Exception, bool) {
+	if span.Status < 500 {
+		return Exception{}, false
+	}
+	message := span.ResponseBodyPrefix
+	if message == "" {
+		message = http.StatusText(span.Status)
+	}
+	return Exception{
+		Type:    "HTTPError",
+		Message: message,
+		Code:    semconv.HTTPResponseStatusCodeKey.Int(span.Status),
+	}, true
+}
+
+func optionalCode(key attribute.Key, code string) attribute.KeyValue {
+	if code == "" {
+		return attribute.KeyValue{}
+	}
+	return key.String(code)
+}
@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package request
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestSpanException(t *testing.T) {
+	tests := []struct {
+		name     string
+		span     Span
+		expected *Exception
+	}{{
+		name: "SQL error",
+		span: Span{Type: EventTypeSQLClient, Status: 1, SQLError: &SQLError{
+			Code: 1146, SQLState: "42S02", Message: "Table 'db.foo' doesn't exist",
+		}},
+		expected: &Exception{
+			Type: "SQLError", Message: "Table 'db.foo' doesn't exist",
+			Code: attribute.String("db.response.status_code", "42S02"),
+		},
+	}, {
+		name: "SQL error without state",
+		span: Span{Type: EventTypeSQLServer, Status: 1, SQLError: &SQLError{Code: 1146, Message: "oops"}},
+		expected: &Exception{
+			Type: "SQLError", Message: "oops",
+			Code: attribute.String("db.response.status_code", "1146"),
+		},
+	}, {
+		name: "successful SQL query",
+		span: Span{Type: EventTypeSQLClient},
+	}, {
+		name: "Redis error",
+		span: Span{Type: EventTypeRedisClient, Status: 1, DBError: DBError{
+			ErrorCode: "WRONGTYPE", Description: "Operation against a key holding the wrong kind of value",
+		}},
+		expected: &Exception{
+			Type: "DBError", Message: "Operation against a key holding the wrong kind of value",
+			Code: attribute.String("db.response.status_code", "WRONGTYPE"),
+		},
+	}, {
+		name: "Mongo error without description",
+		span: Span{Type: EventTypeMongoClient, Status: 1},
+	}, {
+		name: "gRPC error",
+		span: Span{Type: EventTypeGRPC, Status: 14},
+		expected: &Exception{
+			Type: "GRPCError", Message: "Unavailable",
+			Code: attribute.Int("rpc.grpc.status_code", 14),
+		},
+	}, {
+		name: "successful gRPC call",
+		span: Span{Type: EventTypeGRPCClient},
+	}, {
+		name: "HTTP server error with body",
+		span: Span{Type: EventTypeHTTP, Status: 503, ResponseBodyPrefix: `{"error":"database unavailable"}`},
+		expected: &Exception{
+			Type: "HTTPError", Message: `{"error":"database unavailable"}`,
+			Code: attribute.Int("http.response.status_code", 503),
+		},
+	}, {
+		name: "HTTP client server error without body",
+		span: Span{Type: EventTypeHTTPClient, Status: 500},
+		expected: &Exception{
+			Type: "HTTPError", Message: "Internal Server Error",
+			Code: attribute.Int("http.response.status_code", 500),
+		},
+	}, {
+		name: "HTTP client error",
+		span: Span{Type: EventTypeHTTPClient, Status: 404},
+	}, {
+		name: "OpenAI error",
+		span: Span{Type: EventTypeHTTPClient, Status: 429, GenAI: &GenAI{OpenAI: &VendorOpenAI{
+			Error: OpenAIError{Type: "rate_limit_exceeded", Message: "Rate limit reached"},
+		}}},
+		expected: &Exception{
+			Type: "rate_limit_exceeded", Message: "Rate limit reached",
+			Code: attribute.String("error.type", "rate_limit_exceeded"),
+		},
+	}, {
+		name: "Anthropic error",
+		span: Span{Type: EventTypeHTTPClient, Status: 200, GenAI: &GenAI{Anthropic: &VendorAnthropic{
+			Output: AnthropicResponse{Error: &AnthropicError{Type: "overloaded_error", Message: "Overloaded"}},
+		}}},
+		expected: &Exception{
+			Type: "overloaded_error", Message: "Overloaded",
+			Code: attribute.String("error.type", "overloaded_error"),
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exception, ok := SpanException(&tt.span)
+			if tt.expected == nil {
+				assert.False(t, ok)
+				return
+			}
+			assert.True(t, ok)
+			assert.Equal(t, *tt.expected, exception)
+		})
+	}
+}
@@ -6,6 +6,8 @@ package request // import "go.opentelemetry.io/obi/pkg/appolly/app/request"
 import (
 	"strings"
 
+	"golang.org/x/sys/unix"
+
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
 
@@ -148,6 +150,15 @@ func ErrorType(val string) attribute.KeyValue {
 	return attribute.Key(attr.ErrorType).String(val)
 }
 
+// ConnectErrorType returns the symbolic name of the errno that caused a connection
+// failure (e.g. ECONNREFUSED), or "error" if it is unknown
+func ConnectErrorType(errno int) string {
+	if name := unix.ErrnoName(unix.Errno(errno)); name != "" {
+		return name
+	}
+	return "error"
+}
+
 func MessagingOperationName(val string) attribute.KeyValue {
 	return attribute.Key(attr.MessagingOpName).String(val)
 }
@@ -21,6 +21,8 @@ import (
 
 	"go.opentelemetry.io/obi/pkg/appolly/app"
 	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/ebpf/common/dnsparser"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 )
 
 type EventType uint8
@@ -189,9 +191,50 @@ type SQLError struct {
 	Message  string `json:"message"`
 }
 
+// UnknownPartition is the MessagingInfo.Partition value when the partition could
+// not be determined, e.g. for requests to multiple partitions.
+const UnknownPartition = -1
+
 type MessagingInfo struct {
 	Offset    int64 `json:"offset"`
 	Partition int   `json:"partition"`
+	// ConsumerGroup of the consumer that processes the messages, if known
+	ConsumerGroup string `json:"consumerGroup"`
+	// Messages and Bytes account the sent or consumed messages and their payload size
+	Messages int `json:"messages"`
+	Bytes    int `json:"bytes"`
+}
+
+// SpanLink references a span from another trace that is causally related to the span,
+// e.g. the producer of a message that is processed by a consumer span.
+type SpanLink struct {
+	TraceID    trace.TraceID `json:"traceID"`
+	SpanID     trace.SpanID  `json:"spanID"`
+	TraceFlags uint8         `json:"traceFlags,string"`
+}
+
+// SpanLinkFromTraceparent parses a W3C traceparent value (version-traceid-spanid-flags)
+// into a SpanLink. It returns false if the value is not a valid traceparent.
+func SpanLinkFromTraceparent(traceparent string) (SpanLink, bool) {
+	// 2 (version) + 32 (trace ID) + 16 (span ID) + 2 (flags) + 3 separators
+	const traceparentLen = 55
+	if len(traceparent) < traceparentLen || traceparent[2] != '-' || traceparent[35] != '-' || traceparent[52] != '-' ||
+		traceparent[:2] == "ff" || (traceparent[:2] == "00" && len(traceparent) != traceparentLen) {
+		return SpanLink{}, false
+	}
+	traceID, err := trace.TraceIDFromHex(traceparent[3:35])
+	if err != nil {
+		return SpanLink{}, false
+	}
+	spanID, err := trace.SpanIDFromHex(traceparent[36:52])
+	if err != nil {
+		return SpanLink{}, false
+	}
+	flags, err := strconv.ParseUint(traceparent[53:55], 16, 8)
+	if err != nil {
+		return SpanLink{}, false
+	}
+	return SpanLink{TraceID: traceID, SpanID: spanID, TraceFlags: uint8(flags)}, true
 }
 
 type GraphQL struct {
@@ -414,11 +457,26 @@ type Span struct {
 	AWS               *AWS           `json:"-"`
 	GenAI             *GenAI         `json:"-"`
 
+	// Links to the spans of other traces that are causally related to this span, e.g.
+	// the producers of the messages processed by a messaging consumer.
+	Links []SpanLink `json:"links,omitempty"`
+
 	// RequestHeaders stores extracted HTTP request headers based on enrichment rules.
 	// Keys are canonical header names, values are all header values (possibly obfuscated).
 	RequestHeaders map[string][]string `json:"requestHeaders,omitempty"`
 	// ResponseHeaders stores extracted HTTP response headers based on enrichment rules.
 	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	// Baggage stores the W3C baggage entries of the HTTP request that were selected to be
+	// reported as attributes, by key.
+	Baggage map[string]string `json:"baggage,omitempty"`
+	// ResponseBodyPrefix stores the beginning of the body of HTTP 5xx responses, when
+	// the payload is captured, to describe the error of the span.
+	ResponseBodyPrefix string `json:"-"`
+
+	// InFlight is set in the spans that the tracers periodically report for the requests
+	// that have started but not finished yet. They are only used to account the active
+	// requests, as the request will be reported again as a regular span once it finishes.
+	InFlight bool `json:"-"`
 
 	// OverrideTraceName is set under some conditions, like spanmetrics reaching the maximum
 	// cardinality for trace names.
@@ -432,7 +490,7 @@ func (s *Span) Inside(parent *Span) bool {
 // InternalSignal returns whether a span is not aimed to be exported as a metric
 // or a trace, because it's used to internally send messages through the pipeline.
 func (s *Span) InternalSignal() bool {
-	return s.Type == EventTypeProcessAlive
+	return s.Type == EventTypeProcessAlive || s.InFlight
 }
 
 // helper attribute functions used by JSON serialization
@@ -563,7 +621,7 @@ func spanAttributes(s *Span) SpanAttributes {
 			"clientId":   s.Statement,
 			"topic":      s.Path,
 		}
-		if s.MessagingInfo != nil {
+		if s.MessagingInfo != nil && s.MessagingInfo.Partition != UnknownPartition {
 			attrs["partition"] = strconv.FormatUint(uint64(s.MessagingInfo.Partition), 10)
 			if s.Method == MessagingProcess {
 				attrs["offset"] = strconv.FormatUint(uint64(s.MessagingInfo.Offset), 10)
@@ -603,6 +661,9 @@ func addHeaderAttributes(attrs SpanAttributes, s *Span) {
 	for name, values := range s.ResponseHeaders {
 		attrs["http.response.header."+strings.ToLower(name)] = strings.Join(values, ", ")
 	}
+	for key, value := range s.Baggage {
+		attrs[string(attr.Baggage(key))] = value
+	}
 }
 
 func (s *Span) SQLErrorDescription() string {
@@ -1053,6 +1114,11 @@ func (s *Span) IsDNSSpan() bool {
 	return s.Type == EventTypeDNS
 }
 
+// IsFailedDNSLookup returns whether the span is a DNS lookup whose response code is not a success
+func (s *Span) IsFailedDNSLookup() bool {
+	return s.Type == EventTypeDNS && s.Status != int(dnsparser.RCodeSuccess)
+}
+
 func (s *Span) isTracesExportURL() bool {
 	switch s.Type {
 	case EventTypeGRPCClient:
@@ -4,6 +4,9 @@
 package request // import "go.opentelemetry.io/obi/pkg/appolly/app/request"
 
 import (
+	"strconv"
+	"strings"
+
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
 
@@ -70,6 +73,8 @@ func spanOTELGetters(name attr.Name) (attributes.Getter[*Span, attribute.KeyValu
 		}
 	case attr.ServerPort:
 		getter = func(s *Span) attribute.KeyValue { return ServerPort(s.HostPort) }
+	case attr.NetworkPeerAddress:
+		getter = func(s *Span) attribute.KeyValue { return semconv.NetworkPeerAddress(s.Host) }
 	case attr.RPCMethod:
 		getter = func(s *Span) attribute.KeyValue {
 			if s.Type == EventTypeHTTPClient && s.SubType == HTTPSubtypeAWSS3 && s.AWS != nil {
@@ -170,8 +175,10 @@ func spanOTELGetters(name attr.Name) (attributes.Getter[*Span, attribute.KeyValu
 		getter = func(span *Span) attribute.KeyValue { return DBNamespace(span.DBNamespace) }
 	case attr.ErrorType:
 		getter = func(span *Span) attribute.KeyValue {
-			if span.Type == EventTypeDNS && span.Status != int(dnsparser.RCodeSuccess) {
+			if span.IsFailedDNSLookup() {
 				return ErrorType(dnsparser.RCode(span.Status).String())
+			} else if span.Type == EventTypeFailedConnect {
+				return ErrorType(ConnectErrorType(span.Status))
 			} else if SpanStatusCode(span) == StatusCodeError {
 				switch span.Type {
 				case EventTypeMemcachedClient, EventTypeMemcachedServer:
@@ -209,6 +216,20 @@ func spanOTELGetters(name attr.Name) (attributes.Getter[*Span, attribute.KeyValu
 			}
 			return semconv.MessagingDestinationName("")
 		}
+	case attr.MessagingPartition:
+		getter = func(span *Span) attribute.KeyValue {
+			if span.MessagingInfo == nil || span.MessagingInfo.Partition == UnknownPartition {
+				return semconv.MessagingDestinationPartitionID("")
+			}
+			return semconv.MessagingDestinationPartitionID(strconv.Itoa(span.MessagingInfo.Partition))
+		}
+	case attr.MessagingConsumerGroup:
+		getter = func(span *Span) attribute.KeyValue {
+			if span.MessagingInfo == nil {
+				return semconv.MessagingConsumerGroupName("")
+			}
+			return semconv.MessagingConsumerGroupName(span.MessagingInfo.ConsumerGroup)
+		}
 	case attr.MessagingOpName:
 		getter = func(span *Span) attribute.KeyValue {
 			switch {
@@ -271,8 +292,15 @@ func spanOTELGetters(name attr.Name) (attributes.Getter[*Span, attribute.KeyValu
 		}
 	case attr.DBCollectionName:
 		getter = func(s *Span) attribute.KeyValue {
-			if s.Type == EventTypeHTTPClient && s.SubType == HTTPSubtypeElasticsearch && s.Elasticsearch != nil {
-				return DBCollectionName(s.Elasticsearch.DBCollectionName)
+			switch s.Type {
+			case EventTypeHTTPClient:
+				if s.SubType == HTTPSubtypeElasticsearch && s.Elasticsearch != nil {
+					return DBCollectionName(s.Elasticsearch.DBCollectionName)
+				}
+			case EventTypeSQLServer:
+				// the path contains the table name. Only used by the db.server.operation.duration
+				// metric, as the client metrics don't report the collection name
+				return DBCollectionName(s.Path)
 			}
 			return DBCollectionName("")
 		}
@@ -395,6 +423,9 @@ func spanOTELGetters(name attr.Name) (attributes.Getter[*Span, attribute.KeyValu
 			return semconv.GenAIResponseModelKey.String(s.GenAIResponseModel())
 		}
 	}
+	if key, ok := strings.CutPrefix(string(name), attr.BaggagePrefix); ok && getter == nil {
+		getter = func(s *Span) attribute.KeyValue { return name.OTEL().String(s.Baggage[key]) }
+	}
 	// default: unlike the Prometheus getters, we don't check here for service name nor k8s metadata
 	// because they are already attributes of the Resource instead of the attributes.
 	return getter, getter != nil
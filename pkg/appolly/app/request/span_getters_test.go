@@ -13,6 +13,21 @@ import (
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 )
 
+func TestSpanGetters_Baggage(t *testing.T) {
+	span := &Span{Type: EventTypeHTTP, Baggage: map[string]string{"tenant.id": "acme"}}
+
+	getter, ok := spanOTELGetters("baggage.tenant.id")
+	require.True(t, ok)
+	assert.Equal(t, "baggage.tenant.id", string(getter(span).Key))
+	assert.Equal(t, "acme", getter(span).Value.AsString())
+
+	getter, ok = spanOTELGetters("baggage.deployment.ring")
+	require.True(t, ok)
+	assert.Empty(t, getter(span).Value.AsString())
+
+	assert.Equal(t, "acme", spanPromGetters("baggage.tenant.id")(span))
+}
+
 func TestSpanOTELGetters_K8SClientNamespace(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -407,3 +422,93 @@ func TestSpanOTELGetters_HTTPURLScheme(t *testing.T) {
 		})
 	}
 }
+
+func TestSpanOTELGetters_ErrorType(t *testing.T) {
+	tests := []struct {
+		name     string
+		span     *Span
+		expected string
+	}{
+		{
+			name:     "connection refused",
+			span:     &Span{Type: EventTypeFailedConnect, Status: 111},
+			expected: "ECONNREFUSED",
+		},
+		{
+			name:     "connection timed out",
+			span:     &Span{Type: EventTypeFailedConnect, Status: 110},
+			expected: "ETIMEDOUT",
+		},
+		{
+			name:     "failed connection with unknown errno",
+			span:     &Span{Type: EventTypeFailedConnect},
+			expected: "error",
+		},
+		{
+			name:     "failed DNS lookup",
+			span:     &Span{Type: EventTypeDNS, Status: 3},
+			expected: "NXDomain",
+		},
+		{
+			name:     "successful DNS lookup",
+			span:     &Span{Type: EventTypeDNS},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter, ok := spanOTELGetters(attr.ErrorType)
+			require.True(t, ok, "getter should be found for ErrorType")
+
+			kv := getter(tt.span)
+			assert.Equal(t, string(attr.ErrorType), string(kv.Key))
+			assert.Equal(t, tt.expected, kv.Value.AsString())
+		})
+	}
+}
+
+func TestSpanOTELGetters_DBCollectionName(t *testing.T) {
+	tests := []struct {
+		name     string
+		span     *Span
+		expected string
+	}{
+		{
+			name:     "SQL client keeps no collection",
+			span:     &Span{Type: EventTypeSQLClient, Method: "SELECT", Path: "accounts"},
+			expected: "",
+		},
+		{
+			name:     "SQL server table",
+			span:     &Span{Type: EventTypeSQLServer, Method: "INSERT", Path: "orders"},
+			expected: "orders",
+		},
+		{
+			name:     "Mongo client keeps no collection",
+			span:     &Span{Type: EventTypeMongoClient, Method: "find", Path: "users"},
+			expected: "",
+		},
+		{
+			name:     "Elasticsearch index",
+			span:     &Span{Type: EventTypeHTTPClient, SubType: HTTPSubtypeElasticsearch, Elasticsearch: &Elasticsearch{DBCollectionName: "logs"}},
+			expected: "logs",
+		},
+		{
+			name:     "Redis has no collection",
+			span:     &Span{Type: EventTypeRedisServer, Method: "GET", Path: "GET foo"},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter, ok := spanOTELGetters(attr.DBCollectionName)
+			require.True(t, ok, "getter should be found for DBCollectionName")
+
+			kv := getter(tt.span)
+			assert.Equal(t, string(attr.DBCollectionName), string(kv.Key))
+			assert.Equal(t, tt.expected, kv.Value.AsString())
+		})
+	}
+}
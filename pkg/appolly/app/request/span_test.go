@@ -1215,3 +1215,29 @@ func TestSpan_GenAIResponseModel(t *testing.T) {
 		assert.Equal(t, "claude-2.1", result)
 	})
 }
+
+func TestSpanLinkFromTraceparent(t *testing.T) {
+	link, ok := SpanLinkFromTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.True(t, ok)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", link.TraceID.String())
+	assert.Equal(t, "b7ad6b7169203331", link.SpanID.String())
+	assert.Equal(t, uint8(1), link.TraceFlags)
+
+	// future versions might append fields
+	_, ok = SpanLinkFromTraceparent("01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra")
+	assert.True(t, ok)
+
+	for _, invalid := range []string{
+		"",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-00000000000000000000000000000000-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-0000000000000000-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-zz",
+		"00_0af7651916cd43dd8448eb211c80319c_b7ad6b7169203331_01",
+	} {
+		_, ok := SpanLinkFromTraceparent(invalid)
+		assert.Falsef(t, ok, "%q should be invalid", invalid)
+	}
+}
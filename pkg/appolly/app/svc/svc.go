@@ -4,6 +4,8 @@
 package svc // import "go.opentelemetry.io/obi/pkg/appolly/app/svc"
 
 import (
+	"context"
+
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
 
@@ -131,6 +133,9 @@ type Attrs struct {
 	CustomOutRouteMatcher route.Matcher
 	HarvestedRouteMatcher route.Matcher
 	PathTrie              *clusterurl.PathTrie
+
+	// Redaction rules of the service. If nil, the globally defined rules apply
+	Redaction *services.RedactionConfig
 }
 
 func (i *Attrs) GetUID() UID {
@@ -192,7 +197,15 @@ func (i *Attrs) SetHarvestedRoutes(matcher route.Matcher) {
 	i.HarvestedRouteMatcher = matcher
 }
 
-func (i *Attrs) SetCustomRoutes(config *services.CustomRoutesConfig) {
+// SetCustomRoutes sets the route matchers of the service. The OpenAPI documents, if any, are
+// reloaded until the provided context is cancelled.
+func (i *Attrs) SetCustomRoutes(ctx context.Context, config *services.CustomRoutesConfig) {
 	i.CustomInRouteMatcher = route.NewMatcher(config.Incoming)
+	if config.OpenAPI.Enabled() {
+		i.CustomInRouteMatcher = route.Matchers{
+			i.CustomInRouteMatcher,
+			route.SharedOpenAPIMatcher(ctx, config.OpenAPI.Documents, config.OpenAPI.RefreshInterval),
+		}
+	}
 	i.CustomOutRouteMatcher = route.NewMatcher(config.Outgoing)
 }
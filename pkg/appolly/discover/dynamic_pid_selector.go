@@ -210,6 +210,7 @@ func (a *dynamicPIDCriteriaAdapter) GetExportModes() services.ExportModes {
 
 func (a *dynamicPIDCriteriaAdapter) GetSamplerConfig() *services.SamplerConfig     { return nil }
 func (a *dynamicPIDCriteriaAdapter) GetRoutesConfig() *services.CustomRoutesConfig { return nil }
+func (a *dynamicPIDCriteriaAdapter) GetRedactionConfig() *services.RedactionConfig { return nil }
 func (a *dynamicPIDCriteriaAdapter) MetricsConfig() perapp.SvcMetricsConfig {
 	return perapp.SvcMetricsConfig{}
 }
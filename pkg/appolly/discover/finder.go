@@ -119,7 +119,7 @@ func (pf *ProcessFinder) Start(ctx context.Context, opts ...ProcessFinderStartOp
 		swarm.WithID("DynamicMatcher"))
 
 	executableTypes := msgh.QueueFromConfig[[]Event[ebpf.Instrumentable]](pf.cfg, "executableTypes")
-	swi.Add(ExecTyperProvider(pf.cfg, pf.ctxInfo.Metrics, pf.ctxInfo.K8sInformer, criteriaFilteredEvents, executableTypes),
+	swi.Add(ExecTyperProvider(pf.cfg, pf.ctxInfo.Metrics, pf.ctxInfo.K8sInformer, pf.ctxInfo.AppO11y.RouteTemplates, criteriaFilteredEvents, executableTypes),
 		swarm.WithID("ExecTyper"))
 
 	// we could subscribe ContainerStoreUpdater directly to the executableTypes queue and not providing any output channel
@@ -48,6 +48,7 @@ func ExecTyperProvider(
 	cfg *obi.Config,
 	metrics imetrics.Reporter,
 	k8sInformer *kube.MetadataProvider,
+	routeTemplates *clusterurl.TrieStore,
 	input *msg.Queue[[]Event[ProcessMatch]],
 	output *msg.Queue[[]Event[ebpf.Instrumentable]],
 ) swarm.InstanceFunc {
@@ -57,11 +58,14 @@ func ExecTyperProvider(
 		cfg:                 cfg,
 		metrics:             metrics,
 		k8sInformer:         k8sInformer,
+		routeTemplates:      routeTemplates,
 		log:                 slog.With("component", "discover.ExecTyper"),
 		currentPids:         map[app.PID]*exec.FileInfo{},
 		instrumentableCache: instrumentableCache,
 	}
-	return func(_ context.Context) (swarm.RunFunc, error) {
+	return func(ctx context.Context) (swarm.RunFunc, error) {
+		// the per-service OpenAPI documents are reloaded during the pipeline lifetime
+		t.ctx = ctx
 		// TODO: do it per executable
 		if !cfg.Discovery.SkipGoSpecificTracers {
 			t.loadAllGoFunctionNames()
@@ -77,9 +81,11 @@ func ExecTyperProvider(
 }
 
 type typer struct {
+	ctx                 context.Context
 	cfg                 *obi.Config
 	metrics             imetrics.Reporter
 	k8sInformer         *kube.MetadataProvider
+	routeTemplates      *clusterurl.TrieStore
 	log                 *slog.Logger
 	currentPids         map[app.PID]*exec.FileInfo
 	allGoFunctions      []string
@@ -100,6 +106,7 @@ func (t *typer) makeServiceAttrs(processMatch *ProcessMatch) svc.Attrs {
 	exportModes := services.ExportModeUnset
 	var samplerConfig *services.SamplerConfig
 	var routesConfig *services.CustomRoutesConfig
+	var redactionConfig *services.RedactionConfig
 	svcFeatures := t.cfg.Metrics.Features
 
 	for _, s := range processMatch.Criteria {
@@ -123,6 +130,10 @@ func (t *typer) makeServiceAttrs(processMatch *ProcessMatch) svc.Attrs {
 			routesConfig = m
 		}
 
+		if m := s.GetRedactionConfig(); m != nil {
+			redactionConfig = m
+		}
+
 		// if the matching service > instrument entry does not define features,
 		// the globally defined features apply (and override any previous,
 		// wider-scope match features)
@@ -146,18 +157,34 @@ func (t *typer) makeServiceAttrs(processMatch *ProcessMatch) svc.Attrs {
 		ProcPID:            processMatch.Process.Pid,
 		ExportModes:        exportModes,
 		Sampler:            samplerFromConfig(samplerConfig),
-		PathTrie:           clusterurl.NewPathTrie(routesCfg.MaxPathSegmentCardinality, wildcard),
+		PathTrie:           t.pathTrie(name, namespace, routesCfg.MaxPathSegmentCardinality, wildcard),
 		Features:           svcFeatures,
 		LogEnricherEnabled: processMatch.LogEnricherEnabled(),
+		Redaction:          redactionConfig,
 	}
 
 	if routesConfig != nil {
-		s.SetCustomRoutes(routesConfig)
+		s.SetCustomRoutes(t.ctx, routesConfig)
 	}
 
 	return s
 }
 
+// pathTrie returns the trie that caps the cardinality of the routes of the service. If the learned
+// route templates are stored, the trie is shared by all the instances of the service, which is
+// identified by its configured name and namespace. Services without a configured name learn their
+// own route templates, as unrelated services might run the same executable.
+func (t *typer) pathTrie(name, namespace string, maxCardinality int, wildcard byte) *clusterurl.PathTrie {
+	if t.routeTemplates == nil || name == "" {
+		return clusterurl.NewPathTrie(maxCardinality, wildcard)
+	}
+	key := name
+	if namespace != "" {
+		key = namespace + "/" + name
+	}
+	return t.routeTemplates.Get(key)
+}
+
 // FilterClassify returns the Instrumentable types for each received ProcessMatch,
 // and filters out the processes that can't be instrumented (e.g. because of the lack
 // of instrumentation points)
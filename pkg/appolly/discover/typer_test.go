@@ -18,6 +18,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/appolly/services"
 	"go.opentelemetry.io/obi/pkg/export"
 	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
 	"go.opentelemetry.io/obi/pkg/obi"
 	"go.opentelemetry.io/obi/pkg/transform"
 )
@@ -28,6 +29,7 @@ type dummyCriterion struct {
 	export    services.ExportModes
 	sampler   *services.SamplerConfig
 	routes    *services.CustomRoutesConfig
+	redaction *services.RedactionConfig
 	features  export.Features
 }
 
@@ -46,6 +48,7 @@ func (d dummyCriterion) GetNamespace() string
 func (d dummyCriterion) GetExportModes() services.ExportModes                           { return d.export }
 func (d dummyCriterion) GetSamplerConfig() *services.SamplerConfig                      { return d.sampler }
 func (d dummyCriterion) GetRoutesConfig() *services.CustomRoutesConfig                  { return d.routes }
+func (d dummyCriterion) GetRedactionConfig() *services.RedactionConfig                  { return d.redaction }
 
 func (d dummyCriterion) MetricsConfig() perapp.SvcMetricsConfig {
 	return perapp.SvcMetricsConfig{Features: d.features}
@@ -85,6 +88,39 @@ func TestMakeServiceAttrs(t *testing.T) {
 	assert.NotNil(t, attrs2.CustomOutRouteMatcher)
 }
 
+func TestMakeServiceAttrs_SharedRouteTemplates(t *testing.T) {
+	procMatch := func(pid app.PID, exe string, criterion dummyCriterion) *ProcessMatch {
+		return &ProcessMatch{
+			Process:  &services.ProcessInfo{Pid: pid, ExePath: exe},
+			Criteria: []services.Selector{criterion},
+		}
+	}
+	cfg := &obi.Config{Routes: &transform.RoutesConfig{MaxPathSegmentCardinality: 10}}
+
+	// without a store, each process learns its own route templates
+	ty := typer{cfg: cfg}
+	assert.NotSame(t,
+		ty.makeServiceAttrs(procMatch(1, "/bin/svc", dummyCriterion{name: "svc"})).PathTrie,
+		ty.makeServiceAttrs(procMatch(2, "/bin/svc", dummyCriterion{name: "svc"})).PathTrie)
+
+	ty = typer{cfg: cfg, routeTemplates: clusterurl.NewTrieStore(nil, 10, '*')}
+	first := ty.makeServiceAttrs(procMatch(1, "/bin/svc", dummyCriterion{name: "svc", namespace: "ns"})).PathTrie
+	first.Insert("/users")
+	assert.Same(t, first,
+		ty.makeServiceAttrs(procMatch(2, "/bin/other", dummyCriterion{name: "svc", namespace: "ns"})).PathTrie)
+	assert.NotSame(t, first,
+		ty.makeServiceAttrs(procMatch(3, "/bin/svc", dummyCriterion{name: "svc"})).PathTrie)
+	// unnamed services running the same executable don't share nor store their route templates
+	assert.NotSame(t,
+		ty.makeServiceAttrs(procMatch(4, "/usr/bin/java", dummyCriterion{})).PathTrie,
+		ty.makeServiceAttrs(procMatch(5, "/usr/bin/java", dummyCriterion{})).PathTrie)
+
+	assert.Equal(t, map[string][]string{
+		"ns/svc": {"/users"},
+		"svc":    {},
+	}, ty.routeTemplates.Templates())
+}
+
 func TestMakeServiceAttrs_FeaturesMatchingMultipleCriteria(t *testing.T) {
 	exTra := services.ExportModes{}
 	exTra.AllowTraces()
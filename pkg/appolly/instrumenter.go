@@ -15,9 +15,11 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/imetrics"
 	"go.opentelemetry.io/obi/pkg/export/otel"
 	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/export/otel/tailsampling"
 	"go.opentelemetry.io/obi/pkg/export/prom"
 	"go.opentelemetry.io/obi/pkg/filter"
 	msg2 "go.opentelemetry.io/obi/pkg/internal/helpers/msg"
+	"go.opentelemetry.io/obi/pkg/internal/process"
 	"go.opentelemetry.io/obi/pkg/internal/traces"
 	"go.opentelemetry.io/obi/pkg/obi"
 	"go.opentelemetry.io/obi/pkg/pipe/global"
@@ -64,25 +66,32 @@ func newGraphBuilder(
 	selectorCfg := &attributes.SelectorConfig{
 		SelectionCfg:            config.Attributes.Select,
 		ExtraGroupAttributesCfg: config.Attributes.ExtraGroupAttributes,
+		BaggageKeysCfg:          config.EBPF.PayloadExtraction.HTTP.Baggage.MetricKeys,
 	}
 
 	// Second, we register instancers for each pipe node, as well as communication queues between them
 	// TODO: consider moving the queues to a public structure so when OBI is used as library, other components can
 	// listen to the messages and expanding the Pipeline
-	tracesReaderToRouter := msg2.QueueFromConfig[[]request.Span](config, "tracesReaderToRouter")
+	tracesReaderToRedactor := msg2.QueueFromConfig[[]request.Span](config, "tracesReaderToRedactor")
 	swi.Add(traces.ReadFromChannel(&traces.ReadDecorator{
 		InstanceID:      config.Attributes.InstanceID,
 		TracesInput:     tracesCh,
-		DecoratedTraces: tracesReaderToRouter,
+		DecoratedTraces: tracesReaderToRedactor,
 	}), swarm.WithID("ReadFromChannel"))
 
+	redactorToRouter := msg2.QueueFromConfig[[]request.Span](config, "redactorToRouter")
+	swi.Add(transform.RedactionProvider(
+		&config.Redaction, perServiceRedaction(config),
+		tracesReaderToRedactor, redactorToRouter,
+	), swarm.WithID("Redaction"))
+
 	routerToKubeDecorator := msg2.QueueFromConfig[[]request.Span](config, "routerToKubeDecorator",
 		// make sure that we are able to wait for the informer sync timeout before failing the pipeline
 		// if a message gets bocked while the Kube decorator starts
 		msg.SendTimeout(max(config.Attributes.Kubernetes.InformersSyncTimeout, config.ChannelSendTimeout)))
 	swi.Add(transform.RoutesProvider(
 		config.Routes,
-		tracesReaderToRouter,
+		redactorToRouter,
 		routerToKubeDecorator,
 	), swarm.WithID("Routes"))
 
@@ -119,8 +128,15 @@ func newGraphBuilder(
 		exportableSpans),
 		swarm.WithID("AttributesFilter"))
 
+	// tail sampling only applies to the exported traces. Metrics are still calculated from all the spans
+	sampledSpans := exportableSpans
+	if config.Traces.TailSampling.Enabled && config.Traces.Enabled() {
+		sampledSpans = msg2.QueueFromConfig[[]request.Span](config, "tailSampledSpans")
+		swi.Add(tailsampling.Sampler(&config.Traces.TailSampling, ctxInfo.Metrics, exportableSpans, sampledSpans),
+			swarm.WithID("TailSampler"))
+	}
 	swi.Add(otel.TracesReceiver(
-		ctxInfo, config.Traces, config.SpanMetricsEnabledForTraces(), selectorCfg, exportableSpans,
+		ctxInfo, config.Traces, config.SpanMetricsEnabledForTraces(), selectorCfg, sampledSpans,
 	), swarm.WithID("OTELTracesReceiver"))
 	swi.Add(debug.PrinterNode(config.TracePrinter, exportableSpans),
 		swarm.WithID("PrinterNode"))
@@ -128,10 +144,12 @@ func newGraphBuilder(
 	// some nodes (ipNodesFilter, span name limiter...) are only passed to the metrics export nodes.
 	// Nodes directly handling raw traces will still get the unfiltered exportableSpans queue.
 	// If no metrics exporter is configured, we will not start the metrics subpipeline to save resources.
-	exportingMetrics := config.Metrics.Features.AnyAppO11yMetric() &&
+	// The process metrics are reported by the OTEL metrics exporter, from the MeterProvider of each service.
+	processStatuses := setupProcessMetrics(config, ctxInfo, swi, processEventsCh)
+	exportingMetrics := (config.Metrics.Features.AnyAppO11yMetric() || processStatuses != nil) &&
 		(config.OTELMetrics.EndpointEnabled() || config.Prometheus.EndpointEnabled())
 	if exportingMetrics {
-		setupMetricsSubPipeline(config, ctxInfo, swi, exportableSpans, selectorCfg, processEventsCh)
+		setupMetricsSubPipeline(config, ctxInfo, swi, exportableSpans, selectorCfg, processEventsCh, processStatuses)
 	}
 
 	swi.Add(prom.BPFMetrics(ctxInfo, &config.Prometheus, joinMetricsConfig(config)),
@@ -151,6 +169,7 @@ func setupMetricsSubPipeline(
 	exportableSpans *msg.Queue[[]request.Span],
 	selectorCfg *attributes.SelectorConfig,
 	processEventsCh *msg.Queue[exec.ProcessEvent],
+	processStatuses *msg.Queue[[]*process.Status],
 ) {
 	jointMetricsConfig := joinMetricsConfig(config)
 
@@ -174,9 +193,11 @@ func setupMetricsSubPipeline(
 		&config.OTELMetrics,
 		jointMetricsConfig,
 		selectorCfg,
+		&config.SLO,
 		unresolvedCfg,
 		spanNameAggregatedMetrics,
 		processEventsCh,
+		processStatuses,
 	), swarm.WithID("OTELMetricsExport"))
 
 	swi.Add(otel.ReportSvcGraphMetrics(
@@ -197,6 +218,39 @@ func setupMetricsSubPipeline(
 		spanNameAggregatedMetrics,
 		processEventsCh,
 	), swarm.WithID("PrometheusEndpoint"))
+
+	// the OpenTelemetry SLO metrics are reported by the OTELMetricsExport node,
+	// from the MeterProvider of each service
+	swi.Add(prom.SLOPrometheusEndpoint(
+		ctxInfo,
+		&prom.SLOPrometheusConfig{Config: &config.Prometheus, SLO: &config.SLO},
+		spanNameAggregatedMetrics,
+	), swarm.WithID("PrometheusSLOEndpoint"))
+}
+
+// setupProcessMetrics samples the resource usage of the instrumented processes, if the process
+// metrics feature is enabled for any service. It returns the queue of sampled process statuses,
+// or nil if the process metrics aren't exported.
+func setupProcessMetrics(
+	config *obi.Config,
+	ctxInfo *global.ContextInfo,
+	swi *swarm.Instancer,
+	processEventsCh *msg.Queue[exec.ProcessEvent],
+) *msg.Queue[[]*process.Status] {
+	jointMetricsConfig := joinMetricsConfig(config)
+	promCfg := &prom.ProcPrometheusConfig{Config: &config.Prometheus, CommonCfg: jointMetricsConfig}
+	otelEnabled := config.OTELMetrics.EndpointEnabled() && jointMetricsConfig.Features.ProcessMetrics()
+	if !otelEnabled && !promCfg.Enabled() {
+		return nil
+	}
+
+	processStatuses := msg2.QueueFromConfig[[]*process.Status](config, "processStatuses")
+	swi.Add(process.Collector(&process.CollectConfig{
+		Interval: config.Processes.Interval,
+	}, processEventsCh, processStatuses), swarm.WithID("ProcessCollector"))
+	swi.Add(prom.ProcPrometheusEndpoint(ctxInfo, promCfg, processStatuses),
+		swarm.WithID("PrometheusProcEndpoint"))
+	return processStatuses
 }
 
 func (gb *graphFunctions) buildGraph(ctx context.Context) (*Instrumenter, error) {
@@ -259,3 +313,18 @@ func joinMetricsConfig(cfg *obi.Config) *perapp.MetricsConfig {
 	}
 	return &mc
 }
+
+// perServiceRedaction returns whether any of the service selectors defines its own redaction rules
+func perServiceRedaction(cfg *obi.Config) bool {
+	for _, d := range cfg.Discovery.Instrument {
+		if d.Redaction != nil {
+			return true
+		}
+	}
+	for _, d := range cfg.Discovery.Services {
+		if d.Redaction != nil {
+			return true
+		}
+	}
+	return false
+}
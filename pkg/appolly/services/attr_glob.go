@@ -121,6 +121,9 @@ type GlobAttributes struct {
 
 	Routes *CustomRoutesConfig `yaml:"routes"`
 
+	// Redaction rules for this service, which override the globally defined rules
+	Redaction *RedactionConfig `yaml:"redaction"`
+
 	// Metrics configuration that is custom for this service match
 	Metrics perapp.SvcMetricsConfig `yaml:"metrics" env:"-"`
 }
@@ -239,6 +242,8 @@ func (ga *GlobAttributes) GetSamplerConfig() *SamplerConfig { return ga.SamplerC
 
 func (ga *GlobAttributes) GetRoutesConfig() *CustomRoutesConfig { return ga.Routes }
 
+func (ga *GlobAttributes) GetRedactionConfig() *RedactionConfig { return ga.Redaction }
+
 func (ga *GlobAttributes) pids() ([]app.PID, bool) {
 	if len(ga.PIDs) == 0 {
 		return nil, false
@@ -124,6 +124,9 @@ type RegexSelector struct {
 
 	Routes *CustomRoutesConfig `yaml:"routes"`
 
+	// Redaction rules for this service, which override the globally defined rules
+	Redaction *RedactionConfig `yaml:"redaction"`
+
 	// Metrics configuration that is custom for this service match
 	Metrics perapp.SvcMetricsConfig `yaml:"metrics"`
 }
@@ -240,6 +243,8 @@ func (a *RegexSelector) GetSamplerConfig() *SamplerConfig { return a.SamplerConf
 
 func (a *RegexSelector) GetRoutesConfig() *CustomRoutesConfig { return a.Routes }
 
+func (a *RegexSelector) GetRedactionConfig() *RedactionConfig { return a.Redaction }
+
 func (a *RegexSelector) pids() ([]app.PID, bool) {
 	if len(a.PIDs) == 0 {
 		return nil, false
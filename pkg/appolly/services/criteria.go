@@ -67,6 +67,9 @@ const (
 	RouteHarvesterLanguageJava   RouteHarvesterLanguage = "java"
 	RouteHarvesterLanguageNodejs RouteHarvesterLanguage = "nodejs"
 	RouteHarvesterLanguageGo     RouteHarvesterLanguage = "go"
+	RouteHarvesterLanguagePython RouteHarvesterLanguage = "python"
+	RouteHarvesterLanguageRuby   RouteHarvesterLanguage = "ruby"
+	RouteHarvesterLanguagePHP    RouteHarvesterLanguage = "php"
 )
 
 // DiscoveryConfig for the discover.ProcessFinder pipeline
@@ -177,6 +180,7 @@ type Selector interface {
 	GetExportModes() ExportModes
 	GetSamplerConfig() *SamplerConfig
 	GetRoutesConfig() *CustomRoutesConfig
+	GetRedactionConfig() *RedactionConfig
 	MetricsConfig() perapp.SvcMetricsConfig
 }
 
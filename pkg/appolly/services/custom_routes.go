@@ -3,7 +3,24 @@
 
 package services // import "go.opentelemetry.io/obi/pkg/appolly/services"
 
+import "time"
+
 type CustomRoutesConfig struct {
 	Incoming []string `yaml:"incoming"`
 	Outgoing []string `yaml:"outgoing"`
+	// OpenAPI documents of the service, whose path templates are used as incoming routes
+	OpenAPI OpenAPIConfig `yaml:"openapi"`
+}
+
+// OpenAPIConfig references the OpenAPI documents whose path templates are used as routes
+type OpenAPIConfig struct {
+	// Documents are the local file paths or http(s) URLs of OpenAPI 3 (or Swagger 2)
+	// documents, in JSON or YAML format
+	Documents []string `yaml:"documents"`
+	// RefreshInterval between two reloads of the documents. Defaults to 5 minutes.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+func (c *OpenAPIConfig) Enabled() bool {
+	return len(c.Documents) > 0
 }
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package services // import "go.opentelemetry.io/obi/pkg/appolly/services"
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// RedactionConfig defines how the URL path segments, the URL query parameters, the captured
+// HTTP headers and the captured baggage entries are redacted before being exported. As the HTTP enrichment rules, the rules are
+// evaluated in order, and the first matching rule wins.
+// The captured HTTP response bodies can't be matched against the rules, so they are redacted with
+// the default action, or masked if the default action is "keep" but any rule redacts values.
+type RedactionConfig struct {
+	// Policy controls the default behavior of the redaction
+	Policy RedactionPolicy `yaml:"policy"`
+	// Rules is an ordered list of keep/mask/hash rules
+	Rules []RedactionRule `yaml:"rules"`
+}
+
+// Enabled returns whether any value might be redacted
+func (c *RedactionConfig) Enabled() bool {
+	if c == nil {
+		return false
+	}
+	if c.Policy.DefaultAction != 0 && c.Policy.DefaultAction != RedactionActionKeep {
+		return true
+	}
+	for i := range c.Rules {
+		if c.Rules[i].Action != RedactionActionKeep {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactionPolicy defines the default action of the redaction rules
+type RedactionPolicy struct {
+	// DefaultAction specifies what to do when no rule matches: "keep" (default), "mask" or "hash"
+	DefaultAction RedactionAction `yaml:"default_action"`
+	// MatchOrder controls how rules are evaluated: "first_match_wins" (default)
+	MatchOrder RuleMatchOrder `yaml:"match_order"`
+	// MaskString is the replacement string used when the action is "mask". Defaults to "*"
+	MaskString string `yaml:"mask_string"`
+}
+
+// RedactionRule defines a single keep/mask/hash rule
+type RedactionRule struct {
+	// Action of the rule: "keep", "mask" or "hash"
+	Action RedactionAction `yaml:"action"`
+	// Type specifies what this rule matches against: "path_segment" (the segment value),
+	// "query" (the query parameter key), "header" (the captured header name) or "baggage"
+	// (the captured baggage entry key)
+	Type RedactionRuleType `yaml:"type"`
+	// Match defines the matching criteria for this rule
+	Match RuleMatch `yaml:"match"`
+}
+
+// UnmarshalYAML deserializes the rule and rejects the rules without an action or a type,
+// which otherwise would never match.
+func (r *RedactionRule) UnmarshalYAML(value *yaml.Node) error {
+	type plain RedactionRule
+	if err := value.Decode((*plain)(r)); err != nil {
+		return err
+	}
+	if r.Action == 0 {
+		return errors.New("redaction rule requires an action (valid: keep, mask, hash)")
+	}
+	if r.Type == 0 {
+		return errors.New("redaction rule requires a type (valid: path_segment, query, header, baggage)")
+	}
+	return nil
+}
+
+// Resolve returns the action for a path segment, query parameter key, header name or baggage key, by
+// evaluating the rules of the provided type in order
+func (c *RedactionConfig) Resolve(ruleType RedactionRuleType, value string) RedactionAction {
+	var lowerValue string
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Type != ruleType {
+			continue
+		}
+		if rule.Match.MatchString(value, &lowerValue) {
+			return rule.Action
+		}
+	}
+	if c.Policy.DefaultAction == 0 {
+		return RedactionActionKeep
+	}
+	return c.Policy.DefaultAction
+}
+
+// RedactionRuleType specifies the target of a redaction rule
+type RedactionRuleType uint8
+
+const (
+	RedactionRuleTypePathSegment RedactionRuleType = iota + 1
+	RedactionRuleTypeQuery
+	RedactionRuleTypeHeader
+	RedactionRuleTypeBaggage
+)
+
+func (t *RedactionRuleType) UnmarshalText(text []byte) error {
+	switch strings.TrimSpace(string(text)) {
+	case "path_segment":
+		*t = RedactionRuleTypePathSegment
+	case "query":
+		*t = RedactionRuleTypeQuery
+	case "header":
+		*t = RedactionRuleTypeHeader
+	case "baggage":
+		*t = RedactionRuleTypeBaggage
+	default:
+		return fmt.Errorf("invalid redaction rule type: %q (valid: path_segment, query, header, baggage)", string(text))
+	}
+	return nil
+}
+
+func (t RedactionRuleType) MarshalText() ([]byte, error) {
+	switch t {
+	case RedactionRuleTypePathSegment:
+		return []byte("path_segment"), nil
+	case RedactionRuleTypeQuery:
+		return []byte("query"), nil
+	case RedactionRuleTypeHeader:
+		return []byte("header"), nil
+	case RedactionRuleTypeBaggage:
+		return []byte("baggage"), nil
+	default:
+		return nil, fmt.Errorf("unknown redaction rule type: %d", t)
+	}
+}
+
+func (RedactionRuleType) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{"path_segment", "query", "header", "baggage"},
+	}
+}
+
+// RedactionAction represents the action of a redaction rule or default policy
+type RedactionAction uint8
+
+const (
+	// RedactionActionKeep reports the value as is
+	RedactionActionKeep RedactionAction = iota + 1
+	// RedactionActionMask replaces the value by the policy mask string
+	RedactionActionMask
+	// RedactionActionHash replaces the value by a hash, so equal values can still be correlated
+	RedactionActionHash
+)
+
+func (a *RedactionAction) UnmarshalText(text []byte) error {
+	switch strings.TrimSpace(string(text)) {
+	case "keep":
+		*a = RedactionActionKeep
+	case "mask":
+		*a = RedactionActionMask
+	case "hash":
+		*a = RedactionActionHash
+	default:
+		return fmt.Errorf("invalid redaction action: %q (valid: keep, mask, hash)", string(text))
+	}
+	return nil
+}
+
+func (a RedactionAction) MarshalText() ([]byte, error) {
+	switch a {
+	case RedactionActionKeep:
+		return []byte("keep"), nil
+	case RedactionActionMask:
+		return []byte("mask"), nil
+	case RedactionActionHash:
+		return []byte("hash"), nil
+	default:
+		return nil, fmt.Errorf("unknown redaction action: %d", a)
+	}
+}
+
+func (RedactionAction) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{"keep", "mask", "hash"},
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package services // import "go.opentelemetry.io/obi/pkg/appolly/services"
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/invopop/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleMatch defines the matching criteria of a policy-based rule, such as the HTTP enrichment
+// and the redaction rules. A rule matches if any of its glob patterns or regular expressions matches.
+type RuleMatch struct {
+	// Patterns is a list of glob patterns to match the rule against
+	Patterns []GlobAttr `yaml:"patterns"`
+	// Regexes is a list of regular expressions to match the rule against
+	Regexes []RegexpAttr `yaml:"regexes"`
+	// CaseSensitive controls whether matching is case-sensitive.
+	CaseSensitive bool `yaml:"case_sensitive"`
+}
+
+// UnmarshalYAML deserializes the match config and compiles the glob patterns and regular expressions.
+func (m *RuleMatch) UnmarshalYAML(value *yaml.Node) error {
+	var raw struct {
+		Patterns      []string `yaml:"patterns"`
+		Regexes       []string `yaml:"regexes"`
+		CaseSensitive bool     `yaml:"case_sensitive"`
+	}
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	m.CaseSensitive = raw.CaseSensitive
+	m.Patterns = make([]GlobAttr, 0, len(raw.Patterns))
+	for _, pattern := range raw.Patterns {
+		if !m.CaseSensitive {
+			pattern = strings.ToLower(pattern)
+		}
+		m.Patterns = append(m.Patterns, NewGlob(pattern))
+	}
+	m.Regexes = make([]RegexpAttr, 0, len(raw.Regexes))
+	for _, expr := range raw.Regexes {
+		if !m.CaseSensitive {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression %q: %w", expr, err)
+		}
+		m.Regexes = append(m.Regexes, RegexpAttr{re: re})
+	}
+	return nil
+}
+
+// MatchString returns whether the value matches any of the patterns. Case-insensitive globs
+// are matched against the lowercased value, while case-insensitive regular expressions already
+// ignore the case. The lowered argument caches the lowercased value, so it is computed only
+// once when the value is matched against a list of rules.
+func (m *RuleMatch) MatchString(value string, lowered *string) bool {
+	globValue := value
+	if !m.CaseSensitive && len(m.Patterns) > 0 {
+		if *lowered == "" {
+			*lowered = strings.ToLower(value)
+		}
+		globValue = *lowered
+	}
+	for i := range m.Patterns {
+		if m.Patterns[i].MatchString(globValue) {
+			return true
+		}
+	}
+	for i := range m.Regexes {
+		if m.Regexes[i].MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleMatchOrder controls how the rules of a policy are evaluated.
+type RuleMatchOrder uint8
+
+const (
+	RuleMatchOrderFirstMatchWins RuleMatchOrder = iota + 1
+)
+
+func (m *RuleMatchOrder) UnmarshalText(text []byte) error {
+	switch strings.TrimSpace(string(text)) {
+	case "first_match_wins":
+		*m = RuleMatchOrderFirstMatchWins
+	default:
+		return fmt.Errorf("invalid match order: %q (valid: first_match_wins)", string(text))
+	}
+	return nil
+}
+
+func (m RuleMatchOrder) MarshalText() ([]byte, error) {
+	switch m {
+	case RuleMatchOrderFirstMatchWins:
+		return []byte("first_match_wins"), nil
+	default:
+		return nil, fmt.Errorf("unknown match order: %d", m)
+	}
+}
+
+func (RuleMatchOrder) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{"first_match_wins"},
+	}
+}
@@ -112,6 +112,9 @@ type EBPFTracer struct {
 	// Kafka Topic UUID to Name cache size.
 	KafkaTopicUUIDCacheSize int `yaml:"kafka_topic_uuid_cache_size" env:"OTEL_KAFKA_TOPIC_UUID_CACHE_SIZE" validate:"gt=0"`
 
+	// Kafka client ID to consumer group cache size.
+	KafkaClientIDGroupCacheSize int `yaml:"kafka_client_id_group_cache_size" env:"OTEL_EBPF_BPF_KAFKA_CLIENT_ID_GROUP_CACHE_SIZE" validate:"gt=0"`
+
 	// MongoDB requests cache size.
 	MongoRequestsCacheSize int `yaml:"mongo_requests_cache_size" env:"OTEL_EBPF_BPF_MONGO_REQUESTS_CACHE_SIZE" validate:"gt=0"`
 
@@ -8,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/invopop/jsonschema"
-	"gopkg.in/yaml.v3"
 
 	"go.opentelemetry.io/obi/pkg/appolly/services"
 )
@@ -23,7 +22,8 @@ func (p PayloadExtraction) Enabled() bool {
 		p.HTTP.AWS.Enabled ||
 		p.HTTP.SQLPP.Enabled ||
 		p.HTTP.GenAI.Enabled() ||
-		p.HTTP.Enrichment.Enabled
+		p.HTTP.Enrichment.Enabled ||
+		p.HTTP.ErrorBody.Enabled
 }
 
 type HTTPConfig struct {
@@ -39,6 +39,47 @@ type HTTPConfig struct {
 	GenAI GenAIConfig `yaml:"genai"`
 	// Enrichment configures HTTP header and payload extraction with policy-based rules
 	Enrichment EnrichmentConfig `yaml:"enrichment"`
+	// Baggage configures the extraction of W3C baggage entries from the request headers
+	Baggage BaggageConfig `yaml:"baggage"`
+	// ErrorBody configures the capture of the body of the HTTP 5xx responses
+	ErrorBody ErrorBodyConfig `yaml:"error_body"`
+}
+
+// ErrorBodyConfig configures reporting the beginning of the body of the HTTP 5xx responses
+// as the message of the span exception events. The bodies might contain sensitive data, so
+// they are not captured unless explicitly enabled, and they are redacted as configured in the
+// redaction section.
+type ErrorBodyConfig struct {
+	// Enable capturing the beginning of the body of the HTTP 5xx responses
+	Enabled bool `yaml:"enabled" env:"OTEL_EBPF_HTTP_ERROR_BODY_ENABLED" validate:"boolean"`
+}
+
+// BaggageConfig selects the entries of the W3C baggage request header that are reported as
+// span and metric attributes, named as the baggage key prefixed by "baggage." (e.g. baggage.tenant.id).
+// Their values are redacted by the redaction rules of type "baggage".
+type BaggageConfig struct {
+	// SpanKeys of the baggage entries that are added as span attributes
+	SpanKeys []string `yaml:"span_keys" env:"OTEL_EBPF_HTTP_BAGGAGE_SPAN_KEYS" envSeparator:","`
+	// MetricKeys of the baggage entries that are allowed as HTTP metric attributes. To protect the
+	// metrics cardinality, they are not reported unless they are also included in the attributes.select
+	// section. These entries are added as span attributes, too.
+	MetricKeys []string `yaml:"metric_keys" env:"OTEL_EBPF_HTTP_BAGGAGE_METRIC_KEYS" envSeparator:","`
+}
+
+func (b *BaggageConfig) Enabled() bool {
+	return len(b.SpanKeys) > 0 || len(b.MetricKeys) > 0
+}
+
+// Keys returns the set of baggage keys that need to be extracted
+func (b *BaggageConfig) Keys() map[string]struct{} {
+	keys := make(map[string]struct{}, len(b.SpanKeys)+len(b.MetricKeys))
+	for _, k := range b.SpanKeys {
+		keys[k] = struct{}{}
+	}
+	for _, k := range b.MetricKeys {
+		keys[k] = struct{}{}
+	}
+	return keys
 }
 
 type GraphQLConfig struct {
@@ -152,34 +193,7 @@ func (HTTPParsingRuleType) JSONSchema() *jsonschema.Schema {
 }
 
 // HTTPParsingMatch defines matching criteria for an HTTP parsing rule.
-type HTTPParsingMatch struct {
-	// Patterns is a list of glob patterns to match the rule against
-	Patterns []services.GlobAttr `yaml:"patterns"`
-	// CaseSensitive controls whether matching is case-sensitive.
-	CaseSensitive bool `yaml:"case_sensitive"`
-}
-
-// UnmarshalYAML deserializes the match config and compiles glob patterns.
-func (m *HTTPParsingMatch) UnmarshalYAML(value *yaml.Node) error {
-	var raw struct {
-		Patterns      []string `yaml:"patterns"`
-		CaseSensitive bool     `yaml:"case_sensitive"`
-	}
-	if err := value.Decode(&raw); err != nil {
-		return err
-	}
-
-	m.CaseSensitive = raw.CaseSensitive
-	m.Patterns = make([]services.GlobAttr, 0, len(raw.Patterns))
-	for _, pattern := range raw.Patterns {
-		compilePattern := pattern
-		if !m.CaseSensitive {
-			compilePattern = strings.ToLower(pattern)
-		}
-		m.Patterns = append(m.Patterns, services.NewGlob(compilePattern))
-	}
-	return nil
-}
+type HTTPParsingMatch = services.RuleMatch
 
 // HTTPParsingAction represents the action for a generic parsing rule or default policy.
 type HTTPParsingAction uint8
@@ -268,34 +282,8 @@ func (HTTPParsingScope) JSONSchema() *jsonschema.Schema {
 }
 
 // HTTPParsingMatchOrder controls how rules are evaluated.
-type HTTPParsingMatchOrder uint8
+type HTTPParsingMatchOrder = services.RuleMatchOrder
 
 const (
-	HTTPParsingMatchOrderFirstMatchWins HTTPParsingMatchOrder = iota + 1
+	HTTPParsingMatchOrderFirstMatchWins = services.RuleMatchOrderFirstMatchWins
 )
-
-func (m *HTTPParsingMatchOrder) UnmarshalText(text []byte) error {
-	switch strings.TrimSpace(string(text)) {
-	case "first_match_wins":
-		*m = HTTPParsingMatchOrderFirstMatchWins
-	default:
-		return fmt.Errorf("invalid parsing match order: %q (valid: first_match_wins)", string(text))
-	}
-	return nil
-}
-
-func (m HTTPParsingMatchOrder) MarshalText() ([]byte, error) {
-	switch m {
-	case HTTPParsingMatchOrderFirstMatchWins:
-		return []byte("first_match_wins"), nil
-	default:
-		return nil, fmt.Errorf("unknown parsing match order: %d", m)
-	}
-}
-
-func (HTTPParsingMatchOrder) JSONSchema() *jsonschema.Schema {
-	return &jsonschema.Schema{
-		Type: "string",
-		Enum: []any{"first_match_wins"},
-	}
-}
@@ -186,7 +186,9 @@ type EBPFParseContext struct {
 	postgresPreparedStatements *simplelru.LRU[postgresPreparedStatementsKey, string]
 	postgresPortals            *simplelru.LRU[postgresPortalsKey, string]
 	kafkaTopicUUIDToName       *simplelru.LRU[kafkaparser.UUID, string]
+	kafkaClientIDToGroup       *simplelru.LRU[string, string]
 	payloadExtraction          config.PayloadExtraction
+	baggageKeys                map[string]struct{}
 	dnsEvents                  *expirable.LRU[dnsparser.DNSId, *request.Span]
 	emitSpans                  func([]request.Span)
 }
@@ -222,8 +224,10 @@ func NewEBPFParseContext(cfg *config.EBPFTracer, spansChan *msg.Queue[[]request.
 		postgresPreparedStatements *simplelru.LRU[postgresPreparedStatementsKey, string]
 		postgresPortals            *simplelru.LRU[postgresPortalsKey, string]
 		kafkaTopicUUIDToName       *simplelru.LRU[kafkaparser.UUID, string]
+		kafkaClientIDToGroup       *simplelru.LRU[string, string]
 		mongoRequestCache          PendingMongoDBRequests
 		payloadExtraction          config.PayloadExtraction
+		baggageKeys                map[string]struct{}
 		dnsEvents                  *expirable.LRU[dnsparser.DNSId, *request.Span]
 		emitSpans                  func([]request.Span)
 	)
@@ -280,9 +284,17 @@ func NewEBPFParseContext(cfg *config.EBPFTracer, spansChan *msg.Queue[[]request.
 			ptlog().Error("failed to create Kafka topic UUID to name cache", "error", err)
 		}
 
+		kafkaClientIDToGroup, err = simplelru.NewLRU[string, string](cfg.KafkaClientIDGroupCacheSize, nil)
+		if err != nil {
+			ptlog().Error("failed to create Kafka client ID to consumer group cache", "error", err)
+		}
+
 		mongoRequestCache = expirable.NewLRU[MongoRequestKey, *MongoRequestValue](cfg.MongoRequestsCacheSize, nil, 0)
 
 		payloadExtraction = cfg.PayloadExtraction
+		if payloadExtraction.HTTP.Baggage.Enabled() {
+			baggageKeys = payloadExtraction.HTTP.Baggage.Keys()
+		}
 
 		dnsEvents = expirable.NewLRU(1024, dnsEventExpireHandler(emitSpans), cfg.DNSRequestTimeout)
 	}
@@ -298,7 +310,9 @@ func NewEBPFParseContext(cfg *config.EBPFTracer, spansChan *msg.Queue[[]request.
 		postgresPreparedStatements: postgresPreparedStatements,
 		postgresPortals:            postgresPortals,
 		kafkaTopicUUIDToName:       kafkaTopicUUIDToName,
+		kafkaClientIDToGroup:       kafkaClientIDToGroup,
 		payloadExtraction:          payloadExtraction,
+		baggageKeys:                baggageKeys,
 		dnsEvents:                  dnsEvents,
 		emitSpans:                  emitSpans,
 	}
@@ -26,8 +26,8 @@ func ReadFailedConnectIntoSpan(record *ringbuf.Record, filter ServiceFilter) (re
 
 func FailedConnectToSpan(trace *TCPRequestInfo) request.Span {
 	var (
-		peer, hostname             string
-		peerPort, hostPort, status int
+		peer, hostname     string
+		peerPort, hostPort int
 	)
 
 	if trace.ConnInfo.S_port != 0 || trace.ConnInfo.D_port != 0 {
@@ -48,7 +48,7 @@ func FailedConnectToSpan(trace *TCPRequestInfo) request.Span {
 		RequestStart:  int64(trace.StartMonotimeNs),
 		Start:         int64(trace.StartMonotimeNs),
 		End:           int64(trace.EndMonotimeNs),
-		Status:        status,
+		Status:        int(trace.ConnErr),
 		TraceID:       trace.Tp.TraceId,
 		SpanID:        trace.Tp.SpanId,
 		ParentSpanID:  trace.Tp.ParentId,
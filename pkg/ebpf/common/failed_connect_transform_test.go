@@ -0,0 +1,28 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+)
+
+func TestFailedConnectToSpan(t *testing.T) {
+	trace := makeTCPReq("", 34567)
+	trace.Len = 0
+	trace.ConnErr = uint32(syscall.ECONNREFUSED)
+
+	span := FailedConnectToSpan(&trace)
+
+	assert.Equal(t, request.EventTypeFailedConnect, span.Type)
+	assert.Equal(t, int(syscall.ECONNREFUSED), span.Status)
+	assert.Equal(t, 34567, span.PeerPort)
+	assert.Equal(t, 8080, span.HostPort)
+	assert.Equal(t, int64(trace.StartMonotimeNs), span.Start)
+	assert.Equal(t, int64(trace.EndMonotimeNs), span.End)
+}
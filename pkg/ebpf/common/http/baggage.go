@@ -0,0 +1,87 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon // import "go.opentelemetry.io/obi/pkg/ebpf/common/http"
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+)
+
+// ExtractBaggage parses the W3C baggage headers of the raw HTTP request and stores into the
+// span the entries whose key is in the provided set. It returns false if no entry was stored.
+func ExtractBaggage(span *request.Span, rawRequest []byte, keys map[string]struct{}) bool {
+	headers := rawHeaders(rawRequest)
+	found := false
+	// the baggage can be split into multiple headers
+	for _, value := range headerValues(headers, "baggage") {
+		// list-member = key OWS "=" OWS value *( OWS ";" OWS property )
+		for member := range strings.SplitSeq(value, ",") {
+			member, _, _ = strings.Cut(member, ";")
+			key, val, ok := strings.Cut(member, "=")
+			if !ok {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			if _, selected := keys[key]; !selected {
+				continue
+			}
+			val = strings.TrimSpace(val)
+			if unescaped, err := url.PathUnescape(val); err == nil {
+				val = unescaped
+			}
+			if span.Baggage == nil {
+				span.Baggage = map[string]string{}
+			}
+			span.Baggage[key] = val
+			found = true
+		}
+	}
+	return found
+}
+
+// rawHeaders returns the (possibly truncated) header section of the raw HTTP request, without
+// the request line. Only complete header lines are kept.
+func rawHeaders(req []byte) []byte {
+	if end := bytes.IndexByte(req, 0); end >= 0 {
+		req = req[:end]
+	}
+	_, headers, found := bytes.Cut(req, []byte("\r\n"))
+	if !found {
+		return nil
+	}
+	if end := bytes.Index(headers, []byte("\r\n\r\n")); end >= 0 {
+		return headers[:end+2]
+	}
+	if end := bytes.LastIndex(headers, []byte("\r\n")); end >= 0 {
+		return headers[:end+2]
+	}
+	return nil
+}
+
+// headerValues returns the values of all the headers with the given name.
+func headerValues(headers []byte, name string) []string {
+	var values []string
+	for len(headers) > 0 {
+		var value string
+		var found bool
+		value, headers, found = nextHeaderValue(headers, name)
+		if found {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// nextHeaderValue consumes the first header line, returning its value if its name matches.
+func nextHeaderValue(headers []byte, name string) (value string, rest []byte, found bool) {
+	line, rest, _ := bytes.Cut(headers, []byte("\r\n"))
+	key, val, ok := bytes.Cut(line, []byte(":"))
+	if ok && strings.EqualFold(string(bytes.TrimSpace(key)), name) {
+		return string(bytes.TrimSpace(val)), rest, true
+	}
+	return "", rest, false
+}
@@ -0,0 +1,36 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ebpfcommon
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+)
+
+func TestExtractBaggage(t *testing.T) {
+	keys := map[string]struct{}{"tenant.id": {}, "deployment.ring": {}, "user": {}}
+	raw := []byte("GET /users HTTP/1.1\r\n" +
+		"Host: example.com\r\n" +
+		"baggage: tenant.id = acme ; ttl=3600, session=s3cr3t\r\n" +
+		"Baggage: deployment.ring=canary%201,invalid,user=\r\n" +
+		"\r\n")
+
+	span := request.Span{}
+	assert.True(t, ExtractBaggage(&span, raw, keys))
+	assert.Equal(t, map[string]string{
+		"tenant.id":       "acme",
+		"deployment.ring": "canary 1",
+		"user":            "",
+	}, span.Baggage)
+
+	span = request.Span{}
+	assert.False(t, ExtractBaggage(&span, raw, map[string]struct{}{"session.id": {}}))
+	assert.Nil(t, span.Baggage)
+
+	span = request.Span{}
+	assert.False(t, ExtractBaggage(&span, []byte("GET /users HTTP/1.1\r\nHost: example.com\r\n\r\n"), keys))
+}
@@ -5,7 +5,6 @@ package ebpfcommon // import "go.opentelemetry.io/obi/pkg/ebpf/common/http"
 
 import (
 	"net/http"
-	"strings"
 
 	"go.opentelemetry.io/obi/pkg/appolly/app/request"
 	"go.opentelemetry.io/obi/pkg/config"
@@ -76,17 +75,8 @@ func resolveHeaderAction(
 		if !scopeApplies(rule.Scope, scope) {
 			continue
 		}
-		matchName := headerName
-		if !rule.Match.CaseSensitive {
-			if lowerName == "" {
-				lowerName = strings.ToLower(headerName)
-			}
-			matchName = lowerName
-		}
-		for i := range rule.Match.Patterns {
-			if rule.Match.Patterns[i].MatchString(matchName) {
-				return rule.Action
-			}
+		if rule.Match.MatchString(headerName, &lowerName) {
+			return rule.Action
 		}
 	}
 	return policy.DefaultAction
@@ -301,6 +301,36 @@ func TestGenericParsingSpan_MultipleGlobsInRule(t *testing.T) {
 	assert.False(t, hasAuth)
 }
 
+func TestGenericParsingSpan_RegexRule(t *testing.T) {
+	cfg := config.EnrichmentConfig{
+		Enabled: true,
+		Policy: config.HTTPParsingPolicy{
+			DefaultAction:     config.HTTPParsingActionExclude,
+			MatchOrder:        config.HTTPParsingMatchOrderFirstMatchWins,
+			ObfuscationString: "*",
+		},
+		Rules: []config.HTTPParsingRule{
+			{
+				Action: config.HTTPParsingActionInclude,
+				Type:   config.HTTPParsingRuleTypeHeaders,
+				Scope:  config.HTTPParsingScopeAll,
+				Match:  config.HTTPParsingMatch{Regexes: []services.RegexpAttr{services.NewRegexp("^X-Request-(Id|Source)$")}},
+			},
+		},
+	}
+	span := &request.Span{Method: "GET", Path: "/test"}
+	req, resp := makeReqResp(
+		map[string]string{"X-Request-Id": "abc123", "X-Request-Secret": "hidden"},
+		nil,
+	)
+
+	ok := EnrichHTTPSpan(span, req, resp, cfg)
+	require.True(t, ok)
+	assert.Equal(t, []string{"abc123"}, span.RequestHeaders["X-Request-Id"])
+	_, hasSecret := span.RequestHeaders["X-Request-Secret"]
+	assert.False(t, hasSecret)
+}
+
 func TestGenericParsingSpan_RuleOrderExcludeBeforeInclude(t *testing.T) {
 	cfg := config.EnrichmentConfig{
 		Enabled: true,
@@ -233,15 +233,39 @@ func HTTPInfoEventToSpan(parseCtx *EBPFParseContext, event *BPFHTTPInfo) (reques
 		requestBuffer = largebuf.NewLargeBufferFrom(event.Buf[:])
 	}
 
+	span := httpEventBuffersToSpan(parseCtx, event, requestBuffer, responseBuffer, hasResponse)
+
+	if parseCtx != nil && len(parseCtx.baggageKeys) > 0 {
+		ebpfhttp.ExtractBaggage(&span, requestBuffer.UnsafeView(), parseCtx.baggageKeys)
+	}
+
+	return span, false, nil
+}
+
+// HTTPInfoInFlightToSpan converts the HTTP info of a request that hasn't finished yet into
+// an in-flight span. Only the request data of the event is parsed, so the large buffers
+// are kept for the span of the finished request.
+func HTTPInfoInFlightToSpan(event *BPFHTTPInfo) request.Span {
+	span := httpRequestToSpan(event, largebuf.NewLargeBufferFrom(event.Buf[:]))
+	span.InFlight = true
+	return span
+}
+
+func httpEventBuffersToSpan(
+	parseCtx *EBPFParseContext,
+	event *BPFHTTPInfo,
+	requestBuffer, responseBuffer *largebuf.LargeBuffer,
+	hasResponse bool,
+) request.Span {
 	if parseCtx != nil && !parseCtx.payloadExtraction.Enabled() {
 		// There's no need to parse HTTP headers/body,
 		// create the span directly.
-		return httpRequestToSpan(event, requestBuffer), false, nil
+		return httpRequestToSpan(event, requestBuffer)
 	}
 
 	if !hasResponse {
 		// Large buffers disabled
-		return httpRequestToSpan(event, requestBuffer), false, nil
+		return httpRequestToSpan(event, requestBuffer)
 	}
 
 	// http.ReadRequest requires a *bufio.Reader; that one allocation is unavoidable.
@@ -250,10 +274,31 @@ func HTTPInfoEventToSpan(parseCtx *EBPFParseContext, event *BPFHTTPInfo) (reques
 	resp, err2 := httpSafeParseResponse(responseBuffer, req)
 	if err != nil || err2 != nil {
 		slog.Debug("error while parsing http request or response, falling back to manual HTTP info parsing", "reqErr", err, "respErr", err2)
-		return httpRequestToSpan(event, requestBuffer), false, nil
+		return httpRequestToSpan(event, requestBuffer)
 	}
 
-	return httpRequestResponseToSpan(parseCtx, event, req, resp), false, nil
+	span := httpRequestResponseToSpan(parseCtx, event, req, resp)
+	if span.Status >= 500 && parseCtx != nil && parseCtx.payloadExtraction.HTTP.ErrorBody.Enabled {
+		span.ResponseBodyPrefix = httpResponseBodyPrefix(responseBuffer, req)
+	}
+	return span
+}
+
+// maxResponseBodyPrefixLen limits the size of the response body that is stored to describe
+// the error of the HTTP 5xx responses
+const maxResponseBodyPrefixLen = 256
+
+// httpResponseBodyPrefix returns the beginning of the response body. Since the body of the
+// parsed response might have been already consumed, the response is parsed again.
+func httpResponseBodyPrefix(responseBuffer *largebuf.LargeBuffer, req *http.Request) string {
+	resp, err := httpSafeParseResponse(responseBuffer, req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+	// the captured body might be truncated, so the read errors are ignored
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodyPrefixLen))
+	return strings.ToValidUTF8(strings.TrimSpace(string(body)), "")
 }
 
 // HTTP response buffers might have been sent incomplete, before the full body.
@@ -19,7 +19,9 @@ import (
 	"go.opentelemetry.io/obi/pkg/appolly/app"
 	"go.opentelemetry.io/obi/pkg/appolly/app/request"
 	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/config"
 	"go.opentelemetry.io/obi/pkg/internal/ebpf/ringbuf"
+	"go.opentelemetry.io/obi/pkg/internal/largebuf"
 )
 
 const bufSize = 256
@@ -119,6 +121,65 @@ func TestHTTPRequestResponseToSpanSetsFullPath(t *testing.T) {
 	})
 }
 
+func TestHTTPInfoInFlightToSpan(t *testing.T) {
+	event := &BPFHTTPInfo{
+		Type:            uint8(request.EventTypeHTTP),
+		ReqMonotimeNs:   100,
+		StartMonotimeNs: 100,
+	}
+	copy(event.Buf[:], "POST /users?id=3 HTTP/1.1\r\nHost: example.com\r\n\r\n")
+	event.Pid.HostPid = 123
+
+	span := HTTPInfoInFlightToSpan(event)
+	assert.True(t, span.InFlight)
+	assert.True(t, span.InternalSignal())
+	assert.Equal(t, request.EventTypeHTTP, span.Type)
+	assert.Equal(t, "POST", span.Method)
+	assert.Equal(t, "/users", span.Path)
+	assert.EqualValues(t, 100, span.Start)
+	assert.EqualValues(t, 123, span.Pid.HostPID)
+}
+
+func TestHTTPResponseBodyPrefix(t *testing.T) {
+	req := &http.Request{Method: http.MethodGet}
+
+	resp := "HTTP/1.1 503 Service Unavailable\r\nContent-Type: application/json\r\nContent-Length: 33\r\n\r\n" +
+		"{\"error\":\"database unavailable\"}\n"
+	assert.JSONEq(t, `{"error":"database unavailable"}`,
+		httpResponseBodyPrefix(largebuf.NewLargeBufferFrom([]byte(resp)), req))
+
+	// chunked bodies are decoded
+	resp = "HTTP/1.1 500 Internal Server Error\r\nTransfer-Encoding: chunked\r\n\r\n5\r\noops!\r\n0\r\n\r\n"
+	assert.Equal(t, "oops!", httpResponseBodyPrefix(largebuf.NewLargeBufferFrom([]byte(resp)), req))
+
+	// long and truncated bodies are cut
+	resp = "HTTP/1.1 500 Internal Server Error\r\nContent-Length: 1000\r\n\r\n" + strings.Repeat("a", 300)
+	assert.Equal(t, strings.Repeat("a", maxResponseBodyPrefixLen),
+		httpResponseBodyPrefix(largebuf.NewLargeBufferFrom([]byte(resp)), req))
+
+	assert.Empty(t, httpResponseBodyPrefix(largebuf.NewLargeBufferFrom([]byte("not HTTP")), req))
+}
+
+func TestHTTPEventBuffersToSpan_ErrorBody(t *testing.T) {
+	event := &BPFHTTPInfo{Type: uint8(request.EventTypeHTTP)}
+	spanFor := func(payload config.PayloadExtraction) request.Span {
+		reqBuf := largebuf.NewLargeBufferFrom([]byte("GET /users HTTP/1.1\r\nHost: example.com\r\n\r\n"))
+		respBuf := largebuf.NewLargeBufferFrom([]byte(
+			"HTTP/1.1 500 Internal Server Error\r\nContent-Length: 13\r\n\r\nsecret token!"))
+		parseCtx := NewEBPFParseContext(&config.EBPFTracer{PayloadExtraction: payload}, nil, nil)
+		return httpEventBuffersToSpan(parseCtx, event, reqBuf, respBuf, true)
+	}
+
+	// the response bodies are not captured unless explicitly enabled
+	span := spanFor(config.PayloadExtraction{HTTP: config.HTTPConfig{GraphQL: config.GraphQLConfig{Enabled: true}}})
+	assert.Equal(t, 500, span.Status)
+	assert.Empty(t, span.ResponseBodyPrefix)
+
+	span = spanFor(config.PayloadExtraction{HTTP: config.HTTPConfig{ErrorBody: config.ErrorBodyConfig{Enabled: true}}})
+	assert.Equal(t, 500, span.Status)
+	assert.Equal(t, "secret token!", span.ResponseBodyPrefix)
+}
+
 func TestHostInfo(t *testing.T) {
 	event := BPFHTTPInfo{
 		ConnInfo: BpfConnectionInfoT{
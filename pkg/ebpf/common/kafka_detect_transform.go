@@ -32,6 +32,10 @@ type KafkaInfo struct {
 	Topic         string
 	ClientID      string
 	PartitionInfo *PartitionInfo
+	// ConsumerGroup is only known for consumers that already committed their offsets
+	ConsumerGroup string
+	// Records of the produce request, or the fetch response
+	Records *kafkaparser.RecordsInfo
 }
 
 func (k Operation) String() string {
@@ -47,12 +51,12 @@ func (k Operation) String() string {
 
 // ProcessPossibleKafkaEvent processes a TCP packet and returns error if the packet is not a valid Kafka request.
 // Otherwise, return kafka.Info with the processed data.
-func ProcessPossibleKafkaEvent(event *TCPRequestInfo, pkt *largebuf.LargeBuffer, rpkt *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string]) (*KafkaInfo, bool, error) {
-	k, ok, err := ProcessKafkaEvent(pkt, rpkt, kafkaTopicUUIDToName)
+func ProcessPossibleKafkaEvent(event *TCPRequestInfo, pkt *largebuf.LargeBuffer, rpkt *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string], kafkaClientIDToGroup *simplelru.LRU[string, string]) (*KafkaInfo, bool, error) {
+	k, ok, err := ProcessKafkaEvent(pkt, rpkt, kafkaTopicUUIDToName, kafkaClientIDToGroup)
 	if err != nil {
 		// If we are getting the information in the response buffer, the event
 		// must be reversed and that's how we captured it.
-		k, ok, err = ProcessKafkaEvent(rpkt, pkt, kafkaTopicUUIDToName)
+		k, ok, err = ProcessKafkaEvent(rpkt, pkt, kafkaTopicUUIDToName, kafkaClientIDToGroup)
 		if err == nil {
 			reverseTCPEvent(event)
 		}
@@ -60,7 +64,7 @@ func ProcessPossibleKafkaEvent(event *TCPRequestInfo, pkt *largebuf.LargeBuffer,
 	return k, ok, err
 }
 
-func ProcessKafkaEvent(pkt *largebuf.LargeBuffer, rpkt *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string]) (*KafkaInfo, bool, error) {
+func ProcessKafkaEvent(pkt *largebuf.LargeBuffer, rpkt *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string], kafkaClientIDToGroup *simplelru.LRU[string, string]) (*KafkaInfo, bool, error) {
 	hdr, err := kafkaparser.NewKafkaRequestHeader(pkt)
 	if err != nil {
 		return nil, true, err
@@ -69,9 +73,11 @@ func ProcessKafkaEvent(pkt *largebuf.LargeBuffer, rpkt *largebuf.LargeBuffer, ka
 	case kafkaparser.APIKeyProduce:
 		return processProduceRequest(hdr)
 	case kafkaparser.APIKeyFetch:
-		return processFetchRequest(hdr, kafkaTopicUUIDToName)
+		return processFetchRequest(hdr, rpkt, kafkaTopicUUIDToName, kafkaClientIDToGroup)
 	case kafkaparser.APIKeyMetadata:
 		return processMetadataResponse(rpkt, hdr, kafkaTopicUUIDToName)
+	case kafkaparser.APIKeyOffsetCommit:
+		return processOffsetCommitRequest(hdr, kafkaClientIDToGroup)
 	default:
 		return nil, true, errors.New("unsupported Kafka API key")
 	}
@@ -93,16 +99,32 @@ func processProduceRequest(hdr kafkaparser.KafkaRequestHeader) (*KafkaInfo, bool
 			Partition: *produceReq.Topics[0].Partition,
 		}
 	}
+	// the span only reports the first topic, but the records of all the topics are accounted
+	var records *kafkaparser.RecordsInfo
+	for _, topic := range produceReq.Topics {
+		if topic.Records != nil {
+			if records == nil {
+				records = &kafkaparser.RecordsInfo{}
+			}
+			records.Add(topic.Records)
+		}
+	}
 	return &KafkaInfo{
 		ClientID:  hdr.ClientID(),
 		Operation: Produce,
 		// TODO: handle multiple topics
 		Topic:         produceReq.Topics[0].Name,
 		PartitionInfo: partitionInfo,
+		Records:       records,
 	}, false, nil
 }
 
-func processFetchRequest(hdr kafkaparser.KafkaRequestHeader, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string]) (*KafkaInfo, bool, error) {
+func processFetchRequest(
+	hdr kafkaparser.KafkaRequestHeader,
+	rpkt *largebuf.LargeBuffer,
+	kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string],
+	kafkaClientIDToGroup *simplelru.LRU[string, string],
+) (*KafkaInfo, bool, error) {
 	r, err := hdr.NewBodyReader()
 	if err != nil {
 		return nil, true, err
@@ -129,15 +151,61 @@ func processFetchRequest(hdr kafkaparser.KafkaRequestHeader, kafkaTopicUUIDToNam
 			Offset:    firstTopic.Partition.FetchOffset,
 		}
 	}
+	var consumerGroup string
+	if kafkaClientIDToGroup != nil {
+		consumerGroup, _ = kafkaClientIDToGroup.Get(hdr.ClientID())
+	}
 	return &KafkaInfo{
 		ClientID:  hdr.ClientID(),
 		Operation: Fetch,
 		// TODO: handle multiple topics
 		Topic:         topicName,
 		PartitionInfo: partitionInfo,
+		ConsumerGroup: consumerGroup,
+		Records:       fetchedRecords(rpkt, hdr),
 	}, false, nil
 }
 
+// fetchedRecords accounts the records of all the topics and partitions in the fetch
+// response, or returns nil if the response can't be parsed.
+func fetchedRecords(rpkt *largebuf.LargeBuffer, hdr kafkaparser.KafkaRequestHeader) *kafkaparser.RecordsInfo {
+	if rpkt == nil {
+		return nil
+	}
+	r := rpkt.NewReader()
+	if _, err := kafkaparser.ParseKafkaResponseHeader(&r, hdr); err != nil {
+		return nil
+	}
+	fetchResponse, err := kafkaparser.ParseFetchResponse(&r, hdr)
+	if err != nil {
+		return nil
+	}
+	records := &kafkaparser.RecordsInfo{}
+	for _, topic := range fetchResponse.Topics {
+		for _, partition := range topic.Partitions {
+			records.Add(partition.Records)
+		}
+	}
+	return records
+}
+
+// processOffsetCommitRequest does not produce any span, but remembers the consumer group of each
+// client ID, so it can be later added to the fetch requests of the same client.
+func processOffsetCommitRequest(hdr kafkaparser.KafkaRequestHeader, kafkaClientIDToGroup *simplelru.LRU[string, string]) (*KafkaInfo, bool, error) {
+	r, err := hdr.NewBodyReader()
+	if err != nil {
+		return nil, true, err
+	}
+	offsetCommitReq, err := kafkaparser.ParseOffsetCommitRequest(&r, hdr)
+	if err != nil {
+		return nil, true, err
+	}
+	if kafkaClientIDToGroup != nil && hdr.ClientID() != "" {
+		kafkaClientIDToGroup.Add(hdr.ClientID(), offsetCommitReq.GroupID)
+	}
+	return nil, true, nil
+}
+
 func processMetadataResponse(rpkt *largebuf.LargeBuffer, hdr kafkaparser.KafkaRequestHeader, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string]) (*KafkaInfo, bool, error) {
 	if rpkt == nil {
 		return nil, true, errors.New("no response buffer for metadata request")
@@ -167,7 +235,7 @@ func ProcessKafkaRequest(pkt *largebuf.LargeBuffer, kafkaTopicUUIDToName *simple
 	case kafkaparser.APIKeyProduce:
 		return processProduceRequest(hdr)
 	case kafkaparser.APIKeyFetch:
-		return processFetchRequest(hdr, kafkaTopicUUIDToName)
+		return processFetchRequest(hdr, nil, kafkaTopicUUIDToName, nil)
 	default:
 		return nil, true, errors.New("unsupported Kafka API key")
 	}
@@ -190,11 +258,25 @@ func TCPToKafkaToSpan(trace *TCPRequestInfo, data *KafkaInfo) request.Span {
 
 	var messagingInfo *request.MessagingInfo
 
-	if data.PartitionInfo != nil {
+	if data.PartitionInfo != nil || data.Records != nil || data.ConsumerGroup != "" {
 		messagingInfo = &request.MessagingInfo{
-			Partition: data.PartitionInfo.Partition,
-			Offset:    data.PartitionInfo.Offset,
+			Partition:     request.UnknownPartition,
+			ConsumerGroup: data.ConsumerGroup,
+		}
+		if data.PartitionInfo != nil {
+			messagingInfo.Partition = data.PartitionInfo.Partition
+			messagingInfo.Offset = data.PartitionInfo.Offset
 		}
+		if data.Records != nil {
+			messagingInfo.Messages = data.Records.Messages
+			messagingInfo.Bytes = data.Records.Bytes
+		}
+	}
+
+	// the consumers are linked to the producers of the fetched records
+	var links []request.SpanLink
+	if data.Operation == Fetch && data.Records != nil {
+		links = messagingLinks(data.Records.Traceparents, trace.Tp.TraceId)
 	}
 
 	return request.Span{
@@ -221,5 +303,6 @@ func TCPToKafkaToSpan(trace *TCPRequestInfo, data *KafkaInfo) request.Span {
 			Namespace: trace.Pid.Ns,
 		},
 		MessagingInfo: messagingInfo,
+		Links:         links,
 	}
 }
@@ -4,12 +4,14 @@
 package ebpfcommon
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/hashicorp/golang-lru/v2/simplelru"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
 	"go.opentelemetry.io/obi/pkg/internal/ebpf/kafkaparser"
 	"go.opentelemetry.io/obi/pkg/internal/largebuf"
 )
@@ -132,6 +134,7 @@ func TestProcessKafkaRequest(t *testing.T) {
 				PartitionInfo: &PartitionInfo{
 					Partition: 0,
 				},
+				Records: &kafkaparser.RecordsInfo{Bytes: 72, Messages: 1},
 			},
 		},
 		{
@@ -144,6 +147,7 @@ func TestProcessKafkaRequest(t *testing.T) {
 				PartitionInfo: &PartitionInfo{
 					Partition: 0,
 				},
+				Records: &kafkaparser.RecordsInfo{Bytes: 77},
 			},
 		},
 		{
@@ -171,14 +175,15 @@ func TestProcessKafkaRequest(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cache, _ := simplelru.NewLRU[kafkaparser.UUID, string](1000, nil)
+			groups, _ := simplelru.NewLRU[string, string](1000, nil)
 			if len(tt.preRequests) > 0 {
 				for _, preInput := range tt.preRequests {
-					_, ignore, err := ProcessKafkaEvent(largebuf.NewLargeBufferFrom(preInput.request), largebuf.NewLargeBufferFrom(preInput.response), cache)
+					_, ignore, err := ProcessKafkaEvent(largebuf.NewLargeBufferFrom(preInput.request), largebuf.NewLargeBufferFrom(preInput.response), cache, groups)
 					require.NoError(t, err)
 					require.True(t, ignore)
 				}
 			}
-			res, _, err := ProcessKafkaEvent(largebuf.NewLargeBufferFrom(tt.request), nil, cache)
+			res, _, err := ProcessKafkaEvent(largebuf.NewLargeBufferFrom(tt.request), nil, cache, groups)
 			if tt.err {
 				assert.Error(t, err)
 				return
@@ -187,3 +192,88 @@ func TestProcessKafkaRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessKafkaEvent_ConsumedMessages(t *testing.T) {
+	be16 := func(b []byte, v int16) []byte { return binary.BigEndian.AppendUint16(b, uint16(v)) }
+	be32 := func(b []byte, v int32) []byte { return binary.BigEndian.AppendUint32(b, uint32(v)) }
+	be64 := func(b []byte, v int64) []byte { return binary.BigEndian.AppendUint64(b, uint64(v)) }
+	str := func(b []byte, s string) []byte { return append(be16(b, int16(len(s))), s...) }
+	// prepends the message size
+	message := func(b []byte) []byte { return append(be32(nil, int32(len(b))), b...) }
+	requestHeader := func(apiKey kafkaparser.KafkaAPIKey, version int16) []byte {
+		return str(be32(be16(be16(nil, int16(apiKey)), version), 33), "sarama")
+	}
+
+	topics, _ := simplelru.NewLRU[kafkaparser.UUID, string](10, nil)
+	groups, _ := simplelru.NewLRU[string, string](10, nil)
+
+	// the consumer group is learned from the offset commits
+	offsetCommit := message(str(requestHeader(kafkaparser.APIKeyOffsetCommit, 2), "billing"))
+	_, ignore, err := ProcessKafkaEvent(largebuf.NewLargeBufferFrom(offsetCommit), nil, topics, groups)
+	require.NoError(t, err)
+	require.True(t, ignore)
+
+	fetch := requestHeader(kafkaparser.APIKeyFetch, 4)
+	fetch = be32(be32(be32(be32(fetch, -1), 500), 1), 1024) // replica_id, max_wait_ms, min_bytes, max_bytes
+	fetch = append(fetch, 0)                                // isolation_level
+	fetch = str(be32(fetch, 1), "orders")                   // topics
+	fetch = be32(be64(be32(be32(fetch, 1), 2), 19), 1024)   // partitions, partition, fetch_offset, partition_max_bytes
+
+	// record batch with 4 records
+	batch := make([]byte, 80)
+	binary.BigEndian.PutUint32(batch[8:], uint32(len(batch)-12))
+	batch[16] = 2
+	binary.BigEndian.PutUint32(batch[57:], 4)
+
+	response := be32(nil, 33)                                                // correlation_id
+	response = str(be32(be32(response, 0), 1), "orders")                     // throttle_time_ms, responses
+	response = be32(be32(response, 1), 2)                                    // partitions, partition_index
+	response = be64(be64(be16(response, 0), 30), 30)                         // error_code, high_watermark, last_stable_offset
+	response = append(be32(be32(response, -1), int32(len(batch))), batch...) // aborted_transactions, records
+
+	info, ignore, err := ProcessKafkaEvent(largebuf.NewLargeBufferFrom(message(fetch)),
+		largebuf.NewLargeBufferFrom(message(response)), topics, groups)
+	require.NoError(t, err)
+	require.False(t, ignore)
+	assert.Equal(t, &KafkaInfo{
+		Operation:     Fetch,
+		Topic:         "orders",
+		ClientID:      "sarama",
+		PartitionInfo: &PartitionInfo{Partition: 2, Offset: 19},
+		ConsumerGroup: "billing",
+		Records:       &kafkaparser.RecordsInfo{Bytes: 80, Messages: 4},
+	}, info)
+
+	span := TCPToKafkaToSpan(&TCPRequestInfo{}, info)
+	assert.Equal(t, &request.MessagingInfo{
+		Offset:        19,
+		Partition:     2,
+		ConsumerGroup: "billing",
+		Messages:      4,
+		Bytes:         80,
+	}, span.MessagingInfo)
+}
+
+func TestTCPToKafkaToSpan_Links(t *testing.T) {
+	const (
+		tp1    = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+		tp2    = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+		tpSelf = "00-eae56fbbec9505c102e8aabfc6b5c481-89cbc1f60aab3b04-01"
+	)
+	self, ok := request.SpanLinkFromTraceparent(tpSelf)
+	require.True(t, ok)
+	link1, _ := request.SpanLinkFromTraceparent(tp1)
+	link2, _ := request.SpanLinkFromTraceparent(tp2)
+
+	trace := &TCPRequestInfo{}
+	trace.Tp.TraceId = self.TraceID
+	records := &kafkaparser.RecordsInfo{Messages: 5, Traceparents: []string{tp1, "invalid", tp1, tpSelf, tp2}}
+
+	// consumers are linked to each producer trace, ignoring duplicates and its own trace
+	span := TCPToKafkaToSpan(trace, &KafkaInfo{Operation: Fetch, Topic: "orders", Records: records})
+	assert.Equal(t, []request.SpanLink{link1, link2}, span.Links)
+
+	// producers are not linked to the context they propagate
+	span = TCPToKafkaToSpan(trace, &KafkaInfo{Operation: Produce, Topic: "orders", Records: records})
+	assert.Empty(t, span.Links)
+}
@@ -30,6 +30,13 @@ type MQTTInfo struct {
 
 	// PacketID is the packet identifier for QoS > 0.
 	PacketID uint16
+
+	// PayloadLen is the size of the PUBLISH message. For MQTT 5.0, it
+	// also includes the size of the message properties.
+	PayloadLen int
+
+	// Traceparents contains the traceparent user properties of MQTT 5.0 PUBLISH packets.
+	Traceparents []string
 }
 
 // packetTypeToMethod converts an MQTT packet type to an OpenTelemetry messaging operation name.
@@ -104,7 +111,7 @@ func processMQTTPacket(pkt []byte, startOffset int, packet mqttparser.MQTTContro
 
 	switch packet.FixedHeader.PacketType {
 	case mqttparser.PacketTypePUBLISH:
-		return processPublishPacket(pkt, varHeaderOffset, packet.FixedHeader.Flags)
+		return processPublishPacket(pkt, varHeaderOffset, packet.FixedHeader.Flags, packet.FixedHeader.RemainingLength)
 	case mqttparser.PacketTypeSUBSCRIBE:
 		return processSubscribePacket(pkt, varHeaderOffset, packet.FixedHeader.RemainingLength)
 	case mqttparser.PacketTypeCONNECT:
@@ -128,17 +135,26 @@ func processMQTTPacket(pkt []byte, startOffset int, packet mqttparser.MQTTContro
 	}
 }
 
-func processPublishPacket(pkt []byte, offset int, flags uint8) (*MQTTInfo, bool, error) {
-	publish, _, err := mqttparser.ParsePublishPacket(pkt, offset, flags)
+func processPublishPacket(pkt []byte, offset int, flags uint8, remainingLength int) (*MQTTInfo, bool, error) {
+	publish, payloadOffset, err := mqttparser.ParsePublishPacket(pkt, offset, flags)
 	if err != nil {
 		return nil, true, err
 	}
 
+	var traceparents []string
+	for _, up := range publish.UserProperties {
+		if up.Key == "traceparent" {
+			traceparents = append(traceparents, up.Value)
+		}
+	}
+
 	return &MQTTInfo{
-		PacketType: mqttparser.PacketTypePUBLISH,
-		Topic:      publish.TopicName,
-		QoS:        publish.QoS,
-		PacketID:   publish.PacketID,
+		PacketType:   mqttparser.PacketTypePUBLISH,
+		Topic:        publish.TopicName,
+		QoS:          publish.QoS,
+		PacketID:     publish.PacketID,
+		PayloadLen:   max(remainingLength-(payloadOffset-offset), 0),
+		Traceparents: traceparents,
 	}, false, nil
 }
 
@@ -202,6 +218,15 @@ func TCPToMQTTToSpan(trace *TCPRequestInfo, data *MQTTInfo) request.Span {
 		reqType = request.EventTypeMQTTServer
 	}
 
+	var messagingInfo *request.MessagingInfo
+	if data.PacketType == mqttparser.PacketTypePUBLISH {
+		messagingInfo = &request.MessagingInfo{
+			Partition: request.UnknownPartition,
+			Messages:  1,
+			Bytes:     data.PayloadLen,
+		}
+	}
+
 	return request.Span{
 		Type:          reqType,
 		Method:        packetTypeToMethod(data.PacketType),
@@ -225,5 +250,8 @@ func TCPToMQTTToSpan(trace *TCPRequestInfo, data *MQTTInfo) request.Span {
 			UserPID:   app.PID(trace.Pid.UserPid),
 			Namespace: trace.Pid.Ns,
 		},
+		MessagingInfo: messagingInfo,
+		// PUBLISH packets received by the subscribers are linked to their publishers
+		Links: messagingLinks(data.Traceparents, trace.Tp.TraceId),
 	}
 }
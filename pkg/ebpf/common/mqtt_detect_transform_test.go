@@ -362,6 +362,32 @@ func TestTCPToMQTTToSpan(t *testing.T) {
 	assert.EqualValues(t, 1234, span.Pid.UserPID)
 }
 
+func TestTCPToMQTTToSpan_Links(t *testing.T) {
+	const tp = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+	str := func(s string) []byte { return append([]byte{byte(len(s) >> 8), byte(len(s))}, s...) }
+	props := append(append([]byte{0x26}, str("traceparent")...), str(tp)...)
+	packet := append(str("sensors/temperature"), 0x00, 0x2A, byte(len(props)))
+	packet = append(packet, props...)
+	packet = append(packet, "25.5"...)
+	packet = append([]byte{0x32, byte(len(packet))}, packet...) // PUBLISH, QoS=1
+
+	info, ignore, err := ProcessMQTTEvent(packet)
+	require.NoError(t, err)
+	require.False(t, ignore)
+	assert.Equal(t, []string{tp}, info.Traceparents)
+
+	link, ok := request.SpanLinkFromTraceparent(tp)
+	require.True(t, ok)
+	span := TCPToMQTTToSpan(&TCPRequestInfo{}, info)
+	assert.Equal(t, []request.SpanLink{link}, span.Links)
+
+	// the span is not linked to its own trace
+	trace := &TCPRequestInfo{}
+	trace.Tp.TraceId = link.TraceID
+	span = TCPToMQTTToSpan(trace, info)
+	assert.Empty(t, span.Links)
+}
+
 // Test with real-world MQTT packet captures
 func TestProcessMQTTEvent_RealWorldPackets(t *testing.T) {
 	tests := []struct {
@@ -378,13 +404,14 @@ func TestProcessMQTTEvent_RealWorldPackets(t *testing.T) {
 				0x18,       // Remaining length: 24
 				0x00, 0x10, // Topic length: 16
 				'h', 'o', 'm', 'e', '/', 't', 'e', 'm', 'p', 'e', 'r', 'a', 't', 'u', 'r', 'e',
-				// Payload: "22.5" (not parsed)
+				// Payload: "22.5" (only its size is parsed)
 				'2', '2', '.', '5', 0x00, 0x00,
 			},
 			expected: &MQTTInfo{
 				PacketType: mqttparser.PacketTypePUBLISH,
 				Topic:      "home/temperature",
 				QoS:        mqttparser.QoSAtMostOnce,
+				PayloadLen: 6,
 			},
 		},
 		{
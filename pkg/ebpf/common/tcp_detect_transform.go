@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	"github.com/hashicorp/golang-lru/v2/simplelru"
 
@@ -80,7 +81,7 @@ func ReadTCPRequestIntoSpan(parseCtx *EBPFParseContext, cfg *config.EBPFTracer,
 func dispatchKernelAssignedProtocol(parseCtx *EBPFParseContext, event *TCPRequestInfo, requestBuffer, responseBuffer *largebuf.LargeBuffer) (request.Span, bool, bool, error) {
 	switch event.ProtocolType {
 	case ProtocolTypeKafka:
-		return dispatchKafka(event, requestBuffer, responseBuffer, parseCtx.kafkaTopicUUIDToName)
+		return dispatchKafka(event, requestBuffer, responseBuffer, parseCtx.kafkaTopicUUIDToName, parseCtx.kafkaClientIDToGroup)
 	case ProtocolTypeMQTT:
 		return dispatchMQTT(event, requestBuffer, responseBuffer)
 	case ProtocolTypeMySQL:
@@ -92,8 +93,8 @@ func dispatchKernelAssignedProtocol(parseCtx *EBPFParseContext, event *TCPReques
 	return request.Span{}, false, false, nil
 }
 
-func dispatchKafka(event *TCPRequestInfo, requestBuffer, responseBuffer *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string]) (request.Span, bool, bool, error) {
-	k, ignore, err := ProcessPossibleKafkaEvent(event, requestBuffer, responseBuffer, kafkaTopicUUIDToName)
+func dispatchKafka(event *TCPRequestInfo, requestBuffer, responseBuffer *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string], kafkaClientIDToGroup *simplelru.LRU[string, string]) (request.Span, bool, bool, error) {
+	k, ignore, err := ProcessPossibleKafkaEvent(event, requestBuffer, responseBuffer, kafkaTopicUUIDToName, kafkaClientIDToGroup)
 
 	if ignore && err == nil {
 		return request.Span{}, true, true, nil // parsed kafka event, but we don't want to create a span for it
@@ -259,7 +260,7 @@ func detectHeuristicProtocol(parseCtx *EBPFParseContext, event *TCPRequestInfo,
 	}
 
 	// Kafka can arrive here for packets the kernel couldn't classify (e.g. OBI attached mid-connection).
-	if span, ignore, matched, err := matchKafkaFallback(event, requestBuffer, responseBuffer, parseCtx.kafkaTopicUUIDToName); matched {
+	if span, ignore, matched, err := matchKafkaFallback(event, requestBuffer, responseBuffer, parseCtx.kafkaTopicUUIDToName, parseCtx.kafkaClientIDToGroup); matched {
 		return span, ignore, matched, err
 	}
 
@@ -354,8 +355,8 @@ func matchMQTT(event *TCPRequestInfo, requestBuffer, responseBuffer *largebuf.La
 
 // matchKafkaFallback handles Kafka for unclassified packets (e.g. when the kernel missed the
 // connection start). Unlike dispatchKafka, errors here mean "not Kafka" — no error is surfaced.
-func matchKafkaFallback(event *TCPRequestInfo, requestBuffer, responseBuffer *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string]) (request.Span, bool, bool, error) { //nolint:unparam
-	k, ignore, err := ProcessPossibleKafkaEvent(event, requestBuffer, responseBuffer, kafkaTopicUUIDToName)
+func matchKafkaFallback(event *TCPRequestInfo, requestBuffer, responseBuffer *largebuf.LargeBuffer, kafkaTopicUUIDToName *simplelru.LRU[kafkaparser.UUID, string], kafkaClientIDToGroup *simplelru.LRU[string, string]) (request.Span, bool, bool, error) { //nolint:unparam
+	k, ignore, err := ProcessPossibleKafkaEvent(event, requestBuffer, responseBuffer, kafkaTopicUUIDToName, kafkaClientIDToGroup)
 
 	if ignore && err == nil {
 		return request.Span{}, true, true, nil // parsed kafka event, but we don't want to create a span for it
@@ -407,3 +408,20 @@ func reverseTCPEvent(trace *TCPRequestInfo) {
 	trace.ConnInfo.D_addr = addr
 	trace.ConnInfo.D_port = port
 }
+
+// messagingLinks returns the span links to the producers of the messages, from the
+// traceparent values found in their headers. Invalid or duplicate values are ignored,
+// as well as the links to the trace of the span itself.
+func messagingLinks(traceparents []string, self [16]uint8) []request.SpanLink {
+	var links []request.SpanLink
+	for _, tp := range traceparents {
+		link, ok := request.SpanLinkFromTraceparent(tp)
+		if !ok || link.TraceID == self || slices.ContainsFunc(links, func(l request.SpanLink) bool {
+			return l.TraceID == link.TraceID && l.SpanID == link.SpanID
+		}) {
+			continue
+		}
+		links = append(links, link)
+	}
+	return links
+}
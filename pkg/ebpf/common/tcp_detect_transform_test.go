@@ -258,6 +258,11 @@ func TestTCPReqMQTTParsing(t *testing.T) {
 	assert.Equal(t, request.MessagingPublish, s.Method)
 	assert.Equal(t, "test/topic", s.Path)
 	assert.Equal(t, request.EventTypeMQTTClient, s.Type)
+	// a message without payload is still accounted
+	require.NotNil(t, s.MessagingInfo)
+	assert.Equal(t, 1, s.MessagingInfo.Messages)
+	assert.Equal(t, 0, s.MessagingInfo.Bytes)
+	assert.Equal(t, request.UnknownPartition, s.MessagingInfo.Partition)
 }
 
 func TestTCPReqMQTTHeuristicFailure(t *testing.T) {
@@ -36,6 +36,7 @@ const (
 	GroupNetGeoIP
 	GroupStats
 	GroupStatsKube
+	GroupBaggage
 )
 
 func (e *AttrGroups) Has(groups AttrGroups) bool {
@@ -297,9 +298,17 @@ func getDefinitions(
 		extraGroupAttributes[GroupGRPCClientInfo],
 	)
 
+	// the W3C baggage entries that are allowed as metric attributes. Unlike other extra
+	// attributes, they are not reported unless they are explicitly selected, to protect
+	// the metrics cardinality
+	httpBaggage := AttrReportGroup{Attributes: map[attr.Name]Default{}}
+	for _, name := range extraGroupAttributes[GroupBaggage] {
+		httpBaggage.Attributes[name] = false
+	}
+
 	httpCommon := NewAttrReportGroup(
 		false,
-		[]*AttrReportGroup{&httpRoutes},
+		[]*AttrReportGroup{&httpRoutes, &httpBaggage},
 		map[attr.Name]Default{
 			attr.HTTPRequestMethod:      true,
 			attr.HTTPURLScheme:          true,
@@ -362,6 +371,22 @@ func getDefinitions(
 				attr.RPCGRPCStatusCode: true,
 			},
 		},
+		// active requests don't have response status codes, as they are
+		// accounted since the request start, before the response is known
+		HTTPServerActiveRequests.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes, &httpRoutes, &serverInfo},
+			Attributes: map[attr.Name]Default{
+				attr.HTTPRequestMethod: true,
+				attr.HTTPURLScheme:     true,
+			},
+		},
+		HTTPClientActiveRequests.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes, &httpClientInfo},
+			Attributes: map[attr.Name]Default{
+				attr.HTTPRequestMethod: true,
+				attr.HTTPURLScheme:     true,
+			},
+		},
 		DBClientDuration.Section: {
 			SubGroups: []*AttrReportGroup{&appAttributes},
 			Attributes: map[attr.Name]Default{
@@ -371,12 +396,43 @@ func getDefinitions(
 				attr.ErrorType:    true,
 			},
 		},
+		DBServerDuration.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.DBOperation:      true,
+				attr.DBSystemName:     true,
+				attr.DBCollectionName: true,
+				attr.ErrorType:        true,
+			},
+		},
 		MessagingPublishDuration.Section: {
 			SubGroups: []*AttrReportGroup{&messagingAttributes},
 		},
 		MessagingProcessDuration.Section: {
 			SubGroups: []*AttrReportGroup{&messagingAttributes},
 		},
+		MessagingSentMessages.Section: {
+			SubGroups:  []*AttrReportGroup{&messagingAttributes},
+			Attributes: map[attr.Name]Default{attr.MessagingPartition: true},
+		},
+		MessagingSentBytes.Section: {
+			SubGroups:  []*AttrReportGroup{&messagingAttributes},
+			Attributes: map[attr.Name]Default{attr.MessagingPartition: true},
+		},
+		MessagingConsumedMessages.Section: {
+			SubGroups: []*AttrReportGroup{&messagingAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.MessagingPartition:     true,
+				attr.MessagingConsumerGroup: true,
+			},
+		},
+		MessagingConsumedBytes.Section: {
+			SubGroups: []*AttrReportGroup{&messagingAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.MessagingPartition:     true,
+				attr.MessagingConsumerGroup: true,
+			},
+		},
 		Traces.Section: {
 			Attributes: map[attr.Name]Default{
 				attr.DBQueryText:       false,
@@ -412,6 +468,22 @@ func getDefinitions(
 				attr.CudaMemcpyKind: true,
 			},
 		},
+		NetworkConnectionFailures.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.ServerAddr:         true,
+				attr.ServerPort:         true,
+				attr.NetworkPeerAddress: false,
+				attr.ErrorType:          true,
+			},
+		},
+		DNSLookupFailures.Section: {
+			SubGroups: []*AttrReportGroup{&appAttributes},
+			Attributes: map[attr.Name]Default{
+				attr.DNSQuestionName: true,
+				attr.ErrorType:       true,
+			},
+		},
 		DNSLookupDuration.Section: {
 			SubGroups: []*AttrReportGroup{&appAttributes},
 			Attributes: map[attr.Name]Default{
@@ -85,6 +85,9 @@ func parseExtraAttrGroup(group string) (AttrGroups, error) {
 type SelectorConfig struct {
 	SelectionCfg            Selection
 	ExtraGroupAttributesCfg map[string][]attr.Name
+	// BaggageKeysCfg are the keys of the W3C baggage entries that can be selected
+	// as HTTP metric attributes
+	BaggageKeysCfg []string
 }
 
 // AttrSelector returns, for each metric, the attributes that have to be reported
@@ -112,6 +115,9 @@ func NewCustomAttrSelector(
 	extraDefinitionsProvider func(groups AttrGroups, extraGroupAttributes GroupAttributes) map[Section]AttrReportGroup,
 ) (*AttrSelector, error) {
 	extraGroupAttributes := NewGroupAttributes(cfg.ExtraGroupAttributesCfg)
+	for _, key := range cfg.BaggageKeysCfg {
+		extraGroupAttributes[GroupBaggage] = append(extraGroupAttributes[GroupBaggage], attr.Baggage(key))
+	}
 
 	definitions := getDefinitions(groups, extraGroupAttributes)
 
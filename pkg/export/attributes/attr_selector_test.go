@@ -232,6 +232,25 @@ func TestExtraGroupAttributes(t *testing.T) {
 	}, p.For(HTTPServerRequestSize))
 }
 
+func TestBaggageAttributes(t *testing.T) {
+	cfg := &SelectorConfig{BaggageKeysCfg: []string{"tenant.id", "deployment.ring"}}
+	p, err := NewAttrSelector(0, cfg)
+	require.NoError(t, err)
+	// baggage attributes are not reported by default
+	assert.NotContains(t, p.For(HTTPServerDuration), attr.Name("baggage.tenant.id"))
+
+	cfg.SelectionCfg = Selection{
+		"http.server.request.duration": InclusionLists{Include: []string{"baggage.*"}},
+		"rpc.server.duration":          InclusionLists{Include: []string{"baggage.*"}},
+	}
+	cfg.SelectionCfg.Normalize()
+	p, err = NewAttrSelector(0, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []attr.Name{"baggage.deployment.ring", "baggage.tenant.id"}, p.For(HTTPServerDuration))
+	// only HTTP metrics accept baggage attributes
+	assert.NotContains(t, p.For(RPCServerDuration), attr.Name("baggage.tenant.id"))
+}
+
 func TestTraces(t *testing.T) {
 	p, err := NewAttrSelector(GroupTraces, &SelectorConfig{
 		SelectionCfg: Selection{
@@ -74,11 +74,26 @@ var (
 		Prom:    "rpc_client_duration_seconds",
 		OTEL:    "rpc.client.duration",
 	}
+	HTTPServerActiveRequests = Name{
+		Section: "http.server.active_requests",
+		Prom:    "http_server_active_requests",
+		OTEL:    "http.server.active_requests",
+	}
+	HTTPClientActiveRequests = Name{
+		Section: "http.client.active_requests",
+		Prom:    "http_client_active_requests",
+		OTEL:    "http.client.active_requests",
+	}
 	DBClientDuration = Name{
 		Section: "db.client.operation.duration",
 		Prom:    "db_client_operation_duration_seconds",
 		OTEL:    "db.client.operation.duration",
 	}
+	DBServerDuration = Name{
+		Section: "db.server.operation.duration",
+		Prom:    "db_server_operation_duration_seconds",
+		OTEL:    "db.server.operation.duration",
+	}
 	MessagingPublishDuration = Name{
 		Section: "messaging.client.operation.duration",
 		Prom:    "messaging_client_operation_duration_seconds",
@@ -89,6 +104,26 @@ var (
 		Prom:    "messaging_process_duration_seconds",
 		OTEL:    "messaging.process.duration",
 	}
+	MessagingSentMessages = Name{
+		Section: "messaging.client.sent.messages",
+		Prom:    "messaging_client_sent_messages_total",
+		OTEL:    "messaging.client.sent.messages",
+	}
+	MessagingConsumedMessages = Name{
+		Section: "messaging.client.consumed.messages",
+		Prom:    "messaging_client_consumed_messages_total",
+		OTEL:    "messaging.client.consumed.messages",
+	}
+	MessagingSentBytes = Name{
+		Section: "obi.messaging.client.sent.bytes",
+		Prom:    "obi_messaging_client_sent_bytes_total",
+		OTEL:    "obi.messaging.client.sent.bytes",
+	}
+	MessagingConsumedBytes = Name{
+		Section: "obi.messaging.client.consumed.bytes",
+		Prom:    "obi_messaging_client_consumed_bytes_total",
+		OTEL:    "obi.messaging.client.consumed.bytes",
+	}
 	GPUCudaKernelLaunchCalls = Name{
 		Section: "gpu.cuda.kernel.launch.calls",
 		Prom:    "gpu_cuda_kernel_launch_calls_total",
@@ -119,6 +154,16 @@ var (
 		Prom:    "gpu_cuda_memory_copies_bytes_total",
 		OTEL:    "gpu.cuda.memory.copies",
 	}
+	NetworkConnectionFailures = Name{
+		Section: "obi.network.connection.failures",
+		Prom:    "obi_network_connection_failures_total",
+		OTEL:    "obi.network.connection.failures",
+	}
+	DNSLookupFailures = Name{
+		Section: "obi.dns.lookup.failures",
+		Prom:    "obi_dns_lookup_failures_total",
+		OTEL:    "obi.dns.lookup.failures",
+	}
 	DNSLookupDuration = Name{
 		Section: "dns.lookup.duration",
 		Prom:    "dns_lookup_duration_seconds",
@@ -144,6 +189,51 @@ var (
 		Prom:    "gen_ai_client_operation_duration_seconds",
 		OTEL:    "gen_ai.client.operation.duration",
 	}
+	ProcessCPUTime = Name{
+		Section: "process.cpu.time",
+		Prom:    "process_cpu_time_seconds_total",
+		OTEL:    "process.cpu.time",
+	}
+	ProcessMemoryUsage = Name{
+		Section: "process.memory.usage",
+		Prom:    "process_memory_usage_bytes",
+		OTEL:    "process.memory.usage",
+	}
+	ProcessMemoryVirtual = Name{
+		Section: "process.memory.virtual",
+		Prom:    "process_memory_virtual_bytes",
+		OTEL:    "process.memory.virtual",
+	}
+	ProcessDiskIO = Name{
+		Section: "process.disk.io",
+		Prom:    "process_disk_io_bytes_total",
+		OTEL:    "process.disk.io",
+	}
+	ProcessOpenFileDescriptors = Name{
+		Section: "process.open_file_descriptor.count",
+		Prom:    "process_open_file_descriptor_count",
+		OTEL:    "process.open_file_descriptor.count",
+	}
+	ProcessThreads = Name{
+		Section: "process.thread.count",
+		Prom:    "process_thread_count",
+		OTEL:    "process.thread.count",
+	}
+	SLOEvents = Name{
+		Section: "obi.slo.events",
+		Prom:    "obi_slo_events_total",
+		OTEL:    "obi.slo.events",
+	}
+	SLOGoodEvents = Name{
+		Section: "obi.slo.good_events",
+		Prom:    "obi_slo_good_events_total",
+		OTEL:    "obi.slo.good_events",
+	}
+	SLOApdex = Name{
+		Section: "obi.slo.apdex",
+		Prom:    "obi_slo_apdex",
+		OTEL:    "obi.slo.apdex",
+	}
 )
 
 // normalizeMetric will facilitate the user-input in the attributes.enable section.
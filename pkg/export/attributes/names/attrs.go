@@ -37,6 +37,7 @@ const (
 	ClientAddr             = Name(semconv.ClientAddressKey)
 	ServerAddr             = Name(semconv.ServerAddressKey)
 	ServerPort             = Name(semconv.ServerPortKey)
+	NetworkPeerAddress     = Name(semconv.NetworkPeerAddressKey)
 	HTTPRequestBodySize    = Name(semconv.HTTPRequestBodySizeKey)
 	HTTPResponseBodySize   = Name(semconv.HTTPResponseBodySizeKey)
 	SpanKind               = Name("span.kind")
@@ -62,12 +63,16 @@ const (
 	MessagingMessageID     = Name(semconv.MessagingMessageIDKey)
 	MessagingSystem        = Name(semconv.MessagingSystemKey)
 	MessagingDestination   = Name(semconv.MessagingDestinationNameKey)
+	MessagingConsumerGroup = Name(semconv.MessagingConsumerGroupNameKey)
 	GraphQLDocument        = Name(semconv.GraphQLDocumentKey)
 	GraphQLOperationName   = Name(semconv.GraphQLOperationNameKey)
 	GraphQLOperationType   = Name(semconv.GraphQLOperationTypeKey)
 	DNSAnswers             = Name(semconv.DNSAnswersKey)
 	ErrorMessage           = Name(semconv.ErrorMessageKey)
 	TelemetrySDKLanguage   = Name(semconv.TelemetrySDKLanguageKey)
+	ProcessPID             = Name(semconv.ProcessPIDKey)
+	CPUMode                = Name(semconv.CPUModeKey)
+	DiskIODirection        = Name(semconv.DiskIODirectionKey)
 
 	K8sNamespaceName   = Name("k8s.namespace.name")
 	K8sPodName         = Name("k8s.pod.name")
@@ -92,6 +97,14 @@ const (
 	ContainerID   = Name(semconv.ContainerIDKey)
 )
 
+// BaggagePrefix is prepended to the keys of the W3C baggage entries that are reported as attributes
+const BaggagePrefix = "baggage."
+
+// Baggage returns the attribute name of the W3C baggage entry with the given key
+func Baggage(key string) Name {
+	return Name(BaggagePrefix + key)
+}
+
 // OBI-specific network attributes
 // obi.-prefixed attributes are a var instead of a constant to allow overriding the prefix
 // from components that vendor OBI as a library
@@ -194,6 +207,7 @@ const (
 
 	ServiceInstanceID = Name(semconv.ServiceInstanceIDKey)
 	SkipSpanMetrics   = Name("span.metrics.skip")
+	SLOName           = Name("slo.name")
 
 	VendorVersionSuffix  = Name(".version")
 	VendorRevisionSuffix = Name(".revision")
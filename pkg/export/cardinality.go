@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package export // import "go.opentelemetry.io/obi/pkg/export"
+
+// OverflowAttribute is the attribute of the series where the measurements are aggregated
+// once a metric exceeds its cardinality limit, as described by the OpenTelemetry specification:
+// https://opentelemetry.io/docs/specs/otel/metrics/sdk/#overflow-attribute
+const OverflowAttribute = "otel.metric.overflow"
+
+// CardinalityLimits defines the maximum number of distinct attribute sets (series) that
+// each metric can report. Once a metric reaches its limit, the measurements for new attribute sets
+// are aggregated into a single series with the otel.metric.overflow=true attribute.
+// The limit accounts for the overflow series.
+// The limits are applied to each metric separately: there is no global limit for the overall
+// number of series of all the metrics.
+// The scope of the limits depends on the exporter. The OpenTelemetry exporter reports the metrics
+// of each service from a different MeterProvider, so the limits apply to the series of each service.
+// The Prometheus exporter exposes the metrics of all the services together, so the limits apply to
+// the overall series of each metric.
+type CardinalityLimits struct {
+	// PerMetric limit, applied separately to each metric that isn't listed in the Metrics
+	// section. Zero means unlimited.
+	PerMetric int `yaml:"per_metric" env:"PER_METRIC" validate:"gte=0"`
+	// Metrics overrides the PerMetric limit for the metrics with the given names. For example:
+	// {"http.server.request.duration": 1000}. A zero value disables the limit for that metric.
+	Metrics map[string]int `yaml:"metrics" env:"METRICS"`
+}
+
+// For returns the cardinality limit of the metric with the provided name,
+// or zero if it is unlimited.
+func (c *CardinalityLimits) For(metric string) int {
+	if limit, ok := c.Metrics[metric]; ok {
+		return limit
+	}
+	return c.PerMetric
+}
+
+// Enabled returns whether any metric is limited
+func (c *CardinalityLimits) Enabled() bool {
+	if c.PerMetric > 0 {
+		return true
+	}
+	for _, limit := range c.Metrics {
+		if limit > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"testing"
+
+	"github.com/caarlos0/env/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCardinalityLimits(t *testing.T) {
+	limits := CardinalityLimits{}
+	assert.False(t, limits.Enabled())
+	assert.Zero(t, limits.For("foo"))
+
+	limits = CardinalityLimits{Metrics: map[string]int{"foo": 0}}
+	assert.False(t, limits.Enabled())
+
+	limits = CardinalityLimits{PerMetric: 100, Metrics: map[string]int{"foo": 10, "bar": 0}}
+	assert.True(t, limits.Enabled())
+	assert.Equal(t, 10, limits.For("foo"))
+	assert.Zero(t, limits.For("bar"))
+	assert.Equal(t, 100, limits.For("baz"))
+}
+
+func TestCardinalityLimits_Env(t *testing.T) {
+	t.Setenv("LIMITS_PER_METRIC", "100")
+	t.Setenv("LIMITS_METRICS", "http.server.request.duration:10,foo:0")
+	limits := CardinalityLimits{}
+	require.NoError(t, env.ParseWithOptions(&limits, env.Options{Prefix: "LIMITS_"}))
+	assert.Equal(t, CardinalityLimits{
+		PerMetric: 100,
+		Metrics:   map[string]int{"http.server.request.duration": 10, "foo": 0},
+	}, limits)
+}
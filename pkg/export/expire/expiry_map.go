@@ -105,6 +105,21 @@ func (ex *ExpiryMap[T]) DeleteAll() []T {
 	return entries
 }
 
+// Delete removes the entry for the given slice of label values, returning it
+// if it was stored
+func (ex *ExpiryMap[T]) Delete(lbls []string) (T, bool) {
+	h := labelsKey(lbls)
+	ex.mt.Lock()
+	defer ex.mt.Unlock()
+	e, ok := ex.entries[h]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	delete(ex.entries, h)
+	return e.val, true
+}
+
 // All returns an array with all the stored entries. It might contain expired entries
 // if DeleteExpired is not invoked before it.
 // TODO: use https://tip.golang.org/wiki/RangefuncExperiment when available
@@ -118,6 +133,23 @@ func (ex *ExpiryMap[T]) All() []T {
 	return items
 }
 
+// Contains returns whether there is an entry for the given slice of label values.
+// It does not update the "last access" time of the entry.
+func (ex *ExpiryMap[T]) Contains(lbls []string) bool {
+	ex.mt.RLock()
+	defer ex.mt.RUnlock()
+	_, ok := ex.entries[labelsKey(lbls)]
+	return ok
+}
+
+// Len returns the number of stored entries. It might account expired entries
+// if DeleteExpired is not invoked before it.
+func (ex *ExpiryMap[T]) Len() int {
+	ex.mt.RLock()
+	defer ex.mt.RUnlock()
+	return len(ex.entries)
+}
+
 func labelsKey(lbls []string) string {
 	return strings.Join(lbls, ":")
 }
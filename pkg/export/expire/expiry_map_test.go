@@ -71,3 +71,17 @@ func TestExpiryMap_NormalExpiration(t *testing.T) {
 	assert.Equal(t, "new_entry1", val1Again)
 	assert.Equal(t, "new_entry2", val2Again)
 }
+
+func TestExpiryMap_Delete(t *testing.T) {
+	em := NewExpiryMap[string](time.Now, time.Minute)
+	em.GetOrCreate([]string{"label1", "value1"}, func() string { return "entry1" })
+	em.GetOrCreate([]string{"label2", "value2"}, func() string { return "entry2" })
+
+	val, ok := em.Delete([]string{"label1", "value1"})
+	assert.True(t, ok)
+	assert.Equal(t, "entry1", val)
+
+	_, ok = em.Delete([]string{"label1", "value1"})
+	assert.False(t, ok)
+	assert.Equal(t, []string{"entry2"}, em.All())
+}
@@ -32,6 +32,7 @@ const (
 	FeatureGraph
 	FeatureApplicationHost
 	FeatureEBPF
+	FeatureProcess
 	FeatureAll = Features(^uint(0)) // all bits to 1
 )
 
@@ -48,6 +49,7 @@ var FeatureMapper = map[string]Features{
 	"application_service_graph": FeatureGraph,
 	"application_host":          FeatureApplicationHost,
 	"ebpf":                      FeatureEBPF,
+	"process":                   FeatureProcess,
 	"all":                       FeatureAll,
 	"*":                         FeatureAll,
 }
@@ -186,6 +188,10 @@ func (f Features) BPF() bool {
 	return f.any(FeatureEBPF)
 }
 
+func (f Features) ProcessMetrics() bool {
+	return f.any(FeatureProcess)
+}
+
 // InvalidSpanMetricsConfig is used to make sure that you can't define both legacy and OTEL span metrics at the same time.
 // It returns false when FeatureAll is set (e.g. via "*" or "all"), because the user didn't explicitly
 // pick both conflicting formats. In that case, the caller should resolve the conflict automatically.
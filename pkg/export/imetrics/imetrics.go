@@ -108,6 +108,20 @@ type Reporter interface {
 	// BPFPacketStats sets the counters of how many packets have been internally accounted vs how many packets
 	// have been ignored due to internal BPF map collisions
 	BPFPacketStats(count, ignored uint64)
+	// TailSamplingDecision is invoked every time the tail sampler decides whether a trace is sampled or dropped,
+	// and which policy took the decision
+	TailSamplingDecision(decision, policy string)
+	// TailSamplingBufferedTraces sets the number of traces that are buffered by the tail sampler, waiting for a decision
+	TailSamplingBufferedTraces(traces int)
+	// DiskQueueSize sets the number of items, and their size in bytes, that are stored in the disk queue of
+	// the OTLP exporter for the given signal (traces or metrics), waiting to be sent
+	DiskQueueSize(signal string, items, bytes int)
+	// DiskQueueDropped is invoked every time the disk queue of the OTLP exporter for the given signal drops
+	// items, because the queue is full or the items are rejected by the remote endpoint
+	DiskQueueDropped(signal string, items int)
+	// MetricCardinalityOverflow is invoked once for each new attribute set whose measurements are aggregated
+	// into the overflow series of a metric, because the metric reached its cardinality limit
+	MetricCardinalityOverflow(exporter, metric string)
 }
 
 // NoopReporter is a metrics Reporter that just does nothing
@@ -131,3 +145,8 @@ func (n NoopReporter) BpfMapMaxEntries(_, _, _ string, _ int)          {}
 func (n NoopReporter) BpfInternalMetricsScrapeInterval() time.Duration { return 0 }
 func (n NoopReporter) InformerLag(_ float64)                           {}
 func (n NoopReporter) BPFPacketStats(_, _ uint64)                      {}
+func (n NoopReporter) TailSamplingDecision(_, _ string)                {}
+func (n NoopReporter) TailSamplingBufferedTraces(_ int)                {}
+func (n NoopReporter) DiskQueueSize(_ string, _, _ int)                {}
+func (n NoopReporter) DiskQueueDropped(_ string, _ int)                {}
+func (n NoopReporter) MetricCardinalityOverflow(_, _ string)           {}
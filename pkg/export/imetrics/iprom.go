@@ -49,6 +49,15 @@ type PrometheusReporter struct {
 	totalIgnoredPackets   uint64
 	bpfPacketCount        prometheus.Counter
 	bpfIgnoredPacketCount prometheus.Counter
+
+	tailSamplingDecisions      *prometheus.CounterVec
+	tailSamplingBufferedTraces prometheus.Gauge
+
+	diskQueueItems   *prometheus.GaugeVec
+	diskQueueBytes   *prometheus.GaugeVec
+	diskQueueDropped *prometheus.CounterVec
+
+	cardinalityOverflows *prometheus.CounterVec
 }
 
 func NewPrometheusReporter(cfg *InternalMetricsConfig, manager *connector.PrometheusManager, registry *prometheus.Registry) *PrometheusReporter {
@@ -137,6 +146,31 @@ func NewPrometheusReporter(cfg *InternalMetricsConfig, manager *connector.Promet
 			Name: attr.VendorPrefix + "_bpf_network_packets_total",
 			Help: "How many network packets have been internally accounted",
 		}),
+		tailSamplingDecisions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attr.VendorPrefix + "_tail_sampling_traces_total",
+			Help: "Number of traces decided by the tail sampler, by decision (sampled or dropped) and policy",
+		}, []string{"decision", "policy"}),
+		tailSamplingBufferedTraces: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: attr.VendorPrefix + "_tail_sampling_buffered_traces",
+			Help: "Number of traces that are buffered by the tail sampler, waiting for a decision",
+		}),
+		diskQueueItems: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: attr.VendorPrefix + "_otel_disk_queue_items",
+			Help: "Number of items stored in the disk queue of the OTLP exporter, waiting to be sent",
+		}, []string{"signal"}),
+		diskQueueBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: attr.VendorPrefix + "_otel_disk_queue_bytes",
+			Help: "Size, in bytes, of the disk queue of the OTLP exporter",
+		}, []string{"signal"}),
+		diskQueueDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attr.VendorPrefix + "_otel_disk_queue_dropped_total",
+			Help: "Number of items dropped from the disk queue of the OTLP exporter",
+		}, []string{"signal"}),
+		cardinalityOverflows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: attr.VendorPrefix + "_metric_cardinality_overflows_total",
+			Help: "Number of new attribute sets whose measurements were aggregated into the overflow series " +
+				"of a metric, because it reached its cardinality limit",
+		}, []string{"exporter", "metric"}),
 	}
 	metrics := []prometheus.Collector{
 		pr.tracerFlushes,
@@ -155,6 +189,12 @@ func NewPrometheusReporter(cfg *InternalMetricsConfig, manager *connector.Promet
 		pr.informerLag,
 		pr.bpfPacketCount,
 		pr.bpfIgnoredPacketCount,
+		pr.tailSamplingDecisions,
+		pr.tailSamplingBufferedTraces,
+		pr.diskQueueItems,
+		pr.diskQueueBytes,
+		pr.diskQueueDropped,
+		pr.cardinalityOverflows,
 	}
 	if registry != nil {
 		registry.MustRegister(metrics...)
@@ -245,3 +285,24 @@ func (p *PrometheusReporter) BPFPacketStats(count, ignored uint64) {
 	p.bpfIgnoredPacketCount.Add(float64(ignored - p.totalIgnoredPackets))
 	p.totalPackets, p.totalIgnoredPackets = count, ignored
 }
+
+func (p *PrometheusReporter) TailSamplingDecision(decision, policy string) {
+	p.tailSamplingDecisions.WithLabelValues(decision, policy).Inc()
+}
+
+func (p *PrometheusReporter) TailSamplingBufferedTraces(traces int) {
+	p.tailSamplingBufferedTraces.Set(float64(traces))
+}
+
+func (p *PrometheusReporter) DiskQueueSize(signal string, items, bytes int) {
+	p.diskQueueItems.WithLabelValues(signal).Set(float64(items))
+	p.diskQueueBytes.WithLabelValues(signal).Set(float64(bytes))
+}
+
+func (p *PrometheusReporter) DiskQueueDropped(signal string, items int) {
+	p.diskQueueDropped.WithLabelValues(signal).Add(float64(items))
+}
+
+func (p *PrometheusReporter) MetricCardinalityOverflow(exporter, metric string) {
+	p.cardinalityOverflows.WithLabelValues(exporter, metric).Inc()
+}
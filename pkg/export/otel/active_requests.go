@@ -0,0 +1,128 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otel // import "go.opentelemetry.io/obi/pkg/export/otel"
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+)
+
+const (
+	// ActiveRequestsTimeout is the time after which a request that hasn't been reported
+	// again as in-flight is considered finished, even if its finished span is not received
+	// (e.g. because it was dropped or filtered). The tracers report the in-flight requests
+	// every few seconds, so this value must be much longer than the report period.
+	ActiveRequestsTimeout = 10 * time.Second
+	// ActiveRequestsMaxTracked is the maximum number of in-flight requests that are accounted.
+	// Further requests are not accounted until some of the tracked requests finish.
+	ActiveRequestsMaxTracked = 50_000
+)
+
+// ActiveRequestsTracker accounts the requests that have started but not finished yet.
+// The tracers periodically report the in-flight requests as spans whose InFlight field is set.
+// A request is accounted the first time it is reported in-flight (the caller adds +1 to the metric),
+// and is discounted when its finished span is received (the caller adds -1 to the metric where the
+// request was accounted). If the finished span never arrives, the request is discounted once it
+// hasn't been reported in-flight during the ActiveRequestsTimeout.
+// The requests that finish before being reported in-flight are never accounted.
+// The Metric type parameter is the instrument (plus any required attributes) where the
+// requests are accounted. It must be comparable, so the requests of the removed metrics
+// can be forgotten. It is safe for concurrent use.
+type ActiveRequestsTracker[Metric comparable] struct {
+	mt         sync.Mutex
+	timeout    time.Duration
+	maxTracked int
+	requests   map[activeRequestKey]*activeRequest[Metric]
+	clock      func() time.Time
+}
+
+// activeRequestKey identifies a request from both its in-flight and finished spans
+type activeRequestKey struct {
+	pid   app.PID
+	start int64
+}
+
+type activeRequest[Metric any] struct {
+	metric   Metric
+	lastSeen time.Time
+}
+
+// NewActiveRequestsTracker creates an ActiveRequestsTracker that discounts the requests
+// that haven't been reported in-flight during the provided timeout.
+func NewActiveRequestsTracker[Metric comparable](timeout time.Duration) *ActiveRequestsTracker[Metric] {
+	return &ActiveRequestsTracker[Metric]{
+		timeout:    timeout,
+		maxTracked: ActiveRequestsMaxTracked,
+		requests:   map[activeRequestKey]*activeRequest[Metric]{},
+		clock:      time.Now,
+	}
+}
+
+func activeRequestKeyOf(span *request.Span) activeRequestKey {
+	return activeRequestKey{pid: span.Pid.HostPID, start: span.Start}
+}
+
+// Start tracks a request from its in-flight span, and returns true if the request
+// has to be accounted in the provided metric because it wasn't tracked yet.
+// It returns false if the request was already tracked, or if the maximum number of
+// tracked requests has been reached.
+func (at *ActiveRequestsTracker[Metric]) Start(span *request.Span, m Metric) bool {
+	at.mt.Lock()
+	defer at.mt.Unlock()
+	key := activeRequestKeyOf(span)
+	if req, ok := at.requests[key]; ok {
+		req.lastSeen = at.clock()
+		return false
+	}
+	if len(at.requests) >= at.maxTracked {
+		return false
+	}
+	at.requests[key] = &activeRequest[Metric]{metric: m, lastSeen: at.clock()}
+	return true
+}
+
+// End stops tracking a request from its finished span. If the request was tracked,
+// it returns the metric where the request has to be discounted.
+func (at *ActiveRequestsTracker[Metric]) End(span *request.Span) (Metric, bool) {
+	at.mt.Lock()
+	defer at.mt.Unlock()
+	key := activeRequestKeyOf(span)
+	req, ok := at.requests[key]
+	if !ok {
+		var none Metric
+		return none, false
+	}
+	delete(at.requests, key)
+	return req.metric, true
+}
+
+// Expire stops tracking the requests that haven't been reported in-flight during
+// the tracker timeout, and invokes the discount function for each of them.
+func (at *ActiveRequestsTracker[Metric]) Expire(discount func(m Metric)) {
+	at.mt.Lock()
+	defer at.mt.Unlock()
+	oldest := at.clock().Add(-at.timeout)
+	for key, req := range at.requests {
+		if req.lastSeen.Before(oldest) {
+			delete(at.requests, key)
+			discount(req.metric)
+		}
+	}
+}
+
+// Forget stops tracking the requests of a metric without discounting them, e.g.
+// after the metric has been removed because it expired. Otherwise, discounting them
+// would report the metric again.
+func (at *ActiveRequestsTracker[Metric]) Forget(m Metric) {
+	at.mt.Lock()
+	defer at.mt.Unlock()
+	for key, req := range at.requests {
+		if req.metric == m {
+			delete(at.requests, key)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+)
+
+func inFlightSpan(pid app.PID, start int64) *request.Span {
+	return &request.Span{
+		Type:     request.EventTypeHTTP,
+		Pid:      request.PidInfo{HostPID: pid},
+		Start:    start,
+		InFlight: true,
+	}
+}
+
+func finishedSpan(pid app.PID, start int64) *request.Span {
+	s := inFlightSpan(pid, start)
+	s.InFlight = false
+	s.End = start + 100
+	return s
+}
+
+func TestActiveRequestsTracker(t *testing.T) {
+	now := time.Unix(1000, 0)
+	at := NewActiveRequestsTracker[string](10 * time.Second)
+	at.clock = func() time.Time { return now }
+
+	// requests are accounted the first time they are reported in-flight
+	assert.True(t, at.Start(inFlightSpan(1, 10), "foo"))
+	assert.True(t, at.Start(inFlightSpan(1, 20), "foo"))
+	assert.True(t, at.Start(inFlightSpan(2, 10), "bar"))
+	assert.False(t, at.Start(inFlightSpan(1, 10), "foo"))
+
+	// finished requests are discounted from the metric where they were accounted
+	m, ok := at.End(finishedSpan(1, 10))
+	require.True(t, ok)
+	assert.Equal(t, "foo", m)
+	_, ok = at.End(finishedSpan(1, 10))
+	assert.False(t, ok)
+
+	// requests that finish before being reported in-flight aren't discounted
+	_, ok = at.End(finishedSpan(1, 30))
+	assert.False(t, ok)
+
+	// requests that are still reported in-flight don't expire
+	now = now.Add(8 * time.Second)
+	assert.False(t, at.Start(inFlightSpan(1, 20), "foo"))
+	now = now.Add(8 * time.Second)
+	var discounted []string
+	at.Expire(func(m string) { discounted = append(discounted, m) })
+	assert.Equal(t, []string{"bar"}, discounted)
+
+	m, ok = at.End(finishedSpan(1, 20))
+	require.True(t, ok)
+	assert.Equal(t, "foo", m)
+	assert.Empty(t, at.requests)
+}
+
+func TestActiveRequestsTracker_MaxTracked(t *testing.T) {
+	at := NewActiveRequestsTracker[string](10 * time.Second)
+	at.maxTracked = 2
+
+	assert.True(t, at.Start(inFlightSpan(1, 10), "foo"))
+	assert.True(t, at.Start(inFlightSpan(1, 20), "foo"))
+	assert.False(t, at.Start(inFlightSpan(1, 30), "foo"))
+	assert.Len(t, at.requests, 2)
+
+	// finished requests make room for new requests
+	at.End(finishedSpan(1, 10))
+	assert.True(t, at.Start(inFlightSpan(1, 30), "foo"))
+}
+
+func TestActiveRequestsTracker_Forget(t *testing.T) {
+	now := time.Unix(1000, 0)
+	at := NewActiveRequestsTracker[string](10 * time.Second)
+	at.clock = func() time.Time { return now }
+
+	at.Start(inFlightSpan(1, 10), "foo")
+	at.Start(inFlightSpan(1, 20), "bar")
+	at.Start(inFlightSpan(1, 30), "foo")
+
+	at.Forget("foo")
+	at.Forget("unknown")
+
+	_, ok := at.End(finishedSpan(1, 10))
+	assert.False(t, ok)
+
+	var discounted []string
+	now = now.Add(time.Minute)
+	at.Expire(func(m string) { discounted = append(discounted, m) })
+	assert.Equal(t, []string{"bar"}, discounted)
+}
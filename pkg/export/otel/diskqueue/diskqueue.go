@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package diskqueue provides traces and metrics consumers that persist the received data into
+// a disk-backed queue, and forward it in order to the next consumer, retrying while it fails.
+// It allows the OTLP exporters to keep the data during the unavailability of the remote endpoint,
+// or across the restarts of the agent.
+package diskqueue // import "go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
+)
+
+const (
+	SignalTraces  = "traces"
+	SignalMetrics = "metrics"
+
+	defaultMaxSizeMB     = 256
+	defaultSegmentSizeMB = 8
+
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// DiskQueueConfig for the disk-backed queue of the OTLP exporters
+type DiskQueueConfig struct {
+	// Directory where the queue segment files are stored. Each signal is stored in its own
+	// subdirectory. If empty, the disk queue is disabled.
+	Directory string `yaml:"directory" env:"DIRECTORY"`
+	// MaxSizeMB is the maximum size, in megabytes, of the queue. When it is reached, the
+	// oldest items are dropped. Defaults to 256.
+	MaxSizeMB int `yaml:"max_size_mb" env:"MAX_SIZE_MB" validate:"gte=0"`
+	// SegmentSizeMB is the maximum size, in megabytes, of each segment file. The disk space of
+	// a segment is released after all its items are sent. Defaults to 8.
+	SegmentSizeMB int `yaml:"segment_size_mb" env:"SEGMENT_SIZE_MB" validate:"gte=0"`
+}
+
+// Enabled returns whether a queue directory has been provided
+func (c *DiskQueueConfig) Enabled() bool {
+	return c.Directory != ""
+}
+
+func (c *DiskQueueConfig) maxSizeBytes() int64 {
+	if c.MaxSizeMB == 0 {
+		return defaultMaxSizeMB * 1024 * 1024
+	}
+	return int64(c.MaxSizeMB) * 1024 * 1024
+}
+
+func (c *DiskQueueConfig) segmentSizeBytes() int64 {
+	if c.SegmentSizeMB == 0 {
+		return defaultSegmentSizeMB * 1024 * 1024
+	}
+	return int64(c.SegmentSizeMB) * 1024 * 1024
+}
+
+// drainer stores the serialized items into the queue, and sends them in order from a background
+// goroutine, retrying with exponential backoff until the send succeeds or fails permanently
+type drainer struct {
+	log     *slog.Logger
+	signal  string
+	queue   *Queue
+	metrics imetrics.Reporter
+	send    func(ctx context.Context, data []byte) error
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newDrainer(
+	cfg *DiskQueueConfig, signal string, metrics imetrics.Reporter,
+	send func(ctx context.Context, data []byte) error,
+) (*drainer, error) {
+	if metrics == nil {
+		metrics = imetrics.NoopReporter{}
+	}
+	d := &drainer{
+		log:        slog.With("component", "diskqueue.Consumer", "signal", signal),
+		signal:     signal,
+		metrics:    metrics,
+		send:       send,
+		minBackoff: defaultMinBackoff,
+		maxBackoff: defaultMaxBackoff,
+	}
+	var err error
+	d.queue, err = OpenQueue(filepath.Join(cfg.Directory, signal), cfg.maxSizeBytes(), cfg.segmentSizeBytes(), d.dropped)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s disk queue: %w", signal, err)
+	}
+	if items, _ := d.queue.Len(); items > 0 {
+		d.log.Info("resuming disk queue", "items", items)
+	}
+	d.reportSize()
+	return d, nil
+}
+
+// Start sending the queued items in background
+func (d *drainer) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	go d.run(ctx)
+}
+
+// Shutdown stops sending the queued items and closes the queue. The items that haven't been
+// sent are kept in the disk.
+func (d *drainer) Shutdown(ctx context.Context) error {
+	if d.cancel != nil {
+		d.cancel()
+		select {
+		case <-d.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return d.queue.Close()
+}
+
+func (d *drainer) push(data []byte) error {
+	err := d.queue.Push(data)
+	d.reportSize()
+	return err
+}
+
+func (d *drainer) run(ctx context.Context) {
+	defer close(d.done)
+	backoff := d.minBackoff
+	for {
+		data, ok, err := d.queue.Peek()
+		if err != nil {
+			d.log.Warn("discarding unreadable disk queue segment", "error", err)
+			d.reportSize()
+			continue
+		}
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-d.queue.Notify():
+			}
+			continue
+		}
+		if err := d.send(ctx, data); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if !consumererror.IsPermanent(err) {
+				d.log.Debug("can't send queued item. Retrying", "error", err, "backoff", backoff)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(backoff):
+				}
+				backoff = min(2*backoff, d.maxBackoff)
+				continue
+			}
+			d.log.Warn("dropping queued item that can't be sent", "error", err)
+			d.dropped(1)
+		}
+		backoff = d.minBackoff
+		if err := d.queue.Ack(); err != nil {
+			d.log.Warn("can't persist the disk queue position", "error", err)
+		}
+		d.reportSize()
+	}
+}
+
+func (d *drainer) dropped(items int) {
+	d.metrics.DiskQueueDropped(d.signal, items)
+}
+
+func (d *drainer) reportSize() {
+	items, bytes := d.queue.Len()
+	d.metrics.DiskQueueSize(d.signal, items, int(bytes))
+}
+
+// Traces consumer that stores the traces in the disk queue, and forwards them to the next consumer
+type Traces struct {
+	*drainer
+	marshaler ptrace.ProtoMarshaler
+}
+
+// NewTraces opens the traces disk queue. The queued traces are forwarded to the next consumer
+// after invoking Start.
+func NewTraces(cfg *DiskQueueConfig, next consumer.Traces, metrics imetrics.Reporter) (*Traces, error) {
+	unmarshaler := ptrace.ProtoUnmarshaler{}
+	d, err := newDrainer(cfg, SignalTraces, metrics, func(ctx context.Context, data []byte) error {
+		td, err := unmarshaler.UnmarshalTraces(data)
+		if err != nil {
+			return consumererror.NewPermanent(fmt.Errorf("unmarshaling queued traces: %w", err))
+		}
+		return next.ConsumeTraces(ctx, td)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Traces{drainer: d}, nil
+}
+
+func (t *Traces) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
+	data, err := t.marshaler.MarshalTraces(td)
+	if err != nil {
+		return consumererror.NewPermanent(fmt.Errorf("marshaling traces: %w", err))
+	}
+	return t.push(data)
+}
+
+func (t *Traces) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// Metrics consumer that stores the metrics in the disk queue, and forwards them to the next consumer
+type Metrics struct {
+	*drainer
+	marshaler pmetric.ProtoMarshaler
+}
+
+// NewMetrics opens the metrics disk queue. The queued metrics are forwarded to the next consumer
+// after invoking Start.
+func NewMetrics(cfg *DiskQueueConfig, next consumer.Metrics, metrics imetrics.Reporter) (*Metrics, error) {
+	unmarshaler := pmetric.ProtoUnmarshaler{}
+	d, err := newDrainer(cfg, SignalMetrics, metrics, func(ctx context.Context, data []byte) error {
+		md, err := unmarshaler.UnmarshalMetrics(data)
+		if err != nil {
+			return consumererror.NewPermanent(fmt.Errorf("unmarshaling queued metrics: %w", err))
+		}
+		return next.ConsumeMetrics(ctx, md)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Metrics{drainer: d}, nil
+}
+
+func (m *Metrics) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	data, err := m.marshaler.MarshalMetrics(md)
+	if err != nil {
+		return consumererror.NewPermanent(fmt.Errorf("marshaling metrics: %w", err))
+	}
+	return m.push(data)
+}
+
+func (m *Metrics) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
@@ -0,0 +1,187 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package diskqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
+)
+
+const timeout = 5 * time.Second
+
+func TestTraces_RetryInOrder(t *testing.T) {
+	next := &fakeTracesConsumer{err: errors.New("endpoint unavailable")}
+	metrics := &fakeMetrics{}
+	dq, err := NewTraces(&DiskQueueConfig{Directory: t.TempDir()}, next, metrics)
+	require.NoError(t, err)
+	dq.minBackoff, dq.maxBackoff = time.Millisecond, 5*time.Millisecond
+	dq.Start()
+
+	for _, name := range []string{"a", "b", "c"} {
+		require.NoError(t, dq.ConsumeTraces(t.Context(), tracesWithSpan(name)))
+	}
+	// while the endpoint is unavailable, the traces remain in the queue
+	require.Eventually(t, func() bool { return next.attempts() > 3 }, timeout, time.Millisecond)
+	assert.Empty(t, next.spanNames())
+	items, _ := dq.queue.Len()
+	assert.Equal(t, 3, items)
+
+	next.setError(nil)
+	require.Eventually(t, func() bool { return len(next.spanNames()) == 3 }, timeout, time.Millisecond)
+	assert.Equal(t, []string{"a", "b", "c"}, next.spanNames())
+	require.NoError(t, dq.Shutdown(t.Context()))
+
+	items, bytes := metrics.size()
+	assert.Zero(t, items)
+	assert.Positive(t, bytes)
+	assert.Zero(t, metrics.droppedItems())
+}
+
+func TestTraces_PermanentErrorsAreDropped(t *testing.T) {
+	next := &fakeTracesConsumer{err: consumererror.NewPermanent(errors.New("bad request"))}
+	metrics := &fakeMetrics{}
+	dq, err := NewTraces(&DiskQueueConfig{Directory: t.TempDir()}, next, metrics)
+	require.NoError(t, err)
+	dq.Start()
+	defer dq.Shutdown(context.Background())
+
+	require.NoError(t, dq.ConsumeTraces(t.Context(), tracesWithSpan("a")))
+	require.Eventually(t, func() bool { return metrics.droppedItems() == 1 }, timeout, time.Millisecond)
+	items, _ := dq.queue.Len()
+	assert.Zero(t, items)
+}
+
+func TestTraces_SurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	dq, err := NewTraces(&DiskQueueConfig{Directory: dir}, &fakeTracesConsumer{}, nil)
+	require.NoError(t, err)
+	// not started, so the traces are only stored
+	require.NoError(t, dq.ConsumeTraces(t.Context(), tracesWithSpan("a")))
+	require.NoError(t, dq.ConsumeTraces(t.Context(), tracesWithSpan("b")))
+	require.NoError(t, dq.Shutdown(t.Context()))
+
+	next := &fakeTracesConsumer{}
+	dq, err = NewTraces(&DiskQueueConfig{Directory: dir}, next, nil)
+	require.NoError(t, err)
+	dq.Start()
+	defer dq.Shutdown(context.Background())
+	require.Eventually(t, func() bool { return len(next.spanNames()) == 2 }, timeout, time.Millisecond)
+	assert.Equal(t, []string{"a", "b"}, next.spanNames())
+}
+
+func TestMetrics(t *testing.T) {
+	var received []string
+	var mt sync.Mutex
+	next, err := consumer.NewMetrics(func(_ context.Context, md pmetric.Metrics) error {
+		mt.Lock()
+		defer mt.Unlock()
+		received = append(received, md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+		return nil
+	})
+	require.NoError(t, err)
+	dq, err := NewMetrics(&DiskQueueConfig{Directory: t.TempDir()}, next, nil)
+	require.NoError(t, err)
+	dq.Start()
+	defer dq.Shutdown(context.Background())
+
+	md := pmetric.NewMetrics()
+	md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("obi.test")
+	require.NoError(t, dq.ConsumeMetrics(t.Context(), md))
+	require.Eventually(t, func() bool {
+		mt.Lock()
+		defer mt.Unlock()
+		return len(received) == 1
+	}, timeout, time.Millisecond)
+	assert.Equal(t, []string{"obi.test"}, received)
+}
+
+func tracesWithSpan(name string) ptrace.Traces {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(name)
+	return td
+}
+
+type fakeTracesConsumer struct {
+	mt       sync.Mutex
+	err      error
+	tries    int
+	received []string
+}
+
+func (f *fakeTracesConsumer) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	f.tries++
+	if f.err != nil {
+		return f.err
+	}
+	f.received = append(f.received, td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	return nil
+}
+
+func (f *fakeTracesConsumer) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{}
+}
+
+func (f *fakeTracesConsumer) setError(err error) {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	f.err = err
+}
+
+func (f *fakeTracesConsumer) attempts() int {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	return f.tries
+}
+
+func (f *fakeTracesConsumer) spanNames() []string {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	return append([]string(nil), f.received...)
+}
+
+type fakeMetrics struct {
+	imetrics.NoopReporter
+	mt      sync.Mutex
+	items   int
+	bytes   int
+	dropped int
+}
+
+func (f *fakeMetrics) DiskQueueSize(_ string, items, bytes int) {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	f.items, f.bytes = items, bytes
+}
+
+func (f *fakeMetrics) DiskQueueDropped(_ string, items int) {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	f.dropped += items
+}
+
+func (f *fakeMetrics) size() (int, int) {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	return f.items, f.bytes
+}
+
+func (f *fakeMetrics) droppedItems() int {
+	f.mt.Lock()
+	defer f.mt.Unlock()
+	return f.dropped
+}
@@ -0,0 +1,424 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package diskqueue // import "go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	segmentExt     = ".seg"
+	cursorFileName = "cursor"
+	// each record is prefixed by the payload length and its CRC32 checksum, as little-endian uint32s
+	recordHeaderLen = 8
+)
+
+var errCorruptedRecord = errors.New("corrupted record")
+
+type segment struct {
+	id uint64
+	// size of the segment file, in bytes
+	size int64
+	// number of records that haven't been consumed yet
+	records int
+}
+
+// Queue is a FIFO queue of records that is stored in a directory as a sequence of append-only
+// segment files. The read position is persisted on each Ack, so the pending records survive
+// the restarts of the process. When the total size of the segments would exceed the maximum
+// size, the oldest segments are discarded.
+// The records are not synced to the disk on each write, so they survive the crashes of the process
+// but not the crashes of the operating system.
+type Queue struct {
+	mt           sync.Mutex
+	dir          string
+	maxBytes     int64
+	segmentBytes int64
+	// onDrop is invoked with the number of pending records that are discarded
+	onDrop func(records int)
+
+	segments   []*segment
+	nextID     uint64
+	totalBytes int64
+	items      int
+
+	writer *os.File
+	reader *os.File
+	// readerID is the identifier of the segment that is open by the reader
+	readerID uint64
+	// readOff is the offset of the next record to read in the first segment
+	readOff int64
+
+	// pending record, returned by Peek and not yet acknowledged
+	pendingSeg uint64
+	pendingLen int64
+
+	notify chan struct{}
+}
+
+// OpenQueue opens the queue stored in the given directory, or creates it if it does not exist.
+func OpenQueue(dir string, maxBytes, segmentBytes int64, onDrop func(records int)) (*Queue, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating queue directory: %w", err)
+	}
+	if onDrop == nil {
+		onDrop = func(int) {}
+	}
+	q := &Queue{
+		dir:          dir,
+		maxBytes:     maxBytes,
+		segmentBytes: min(segmentBytes, maxBytes),
+		onDrop:       onDrop,
+		notify:       make(chan struct{}, 1),
+	}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// load the existing segments, discarding the already consumed ones and truncating
+// any partially written record at the end of each segment
+func (q *Queue) load() error {
+	cursorID, cursorOff := q.readCursor()
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return fmt.Errorf("reading queue directory: %w", err)
+	}
+	var ids []uint64
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), segmentExt)
+		if !ok || e.IsDir() {
+			continue
+		}
+		id, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+		q.nextID = max(q.nextID, id+1)
+	}
+	// the cursor outlives the segments of a drained queue, so the new segments must be
+	// numbered after it. Otherwise, they would be discarded as consumed in the next load
+	if cursorOff > 0 {
+		q.nextID = max(q.nextID, cursorID+1)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		start := int64(0)
+		if id < cursorID {
+			_ = os.Remove(q.segmentPath(id))
+			continue
+		} else if id == cursorID {
+			start = cursorOff
+		}
+		seg, err := q.scanSegment(id, start)
+		if err != nil {
+			return err
+		}
+		if seg.records == 0 {
+			_ = os.Remove(q.segmentPath(id))
+			continue
+		}
+		if len(q.segments) == 0 {
+			q.readOff = start
+		}
+		q.segments = append(q.segments, seg)
+		q.totalBytes += seg.size
+		q.items += seg.records
+	}
+	return nil
+}
+
+// scanSegment counts the valid records from the start offset, and truncates the segment
+// after the last valid record
+func (q *Queue) scanSegment(id uint64, start int64) (*segment, error) {
+	f, err := os.OpenFile(q.segmentPath(id), os.O_RDWR, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening queue segment: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("reading queue segment: %w", err)
+	}
+	seg := &segment{id: id}
+	off := start
+	if off > info.Size() {
+		off = info.Size()
+	}
+	for {
+		data, err := readRecord(f, off, q.segmentBytes)
+		if err != nil {
+			break
+		}
+		off += recordHeaderLen + int64(len(data))
+		seg.records++
+	}
+	if off < info.Size() {
+		if err := f.Truncate(off); err != nil {
+			return nil, fmt.Errorf("truncating queue segment: %w", err)
+		}
+	}
+	seg.size = off
+	return seg, nil
+}
+
+// Push appends a record to the queue, discarding the oldest segments if the queue is full.
+func (q *Queue) Push(data []byte) error {
+	recLen := recordHeaderLen + int64(len(data))
+	if recLen > q.segmentBytes {
+		q.onDrop(1)
+		return fmt.Errorf("record of %d bytes does not fit into a queue segment of %d bytes", recLen, q.segmentBytes)
+	}
+	q.mt.Lock()
+	defer q.mt.Unlock()
+	for len(q.segments) > 0 && q.totalBytes+recLen > q.maxBytes {
+		if dropped := q.removeFirst(); dropped > 0 {
+			q.onDrop(dropped)
+		}
+	}
+	if err := q.prepareWriter(recLen); err != nil {
+		return err
+	}
+	buf := make([]byte, recLen)
+	binary.LittleEndian.PutUint32(buf, uint32(len(data)))
+	binary.LittleEndian.PutUint32(buf[4:], crc32.ChecksumIEEE(data))
+	copy(buf[recordHeaderLen:], data)
+	last := q.segments[len(q.segments)-1]
+	if n, err := q.writer.Write(buf); err != nil {
+		// discard the partially written record, so it does not corrupt the following ones
+		_ = q.writer.Truncate(last.size)
+		_, _ = q.writer.Seek(last.size, io.SeekStart)
+		return fmt.Errorf("writing queue record (%d bytes written): %w", n, err)
+	}
+	last.size += recLen
+	last.records++
+	q.totalBytes += recLen
+	q.items++
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// prepareWriter opens the last segment for writing, or creates a new segment if the last one
+// can't fit the record
+func (q *Queue) prepareWriter(recLen int64) error {
+	if len(q.segments) > 0 {
+		last := q.segments[len(q.segments)-1]
+		if last.size+recLen <= q.segmentBytes {
+			if q.writer != nil {
+				return nil
+			}
+			f, err := os.OpenFile(q.segmentPath(last.id), os.O_WRONLY, 0)
+			if err != nil {
+				return fmt.Errorf("opening queue segment: %w", err)
+			}
+			if _, err := f.Seek(last.size, io.SeekStart); err != nil {
+				f.Close()
+				return fmt.Errorf("opening queue segment: %w", err)
+			}
+			q.writer = f
+			return nil
+		}
+	}
+	if q.writer != nil {
+		_ = q.writer.Close()
+		q.writer = nil
+	}
+	seg := &segment{id: q.nextID}
+	f, err := os.OpenFile(q.segmentPath(seg.id), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating queue segment: %w", err)
+	}
+	q.nextID++
+	q.writer = f
+	if len(q.segments) == 0 {
+		q.readOff = 0
+	}
+	q.segments = append(q.segments, seg)
+	return nil
+}
+
+// Peek returns the oldest record of the queue without removing it. It returns false if the
+// queue is empty. The record is removed from the queue by invoking Ack.
+func (q *Queue) Peek() ([]byte, bool, error) {
+	q.mt.Lock()
+	defer q.mt.Unlock()
+	for len(q.segments) > 0 {
+		first := q.segments[0]
+		if first.records == 0 {
+			if len(q.segments) == 1 {
+				// waiting for more records to be appended to the last segment
+				return nil, false, nil
+			}
+			q.removeFirst()
+			continue
+		}
+		if q.reader == nil || q.readerID != first.id {
+			if err := q.openReader(first.id); err != nil {
+				// discarding the segment, as the next Peek would fail again
+				q.onDrop(q.removeFirst())
+				return nil, false, fmt.Errorf("reading queue segment %d: %w", first.id, err)
+			}
+		}
+		data, err := readRecord(q.reader, q.readOff, q.segmentBytes)
+		if err != nil {
+			// the rest of the segment can't be read. Discarding it
+			dropped := q.removeFirst()
+			q.onDrop(dropped)
+			return nil, false, fmt.Errorf("reading queue segment %d: %w", first.id, err)
+		}
+		q.pendingSeg = first.id
+		q.pendingLen = recordHeaderLen + int64(len(data))
+		return data, true, nil
+	}
+	return nil, false, nil
+}
+
+// Ack removes from the queue the record that was returned by the last Peek invocation.
+func (q *Queue) Ack() error {
+	q.mt.Lock()
+	defer q.mt.Unlock()
+	if q.pendingLen == 0 || len(q.segments) == 0 || q.segments[0].id != q.pendingSeg {
+		// the segment of the record has been discarded in the meantime
+		q.pendingLen = 0
+		return nil
+	}
+	first := q.segments[0]
+	q.readOff += q.pendingLen
+	q.pendingLen = 0
+	first.records--
+	q.items--
+	if first.records == 0 && len(q.segments) > 1 {
+		q.removeFirst()
+	}
+	return q.writeCursor()
+}
+
+// Len returns the number of pending records, and the size in bytes of the queue segments
+func (q *Queue) Len() (items int, bytes int64) {
+	q.mt.Lock()
+	defer q.mt.Unlock()
+	return q.items, q.totalBytes
+}
+
+// Notify returns a channel that receives a value after new records are pushed
+func (q *Queue) Notify() <-chan struct{} {
+	return q.notify
+}
+
+// Close the segment files. Pending records will be available when the queue is open again.
+func (q *Queue) Close() error {
+	q.mt.Lock()
+	defer q.mt.Unlock()
+	var errs []error
+	if q.writer != nil {
+		errs = append(errs, q.writer.Close())
+		q.writer = nil
+	}
+	if q.reader != nil {
+		errs = append(errs, q.reader.Close())
+		q.reader = nil
+	}
+	return errors.Join(errs...)
+}
+
+// removeFirst deletes the oldest segment, returning the number of its pending records
+func (q *Queue) removeFirst() int {
+	first := q.segments[0]
+	if q.reader != nil && q.readerID == first.id {
+		_ = q.reader.Close()
+		q.reader = nil
+	}
+	if len(q.segments) == 1 && q.writer != nil {
+		_ = q.writer.Close()
+		q.writer = nil
+	}
+	_ = os.Remove(q.segmentPath(first.id))
+	q.segments = q.segments[1:]
+	q.totalBytes -= first.size
+	q.items -= first.records
+	q.readOff = 0
+	return first.records
+}
+
+func (q *Queue) openReader(id uint64) error {
+	if q.reader != nil {
+		_ = q.reader.Close()
+		q.reader = nil
+	}
+	f, err := os.Open(q.segmentPath(id))
+	if err != nil {
+		return fmt.Errorf("opening queue segment: %w", err)
+	}
+	q.reader, q.readerID = f, id
+	return nil
+}
+
+// writeCursor persists the read position, replacing the cursor file atomically
+func (q *Queue) writeCursor() error {
+	if len(q.segments) == 0 {
+		return nil
+	}
+	buf := make([]byte, 16)
+	binary.LittleEndian.PutUint64(buf, q.segments[0].id)
+	binary.LittleEndian.PutUint64(buf[8:], uint64(q.readOff))
+	tmp := filepath.Join(q.dir, cursorFileName+".tmp")
+	if err := os.WriteFile(tmp, buf, 0o600); err != nil {
+		return fmt.Errorf("writing queue cursor: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(q.dir, cursorFileName)); err != nil {
+		return fmt.Errorf("writing queue cursor: %w", err)
+	}
+	return nil
+}
+
+func (q *Queue) readCursor() (id uint64, off int64) {
+	buf, err := os.ReadFile(filepath.Join(q.dir, cursorFileName))
+	if err != nil || len(buf) != 16 {
+		return 0, 0
+	}
+	return binary.LittleEndian.Uint64(buf), int64(binary.LittleEndian.Uint64(buf[8:]))
+}
+
+func (q *Queue) segmentPath(id uint64) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%020d%s", id, segmentExt))
+}
+
+// readRecord at the given offset, verifying that its size does not exceed the maximum length
+// and that it matches the checksum
+func readRecord(r io.ReaderAt, off, maxLen int64) ([]byte, error) {
+	header := make([]byte, recordHeaderLen)
+	if _, err := r.ReadAt(header, off); err != nil {
+		return nil, err
+	}
+	size := int64(binary.LittleEndian.Uint32(header))
+	if size > maxLen-recordHeaderLen {
+		return nil, errCorruptedRecord
+	}
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, off+recordHeaderLen); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(header[4:]) {
+		return nil, errCorruptedRecord
+	}
+	return data, nil
+}
@@ -0,0 +1,248 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package diskqueue
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_FIFO(t *testing.T) {
+	q, err := OpenQueue(t.TempDir(), 1024, 64, nil)
+	require.NoError(t, err)
+	defer q.Close()
+
+	_, ok, err := q.Peek()
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	for i := range 10 {
+		require.NoError(t, q.Push(fmt.Appendf(nil, "record-%d", i)))
+	}
+	items, _ := q.Len()
+	assert.Equal(t, 10, items)
+
+	for i := range 10 {
+		data, ok, err := q.Peek()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("record-%d", i), string(data))
+		// peeking again without acknowledging returns the same record
+		data, _, _ = q.Peek()
+		assert.Equal(t, fmt.Sprintf("record-%d", i), string(data))
+		require.NoError(t, q.Ack())
+	}
+	_, ok, err = q.Peek()
+	require.NoError(t, err)
+	assert.False(t, ok)
+	items, _ = q.Len()
+	assert.Zero(t, items)
+}
+
+func TestQueue_Restart(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenQueue(dir, 1024, 64, nil)
+	require.NoError(t, err)
+	for i := range 10 {
+		require.NoError(t, q.Push(fmt.Appendf(nil, "record-%d", i)))
+	}
+	// consume the first 3 records
+	for range 3 {
+		_, _, err := q.Peek()
+		require.NoError(t, err)
+		require.NoError(t, q.Ack())
+	}
+	// the 4th record is peeked but not acknowledged
+	_, _, err = q.Peek()
+	require.NoError(t, err)
+	require.NoError(t, q.Close())
+
+	q, err = OpenQueue(dir, 1024, 64, nil)
+	require.NoError(t, err)
+	defer q.Close()
+	items, _ := q.Len()
+	assert.Equal(t, 7, items)
+	for i := 3; i < 10; i++ {
+		data, ok, err := q.Peek()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("record-%d", i), string(data))
+		require.NoError(t, q.Ack())
+	}
+	// new records are appended after the restart
+	require.NoError(t, q.Push([]byte("after restart")))
+	data, ok, err := q.Peek()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "after restart", string(data))
+}
+
+func TestQueue_RestartAfterDrain(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenQueue(dir, 1024, 64, nil)
+	require.NoError(t, err)
+	for i := range 10 {
+		require.NoError(t, q.Push(fmt.Appendf(nil, "record-%d", i)))
+	}
+	for range 10 {
+		_, _, err := q.Peek()
+		require.NoError(t, err)
+		require.NoError(t, q.Ack())
+	}
+	require.NoError(t, q.Close())
+
+	// restarting twice, so the drained segments are removed but the cursor file is kept
+	for range 2 {
+		q, err = OpenQueue(dir, 1024, 64, nil)
+		require.NoError(t, err)
+		items, _ := q.Len()
+		require.Zero(t, items)
+		require.NoError(t, q.Close())
+	}
+
+	q, err = OpenQueue(dir, 1024, 64, nil)
+	require.NoError(t, err)
+	for i := range 5 {
+		require.NoError(t, q.Push(fmt.Appendf(nil, "new-%d", i)))
+	}
+	require.NoError(t, q.Close())
+
+	// the records pushed after the drain must not be discarded as consumed
+	for range 2 {
+		q, err = OpenQueue(dir, 1024, 64, nil)
+		require.NoError(t, err)
+		items, _ := q.Len()
+		require.Equal(t, 5, items)
+		require.NoError(t, q.Close())
+	}
+
+	q, err = OpenQueue(dir, 1024, 64, nil)
+	require.NoError(t, err)
+	defer q.Close()
+	for i := range 5 {
+		data, ok, err := q.Peek()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, fmt.Sprintf("new-%d", i), string(data))
+		require.NoError(t, q.Ack())
+	}
+}
+
+func TestQueue_DropOldestWhenFull(t *testing.T) {
+	dropped := 0
+	// each record takes 16 bytes, so each segment fits 2 records, and the queue fits 3 segments
+	q, err := OpenQueue(t.TempDir(), 96, 32, func(records int) { dropped += records })
+	require.NoError(t, err)
+	defer q.Close()
+
+	for i := range 8 {
+		require.NoError(t, q.Push(fmt.Appendf(nil, "rec%05d", i)))
+	}
+	assert.Equal(t, 2, dropped)
+	items, size := q.Len()
+	assert.Equal(t, 6, items)
+	assert.EqualValues(t, 96, size)
+
+	data, ok, err := q.Peek()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "rec00002", string(data))
+
+	// the segment of the peeked record is dropped before the record is acknowledged
+	require.NoError(t, q.Push([]byte("rec00008")))
+	assert.Equal(t, 4, dropped)
+	require.NoError(t, q.Ack())
+	data, _, err = q.Peek()
+	require.NoError(t, err)
+	assert.Equal(t, "rec00004", string(data))
+
+	// records larger than a segment are rejected
+	require.Error(t, q.Push(make([]byte, 32)))
+	assert.Equal(t, 5, dropped)
+}
+
+func TestQueue_SegmentsAreRemoved(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenQueue(dir, 1024, 32, nil)
+	require.NoError(t, err)
+	defer q.Close()
+	for i := range 6 {
+		require.NoError(t, q.Push(fmt.Appendf(nil, "rec%05d", i)))
+	}
+	assert.Len(t, segmentFiles(t, dir), 3)
+	for range 4 {
+		_, _, err := q.Peek()
+		require.NoError(t, err)
+		require.NoError(t, q.Ack())
+	}
+	assert.Len(t, segmentFiles(t, dir), 1)
+}
+
+func TestQueue_TruncatedRecord(t *testing.T) {
+	dir := t.TempDir()
+	q, err := OpenQueue(dir, 1024, 1024, nil)
+	require.NoError(t, err)
+	require.NoError(t, q.Push([]byte("complete")))
+	require.NoError(t, q.Push([]byte("truncated")))
+	require.NoError(t, q.Close())
+
+	// simulate a partial write of the last record
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 1)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	require.NoError(t, os.Truncate(files[0], info.Size()-3))
+
+	q, err = OpenQueue(dir, 1024, 1024, nil)
+	require.NoError(t, err)
+	defer q.Close()
+	items, _ := q.Len()
+	assert.Equal(t, 1, items)
+
+	require.NoError(t, q.Push([]byte("appended")))
+	for _, expected := range []string{"complete", "appended"} {
+		data, ok, err := q.Peek()
+		require.NoError(t, err)
+		require.True(t, ok)
+		assert.Equal(t, expected, string(data))
+		require.NoError(t, q.Ack())
+	}
+}
+
+func TestQueue_UnreadableSegment(t *testing.T) {
+	dir := t.TempDir()
+	dropped := 0
+	q, err := OpenQueue(dir, 1024, 32, func(records int) { dropped += records })
+	require.NoError(t, err)
+	defer q.Close()
+	for i := range 4 {
+		require.NoError(t, q.Push(fmt.Appendf(nil, "rec%05d", i)))
+	}
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 2)
+	require.NoError(t, os.Remove(files[0]))
+
+	// the segment that can't be open is discarded, so the next Peek continues with the next segment
+	_, _, err = q.Peek()
+	require.Error(t, err)
+	assert.Equal(t, 2, dropped)
+	data, ok, err := q.Peek()
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "rec00002", string(data))
+	items, _ := q.Len()
+	assert.Equal(t, 2, items)
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+segmentExt))
+	require.NoError(t, err)
+	return files
+}
@@ -42,6 +42,8 @@ type Expirer[Record any, Metric removableMetric[ValType], ValType any] struct {
 	clock          expire.Clock
 	lastExpiration time.Time
 	ttl            time.Duration
+
+	onRemove func(attribute.Set)
 }
 
 // NewExpirer creates an expirer that wraps data points of a given type. Its labeled instances are dropped
@@ -71,6 +73,13 @@ func NewExpirer[Record any, Metric removableMetric[ValType], ValType any](
 	return &exp
 }
 
+// OnRemove sets a function that is invoked with the attributes of each metric instance
+// that is removed, either because it expired or because all the metrics were removed.
+func (ex *Expirer[Record, Metric, ValType]) OnRemove(fn func(attribute.Set)) *Expirer[Record, Metric, ValType] {
+	ex.onRemove = fn
+	return ex
+}
+
 // ForRecord returns the data point for the given eBPF record. If that record
 // is accessed for the first time, a new data point is created.
 // If not, a cached copy is returned and the "last access" cache time is updated.
@@ -118,6 +127,9 @@ func (ex *Expirer[Record, Metric, ValType]) deleteMetricInstance(ctx context.Con
 		ex.logger(attrs).Debug("deleting old OTEL metric")
 	}
 	ex.metric.Remove(ctx, metric.WithAttributeSet(attrs))
+	if ex.onRemove != nil {
+		ex.onRemove(attrs)
+	}
 }
 
 func (ex *Expirer[Record, Metric, ValType]) logger(attrs attribute.Set) *slog.Logger {
@@ -190,7 +190,7 @@ func TestAppMetricsExpiration_ByMetricAttrs(t *testing.T) {
 			ExtraGroupAttributesCfg: map[string][]attr.Name{
 				"k8s_app_meta": {"k8s.app.version"},
 			},
-		}, request.UnresolvedNames{}, metrics, processEvents)(ctx)
+		}, nil, request.UnresolvedNames{}, metrics, processEvents, nil)(ctx)
 	require.NoError(t, err)
 
 	go otelExporter(ctx)
@@ -332,7 +332,7 @@ func TestAppMetricsExpiration_BySvcID(t *testing.T) {
 					Include: []string{"url.path"},
 				},
 			},
-		}, request.UnresolvedNames{}, metrics, processEvents)(ctx)
+		}, nil, request.UnresolvedNames{}, metrics, processEvents, nil)(ctx)
 	require.NoError(t, err)
 
 	go otelExporter(ctx)
@@ -10,6 +10,12 @@ import "go.opentelemetry.io/otel/attribute"
 // limit.
 var overflowSet = attribute.NewSet(attribute.Bool("otel.metric.overflow", true))
 
+// limitConfig defines the aggregation limit of the aggregators.
+type limitConfig struct {
+	aggregation int
+	onOverflow  func()
+}
+
 // limiter limits aggregate values.
 type limiter[V any] struct {
 	// aggLimit is the maximum number of metric streams that can be aggregated.
@@ -19,11 +25,22 @@ type limiter[V any] struct {
 	// into an "overflow" metric stream. That stream will only contain the
 	// "otel.metric.overflow"=true attribute.
 	aggLimit int
+	// onOverflow is invoked, if not nil, the first time a measurement for each
+	// distinct attribute set is aggregated into the "overflow" metric stream.
+	onOverflow func()
+	// rejected stores the attribute sets that have been already aggregated into
+	// the "overflow" metric stream, so onOverflow is invoked once per set. It is
+	// bounded to aggLimit entries, and reset once it is full.
+	rejected map[attribute.Distinct]struct{}
 }
 
 // newLimiter returns a new Limiter with the provided aggregation limit.
-func newLimiter[V any](aggregation int) limiter[V] {
-	return limiter[V]{aggLimit: aggregation}
+func newLimiter[V any](cfg limitConfig) limiter[V] {
+	l := limiter[V]{aggLimit: cfg.aggregation, onOverflow: cfg.onOverflow}
+	if l.aggLimit > 0 && l.onOverflow != nil {
+		l.rejected = map[attribute.Distinct]struct{}{}
+	}
+	return l
 }
 
 // Attributes checks if adding a measurement for attrs will exceed the
@@ -34,9 +51,25 @@ func (l limiter[V]) Attributes(attrs attribute.Set, measurements map[attribute.D
 	if l.aggLimit > 0 {
 		_, exists := measurements[attrs.Equivalent()]
 		if !exists && len(measurements) >= l.aggLimit-1 {
+			if l.rejected != nil {
+				l.reject(attrs.Equivalent())
+			}
 			return overflowSet
 		}
 	}
 
 	return attrs
 }
+
+// reject invokes onOverflow if the attribute set had not been rejected before.
+// It must be invoked with the aggregator values locked.
+func (l limiter[V]) reject(attrs attribute.Distinct) {
+	if _, ok := l.rejected[attrs]; ok {
+		return
+	}
+	if len(l.rejected) >= l.aggLimit {
+		clear(l.rejected)
+	}
+	l.rejected[attrs] = struct{}{}
+	l.onOverflow()
+}
@@ -13,9 +13,28 @@ import (
 
 // config contains configuration options for a MeterProvider.
 type config struct {
-	res     *resource.Resource
-	readers []Reader
-	views   []View
+	res       *resource.Resource
+	readers   []Reader
+	views     []View
+	overrides []Override
+	limits    cardinalityLimits
+	// exemplarFilter overrides the OTEL_METRICS_EXEMPLAR_FILTER environment variable, if not empty
+	exemplarFilter string
+}
+
+// cardinalityLimits defines the aggregation limits of the instruments.
+type cardinalityLimits struct {
+	limit      func(instrument string) int
+	onOverflow func(instrument string)
+}
+
+// limitFor returns the aggregation limit for the given instrument name, or
+// zero if there is no limit.
+func (cl cardinalityLimits) limitFor(instrument string) int {
+	if cl.limit == nil {
+		return 0
+	}
+	return cl.limit(instrument)
 }
 
 // readerSignals returns a force-flush and shutdown function for a
@@ -135,3 +154,47 @@ func WithView(views ...View) Option {
 		return cfg
 	})
 }
+
+// WithOverride associates overrides with a MeterProvider. The overrides are
+// applied, in order, to the Streams resolved by the views.
+func WithOverride(overrides ...Override) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.overrides = append(cfg.overrides, overrides...)
+		return cfg
+	})
+}
+
+// WithCardinalityLimit sets the function that returns the maximum number of
+// distinct attribute sets that each instrument, given its name, can aggregate.
+// Measurements for new attribute sets after reaching the limit are aggregated
+// into a single "otel.metric.overflow"=true attribute set.
+//
+// A value less than or equal to zero falls back to the experimental
+// OTEL_GO_X_CARDINALITY_LIMIT environment variable, if set. Otherwise, the
+// attribute sets are unlimited.
+func WithCardinalityLimit(limit func(instrument string) int) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.limits.limit = limit
+		return cfg
+	})
+}
+
+// WithCardinalityOverflowHandler sets a function that is invoked, with the
+// instrument name, the first time a measurement for each new attribute set is
+// aggregated into the overflow attribute set.
+func WithCardinalityOverflowHandler(onOverflow func(instrument string)) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.limits.onOverflow = onOverflow
+		return cfg
+	})
+}
+
+// WithExemplarFilter sets the exemplar filter of all the instruments. Accepted
+// values are "always_on", "always_off" and "trace_based". It takes precedence
+// over the OTEL_METRICS_EXEMPLAR_FILTER environment variable.
+func WithExemplarFilter(filter string) Option {
+	return optionFunc(func(cfg config) config {
+		cfg.exemplarFilter = filter
+		return cfg
+	})
+}
@@ -17,16 +17,21 @@ import (
 // creation func based on the passed InstrumentKind and user defined
 // environment variables.
 //
+// The filterName is the exemplar filter configured with WithExemplarFilter. If
+// it is empty, the OTEL_METRICS_EXEMPLAR_FILTER environment variable is used.
+//
 // Note: This will only return non-nil values when the experimental exemplar
-// feature is enabled and the OTEL_METRICS_EXEMPLAR_FILTER environment variable
-// is not set to always_off.
-func reservoirFunc[N int64 | float64](agg sdkmetric.Aggregation) func() exemplar.FilteredReservoir[N] {
+// feature is enabled and the exemplar filter is not set to always_off.
+func reservoirFunc[N int64 | float64](agg sdkmetric.Aggregation, filterName string) func() exemplar.FilteredReservoir[N] {
 	// https://github.com/open-telemetry/opentelemetry-specification/blob/d4b241f451674e8f611bb589477680341006ad2b/specification/configuration/sdk-environment-variables.md#exemplar
 	const filterEnvKey = "OTEL_METRICS_EXEMPLAR_FILTER"
 
 	var filter exemplar.Filter
 
-	switch os.Getenv(filterEnvKey) {
+	if filterName == "" {
+		filterName = os.Getenv(filterEnvKey)
+	}
+	switch filterName {
 	case "always_on":
 		filter = exemplar.AlwaysOnFilter
 	case "always_off":
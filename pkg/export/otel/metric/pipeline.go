@@ -40,14 +40,17 @@ type instrumentSync struct {
 	compAgg     aggregate.ComputeAggregation
 }
 
-func newPipeline(res *resource.Resource, reader Reader, views []View) *pipeline {
+func newPipeline(res *resource.Resource, reader Reader, views []View, overrides []Override, limits cardinalityLimits, exemplarFilter string) *pipeline {
 	if res == nil {
 		res = resource.Empty()
 	}
 	return &pipeline{
-		resource: res,
-		reader:   reader,
-		views:    views,
+		resource:       res,
+		reader:         reader,
+		views:          views,
+		overrides:      overrides,
+		limits:         limits,
+		exemplarFilter: exemplarFilter,
 		// aggregations is lazy allocated when needed.
 	}
 }
@@ -61,8 +64,11 @@ func newPipeline(res *resource.Resource, reader Reader, views []View) *pipeline
 type pipeline struct {
 	resource *resource.Resource
 
-	reader Reader
-	views  []View
+	reader         Reader
+	views          []View
+	overrides      []Override
+	limits         cardinalityLimits
+	exemplarFilter string
 
 	sync.Mutex
 	aggregations   map[instrumentation.Scope][]instrumentSync
@@ -230,6 +236,7 @@ func (i *inserter[N]) Instrument(inst Instrument, readerAggregation sdkmetric.Ag
 			continue
 		}
 		matched = true
+		stream = i.pipeline.override(inst, stream)
 		in, rem, id, err := i.cachedAggregator(inst.Scope, inst.Kind, stream, readerAggregation)
 		if err != nil {
 			errs.append(err)
@@ -256,6 +263,7 @@ func (i *inserter[N]) Instrument(inst Instrument, readerAggregation sdkmetric.Ag
 		Description: inst.Description,
 		Unit:        inst.Unit,
 	}
+	stream = i.pipeline.override(inst, stream)
 	in, rem, _, err := i.cachedAggregator(inst.Scope, inst.Kind, stream, readerAggregation)
 	if err != nil {
 		errs.append(err)
@@ -271,6 +279,14 @@ func (i *inserter[N]) Instrument(inst Instrument, readerAggregation sdkmetric.Ag
 	return measures, removers, errs.errorOrNil()
 }
 
+// override applies the pipeline overrides to the stream of the instrument.
+func (p *pipeline) override(inst Instrument, stream Stream) Stream {
+	for _, o := range p.overrides {
+		stream = o(inst, stream)
+	}
+	return stream
+}
+
 // addCallback registers a single instrument callback to be run when
 // `produce()` is called.
 func (i *inserter[N]) addCallback(cback func(context.Context) error) {
@@ -347,7 +363,7 @@ func (i *inserter[N]) cachedAggregator(scope instrumentation.Scope, kind Instrum
 	cv := i.aggregators.Lookup(normID, func() aggVal[N] {
 		b := aggregate.Builder[N]{
 			Temporality:   i.pipeline.reader.temporality(kind),
-			ReservoirFunc: reservoirFunc[N](stream.Aggregation),
+			ReservoirFunc: reservoirFunc[N](stream.Aggregation, i.pipeline.exemplarFilter),
 		}
 		b.Filter = stream.AttributeFilter
 		// A value less than or equal to zero will disable the aggregation
@@ -355,6 +371,13 @@ func (i *inserter[N]) cachedAggregator(scope instrumentation.Scope, kind Instrum
 		// CardinalityLimit.Lookup returns 0 by default if unset (or
 		// unrecognized input). Use that value directly.
 		b.AggregationLimit, _ = x.CardinalityLimit.Lookup()
+		if limit := i.pipeline.limits.limitFor(stream.Name); limit > 0 {
+			b.AggregationLimit = limit
+		}
+		if onOverflow := i.pipeline.limits.onOverflow; onOverflow != nil {
+			name := stream.Name
+			b.OverflowFunc = func() { onOverflow(name) }
+		}
 
 		in, remove, out, err := i.aggregateFunc(b, stream.Aggregation, kind)
 		if err != nil {
@@ -551,10 +574,10 @@ func isAggregatorCompatible(kind InstrumentKind, agg sdkmetric.Aggregation) erro
 // measurement.
 type pipelines []*pipeline
 
-func newPipelines(res *resource.Resource, readers []Reader, views []View) pipelines {
+func newPipelines(res *resource.Resource, readers []Reader, views []View, overrides []Override, limits cardinalityLimits, exemplarFilter string) pipelines {
 	pipes := make([]*pipeline, 0, len(readers))
 	for _, r := range readers {
-		p := newPipeline(res, r, views)
+		p := newPipeline(res, r, views, overrides, limits, exemplarFilter)
 		r.register(p)
 		pipes = append(pipes, p)
 	}
@@ -8,8 +8,10 @@ import (
 	"regexp"
 	"strings"
 
+	"go.opentelemetry.io/otel/attribute"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 
+	"go.opentelemetry.io/obi/pkg/export"
 	"go.opentelemetry.io/obi/pkg/export/otel/metric/global"
 )
 
@@ -101,6 +103,65 @@ func NewView(criteria Instrument, mask Stream) View {
 	}
 }
 
+// Override modifies the Stream of the matching instruments, as resolved by the Views (or
+// the default Stream if no View matches). Unlike Views, Overrides don't create additional
+// Streams, so they don't conflict with other Views matching the same instrument.
+type Override func(Instrument, Stream) Stream
+
+// NewOverride returns an Override that applies the user-provided view configuration
+// to the instruments whose name matches the view instrument.
+func NewOverride(view *export.MetricView) Override {
+	if view.Rename != "" && !view.IsRename() {
+		global.Error(
+			errMultiInst, "ignoring view rename",
+			"instrument", view.Instrument,
+			"rename", view.Rename,
+		)
+	}
+	var allowKeys attribute.Filter
+	if len(view.AttributeKeys) > 0 {
+		keys := make([]attribute.Key, 0, len(view.AttributeKeys))
+		for _, k := range view.AttributeKeys {
+			keys = append(keys, attribute.Key(k))
+		}
+		allowKeys = attribute.NewAllowKeysFilter(keys...)
+	}
+	return func(i Instrument, s Stream) Stream {
+		if !view.Matches(i.Name) {
+			return s
+		}
+		if view.Drop {
+			s.Aggregation = sdkmetric.AggregationDrop{}
+			return s
+		}
+		if view.IsRename() {
+			s.Name = view.Rename
+		}
+		if allowKeys != nil {
+			if filter := s.AttributeFilter; filter != nil {
+				s.AttributeFilter = func(kv attribute.KeyValue) bool {
+					return filter(kv) && allowKeys(kv)
+				}
+			} else {
+				s.AttributeFilter = allowKeys
+			}
+		}
+		if i.Kind == InstrumentKindHistogram {
+			if view.ExponentialScale != nil {
+				s.Aggregation = sdkmetric.AggregationBase2ExponentialHistogram{
+					MaxScale: *view.ExponentialScale,
+					MaxSize:  160,
+				}
+			} else if len(view.Buckets) > 0 {
+				s.Aggregation = sdkmetric.AggregationExplicitBucketHistogram{
+					Boundaries: view.Buckets,
+				}
+			}
+		}
+		return s
+	}
+}
+
 // nonZero returns v if it is non-zero-valued, otherwise alt.
 func nonZero[T comparable](v, alt T) T {
 	var zero T
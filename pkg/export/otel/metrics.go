@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/instrumentation"
@@ -28,6 +29,8 @@ import (
 	instrument "go.opentelemetry.io/obi/pkg/export/otel/metric/api/metric"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/export/slo"
+	"go.opentelemetry.io/obi/pkg/internal/process"
 	"go.opentelemetry.io/obi/pkg/pipe/global"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
 	"go.opentelemetry.io/obi/pkg/pipe/swarm"
@@ -74,6 +77,7 @@ type MetricsReporter struct {
 	nodeMeta         meta.NodeMeta
 	attributes       *attributes.AttrSelector
 	exporter         sdkmetric.Exporter
+	internalMetrics  imetrics.Reporter
 	reporters        otelcfg.ReporterPool[*svc.Attrs, *Metrics]
 	hostInfo         *Expirer[*request.Span, instrument.Int64Gauge, int64]
 	targetInfo       instrument.Int64UpDownCounter
@@ -90,8 +94,13 @@ type MetricsReporter struct {
 	attrGRPCServer             []attributes.Field[*request.Span, attribute.KeyValue]
 	attrGRPCClient             []attributes.Field[*request.Span, attribute.KeyValue]
 	attrDBClient               []attributes.Field[*request.Span, attribute.KeyValue]
+	attrDBServer               []attributes.Field[*request.Span, attribute.KeyValue]
 	attrMessagingPublish       []attributes.Field[*request.Span, attribute.KeyValue]
 	attrMessagingProcess       []attributes.Field[*request.Span, attribute.KeyValue]
+	attrMessagingSent          []attributes.Field[*request.Span, attribute.KeyValue]
+	attrMessagingSentBytes     []attributes.Field[*request.Span, attribute.KeyValue]
+	attrMessagingConsumed      []attributes.Field[*request.Span, attribute.KeyValue]
+	attrMessagingConsumedBytes []attributes.Field[*request.Span, attribute.KeyValue]
 	attrHTTPRequestSize        []attributes.Field[*request.Span, attribute.KeyValue]
 	attrHTTPResponseSize       []attributes.Field[*request.Span, attribute.KeyValue]
 	attrHTTPClientRequestSize  []attributes.Field[*request.Span, attribute.KeyValue]
@@ -103,13 +112,24 @@ type MetricsReporter struct {
 	attrGPUMemoryAllocations   []attributes.Field[*request.Span, attribute.KeyValue]
 	attrGPUMemoryCopies        []attributes.Field[*request.Span, attribute.KeyValue]
 	attrDNSLookupDuration      []attributes.Field[*request.Span, attribute.KeyValue]
+	attrDNSLookupFailures      []attributes.Field[*request.Span, attribute.KeyValue]
+	attrConnectionFailures     []attributes.Field[*request.Span, attribute.KeyValue]
 	attrGenAIInputTokenUsage   []attributes.Field[*request.Span, attribute.KeyValue]
 	attrGenAIOutputTokenUsage  []attributes.Field[*request.Span, attribute.KeyValue]
 	attrGenAIClientDuration    []attributes.Field[*request.Span, attribute.KeyValue]
+	attrHTTPActiveRequests     []attributes.Field[*request.Span, attribute.KeyValue]
+	attrHTTPClientActive       []attributes.Field[*request.Span, attribute.KeyValue]
+
+	// accounts the requests that are reported in-flight until they finish
+	activeRequests *ActiveRequestsTracker[activeRequestsCounter]
+
+	// evaluates the spans against the user-defined SLOs. Nil if no SLO is defined.
+	slo *slo.Tracker
 
 	userAttribSelection attributes.Selection
 	input               <-chan []request.Span
 	processEvents       <-chan exec.ProcessEvent
+	processStatuses     <-chan []*process.Status
 
 	log *slog.Logger
 
@@ -131,12 +151,20 @@ type Metrics struct {
 	grpcDuration           *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	grpcClientDuration     *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	dbClientDuration       *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	dbServerDuration       *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	msgPublishDuration     *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	msgProcessDuration     *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	msgSentMessages        *Expirer[*request.Span, instrument.Int64Counter, int64]
+	msgSentBytes           *Expirer[*request.Span, instrument.Int64Counter, int64]
+	msgConsumedMessages    *Expirer[*request.Span, instrument.Int64Counter, int64]
+	msgConsumedBytes       *Expirer[*request.Span, instrument.Int64Counter, int64]
 	httpRequestSize        *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	httpResponseSize       *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	httpClientRequestSize  *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	httpClientResponseSize *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	// in-flight requests
+	httpActiveRequests       *Expirer[*request.Span, instrument.Int64UpDownCounter, int64]
+	httpClientActiveRequests *Expirer[*request.Span, instrument.Int64UpDownCounter, int64]
 	// trace span metrics
 	spanMetricsLatency           *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	spanMetricsCallsTotal        *Expirer[*request.Span, instrument.Int64Counter, int64]
@@ -151,10 +179,31 @@ type Metrics struct {
 	gpuMemoryCopySize    *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	// dns
 	dnsLookupDuration *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	dnsLookupFailures *Expirer[*request.Span, instrument.Int64Counter, int64]
+	// failed connections
+	connectionFailures *Expirer[*request.Span, instrument.Int64Counter, int64]
 	// genai
 	genAIInputTokenUsage  *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	genAIOutputTokenUsage *Expirer[*request.Span, instrument.Float64Histogram, float64]
 	genAIClientDuration   *Expirer[*request.Span, instrument.Float64Histogram, float64]
+	// slo
+	sloEvents     *Expirer[*request.Span, instrument.Int64Counter, int64]
+	sloGoodEvents *Expirer[*request.Span, instrument.Int64Counter, int64]
+	sloApdex      instrument.Registration
+	// process
+	procCPUTime       *Expirer[*process.Status, instrument.Float64Counter, float64]
+	procDiskIO        *Expirer[*process.Status, instrument.Int64Counter, int64]
+	procMemoryUsage   *procUpDownCounter
+	procMemoryVirtual *procUpDownCounter
+	procOpenFDs       *procUpDownCounter
+	procThreads       *procUpDownCounter
+}
+
+// activeRequestsCounter keeps the counter and attributes where an in-flight request
+// was accounted, to discount it from the same metric when it finishes
+type activeRequestsCounter struct {
+	counter instrument.Int64UpDownCounter
+	attrs   attribute.Set
 }
 
 type TargetMetrics struct {
@@ -167,12 +216,15 @@ func ReportMetrics(
 	cfg *otelcfg.MetricsConfig,
 	jointMetricsCfg *perapp.MetricsConfig,
 	selectorCfg *attributes.SelectorConfig,
+	sloCfg *slo.Config,
 	unresolved request.UnresolvedNames,
 	input *msg.Queue[[]request.Span],
 	processEventCh *msg.Queue[exec.ProcessEvent],
+	processStatuses *msg.Queue[[]*process.Status],
 ) swarm.InstanceFunc {
 	return func(ctx context.Context) (swarm.RunFunc, error) {
-		if !(cfg.EndpointEnabled() && jointMetricsCfg.Features.AppOrSpan()) {
+		if !cfg.EndpointEnabled() ||
+			!(jointMetricsCfg.Features.AppOrSpan() || jointMetricsCfg.Features.ProcessMetrics()) {
 			return swarm.EmptyRunFunc()
 		}
 		otelcfg.SetupInternalOTELSDKLogger(cfg.SDKLogLevel)
@@ -183,9 +235,11 @@ func ReportMetrics(
 			cfg,
 			jointMetricsCfg,
 			selectorCfg,
+			sloCfg,
 			unresolved,
 			input,
 			processEventCh,
+			processStatuses,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("instantiating OTEL metrics reporter: %w", err)
@@ -201,9 +255,11 @@ func newMetricsReporter(
 	cfg *otelcfg.MetricsConfig,
 	jointMetricsCfg *perapp.MetricsConfig,
 	selectorCfg *attributes.SelectorConfig,
+	sloCfg *slo.Config,
 	unresolved request.UnresolvedNames,
 	input *msg.Queue[[]request.Span],
 	processEventCh *msg.Queue[exec.ProcessEvent],
+	processStatuses *msg.Queue[[]*process.Status],
 ) (*MetricsReporter, error) {
 	log := mlog()
 
@@ -222,6 +278,7 @@ func newMetricsReporter(
 		targetMetrics:       map[svc.UID]*TargetMetrics{},
 		attributes:          attribProvider,
 		nodeMeta:            ctxInfo.NodeMeta,
+		internalMetrics:     ctxInfo.Metrics,
 		input:               input.Subscribe(msg.SubscriberName("otelMetrics.InputSpans")),
 		processEvents:       processEventCh.Subscribe(msg.SubscriberName("otelMetrics.ProcessEvents")),
 		userAttribSelection: selectorCfg.SelectionCfg,
@@ -234,6 +291,11 @@ func newMetricsReporter(
 		mr.spanExtraAttrs = append(mr.spanExtraAttrs, attr.Name(label))
 	}
 
+	// the process statuses are only provided if the process metrics feature is enabled
+	if processStatuses != nil {
+		mr.processStatuses = processStatuses.Subscribe(msg.SubscriberName("otelMetrics.ProcessStatuses"))
+	}
+
 	mr.createEventMetrics = mr.createTargetMetricData
 	mr.deleteEventMetrics = mr.deleteTargetMetricData
 
@@ -251,6 +313,10 @@ func newMetricsReporter(
 			mr.attrGetters, mr.attributes.For(attributes.HTTPClientRequestSize))
 		mr.attrHTTPClientResponseSize = attributes.OpenTelemetryGetters(
 			mr.attrGetters, mr.attributes.For(attributes.HTTPClientResponseSize))
+		mr.attrHTTPActiveRequests = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.HTTPServerActiveRequests))
+		mr.attrHTTPClientActive = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.HTTPClientActiveRequests))
 	}
 
 	if is.GRPCEnabled() {
@@ -260,9 +326,17 @@ func newMetricsReporter(
 			mr.attrGetters, mr.attributes.For(attributes.RPCClientDuration))
 	}
 
+	mr.activeRequests = NewActiveRequestsTracker[activeRequestsCounter](ActiveRequestsTimeout)
+
+	if sloCfg != nil && sloCfg.Enabled() && jointMetricsCfg.Features.AppRED() {
+		mr.slo = slo.NewTracker(sloCfg, timeNow)
+	}
+
 	if is.DBEnabled() {
 		mr.attrDBClient = attributes.OpenTelemetryGetters(
 			mr.attrGetters, mr.attributes.For(attributes.DBClientDuration))
+		mr.attrDBServer = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.DBServerDuration))
 	}
 
 	if is.MQEnabled() {
@@ -270,6 +344,14 @@ func newMetricsReporter(
 			mr.attrGetters, mr.attributes.For(attributes.MessagingPublishDuration))
 		mr.attrMessagingProcess = attributes.OpenTelemetryGetters(
 			mr.attrGetters, mr.attributes.For(attributes.MessagingProcessDuration))
+		mr.attrMessagingSent = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.MessagingSentMessages))
+		mr.attrMessagingSentBytes = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.MessagingSentBytes))
+		mr.attrMessagingConsumed = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.MessagingConsumedMessages))
+		mr.attrMessagingConsumedBytes = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.MessagingConsumedBytes))
 	}
 
 	if is.GPUEnabled() {
@@ -290,8 +372,14 @@ func newMetricsReporter(
 	if is.DNSEnabled() {
 		mr.attrDNSLookupDuration = attributes.OpenTelemetryGetters(
 			mr.attrGetters, mr.attributes.For(attributes.DNSLookupDuration))
+		mr.attrDNSLookupFailures = attributes.OpenTelemetryGetters(
+			mr.attrGetters, mr.attributes.For(attributes.DNSLookupFailures))
 	}
 
+	// failed connections are not bound to any specific instrumentation
+	mr.attrConnectionFailures = attributes.OpenTelemetryGetters(
+		mr.attrGetters, mr.attributes.For(attributes.NetworkConnectionFailures))
+
 	if is.GenAIEnabled() {
 		mr.attrGenAIInputTokenUsage = attributes.OpenTelemetryGetters(
 			mr.attrGetters, mr.attributes.For(attributes.GenAIClientInputTokenUsage))
@@ -373,6 +461,7 @@ func (mr *MetricsReporter) otelMetricOptions(mlog *slog.Logger) []metric.Option
 	if mr.is.DBEnabled() {
 		opts = append(opts,
 			metric.WithView(otelHistogramConfig(attributes.DBClientDuration.OTEL, mr.cfg.Buckets.DurationHistogram, useExponentialHistograms)),
+			metric.WithView(otelHistogramConfig(attributes.DBServerDuration.OTEL, mr.cfg.Buckets.DurationHistogram, useExponentialHistograms)),
 		)
 	}
 
@@ -476,6 +565,18 @@ func (mr *MetricsReporter) setupOtelMeters(m *Metrics, meter instrument.Meter) e
 		}
 		m.httpClientResponseSize = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
 			m.ctx, httpClientResponseSize, mr.attrHTTPClientResponseSize, timeNow, mr.cfg.TTL)
+
+		httpActiveRequests, err := meter.Int64UpDownCounter(attributes.HTTPServerActiveRequests.OTEL, instrument.WithUnit("{request}"))
+		if err != nil {
+			return fmt.Errorf("creating http active requests metric: %w", err)
+		}
+		m.httpActiveRequests = mr.activeRequestsExpirer(m, httpActiveRequests, mr.attrHTTPActiveRequests)
+
+		httpClientActiveRequests, err := meter.Int64UpDownCounter(attributes.HTTPClientActiveRequests.OTEL, instrument.WithUnit("{request}"))
+		if err != nil {
+			return fmt.Errorf("creating http client active requests metric: %w", err)
+		}
+		m.httpClientActiveRequests = mr.activeRequestsExpirer(m, httpClientActiveRequests, mr.attrHTTPClientActive)
 	}
 
 	if mr.is.GRPCEnabled() {
@@ -492,6 +593,7 @@ func (mr *MetricsReporter) setupOtelMeters(m *Metrics, meter instrument.Meter) e
 		}
 		m.grpcClientDuration = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
 			m.ctx, grpcClientDuration, mr.attrGRPCClient, timeNow, mr.cfg.TTL)
+
 	}
 
 	if mr.is.DBEnabled() {
@@ -501,6 +603,13 @@ func (mr *MetricsReporter) setupOtelMeters(m *Metrics, meter instrument.Meter) e
 		}
 		m.dbClientDuration = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
 			m.ctx, dbClientDuration, mr.attrDBClient, timeNow, mr.cfg.TTL)
+
+		dbServerDuration, err := meter.Float64Histogram(attributes.DBServerDuration.OTEL, instrument.WithUnit("s"))
+		if err != nil {
+			return fmt.Errorf("creating db server duration histogram metric: %w", err)
+		}
+		m.dbServerDuration = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
+			m.ctx, dbServerDuration, mr.attrDBServer, timeNow, mr.cfg.TTL)
 	}
 
 	if mr.is.MQEnabled() {
@@ -517,6 +626,34 @@ func (mr *MetricsReporter) setupOtelMeters(m *Metrics, meter instrument.Meter) e
 		}
 		m.msgProcessDuration = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
 			m.ctx, msgProcessDuration, mr.attrMessagingProcess, timeNow, mr.cfg.TTL)
+
+		msgSentMessages, err := meter.Int64Counter(attributes.MessagingSentMessages.OTEL, instrument.WithUnit("{message}"))
+		if err != nil {
+			return fmt.Errorf("creating messaging client sent messages metric: %w", err)
+		}
+		m.msgSentMessages = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+			m.ctx, msgSentMessages, mr.attrMessagingSent, timeNow, mr.cfg.TTL)
+
+		msgSentBytes, err := meter.Int64Counter(attributes.MessagingSentBytes.OTEL, instrument.WithUnit("By"))
+		if err != nil {
+			return fmt.Errorf("creating messaging client sent bytes metric: %w", err)
+		}
+		m.msgSentBytes = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+			m.ctx, msgSentBytes, mr.attrMessagingSentBytes, timeNow, mr.cfg.TTL)
+
+		msgConsumedMessages, err := meter.Int64Counter(attributes.MessagingConsumedMessages.OTEL, instrument.WithUnit("{message}"))
+		if err != nil {
+			return fmt.Errorf("creating messaging client consumed messages metric: %w", err)
+		}
+		m.msgConsumedMessages = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+			m.ctx, msgConsumedMessages, mr.attrMessagingConsumed, timeNow, mr.cfg.TTL)
+
+		msgConsumedBytes, err := meter.Int64Counter(attributes.MessagingConsumedBytes.OTEL, instrument.WithUnit("By"))
+		if err != nil {
+			return fmt.Errorf("creating messaging client consumed bytes metric: %w", err)
+		}
+		m.msgConsumedBytes = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+			m.ctx, msgConsumedBytes, mr.attrMessagingConsumedBytes, timeNow, mr.cfg.TTL)
 	}
 
 	if mr.is.GPUEnabled() {
@@ -570,7 +707,21 @@ func (mr *MetricsReporter) setupOtelMeters(m *Metrics, meter instrument.Meter) e
 		}
 		m.dnsLookupDuration = NewExpirer[*request.Span, instrument.Float64Histogram, float64](
 			m.ctx, dnsLookupDuration, mr.attrDNSLookupDuration, timeNow, mr.cfg.TTL)
+
+		dnsLookupFailures, err := meter.Int64Counter(attributes.DNSLookupFailures.OTEL, instrument.WithUnit("{failure}"))
+		if err != nil {
+			return fmt.Errorf("creating dns lookup failures counter: %w", err)
+		}
+		m.dnsLookupFailures = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+			m.ctx, dnsLookupFailures, mr.attrDNSLookupFailures, timeNow, mr.cfg.TTL)
+	}
+
+	connectionFailures, err := meter.Int64Counter(attributes.NetworkConnectionFailures.OTEL, instrument.WithUnit("{failure}"))
+	if err != nil {
+		return fmt.Errorf("creating connection failures counter: %w", err)
 	}
+	m.connectionFailures = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+		m.ctx, connectionFailures, mr.attrConnectionFailures, timeNow, mr.cfg.TTL)
 
 	if mr.is.GenAIEnabled() {
 		genAIClientDuration, err := meter.Float64Histogram(attributes.GenAIClientOperationDuration.OTEL, instrument.WithUnit("s"))
@@ -704,10 +855,12 @@ func (mr *MetricsReporter) newMetricsInstance(service *svc.Attrs) Metrics {
 		metric.WithResource(resources),
 		metric.WithReader(metric.NewPeriodicReader(mr.exporter,
 			metric.WithInterval(mr.cfg.Interval))),
+		metric.WithExemplarFilter(mr.cfg.ExemplarFilter),
 	}
 
 	opts = append(opts, mr.otelMetricOptions(mlog)...)
 	opts = append(opts, mr.spanMetricOptions(mlog)...)
+	opts = append(opts, meterProviderOptions(mr.cfg, mr.internalMetrics)...)
 
 	return Metrics{
 		ctx:     mr.ctx,
@@ -749,6 +902,20 @@ func (mr *MetricsReporter) newMetricSet(service *svc.Attrs) (*Metrics, error) {
 		}
 	}
 
+	if mr.slo != nil {
+		err = mr.setupSLOMeters(&m, meter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if mr.jointMetricsCfg.Features.ProcessMetrics() {
+		err = mr.setupProcMeters(&m, meter)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &m, nil
 }
 
@@ -772,6 +939,27 @@ func (mr *MetricsReporter) close() {
 	}
 }
 
+// meterProviderOptions returns the MeterProvider options that apply the user-provided views and
+// cardinality limits, reporting the overflowed measurements as internal metrics.
+func meterProviderOptions(cfg *otelcfg.MetricsConfig, internalMetrics imetrics.Reporter) []metric.Option {
+	var opts []metric.Option
+	for i := range cfg.Views {
+		opts = append(opts, metric.WithOverride(metric.NewOverride(&cfg.Views[i])))
+	}
+	if !cfg.CardinalityLimits.Enabled() {
+		return opts
+	}
+	if internalMetrics == nil {
+		internalMetrics = imetrics.NoopReporter{}
+	}
+	return append(opts,
+		metric.WithCardinalityLimit(cfg.CardinalityLimits.For),
+		metric.WithCardinalityOverflowHandler(func(instrument string) {
+			internalMetrics.MetricCardinalityOverflow("otel", instrument)
+		}),
+	)
+}
+
 // instrumentMetricsExporter checks whether the context is configured to report internal metrics and,
 // in this case, wraps the passed metrics exporter inside an instrumented exporter
 func instrumentMetricsExporter(internalMetrics imetrics.Reporter, in sdkmetric.Exporter) sdkmetric.Exporter {
@@ -929,11 +1117,25 @@ func (r *Metrics) record(span *request.Span, mr *MetricsReporter) {
 				httpClientResponseSize, attrs := r.httpClientResponseSize.ForRecord(span)
 				httpClientResponseSize.Record(ctx, float64(span.ResponseBodyLength()), instrument.WithAttributeSet(attrs))
 			}
-		case request.EventTypeRedisServer, request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeMongoClient, request.EventTypeCouchbaseClient, request.EventTypeMemcachedClient, request.EventTypeMemcachedServer:
+		case request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeMongoClient, request.EventTypeCouchbaseClient, request.EventTypeMemcachedClient:
 			if mr.is.DBEnabled() {
 				dbClientDuration, attrs := r.dbClientDuration.ForRecord(span)
 				dbClientDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
 			}
+		case request.EventTypeRedisServer, request.EventTypeMemcachedServer:
+			if mr.is.DBEnabled() {
+				// Redis and Memcached server operations have been historically reported as
+				// db.client.operation.duration, so we keep them there for backwards compatibility
+				dbClientDuration, attrs := r.dbClientDuration.ForRecord(span)
+				dbClientDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
+				dbServerDuration, attrs := r.dbServerDuration.ForRecord(span)
+				dbServerDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
+			}
+		case request.EventTypeSQLServer:
+			if mr.is.DBEnabled() {
+				dbServerDuration, attrs := r.dbServerDuration.ForRecord(span)
+				dbServerDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
+			}
 		case request.EventTypeKafkaClient, request.EventTypeKafkaServer:
 			if mr.is.KafkaEnabled() {
 				switch span.Method {
@@ -944,6 +1146,7 @@ func (r *Metrics) record(span *request.Span, mr *MetricsReporter) {
 					msgProcessDuration, attrs := r.msgProcessDuration.ForRecord(span)
 					msgProcessDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
 				}
+				r.recordMessages(ctx, span)
 			}
 		case request.EventTypeMQTTClient, request.EventTypeMQTTServer:
 			if mr.is.MQTTEnabled() {
@@ -955,6 +1158,7 @@ func (r *Metrics) record(span *request.Span, mr *MetricsReporter) {
 					msgProcessDuration, attrs := r.msgProcessDuration.ForRecord(span)
 					msgProcessDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
 				}
+				r.recordMessages(ctx, span)
 			}
 		case request.EventTypeGPUCudaKernelLaunch:
 			if mr.is.GPUEnabled() {
@@ -986,7 +1190,15 @@ func (r *Metrics) record(span *request.Span, mr *MetricsReporter) {
 			if mr.is.DNSEnabled() {
 				dnsDuration, attrs := r.dnsLookupDuration.ForRecord(span)
 				dnsDuration.Record(ctx, duration, instrument.WithAttributeSet(attrs))
+
+				if span.IsFailedDNSLookup() {
+					dnsFailures, attrs := r.dnsLookupFailures.ForRecord(span)
+					dnsFailures.Add(ctx, 1, instrument.WithAttributeSet(attrs))
+				}
 			}
+		case request.EventTypeFailedConnect:
+			connFailures, attrs := r.connectionFailures.ForRecord(span)
+			connFailures.Add(ctx, 1, instrument.WithAttributeSet(attrs))
 		}
 	}
 
@@ -1068,6 +1280,8 @@ func (mr *MetricsReporter) disassociatePIDFromService(pid app.PID) (bool, svc.UI
 
 func (mr *MetricsReporter) reportMetrics(ctx context.Context) {
 	defer mr.close()
+	expireActive := time.NewTicker(ActiveRequestsTimeout)
+	defer expireActive.Stop()
 	for {
 		select {
 		case <-ctx.Done():
@@ -1085,10 +1299,66 @@ func (mr *MetricsReporter) reportMetrics(ctx context.Context) {
 				return
 			}
 			mr.onSpan(spans)
+		case statuses, ok := <-mr.processStatuses:
+			if !ok {
+				mr.log.Debug("process statuses channel closed, stopping metrics reporting")
+				return
+			}
+			mr.onProcessStatuses(statuses)
+		case <-expireActive.C:
+			mr.expireActiveRequests()
 		}
 	}
 }
 
+// activeRequestsExpirer returns an Expirer whose removed metric instances are also
+// forgotten by the active requests tracker
+func (mr *MetricsReporter) activeRequestsExpirer(
+	m *Metrics, counter instrument.Int64UpDownCounter, attrs []attributes.Field[*request.Span, attribute.KeyValue],
+) *Expirer[*request.Span, instrument.Int64UpDownCounter, int64] {
+	return NewExpirer[*request.Span, instrument.Int64UpDownCounter, int64](
+		m.ctx, counter, attrs, timeNow, mr.cfg.TTL,
+	).OnRemove(func(set attribute.Set) {
+		mr.activeRequests.Forget(activeRequestsCounter{counter: counter, attrs: set})
+	})
+}
+
+// recordInFlight accounts an in-flight request the first time it is reported
+func (r *Metrics) recordInFlight(span *request.Span, mr *MetricsReporter) {
+	if !otelMetricsAccepted(span) || !mr.is.HTTPEnabled() {
+		return
+	}
+	var active *Expirer[*request.Span, instrument.Int64UpDownCounter, int64]
+	switch span.Type {
+	case request.EventTypeHTTP:
+		active = r.httpActiveRequests
+	case request.EventTypeHTTPClient:
+		active = r.httpClientActiveRequests
+	default:
+		return
+	}
+	// accessing the metric on each report keeps it from expiring while the request is active
+	counter, attrs := active.ForRecord(span)
+	if mr.activeRequests.Start(span, activeRequestsCounter{counter: counter, attrs: attrs}) {
+		counter.Add(mr.ctx, 1, instrument.WithAttributeSet(attrs))
+	}
+}
+
+// endActiveRequest discounts a finished request if it was accounted as in-flight
+func (mr *MetricsReporter) endActiveRequest(span *request.Span) {
+	if m, ok := mr.activeRequests.End(span); ok {
+		m.counter.Add(mr.ctx, -1, instrument.WithAttributeSet(m.attrs))
+	}
+}
+
+// expireActiveRequests discounts the requests that aren't reported in-flight anymore,
+// but whose finished span wasn't received
+func (mr *MetricsReporter) expireActiveRequests() {
+	mr.activeRequests.Expire(func(m activeRequestsCounter) {
+		m.counter.Add(mr.ctx, -1, instrument.WithAttributeSet(m.attrs))
+	})
+}
+
 func (mr *MetricsReporter) resourceAttrsForService(service *svc.Attrs) []attribute.KeyValue {
 	attrs := []attribute.KeyValue{
 		attribute.String(string(attr.Instance), service.UID.Instance),
@@ -1209,9 +1479,12 @@ func (mr *MetricsReporter) onProcessEvent(pe *exec.ProcessEvent) {
 func (mr *MetricsReporter) onSpan(spans []request.Span) {
 	for i := range spans {
 		s := &spans[i]
-		if s.InternalSignal() {
+		if s.InternalSignal() && !s.InFlight {
 			continue
 		}
+		if !s.InFlight {
+			mr.endActiveRequest(s)
+		}
 		if !s.Service.ExportModes.CanExportMetrics() {
 			continue
 		}
@@ -1225,7 +1498,14 @@ func (mr *MetricsReporter) onSpan(spans []request.Span) {
 				"error", err, "service", s.Service)
 			continue
 		}
+		if s.InFlight {
+			reporter.recordInFlight(s, mr)
+			continue
+		}
 		reporter.record(s, mr)
+		if mr.slo != nil {
+			reporter.recordSLO(s, mr)
+		}
 
 		if s.Service.Features.AppHost() {
 			hostInfo, attrs := mr.hostInfo.ForRecord(s)
@@ -1240,6 +1520,32 @@ func cleanupMetrics(ctx context.Context, m *Expirer[*request.Span, instrument.Fl
 	}
 }
 
+// recordMessages accounts the messages and payload bytes that are sent or
+// consumed by a messaging span, if the protocol parser could extract them.
+func (r *Metrics) recordMessages(ctx context.Context, span *request.Span) {
+	info := span.MessagingInfo
+	if info == nil || (info.Messages == 0 && info.Bytes == 0) {
+		return
+	}
+	var messages, bytes *Expirer[*request.Span, instrument.Int64Counter, int64]
+	switch span.Method {
+	case request.MessagingPublish:
+		messages, bytes = r.msgSentMessages, r.msgSentBytes
+	case request.MessagingProcess:
+		messages, bytes = r.msgConsumedMessages, r.msgConsumedBytes
+	default:
+		return
+	}
+	if info.Messages > 0 {
+		counter, attrs := messages.ForRecord(span)
+		counter.Add(ctx, int64(info.Messages), instrument.WithAttributeSet(attrs))
+	}
+	if info.Bytes > 0 {
+		counter, attrs := bytes.ForRecord(span)
+		counter.Add(ctx, int64(info.Bytes), instrument.WithAttributeSet(attrs))
+	}
+}
+
 func cleanupCounterMetrics(ctx context.Context, m *Expirer[*request.Span, instrument.Int64Counter, int64]) {
 	if m != nil {
 		m.RemoveAllMetrics(ctx)
@@ -1252,14 +1558,25 @@ func cleanupFloatCounterMetrics(ctx context.Context, m *Expirer[*request.Span, i
 	}
 }
 
+func cleanupUpDownCounterMetrics(ctx context.Context, m *Expirer[*request.Span, instrument.Int64UpDownCounter, int64]) {
+	if m != nil {
+		m.RemoveAllMetrics(ctx)
+	}
+}
+
 func (r *Metrics) cleanupAllMetricsInstances() {
 	cleanupMetrics(r.ctx, r.httpDuration)
 	cleanupMetrics(r.ctx, r.httpClientDuration)
 	cleanupMetrics(r.ctx, r.grpcDuration)
 	cleanupMetrics(r.ctx, r.grpcClientDuration)
 	cleanupMetrics(r.ctx, r.dbClientDuration)
+	cleanupMetrics(r.ctx, r.dbServerDuration)
 	cleanupMetrics(r.ctx, r.msgPublishDuration)
 	cleanupMetrics(r.ctx, r.msgProcessDuration)
+	cleanupCounterMetrics(r.ctx, r.msgSentMessages)
+	cleanupCounterMetrics(r.ctx, r.msgSentBytes)
+	cleanupCounterMetrics(r.ctx, r.msgConsumedMessages)
+	cleanupCounterMetrics(r.ctx, r.msgConsumedBytes)
 	cleanupMetrics(r.ctx, r.httpRequestSize)
 	cleanupMetrics(r.ctx, r.httpResponseSize)
 	cleanupMetrics(r.ctx, r.httpClientRequestSize)
@@ -1267,6 +1584,8 @@ func (r *Metrics) cleanupAllMetricsInstances() {
 	cleanupMetrics(r.ctx, r.spanMetricsLatency)
 	cleanupCounterMetrics(r.ctx, r.spanMetricsCallsTotal)
 	cleanupFloatCounterMetrics(r.ctx, r.spanMetricsRequestSizeTotal)
+	cleanupUpDownCounterMetrics(r.ctx, r.httpActiveRequests)
+	cleanupUpDownCounterMetrics(r.ctx, r.httpClientActiveRequests)
 	cleanupFloatCounterMetrics(r.ctx, r.spanMetricsResponseSizeTotal)
 	cleanupCounterMetrics(r.ctx, r.gpuKernelCallsTotal)
 	cleanupCounterMetrics(r.ctx, r.gpuMemoryAllocsTotal)
@@ -1274,6 +1593,10 @@ func (r *Metrics) cleanupAllMetricsInstances() {
 	cleanupMetrics(r.ctx, r.gpuKernelBlockSize)
 	cleanupMetrics(r.ctx, r.gpuMemoryCopySize)
 	cleanupMetrics(r.ctx, r.dnsLookupDuration)
+	cleanupCounterMetrics(r.ctx, r.dnsLookupFailures)
+	cleanupCounterMetrics(r.ctx, r.connectionFailures)
 	cleanupMetrics(r.ctx, r.genAIClientDuration)
 	cleanupMetrics(r.ctx, r.genAIInputTokenUsage)
+	r.cleanupSLOMetrics()
+	r.cleanupProcMetrics()
 }
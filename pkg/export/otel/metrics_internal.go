@@ -47,6 +47,15 @@ type InternalMetricsReporter struct {
 	totalIgnoredPackets   uint64
 	bpfPacketCount        instrument.Int64Counter
 	bpfIgnoredPacketCount instrument.Int64Counter
+
+	tailSamplingDecisions      instrument.Int64Counter
+	tailSamplingBufferedTraces instrument.Int64Gauge
+
+	diskQueueItems   instrument.Int64Gauge
+	diskQueueBytes   instrument.Int64Gauge
+	diskQueueDropped instrument.Int64Counter
+
+	cardinalityOverflows instrument.Int64Counter
 }
 
 func imlog() *slog.Logger {
@@ -195,6 +204,60 @@ func NewInternalMetricsReporter(ctx context.Context, ctxInfo *global.ContextInfo
 		return nil, err
 	}
 
+	tailSamplingDecisions, err := meter.Int64Counter(
+		attr.VendorPrefix+".tail_sampling.traces",
+		instrument.WithDescription("Number of traces decided by the tail sampler, by decision (sampled or dropped) and policy"),
+		instrument.WithUnit("{trace}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	tailSamplingBufferedTraces, err := meter.Int64Gauge(
+		attr.VendorPrefix+".tail_sampling.buffered_traces",
+		instrument.WithDescription("Number of traces that are buffered by the tail sampler, waiting for a decision"),
+		instrument.WithUnit("{trace}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	diskQueueItems, err := meter.Int64Gauge(
+		attr.VendorPrefix+".otel.disk_queue.items",
+		instrument.WithDescription("Number of items stored in the disk queue of the OTLP exporter, waiting to be sent"),
+		instrument.WithUnit("{item}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	diskQueueBytes, err := meter.Int64Gauge(
+		attr.VendorPrefix+".otel.disk_queue.size",
+		instrument.WithDescription("Size of the disk queue of the OTLP exporter"),
+		instrument.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	diskQueueDropped, err := meter.Int64Counter(
+		attr.VendorPrefix+".otel.disk_queue.dropped",
+		instrument.WithDescription("Number of items dropped from the disk queue of the OTLP exporter"),
+		instrument.WithUnit("{item}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	cardinalityOverflows, err := meter.Int64Counter(
+		attr.VendorPrefix+".metric.cardinality.overflows",
+		instrument.WithDescription("Number of measurements for new attribute sets that were aggregated into the overflow series of a metric, because it reached its cardinality limit"),
+		instrument.WithUnit("{measurement}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	return &InternalMetricsReporter{
 		ctx:                              ctx,
 		tracerFlushes:                    tracerFlushes,
@@ -213,6 +276,12 @@ func NewInternalMetricsReporter(ctx context.Context, ctxInfo *global.ContextInfo
 		informerLag:                      informerLag,
 		bpfPacketCount:                   bpfPacketCount,
 		bpfIgnoredPacketCount:            bpfIgnoredPacketCount,
+		tailSamplingDecisions:            tailSamplingDecisions,
+		tailSamplingBufferedTraces:       tailSamplingBufferedTraces,
+		diskQueueItems:                   diskQueueItems,
+		diskQueueBytes:                   diskQueueBytes,
+		diskQueueDropped:                 diskQueueDropped,
+		cardinalityOverflows:             cardinalityOverflows,
 	}, nil
 }
 
@@ -344,3 +413,31 @@ func (p *InternalMetricsReporter) BPFPacketStats(count, ignored uint64) {
 	p.bpfIgnoredPacketCount.Add(p.ctx, int64(ignored-p.totalIgnoredPackets))
 	p.totalPackets, p.totalIgnoredPackets = count, ignored
 }
+
+func (p *InternalMetricsReporter) TailSamplingDecision(decision, policy string) {
+	p.tailSamplingDecisions.Add(p.ctx, 1, instrument.WithAttributes(
+		attribute.String("decision", decision),
+		attribute.String("policy", policy),
+	))
+}
+
+func (p *InternalMetricsReporter) TailSamplingBufferedTraces(traces int) {
+	p.tailSamplingBufferedTraces.Record(p.ctx, int64(traces))
+}
+
+func (p *InternalMetricsReporter) DiskQueueSize(signal string, items, bytes int) {
+	attrs := instrument.WithAttributes(attribute.String("signal", signal))
+	p.diskQueueItems.Record(p.ctx, int64(items), attrs)
+	p.diskQueueBytes.Record(p.ctx, int64(bytes), attrs)
+}
+
+func (p *InternalMetricsReporter) DiskQueueDropped(signal string, items int) {
+	p.diskQueueDropped.Add(p.ctx, int64(items), instrument.WithAttributes(attribute.String("signal", signal)))
+}
+
+func (p *InternalMetricsReporter) MetricCardinalityOverflow(exporter, metric string) {
+	p.cardinalityOverflows.Add(p.ctx, 1, instrument.WithAttributes(
+		attribute.String("exporter", exporter),
+		attribute.String("metric", metric),
+	))
+}
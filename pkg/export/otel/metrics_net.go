@@ -18,6 +18,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 	"go.opentelemetry.io/obi/pkg/export/expire"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
 	"go.opentelemetry.io/obi/pkg/export/otel/metric"
 	metric2 "go.opentelemetry.io/obi/pkg/export/otel/metric/api/metric"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
@@ -66,11 +67,11 @@ func createFilteredNetworkResource(hostID string, attrSelector attributes.Select
 	return resource.NewWithAttributes(semconv.SchemaURL, attrs...)
 }
 
-func newMeterProvider(res *resource.Resource, exporter *sdkmetric.Exporter, interval time.Duration) *metric.MeterProvider {
-	return metric.NewMeterProvider(
+func newMeterProvider(res *resource.Resource, exporter *sdkmetric.Exporter, cfg *otelcfg.MetricsConfig, internalMetrics imetrics.Reporter) *metric.MeterProvider {
+	return metric.NewMeterProvider(append([]metric.Option{
 		metric.WithResource(res),
-		metric.WithReader(metric.NewPeriodicReader(*exporter, metric.WithInterval(interval))),
-	)
+		metric.WithReader(metric.NewPeriodicReader(*exporter, metric.WithInterval(cfg.Interval))),
+	}, meterProviderOptions(cfg, internalMetrics)...)...)
 }
 
 type netMetricsExporter struct {
@@ -118,7 +119,7 @@ func newMetricsExporter(
 	exporter = instrumentMetricsExporter(ctxInfo.Metrics, exporter)
 
 	resource := createFilteredNetworkResource(ctxInfo.NodeMeta.HostID, cfg.SelectorCfg.SelectionCfg)
-	provider := newMeterProvider(resource, &exporter, cfg.Metrics.Interval)
+	provider := newMeterProvider(resource, &exporter, cfg.Metrics, ctxInfo.Metrics)
 
 	attrProv, err := attributes.NewAttrSelector(ctxInfo.MetricAttributeGroups, cfg.SelectorCfg)
 	if err != nil {
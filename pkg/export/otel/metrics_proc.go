@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otel // import "go.opentelemetry.io/obi/pkg/export/otel"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	instrument "go.opentelemetry.io/obi/pkg/export/otel/metric/api/metric"
+	"go.opentelemetry.io/obi/pkg/internal/process"
+)
+
+var (
+	attrCPUModeUser   = attr.CPUMode.OTEL().String("user")
+	attrCPUModeSystem = attr.CPUMode.OTEL().String("system")
+	attrDiskIORead    = attr.DiskIODirection.OTEL().String("read")
+	attrDiskIOWrite   = attr.DiskIODirection.OTEL().String("write")
+
+	procPIDAttr = []attributes.Field[*process.Status, attribute.KeyValue]{{
+		ExposedName: string(attr.ProcessPID.OTEL()),
+		Get: func(s *process.Status) attribute.KeyValue {
+			return attr.ProcessPID.OTEL().Int(int(s.PID))
+		},
+	}}
+)
+
+// procUpDownCounter reports the sampled value of a process metric (e.g. memory usage) as an
+// UpDownCounter, as the semantic conventions define, by adding the difference with the
+// previously reported value of each process
+type procUpDownCounter struct {
+	*Expirer[*process.Status, instrument.Int64UpDownCounter, int64]
+	last map[attribute.Distinct]int64
+}
+
+func newProcUpDownCounter(m *Metrics, counter instrument.Int64UpDownCounter, mr *MetricsReporter) *procUpDownCounter {
+	c := &procUpDownCounter{last: map[attribute.Distinct]int64{}}
+	c.Expirer = NewExpirer[*process.Status, instrument.Int64UpDownCounter, int64](
+		m.ctx, counter, procPIDAttr, timeNow, mr.cfg.TTL,
+	).OnRemove(func(set attribute.Set) {
+		// a removed metric starts again from zero if the process is reported again
+		delete(c.last, set.Equivalent())
+	})
+	return c
+}
+
+func (c *procUpDownCounter) record(ctx context.Context, s *process.Status, value int64) {
+	counter, attrs := c.ForRecord(s)
+	key := attrs.Equivalent()
+	if delta := value - c.last[key]; delta != 0 {
+		counter.Add(ctx, delta, instrument.WithAttributeSet(attrs))
+	}
+	c.last[key] = value
+}
+
+// setupProcMeters creates the process metrics in the MeterProvider of the service, so they are
+// reported with the same resource attributes as the application metrics.
+func (mr *MetricsReporter) setupProcMeters(m *Metrics, meter instrument.Meter) error {
+	cpuTime, err := meter.Float64Counter(attributes.ProcessCPUTime.OTEL, instrument.WithUnit("s"))
+	if err != nil {
+		return fmt.Errorf("creating process cpu time counter: %w", err)
+	}
+	m.procCPUTime = NewExpirer[*process.Status, instrument.Float64Counter, float64](
+		m.ctx, cpuTime, procPIDAttr, timeNow, mr.cfg.TTL)
+
+	diskIO, err := meter.Int64Counter(attributes.ProcessDiskIO.OTEL, instrument.WithUnit("By"))
+	if err != nil {
+		return fmt.Errorf("creating process disk io counter: %w", err)
+	}
+	m.procDiskIO = NewExpirer[*process.Status, instrument.Int64Counter, int64](
+		m.ctx, diskIO, procPIDAttr, timeNow, mr.cfg.TTL)
+
+	memoryUsage, err := meter.Int64UpDownCounter(attributes.ProcessMemoryUsage.OTEL, instrument.WithUnit("By"))
+	if err != nil {
+		return fmt.Errorf("creating process memory usage counter: %w", err)
+	}
+	m.procMemoryUsage = newProcUpDownCounter(m, memoryUsage, mr)
+
+	memoryVirtual, err := meter.Int64UpDownCounter(attributes.ProcessMemoryVirtual.OTEL, instrument.WithUnit("By"))
+	if err != nil {
+		return fmt.Errorf("creating process virtual memory counter: %w", err)
+	}
+	m.procMemoryVirtual = newProcUpDownCounter(m, memoryVirtual, mr)
+
+	openFDs, err := meter.Int64UpDownCounter(attributes.ProcessOpenFileDescriptors.OTEL, instrument.WithUnit("{file_descriptor}"))
+	if err != nil {
+		return fmt.Errorf("creating process open file descriptors counter: %w", err)
+	}
+	m.procOpenFDs = newProcUpDownCounter(m, openFDs, mr)
+
+	threads, err := meter.Int64UpDownCounter(attributes.ProcessThreads.OTEL, instrument.WithUnit("{thread}"))
+	if err != nil {
+		return fmt.Errorf("creating process threads counter: %w", err)
+	}
+	m.procThreads = newProcUpDownCounter(m, threads, mr)
+
+	return nil
+}
+
+func (mr *MetricsReporter) onProcessStatuses(statuses []*process.Status) {
+	for _, s := range statuses {
+		reporter, err := mr.reporters.For(s.Service)
+		if err != nil {
+			mlog().Error("unexpected error creating OTEL resource. Ignoring metric",
+				"error", err, "service", s.Service)
+			continue
+		}
+		reporter.recordProcess(s)
+	}
+}
+
+func (r *Metrics) recordProcess(s *process.Status) {
+	if r.procCPUTime == nil {
+		return
+	}
+	ctx := r.ctx
+	m, attrs := r.procCPUTime.ForRecord(s, attrCPUModeUser)
+	m.Add(ctx, s.CPUTimeUserDelta, instrument.WithAttributeSet(attrs))
+	m, attrs = r.procCPUTime.ForRecord(s, attrCPUModeSystem)
+	m.Add(ctx, s.CPUTimeSystemDelta, instrument.WithAttributeSet(attrs))
+
+	d, attrs := r.procDiskIO.ForRecord(s, attrDiskIORead)
+	d.Add(ctx, s.IOReadBytesDelta, instrument.WithAttributeSet(attrs))
+	d, attrs = r.procDiskIO.ForRecord(s, attrDiskIOWrite)
+	d.Add(ctx, s.IOWriteBytesDelta, instrument.WithAttributeSet(attrs))
+
+	r.procMemoryUsage.record(ctx, s, s.MemoryRSSBytes)
+	r.procMemoryVirtual.record(ctx, s, s.MemoryVirtualBytes)
+	r.procOpenFDs.record(ctx, s, s.OpenFileDescriptors)
+	r.procThreads.record(ctx, s, s.Threads)
+}
+
+func (r *Metrics) cleanupProcMetrics() {
+	if r.procCPUTime == nil {
+		return
+	}
+	r.procCPUTime.RemoveAllMetrics(r.ctx)
+	r.procDiskIO.RemoveAllMetrics(r.ctx)
+	r.procMemoryUsage.RemoveAllMetrics(r.ctx)
+	r.procMemoryVirtual.RemoveAllMetrics(r.ctx)
+	r.procOpenFDs.RemoveAllMetrics(r.ctx)
+	r.procThreads.RemoveAllMetrics(r.ctx)
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"go.opentelemetry.io/obi/internal/test/collector"
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/discover/exec"
+	"go.opentelemetry.io/obi/pkg/export"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/internal/process"
+	"go.opentelemetry.io/obi/pkg/pipe/global"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+func TestProcMetrics(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	mcfg := &otelcfg.MetricsConfig{
+		Interval:          50 * time.Millisecond,
+		CommonEndpoint:    otlp.ServerEndpoint,
+		MetricsProtocol:   otelcfg.ProtocolHTTPProtobuf,
+		TTL:               30 * time.Minute,
+		ReportersCacheLen: 100,
+	}
+
+	spans := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10))
+	input := msg.NewQueue[[]*process.Status](msg.ChannelBufferLen(10))
+	run, err := ReportMetrics(
+		&global.ContextInfo{OTELMetricsExporter: &otelcfg.MetricsExporterInstancer{Cfg: mcfg}},
+		mcfg, &perapp.MetricsConfig{Features: export.FeatureProcess}, &attributes.SelectorConfig{},
+		nil, request.UnresolvedNames{}, spans, processEvents, input)(ctx)
+	require.NoError(t, err)
+	go run(ctx)
+
+	service := &svc.Attrs{UID: svc.UID{Name: "foo", Namespace: "bar", Instance: "foo-1"}}
+	input.Send([]*process.Status{{
+		Service: service,
+		PID:     123,
+
+		CPUTimeUserDelta: 1.5, CPUTimeSystemDelta: 0.5,
+		MemoryRSSBytes: 1000, MemoryVirtualBytes: 5000,
+		IOReadBytesDelta: 10, IOWriteBytesDelta: 20,
+		OpenFileDescriptors: 7, Threads: 3,
+	}})
+
+	expected := map[string]collector.MetricRecord{
+		"process.cpu.time/user":              {FloatVal: 1.5, Type: pmetric.MetricTypeSum},
+		"process.cpu.time/system":            {FloatVal: 0.5, Type: pmetric.MetricTypeSum},
+		"process.disk.io/read":               {IntVal: 10, Type: pmetric.MetricTypeSum},
+		"process.disk.io/write":              {IntVal: 20, Type: pmetric.MetricTypeSum},
+		"process.memory.usage":               {IntVal: 1000, Type: pmetric.MetricTypeSum},
+		"process.memory.virtual":             {IntVal: 5000, Type: pmetric.MetricTypeSum},
+		"process.open_file_descriptor.count": {IntVal: 7, Type: pmetric.MetricTypeSum},
+		"process.thread.count":               {IntVal: 3, Type: pmetric.MetricTypeSum},
+	}
+	assertProcMetrics(t, otlp, expected)
+
+	// the sampled values are reported as UpDownCounters, so the reported
+	// values must follow the last sample, also when it decreases
+	input.Send([]*process.Status{{
+		Service: service,
+		PID:     123,
+
+		CPUTimeUserDelta: 1, CPUTimeSystemDelta: 1,
+		MemoryRSSBytes: 800, MemoryVirtualBytes: 6000,
+		IOReadBytesDelta: 5, IOWriteBytesDelta: 0,
+		OpenFileDescriptors: 4, Threads: 3,
+	}})
+
+	assertProcMetrics(t, otlp, map[string]collector.MetricRecord{
+		"process.cpu.time/user":              {FloatVal: 2.5, Type: pmetric.MetricTypeSum},
+		"process.cpu.time/system":            {FloatVal: 1.5, Type: pmetric.MetricTypeSum},
+		"process.disk.io/read":               {IntVal: 15, Type: pmetric.MetricTypeSum},
+		"process.disk.io/write":              {IntVal: 20, Type: pmetric.MetricTypeSum},
+		"process.memory.usage":               {IntVal: 800, Type: pmetric.MetricTypeSum},
+		"process.memory.virtual":             {IntVal: 6000, Type: pmetric.MetricTypeSum},
+		"process.open_file_descriptor.count": {IntVal: 4, Type: pmetric.MetricTypeSum},
+		"process.thread.count":               {IntVal: 3, Type: pmetric.MetricTypeSum},
+	})
+}
+
+// assertProcMetrics waits until the last received value of each process metric matches the expected value
+func assertProcMetrics(t *testing.T, otlp *collector.TestCollector, expected map[string]collector.MetricRecord) {
+	t.Helper()
+	received := map[string]collector.MetricRecord{}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(t, otlp.Records(), 1, timeout) {
+			key := r.Name
+			if mode, ok := r.Attributes["cpu.mode"]; ok {
+				key += "/" + mode
+			} else if dir, ok := r.Attributes["disk.io.direction"]; ok {
+				key += "/" + dir
+			}
+			received[key] = r
+		}
+		for key, exp := range expected {
+			r, ok := received[key]
+			if !assert.Truef(ct, ok, "%s not received", key) {
+				continue
+			}
+			assert.InDeltaf(ct, exp.FloatVal, r.FloatVal, 0.0001, "%s float value", key)
+			assert.Equalf(ct, exp.IntVal, r.IntVal, "%s int value", key)
+			assert.Equalf(ct, exp.Type, r.Type, "%s metric type", key)
+			assert.Equal(ct, "123", r.Attributes["process.pid"])
+			assert.Equal(ct, "foo", r.ResourceAttributes["service.name"])
+			assert.Equal(ct, "bar", r.ResourceAttributes["service.namespace"])
+			assert.Equal(ct, "foo-1", r.ResourceAttributes["service.instance.id"])
+		}
+	}, timeout, time.Millisecond)
+}
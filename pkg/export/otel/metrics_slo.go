@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otel // import "go.opentelemetry.io/obi/pkg/export/otel"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	instrument "go.opentelemetry.io/obi/pkg/export/otel/metric/api/metric"
+)
+
+// setupSLOMeters creates the SLO metrics in the MeterProvider of the service, so they are
+// reported with the same resource attributes as the rest of the application metrics.
+// The Apdex score is observed on each collection, so it is kept up to date for the services
+// that stop receiving requests.
+func (mr *MetricsReporter) setupSLOMeters(m *Metrics, meter instrument.Meter) error {
+	events, err := meter.Int64Counter(attributes.SLOEvents.OTEL, instrument.WithUnit("{request}"))
+	if err != nil {
+		return fmt.Errorf("creating SLO events counter: %w", err)
+	}
+	m.sloEvents = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+		m.ctx, events, nil, timeNow, mr.cfg.TTL)
+
+	goodEvents, err := meter.Int64Counter(attributes.SLOGoodEvents.OTEL, instrument.WithUnit("{request}"))
+	if err != nil {
+		return fmt.Errorf("creating SLO good events counter: %w", err)
+	}
+	m.sloGoodEvents = NewExpirer[*request.Span, instrument.Int64Counter, int64](
+		m.ctx, goodEvents, nil, timeNow, mr.cfg.TTL)
+
+	apdex, err := meter.Float64ObservableGauge(attributes.SLOApdex.OTEL, instrument.WithUnit("1"))
+	if err != nil {
+		return fmt.Errorf("creating SLO apdex gauge: %w", err)
+	}
+	service := m.service.UID
+	m.sloApdex, err = meter.RegisterCallback(func(_ context.Context, o instrument.Observer) error {
+		for _, score := range mr.slo.ServiceScores(service) {
+			o.ObserveFloat64(apdex, score.Apdex,
+				instrument.WithAttributes(attr.SLOName.OTEL().String(score.Definition.Name)))
+		}
+		return nil
+	}, apdex)
+	if err != nil {
+		return fmt.Errorf("registering SLO apdex callback: %w", err)
+	}
+	return nil
+}
+
+// recordSLO evaluates the span against the SLO definitions, accounting the good and total events
+func (r *Metrics) recordSLO(span *request.Span, mr *MetricsReporter) {
+	for _, ev := range mr.slo.Track(span) {
+		sloName := attr.SLOName.OTEL().String(ev.Definition.Name)
+		c, attrs := r.sloEvents.ForRecord(span, sloName)
+		c.Add(r.ctx, 1, instrument.WithAttributeSet(attrs))
+		if ev.Good {
+			c, attrs = r.sloGoodEvents.ForRecord(span, sloName)
+			c.Add(r.ctx, 1, instrument.WithAttributeSet(attrs))
+		}
+	}
+}
+
+func (r *Metrics) cleanupSLOMetrics() {
+	cleanupCounterMetrics(r.ctx, r.sloEvents)
+	cleanupCounterMetrics(r.ctx, r.sloGoodEvents)
+	if r.sloApdex != nil {
+		if err := r.sloApdex.Unregister(); err != nil {
+			mlog().Debug("can't unregister SLO apdex callback", "error", err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/internal/test/collector"
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/discover/exec"
+	"go.opentelemetry.io/obi/pkg/export"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+	"go.opentelemetry.io/obi/pkg/export/slo"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+func TestSLOMetrics(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10))
+	mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		export.FeatureApplicationRED, otlp, input, processEvents)
+	mr.slo = slo.NewTracker(&slo.Config{Definitions: []slo.Definition{{
+		Name:             "all-routes",
+		LatencyThreshold: 100 * time.Millisecond,
+	}}}, timeNow)
+	go mr.reportMetrics(ctx)
+
+	service := svc.Attrs{
+		UID:      svc.UID{Name: "foo", Namespace: "bar", Instance: "foo-1"},
+		Features: export.FeatureApplicationRED,
+	}
+	span := func(status int, duration time.Duration) request.Span {
+		return request.Span{
+			Service: service, Type: request.EventTypeHTTP, Method: "GET", Route: "/", Status: status,
+			RequestStart: 0, End: duration.Nanoseconds(),
+		}
+	}
+	ignored := span(200, time.Millisecond)
+	request.SetIgnoreMetrics(&ignored)
+	input.Send([]request.Span{
+		span(200, 50*time.Millisecond),
+		span(200, 200*time.Millisecond),
+		span(404, 10*time.Millisecond),
+		ignored,
+	})
+
+	expected := map[string]collector.MetricRecord{
+		"obi.slo.events":      {IntVal: 3},
+		"obi.slo.good_events": {IntVal: 1},
+		// (1 satisfied + 1 tolerating / 2) / 3
+		"obi.slo.apdex": {FloatVal: 0.5},
+	}
+	received := map[string]collector.MetricRecord{}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			received[r.Name] = r
+		}
+		for name, exp := range expected {
+			r := received[name]
+			assert.InDeltaf(ct, exp.FloatVal, r.FloatVal, 0.0001, "%s float value", name)
+			assert.Equalf(ct, exp.IntVal, r.IntVal, "%s int value", name)
+			assert.Equal(ct, "all-routes", r.Attributes["slo.name"])
+			assert.Equal(ct, "foo", r.ResourceAttributes["service.name"])
+		}
+		// the SLO metrics are reported from the same MeterProvider as the application metrics
+		assert.Equal(ct, received[attributes.HTTPServerDuration.OTEL].ResourceAttributes,
+			received["obi.slo.events"].ResourceAttributes)
+	}, timeout, time.Millisecond)
+}
@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/appolly/meta"
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
 	"go.opentelemetry.io/obi/pkg/export/otel/metric"
 	instrument "go.opentelemetry.io/obi/pkg/export/otel/metric/api/metric"
@@ -49,6 +50,7 @@ type SvcGraphMetricsReporter struct {
 	cfg              *otelcfg.MetricsConfig
 	nodeMeta         meta.NodeMeta
 	exporter         sdkmetric.Exporter
+	internalMetrics  imetrics.Reporter
 	reporters        otelcfg.ReporterPool[*svc.Attrs, *SvcGraphMetrics]
 	pidTracker       PidServiceTracker
 	is               instrumentations.InstrumentationSelection
@@ -122,6 +124,7 @@ func newSvcGraphMetricsReporter(
 		cfg:              cfg,
 		is:               is,
 		nodeMeta:         ctxInfo.NodeMeta,
+		internalMetrics:  ctxInfo.Metrics,
 		input:            input.Subscribe(msg.SubscriberName("otel.SvcGraphMetricsReporter.input")),
 		processEvents:    processEventCh.Subscribe(msg.SubscriberName("otel.SvcGraphMetricsReporter.processEvents")),
 		metricAttributes: serviceGraphGetters(unresolved, ctxInfo.K8sInformer.IsKubeEnabled()),
@@ -213,6 +216,7 @@ func (mr *SvcGraphMetricsReporter) newSvcGraphMetricsInstance(service *svc.Attrs
 	}
 
 	opts = append(opts, mr.graphMetricOptions(log)...)
+	opts = append(opts, meterProviderOptions(mr.cfg, mr.internalMetrics)...)
 
 	return &SvcGraphMetrics{
 		ctx:                      mr.ctx,
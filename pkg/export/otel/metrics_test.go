@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -19,6 +20,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"go.opentelemetry.io/obi/internal/test/collector"
 	"go.opentelemetry.io/obi/pkg/appolly/app"
@@ -64,7 +66,7 @@ func TestMetrics_InternalInstrumentation(t *testing.T) {
 	reporter, err := ReportMetrics(&global.ContextInfo{
 		Metrics:             internalMetrics,
 		OTELMetricsExporter: &otelcfg.MetricsExporterInstancer{Cfg: mcfg},
-	}, mcfg, &mpConfig, &attributes.SelectorConfig{}, request.UnresolvedNames{}, exportMetrics, processEvents,
+	}, mcfg, &mpConfig, &attributes.SelectorConfig{}, nil, request.UnresolvedNames{}, exportMetrics, processEvents, nil,
 	)(t.Context())
 	require.NoError(t, err)
 	go reporter(t.Context())
@@ -157,6 +159,7 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 				"db.client.operation.duration",        // SQL client SELECT
 				"db.client.operation.duration",        // REDIS client SET
 				"db.client.operation.duration",        // Redis server GET (TODO is this a bug?)
+				"db.server.operation.duration",        // Redis server GET
 				"db.client.operation.duration",        // MongoDB client find
 				"messaging.client.operation.duration", // Kafka client
 				"messaging.client.operation.duration", // MQTT client
@@ -203,6 +206,7 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 			expected: []string{
 				"db.client.operation.duration",
 				"db.client.operation.duration",
+				"db.server.operation.duration",
 			},
 		},
 		{
@@ -245,6 +249,7 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 				"db.client.operation.duration",
 				"db.client.operation.duration",
 				"db.client.operation.duration",
+				"db.server.operation.duration",
 			},
 		},
 		{
@@ -577,9 +582,11 @@ func makeMetricsReporter(
 				},
 			},
 		},
+		nil,
 		request.UnresolvedNames{},
 		input,
-		processEvents)
+		processEvents,
+		nil)
 
 	require.NoError(t, err)
 	return mr
@@ -641,6 +648,383 @@ func TestAppMetrics_TracesHostInfo(t *testing.T) {
 	}, timeout, 100*time.Millisecond)
 }
 
+func TestAppMetrics_ActiveRequests(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+	mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		export.FeatureApplicationRED, otlp, metrics, processEvents)
+	go mr.reportMetrics(ctx)
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+	span := func(tp request.EventType, start int64, inFlight bool) request.Span {
+		return request.Span{
+			Service: service, Type: tp, Method: "GET", Path: "/foo",
+			Pid: request.PidInfo{HostPID: 123}, Start: start, RequestStart: start, End: start + 10, InFlight: inFlight,
+		}
+	}
+	metrics.Send([]request.Span{
+		span(request.EventTypeHTTP, 10, true),
+		span(request.EventTypeHTTP, 20, true),
+		span(request.EventTypeHTTP, 30, true),
+		span(request.EventTypeHTTPClient, 40, true),
+	})
+	// requests reported again while they are in-flight are accounted once
+	metrics.Send([]request.Span{
+		span(request.EventTypeHTTP, 10, true),
+		span(request.EventTypeHTTPClient, 40, true),
+	})
+	// finished requests are discounted, unless they were never reported in-flight
+	metrics.Send([]request.Span{
+		span(request.EventTypeHTTP, 20, false),
+		span(request.EventTypeHTTP, 50, false),
+	})
+
+	expected := map[string]int64{
+		"http.server.active_requests": 2,
+		"http.client.active_requests": 1,
+	}
+	received := map[string]collector.MetricRecord{}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			if strings.HasSuffix(r.Name, ".active_requests") {
+				received[r.Name] = r
+			}
+		}
+		for name, value := range expected {
+			if assert.Contains(ct, received, name) {
+				assert.Equal(ct, value, received[name].IntVal, name)
+			}
+		}
+	}, timeout, time.Millisecond)
+
+	// status codes are not known when the request starts
+	assert.Equal(t, "GET", received["http.server.active_requests"].Attributes["http.request.method"])
+	assert.NotContains(t, received["http.server.active_requests"].Attributes, "http.response.status_code")
+
+	// in-flight spans aren't recorded as finished requests
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			received[r.Name] = r
+		}
+		if assert.Contains(ct, received, "http.server.request.duration") {
+			assert.Equal(ct, 2, received["http.server.request.duration"].Count)
+		}
+	}, timeout, time.Millisecond)
+}
+
+func TestAppMetrics_Failures(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+	mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		export.FeatureApplicationRED, otlp, metrics, processEvents)
+	go mr.reportMetrics(ctx)
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+	metrics.Send([]request.Span{
+		{Service: service, Type: request.EventTypeFailedConnect, Host: "10.0.0.1", HostName: "db", HostPort: 5432, Status: 111},
+		{Service: service, Type: request.EventTypeFailedConnect, Host: "10.0.0.1", HostName: "db", HostPort: 5432, Status: 111},
+		{Service: service, Type: request.EventTypeDNS, Path: "foo.example.com", Status: 3},
+		{Service: service, Type: request.EventTypeDNS, Path: "bar.example.com"},
+	})
+
+	received := map[string]collector.MetricRecord{}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			if strings.HasSuffix(r.Name, ".failures") {
+				received[r.Name] = r
+			}
+		}
+		assert.Contains(ct, received, "obi.network.connection.failures")
+		assert.Contains(ct, received, "obi.dns.lookup.failures")
+	}, timeout, time.Millisecond)
+
+	conn := received["obi.network.connection.failures"]
+	assert.EqualValues(t, 2, conn.IntVal)
+	assert.Equal(t, "db", conn.Attributes["server.address"])
+	assert.Equal(t, "5432", conn.Attributes["server.port"])
+	assert.Equal(t, "ECONNREFUSED", conn.Attributes["error.type"])
+
+	dns := received["obi.dns.lookup.failures"]
+	assert.EqualValues(t, 1, dns.IntVal)
+	assert.Equal(t, "foo.example.com", dns.Attributes["dns.question.name"])
+	assert.Equal(t, "NXDomain", dns.Attributes["error.type"])
+}
+
+func TestAppMetrics_DBServer(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+	mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		export.FeatureApplicationRED, otlp, metrics, processEvents)
+	go mr.reportMetrics(ctx)
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+	metrics.Send([]request.Span{
+		{Service: service, Type: request.EventTypeSQLServer, Method: "SELECT", Path: "accounts", RequestStart: 100, End: 200},
+	})
+
+	var server collector.MetricRecord
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			// SQL server operations are only reported as server operations
+			assert.NotEqual(ct, "db.client.operation.duration", r.Name)
+			if r.Name == "db.server.operation.duration" {
+				server = r
+			}
+		}
+		assert.Equal(ct, "db.server.operation.duration", server.Name)
+	}, timeout, time.Millisecond)
+
+	assert.Equal(t, "SELECT", server.Attributes["db.operation.name"])
+	assert.Equal(t, "accounts", server.Attributes["db.collection.name"])
+}
+
+func TestAppMetrics_MessagingThroughput(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+	mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		export.FeatureApplicationRED, otlp, metrics, processEvents)
+	go mr.reportMetrics(ctx)
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+	metrics.Send([]request.Span{
+		{
+			Service: service, Type: request.EventTypeKafkaClient, Method: request.MessagingPublish, Path: "orders",
+			RequestStart: 100, End: 200, MessagingInfo: &request.MessagingInfo{Partition: 3, Messages: 10, Bytes: 1000},
+		},
+		{
+			Service: service, Type: request.EventTypeKafkaClient, Method: request.MessagingPublish, Path: "orders",
+			RequestStart: 100, End: 200, MessagingInfo: &request.MessagingInfo{Partition: 3, Messages: 5, Bytes: 500},
+		},
+		{
+			Service: service, Type: request.EventTypeKafkaClient, Method: request.MessagingProcess, Path: "orders",
+			RequestStart: 100, End: 200, MessagingInfo: &request.MessagingInfo{Partition: 3, ConsumerGroup: "billing", Messages: 7, Bytes: 700},
+		},
+		// spans without messaging info are not accounted
+		{Service: service, Type: request.EventTypeKafkaClient, Method: request.MessagingPublish, Path: "orders", RequestStart: 100, End: 200},
+	})
+
+	received := map[string]collector.MetricRecord{}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			if strings.HasSuffix(r.Name, ".messages") || strings.HasSuffix(r.Name, ".bytes") {
+				received[r.Name] = r
+			}
+		}
+		assert.Len(ct, received, 4)
+	}, timeout, time.Millisecond)
+
+	assert.EqualValues(t, 15, received["messaging.client.sent.messages"].IntVal)
+	assert.EqualValues(t, 1500, received["obi.messaging.client.sent.bytes"].IntVal)
+	assert.EqualValues(t, 7, received["messaging.client.consumed.messages"].IntVal)
+	assert.EqualValues(t, 700, received["obi.messaging.client.consumed.bytes"].IntVal)
+
+	sent := received["messaging.client.sent.messages"].Attributes
+	assert.Equal(t, "orders", sent["messaging.destination.name"])
+	assert.Equal(t, "3", sent["messaging.destination.partition.id"])
+	assert.NotContains(t, sent, "messaging.consumer.group.name")
+	assert.Equal(t, "billing", received["messaging.client.consumed.messages"].Attributes["messaging.consumer.group.name"])
+}
+
+func TestAppMetrics_CardinalityLimit(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+	mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		export.FeatureApplicationRED, otlp, metrics, processEvents)
+	mr.cfg.CardinalityLimits = export.CardinalityLimits{
+		Metrics: map[string]int{attributes.HTTPServerDuration.OTEL: 3},
+	}
+	overflows := &overflowsReporter{}
+	mr.internalMetrics = overflows
+	go mr.reportMetrics(ctx)
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+	var spans []request.Span
+	// the overflows are reported once per rejected attribute set
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/c", "/a"} {
+		spans = append(spans, request.Span{
+			Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: path, Status: 200,
+			RequestStart: 100, End: 200,
+		})
+	}
+	metrics.Send(spans)
+
+	series := map[string]int{}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			if r.Name == attributes.HTTPServerDuration.OTEL {
+				if r.Attributes["otel.metric.overflow"] == "true" {
+					series["overflow"] = r.Count
+				} else {
+					series[r.Attributes["url.path"]] = r.Count
+				}
+			}
+		}
+		// the limit accounts for the overflow series
+		assert.Equal(ct, map[string]int{"/a": 2, "/b": 1, "overflow": 3}, series)
+	}, timeout, time.Millisecond)
+
+	// other metrics are not limited
+	assert.Equal(t, map[string]int{"otel:" + attributes.HTTPServerDuration.OTEL: 2}, overflows.get())
+}
+
+type overflowsReporter struct {
+	imetrics.NoopReporter
+	mt        sync.Mutex
+	overflows map[string]int
+}
+
+func (o *overflowsReporter) MetricCardinalityOverflow(exporter, metric string) {
+	o.mt.Lock()
+	defer o.mt.Unlock()
+	if o.overflows == nil {
+		o.overflows = map[string]int{}
+	}
+	o.overflows[exporter+":"+metric]++
+}
+
+func (o *overflowsReporter) get() map[string]int {
+	o.mt.Lock()
+	defer o.mt.Unlock()
+	return maps.Clone(o.overflows)
+}
+
+func TestAppMetrics_Views(t *testing.T) {
+	defer otelcfg.RestoreEnvAfterExecution()()
+	ctx := t.Context()
+
+	otlp, err := collector.Start(ctx)
+	require.NoError(t, err)
+
+	metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+	processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+	mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		export.FeatureApplicationRED, otlp, metrics, processEvents)
+	mr.cfg.Views = export.MetricViews{{
+		Instrument:    export.NewInstrumentPattern(attributes.HTTPServerDuration.OTEL),
+		Rename:        "http.latency",
+		AttributeKeys: []string{"http.request.method"},
+	}, {
+		Instrument: export.NewInstrumentPattern("http.*.body.size"),
+		Drop:       true,
+	}}
+	go mr.reportMetrics(ctx)
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+	metrics.Send([]request.Span{{
+		Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: "/a", Status: 200,
+		RequestStart: 100, End: 200, ContentLength: 10,
+	}, {
+		Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: "/b", Status: 200,
+		RequestStart: 100, End: 200, ContentLength: 10,
+	}})
+
+	names := map[string]struct{}{}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+			names[r.Name] = struct{}{}
+			if r.Name == "http.latency" {
+				// the url.path attribute is removed, so requests to different paths are aggregated
+				assert.Empty(ct, r.Attributes)
+				assert.Equal(ct, 2, r.Count)
+			}
+		}
+		assert.Contains(ct, names, "http.latency")
+	}, timeout, time.Millisecond)
+	assert.NotContains(t, names, attributes.HTTPServerDuration.OTEL)
+	assert.NotContains(t, names, attributes.HTTPServerRequestSize.OTEL)
+	assert.NotContains(t, names, attributes.HTTPServerResponseSize.OTEL)
+}
+
+func TestAppMetrics_ExemplarFilter(t *testing.T) {
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	spanID := trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8}
+	type exemplars map[string][]collector.ExemplarRecord
+	testCases := []struct {
+		filter   string
+		expected exemplars
+	}{{
+		filter: "always_on",
+		expected: exemplars{
+			"/sampled":   {{TraceID: traceID.String(), SpanID: spanID.String(), FloatVal: 0.5}},
+			"/unsampled": {{TraceID: traceID.String(), SpanID: spanID.String(), FloatVal: 0.5}},
+		},
+	}, {
+		filter: "trace_based",
+		expected: exemplars{
+			"/sampled":   {{TraceID: traceID.String(), SpanID: spanID.String(), FloatVal: 0.5}},
+			"/unsampled": nil,
+		},
+	}, {
+		filter:   "always_off",
+		expected: exemplars{"/sampled": nil, "/unsampled": nil},
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.filter, func(t *testing.T) {
+			defer otelcfg.RestoreEnvAfterExecution()()
+			ctx := t.Context()
+
+			otlp, err := collector.Start(ctx)
+			require.NoError(t, err)
+
+			metrics := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(20))
+			processEvents := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(20))
+			mr := makeMetricsReporter(ctx, t, []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+				export.FeatureApplicationRED, otlp, metrics, processEvents)
+			mr.cfg.ExemplarFilter = tc.filter
+			go mr.reportMetrics(ctx)
+
+			service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+			metrics.Send([]request.Span{{
+				Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: "/sampled", Status: 200,
+				RequestStart: 0, End: 500_000_000, TraceID: traceID, SpanID: spanID, TraceFlags: 1,
+			}, {
+				Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: "/unsampled", Status: 200,
+				RequestStart: 0, End: 500_000_000, TraceID: traceID, SpanID: spanID,
+			}})
+
+			got := exemplars{}
+			require.EventuallyWithT(t, func(ct *assert.CollectT) {
+				for _, r := range readNChan(ct, otlp.Records(), 1, timeout) {
+					if r.Name == attributes.HTTPServerDuration.OTEL {
+						got[r.Attributes["url.path"]] = r.Exemplars
+					}
+				}
+				assert.Equal(ct, tc.expected, got)
+			}, timeout, time.Millisecond)
+		})
+	}
+}
+
 func TestMetricResourceAttributes(t *testing.T) {
 	// Test different filtering scenarios
 	testCases := []struct {
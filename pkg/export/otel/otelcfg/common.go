@@ -19,6 +19,7 @@ import (
 	"github.com/hashicorp/golang-lru/v2/simplelru"
 	"google.golang.org/grpc/credentials"
 
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
@@ -333,7 +334,12 @@ type OTLPOptions struct {
 	BaseURLPath   string
 	URLPath       string
 	SkipTLSVerify bool
-	Headers       map[string]string
+	// TLS is the loaded custom TLS configuration, if any. It takes precedence over SkipTLSVerify
+	TLS         *tls.Config
+	Compression Compression
+	Headers     map[string]string
+	// Temporality preference, only for metrics export
+	Temporality TemporalityPreference
 }
 
 func (o *OTLPOptions) AsMetricHTTP() []otlpmetrichttp.Option {
@@ -346,9 +352,17 @@ func (o *OTLPOptions) AsMetricHTTP() []otlpmetrichttp.Option {
 	if o.URLPath != "" {
 		opts = append(opts, otlpmetrichttp.WithURLPath(o.URLPath))
 	}
-	if o.SkipTLSVerify {
+	if o.TLS != nil {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(o.TLS))
+	} else if o.SkipTLSVerify {
 		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(&tls.Config{InsecureSkipVerify: true}))
 	}
+	if o.Compression == CompressionGzip {
+		opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+	}
+	if o.Temporality != TemporalityUnset {
+		opts = append(opts, otlpmetrichttp.WithTemporalitySelector(o.Temporality.Selector()))
+	}
 	if len(o.Headers) > 0 {
 		opts = append(opts, otlpmetrichttp.WithHeaders(o.Headers))
 	}
@@ -362,9 +376,17 @@ func (o *OTLPOptions) AsMetricGRPC() []otlpmetricgrpc.Option {
 	if o.Insecure {
 		opts = append(opts, otlpmetricgrpc.WithInsecure())
 	}
-	if o.SkipTLSVerify {
+	if o.TLS != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(o.TLS)))
+	} else if o.SkipTLSVerify {
 		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
 	}
+	if o.Compression == CompressionGzip {
+		opts = append(opts, otlpmetricgrpc.WithCompressor(string(CompressionGzip)))
+	}
+	if o.Temporality != TemporalityUnset {
+		opts = append(opts, otlpmetricgrpc.WithTemporalitySelector(o.Temporality.Selector()))
+	}
 	if len(o.Headers) > 0 {
 		opts = append(opts, otlpmetricgrpc.WithHeaders(o.Headers))
 	}
@@ -381,9 +403,14 @@ func (o *OTLPOptions) AsTraceHTTP() []otlptracehttp.Option {
 	if o.URLPath != "" {
 		opts = append(opts, otlptracehttp.WithURLPath(o.URLPath))
 	}
-	if o.SkipTLSVerify {
+	if o.TLS != nil {
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(o.TLS))
+	} else if o.SkipTLSVerify {
 		opts = append(opts, otlptracehttp.WithTLSClientConfig(&tls.Config{InsecureSkipVerify: true}))
 	}
+	if o.Compression == CompressionGzip {
+		opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
 	if len(o.Headers) > 0 {
 		opts = append(opts, otlptracehttp.WithHeaders(o.Headers))
 	}
@@ -397,9 +424,14 @@ func (o *OTLPOptions) AsTraceGRPC() []otlptracegrpc.Option {
 	if o.Insecure {
 		opts = append(opts, otlptracegrpc.WithInsecure())
 	}
-	if o.SkipTLSVerify {
+	if o.TLS != nil {
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(o.TLS)))
+	} else if o.SkipTLSVerify {
 		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(&tls.Config{InsecureSkipVerify: true})))
 	}
+	if o.Compression == CompressionGzip {
+		opts = append(opts, otlptracegrpc.WithCompressor(string(CompressionGzip)))
+	}
 	if len(o.Headers) > 0 {
 		opts = append(opts, otlptracegrpc.WithHeaders(o.Headers))
 	}
@@ -520,3 +552,12 @@ func ResolveOTLPEndpoint(endpoint, common string) (string, bool) {
 
 	return "", false
 }
+
+// CollectorHeaders converts the OTLP headers to the format of the OpenTelemetry Collector exporters
+func CollectorHeaders(headers map[string]string) configopaque.MapList {
+	opaque := make(configopaque.MapList, 0, len(headers))
+	for key, value := range headers {
+		opaque = append(opaque, configopaque.Pair{Name: key, Value: configopaque.String(value)})
+	}
+	return opaque
+}
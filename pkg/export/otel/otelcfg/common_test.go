@@ -4,6 +4,7 @@
 package otelcfg
 
 import (
+	"crypto/tls"
 	"fmt"
 	"reflect"
 	"testing"
@@ -29,6 +30,10 @@ func TestOtlpOptions_AsMetricHTTP(t *testing.T) {
 		{in: OTLPOptions{Endpoint: "foo", Insecure: true, SkipTLSVerify: true}, len: 3},
 		{in: OTLPOptions{Endpoint: "foo", URLPath: "/foo", SkipTLSVerify: true}, len: 3},
 		{in: OTLPOptions{Endpoint: "foo", URLPath: "/foo", Insecure: true, SkipTLSVerify: true}, len: 4},
+		{in: OTLPOptions{Endpoint: "foo", TLS: &tls.Config{}, SkipTLSVerify: true}, len: 2},
+		{in: OTLPOptions{Endpoint: "foo", Compression: CompressionGzip}, len: 2},
+		{in: OTLPOptions{Endpoint: "foo", Compression: CompressionNone}, len: 1},
+		{in: OTLPOptions{Endpoint: "foo", Temporality: TemporalityDelta}, len: 2},
 	}
 	for _, tc := range testCases {
 		t.Run(fmt.Sprint(tc), func(t *testing.T) {
@@ -47,6 +52,8 @@ func TestOtlpOptions_AsMetricGRPC(t *testing.T) {
 		{in: OTLPOptions{Endpoint: "foo", Insecure: true}, len: 2},
 		{in: OTLPOptions{Endpoint: "foo", SkipTLSVerify: true}, len: 2},
 		{in: OTLPOptions{Endpoint: "foo", Insecure: true, SkipTLSVerify: true}, len: 3},
+		{in: OTLPOptions{Endpoint: "foo", TLS: &tls.Config{}, Compression: CompressionGzip}, len: 3},
+		{in: OTLPOptions{Endpoint: "foo", Temporality: TemporalityLowMemory}, len: 2},
 	}
 	for _, tc := range testCases {
 		t.Run(fmt.Sprint(tc), func(t *testing.T) {
@@ -16,6 +16,8 @@ import (
 
 	"go.opentelemetry.io/obi/pkg/export"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
+	"go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
 )
 
 // HistogramAggregation defines the histogram aggregation type for metrics export.
@@ -49,9 +51,49 @@ type MetricsConfig struct {
 	// InsecureSkipVerify is not standard, so we don't follow the same naming convention
 	InsecureSkipVerify bool `yaml:"insecure_skip_verify" env:"OTEL_EBPF_INSECURE_SKIP_VERIFY"`
 
+	// TLS allows setting a custom CA and a client certificate for the OTLP endpoint.
+	// Unset files are taken from the common OTEL_EXPORTER_OTLP_CERTIFICATE, OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE
+	// and OTEL_EXPORTER_OTLP_CLIENT_KEY environment variables.
+	TLS       TLSConfig `yaml:"tls" envPrefix:"OTEL_EXPORTER_OTLP_METRICS_"`
+	CommonTLS TLSConfig `yaml:"-" envPrefix:"OTEL_EXPORTER_OTLP_"`
+	// TLSReloadInterval specifies how often the CA, client certificate and key files are reloaded
+	// from disk, so they can be rotated without restarting OBI. Zero disables reloading.
+	TLSReloadInterval time.Duration `yaml:"tls_reload_interval" env:"OTEL_EBPF_METRICS_TLS_RELOAD_INTERVAL" validate:"gte=0"`
+
+	// Compression of the OTLP payloads. Accepted values: none, gzip
+	Compression       Compression `yaml:"compression" env:"OTEL_EXPORTER_OTLP_METRICS_COMPRESSION" validate:"omitempty,oneof=none gzip"`
+	CommonCompression Compression `yaml:"-" env:"OTEL_EXPORTER_OTLP_COMPRESSION" validate:"omitempty,oneof=none gzip"`
+
+	// File writes the metrics as OTLP-JSON lines into a local file, which can be later replayed
+	// with the OpenTelemetry Collector's otlpjsonfile receiver. When set, it takes precedence
+	// over the OTLP endpoint.
+	File otlpfile.Config `yaml:"file" envPrefix:"OTEL_EBPF_METRICS_FILE_"`
+
+	// DiskQueue stores the metrics into a disk-backed queue before sending them to the OTLP endpoint,
+	// so they are kept while the endpoint is unavailable, and across the restarts of OBI.
+	DiskQueue diskqueue.DiskQueueConfig `yaml:"disk_queue" envPrefix:"OTEL_EBPF_METRICS_DISK_QUEUE_"`
+
+	// TemporalityPreference of the exported metrics. Accepted values: cumulative (default), delta, lowmemory.
+	// Delta temporality is only applied to counters and histograms, as specified by the OTLP exporter specification.
+	TemporalityPreference TemporalityPreference `yaml:"temporality_preference" env:"OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE" validate:"omitempty,oneof=cumulative delta lowmemory"`
+
 	Buckets              export.Buckets       `yaml:"buckets"`
 	HistogramAggregation HistogramAggregation `yaml:"histogram_aggregation" env:"OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION"`
 
+	// ExemplarFilter controls when exemplars, with the trace and span IDs of the request that originated
+	// the measurement, are attached to the application metrics. Accepted values: "always_on", "always_off",
+	// "trace_based". If unset, the standard OTEL_METRICS_EXEMPLAR_FILTER environment variable is used,
+	// which defaults to "trace_based".
+	ExemplarFilter string `yaml:"exemplar_filter" env:"OTEL_EBPF_METRICS_EXEMPLAR_FILTER" validate:"omitempty,oneof=always_on always_off trace_based"`
+
+	// CardinalityLimits of each metric instrument, by OpenTelemetry metric name. Each service reports its
+	// metrics in a different resource, so the limits apply per service and metric.
+	CardinalityLimits export.CardinalityLimits `yaml:"cardinality_limits" envPrefix:"OTEL_EBPF_METRICS_CARDINALITY_LIMITS_"`
+
+	// Views override how the matching metric instruments, by OpenTelemetry metric name, are reported:
+	// renaming them, restricting their attributes, overriding their histogram buckets or dropping them.
+	Views export.MetricViews `yaml:"views" validate:"omitempty,dive"`
+
 	ReportersCacheLen int `yaml:"reporters_cache_len" env:"OTEL_EBPF_METRICS_REPORT_CACHE_LEN"`
 
 	// SDKLogLevel works independently from the global LogLevel because it prints GBs of logs in Debug mode
@@ -132,6 +174,18 @@ func (m *MetricsConfig) GuessProtocol() Protocol {
 	return ProtocolHTTPProtobuf
 }
 
+// ClientTLS returns the TLS configuration for the metrics endpoint, falling back to the
+// common TLS configuration for the unset files
+func (m *MetricsConfig) ClientTLS() TLSConfig {
+	tlsCfg := m.TLS.withCommon(&m.CommonTLS)
+	tlsCfg.ReloadInterval = m.TLSReloadInterval
+	return tlsCfg
+}
+
+func (m *MetricsConfig) GetCompression() Compression {
+	return resolveCompression(m.Compression, m.CommonCompression)
+}
+
 func (m *MetricsConfig) OTLPMetricsEndpoint() (string, bool) {
 	if m.OTLPEndpointProvider != nil {
 		return m.OTLPEndpointProvider()
@@ -140,12 +194,12 @@ func (m *MetricsConfig) OTLPMetricsEndpoint() (string, bool) {
 }
 
 // EndpointEnabled specifies that the OTEL metrics node is enabled if and only if
-// either the OTEL endpoint, OTEL metrics endpoint, or a MetricsConsumer is defined.
+// either the OTEL endpoint, OTEL metrics endpoint, an output file, or a MetricsConsumer is defined.
 // If not enabled, this node won't be instantiated
 // Reason to disable linting: it requires to be a value despite it is considered a "heavy struct".
 // This method is invoked only once during startup time so it doesn't have a noticeable performance impact.
 func (m *MetricsConfig) EndpointEnabled() bool {
-	if m.MetricsConsumer != nil {
+	if m.MetricsConsumer != nil || m.File.Enabled() {
 		return true
 	}
 	ep, _ := m.OTLPMetricsEndpoint()
@@ -163,6 +217,7 @@ func httpMetricEndpointOptions(cfg *MetricsConfig) (OTLPOptions, error) {
 		"protocol", cfg.Protocol, "metricsProtocol", cfg.MetricsProtocol, "endpoint", murl.Host)
 
 	setMetricsProtocol(cfg)
+	opts.Scheme = murl.Scheme
 	opts.Endpoint = murl.Host
 	if murl.Scheme == "http" || murl.Scheme == "unix" {
 		log.Debug("Specifying insecure connection", "scheme", murl.Scheme)
@@ -184,6 +239,12 @@ func httpMetricEndpointOptions(cfg *MetricsConfig) (OTLPOptions, error) {
 		log.Debug("Setting InsecureSkipVerify")
 		opts.SkipTLSVerify = cfg.InsecureSkipVerify
 	}
+	tlsCfg := cfg.ClientTLS()
+	if opts.TLS, err = tlsCfg.load(murl.Hostname(), opts.Insecure, opts.SkipTLSVerify); err != nil {
+		return opts, err
+	}
+	opts.Compression = cfg.GetCompression()
+	opts.Temporality = cfg.TemporalityPreference
 
 	if cfg.InjectHeaders != nil {
 		cfg.InjectHeaders(opts.Headers)
@@ -214,6 +275,12 @@ func grpcMetricEndpointOptions(cfg *MetricsConfig) (OTLPOptions, error) {
 		log.Debug("Setting InsecureSkipVerify")
 		opts.SkipTLSVerify = true
 	}
+	tlsCfg := cfg.ClientTLS()
+	if opts.TLS, err = tlsCfg.load(murl.Hostname(), opts.Insecure, opts.SkipTLSVerify); err != nil {
+		return opts, err
+	}
+	opts.Compression = cfg.GetCompression()
+	opts.Temporality = cfg.TemporalityPreference
 
 	if cfg.InjectHeaders != nil {
 		cfg.InjectHeaders(opts.Headers)
@@ -25,7 +25,7 @@ func TestHTTPMetricsEndpointOptions(t *testing.T) {
 	}
 
 	t.Run("testing with two endpoints", func(t *testing.T) {
-		testMetricsHTTPOptions(t, OTLPOptions{Endpoint: "localhost:3232", URLPath: "/v1/metrics", Headers: map[string]string{}}, &mcfg)
+		testMetricsHTTPOptions(t, OTLPOptions{Scheme: "https", Endpoint: "localhost:3232", URLPath: "/v1/metrics", Headers: map[string]string{}}, &mcfg)
 	})
 
 	mcfg = MetricsConfig{
@@ -36,7 +36,7 @@ func TestHTTPMetricsEndpointOptions(t *testing.T) {
 	}
 
 	t.Run("testing with only common endpoint", func(t *testing.T) {
-		testMetricsHTTPOptions(t, OTLPOptions{Endpoint: "localhost:3131", URLPath: "/otlp/v1/metrics", Headers: map[string]string{}}, &mcfg)
+		testMetricsHTTPOptions(t, OTLPOptions{Scheme: "https", Endpoint: "localhost:3131", URLPath: "/otlp/v1/metrics", Headers: map[string]string{}}, &mcfg)
 	})
 
 	mcfg = MetricsConfig{
@@ -47,7 +47,7 @@ func TestHTTPMetricsEndpointOptions(t *testing.T) {
 		},
 	}
 	t.Run("testing with insecure endpoint", func(t *testing.T) {
-		testMetricsHTTPOptions(t, OTLPOptions{Endpoint: "localhost:3232", Insecure: true, Headers: map[string]string{}}, &mcfg)
+		testMetricsHTTPOptions(t, OTLPOptions{Scheme: "http", Endpoint: "localhost:3232", Insecure: true, Headers: map[string]string{}}, &mcfg)
 	})
 
 	mcfg = MetricsConfig{
@@ -59,7 +59,7 @@ func TestHTTPMetricsEndpointOptions(t *testing.T) {
 	}
 
 	t.Run("testing with skip TLS verification", func(t *testing.T) {
-		testMetricsHTTPOptions(t, OTLPOptions{Endpoint: "localhost:3232", URLPath: "/v1/metrics", SkipTLSVerify: true, Headers: map[string]string{}}, &mcfg)
+		testMetricsHTTPOptions(t, OTLPOptions{Scheme: "https", Endpoint: "localhost:3232", URLPath: "/v1/metrics", SkipTLSVerify: true, Headers: map[string]string{}}, &mcfg)
 	})
 }
 
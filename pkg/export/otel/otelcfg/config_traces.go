@@ -16,6 +16,9 @@ import (
 
 	"go.opentelemetry.io/obi/pkg/appolly/services"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
+	"go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
+	"go.opentelemetry.io/obi/pkg/export/otel/tailsampling"
 )
 
 func tlog() *slog.Logger {
@@ -36,8 +39,34 @@ type TracesConfig struct {
 	// InsecureSkipVerify is not standard, so we don't follow the same naming convention
 	InsecureSkipVerify bool `yaml:"insecure_skip_verify" env:"OTEL_EBPF_INSECURE_SKIP_VERIFY"`
 
+	// TLS allows setting a custom CA and a client certificate for the OTLP endpoint.
+	// Unset files are taken from the common OTEL_EXPORTER_OTLP_CERTIFICATE, OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE
+	// and OTEL_EXPORTER_OTLP_CLIENT_KEY environment variables.
+	TLS       TLSConfig `yaml:"tls" envPrefix:"OTEL_EXPORTER_OTLP_TRACES_"`
+	CommonTLS TLSConfig `yaml:"-" envPrefix:"OTEL_EXPORTER_OTLP_"`
+	// TLSReloadInterval specifies how often the client certificate and key files are reloaded
+	// from disk, so they can be rotated without restarting OBI. Zero disables reloading.
+	TLSReloadInterval time.Duration `yaml:"tls_reload_interval" env:"OTEL_EBPF_TRACES_TLS_RELOAD_INTERVAL" validate:"gte=0"`
+
+	// Compression of the OTLP payloads. Accepted values: none, gzip
+	Compression       Compression `yaml:"compression" env:"OTEL_EXPORTER_OTLP_TRACES_COMPRESSION" validate:"omitempty,oneof=none gzip"`
+	CommonCompression Compression `yaml:"-" env:"OTEL_EXPORTER_OTLP_COMPRESSION" validate:"omitempty,oneof=none gzip"`
+
 	SamplerConfig services.SamplerConfig `yaml:"sampler"`
 
+	// TailSampling buffers the spans of each trace during a decision window, and only exports the
+	// traces matching any of the configured policies. It is applied after the head sampler.
+	TailSampling tailsampling.Config `yaml:"tail_sampling" envPrefix:"OTEL_EBPF_TRACES_TAIL_SAMPLING_"`
+
+	// File writes the traces as OTLP-JSON lines into a local file, which can be later replayed
+	// with the OpenTelemetry Collector's otlpjsonfile receiver. When set, it takes precedence
+	// over the OTLP endpoint.
+	File otlpfile.Config `yaml:"file" envPrefix:"OTEL_EBPF_TRACES_FILE_"`
+
+	// DiskQueue stores the traces into a disk-backed queue before sending them to the OTLP endpoint,
+	// so they are kept while the endpoint is unavailable, and across the restarts of OBI.
+	DiskQueue diskqueue.DiskQueueConfig `yaml:"disk_queue" envPrefix:"OTEL_EBPF_TRACES_DISK_QUEUE_"`
+
 	// Configuration options below this line will remain undocumented at the moment,
 	// but can be useful for performance-tuning of some customers.
 	MaxQueueSize int           `yaml:"max_queue_size" env:"OTEL_EBPF_OTLP_TRACES_MAX_QUEUE_SIZE"`
@@ -74,10 +103,11 @@ func (m TracesConfig) MarshalYAML() (any, error) {
 }
 
 // Enabled specifies that the OTEL traces node is enabled if and only if
-// either the OTEL endpoint and OTEL traces endpoint is defined.
+// either the OTEL endpoint, OTEL traces endpoint or an output file is defined.
 // If not enabled, this node won't be instantiated
 func (m *TracesConfig) Enabled() bool {
-	return m.TracesConsumer != nil || m.CommonEndpoint != "" || m.TracesEndpoint != "" || m.GetProtocol() == ProtocolDebug
+	return m.TracesConsumer != nil || m.CommonEndpoint != "" || m.TracesEndpoint != "" ||
+		m.File.Enabled() || m.GetProtocol() == ProtocolDebug
 }
 
 func (m *TracesConfig) GetProtocol() Protocol {
@@ -93,6 +123,18 @@ func (m *TracesConfig) GetProtocol() Protocol {
 	return m.guessProtocol()
 }
 
+// ClientTLS returns the TLS configuration for the traces endpoint, falling back to the
+// common TLS configuration for the unset files
+func (m *TracesConfig) ClientTLS() TLSConfig {
+	tlsCfg := m.TLS.withCommon(&m.CommonTLS)
+	tlsCfg.ReloadInterval = m.TLSReloadInterval
+	return tlsCfg
+}
+
+func (m *TracesConfig) GetCompression() Compression {
+	return resolveCompression(m.Compression, m.CommonCompression)
+}
+
 func (m *TracesConfig) OTLPTracesEndpoint() (string, bool) {
 	if m.OTLPEndpointProvider != nil {
 		return m.OTLPEndpointProvider()
@@ -167,6 +209,7 @@ func HTTPTracesEndpointOptions(cfg *TracesConfig) (OTLPOptions, error) {
 		log.Debug("Setting InsecureSkipVerify")
 		opts.SkipTLSVerify = true
 	}
+	opts.Compression = cfg.GetCompression()
 
 	if cfg.InjectHeaders != nil {
 		cfg.InjectHeaders(opts.Headers)
@@ -197,6 +240,7 @@ func GRPCTracesEndpointOptions(cfg *TracesConfig) (OTLPOptions, error) {
 		log.Debug("Setting InsecureSkipVerify")
 		opts.SkipTLSVerify = true
 	}
+	opts.Compression = cfg.GetCompression()
 
 	if cfg.InjectHeaders != nil {
 		cfg.InjectHeaders(opts.Headers)
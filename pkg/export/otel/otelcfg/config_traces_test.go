@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
 )
 
 func TestHTTPTracesEndpoint(t *testing.T) {
@@ -313,6 +314,7 @@ func TestTracesSetupHTTP_DoNotOverrideEnv(t *testing.T) {
 func TestTracesConfig_Enabled(t *testing.T) {
 	assert.True(t, (&TracesConfig{CommonEndpoint: "foo"}).Enabled())
 	assert.True(t, (&TracesConfig{TracesEndpoint: "foo"}).Enabled())
+	assert.True(t, (&TracesConfig{File: otlpfile.Config{Path: "/tmp/traces.jsonl"}}).Enabled())
 }
 
 func TestTracesConfig_Disabled(t *testing.T) {
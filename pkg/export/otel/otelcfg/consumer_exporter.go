@@ -18,18 +18,22 @@ import (
 // ConsumerExporter is an sdkmetric.Exporter that sends metrics to a collector consumer.
 // It converts SDK metric data to collector pmetric format.
 type ConsumerExporter struct {
-	consumer consumer.Metrics
+	consumer    consumer.Metrics
+	temporality sdkmetric.TemporalitySelector
 }
 
 // NewConsumerExporter creates a new ConsumerExporter that wraps the given consumer.
-func NewConsumerExporter(c consumer.Metrics) *ConsumerExporter {
-	return &ConsumerExporter{consumer: c}
+// If the temporality preference is unset, cumulative temporality is used for all the instruments.
+func NewConsumerExporter(c consumer.Metrics, temporality TemporalityPreference) *ConsumerExporter {
+	return &ConsumerExporter{consumer: c, temporality: temporality.Selector()}
 }
 
 // Temporality returns the temporality to use for the given instrument kind.
-func (e *ConsumerExporter) Temporality(_ sdkmetric.InstrumentKind) metricdata.Temporality {
-	// Default to cumulative temporality for all instrument kinds
-	return metricdata.CumulativeTemporality
+func (e *ConsumerExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	if e.temporality == nil {
+		return metricdata.CumulativeTemporality
+	}
+	return e.temporality(kind)
 }
 
 // Aggregation returns the aggregation to use for the given instrument kind.
@@ -0,0 +1,119 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcfg // import "go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configoptional"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/exporter/otlphttpexporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+
+	"go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
+)
+
+// diskQueueMetricsExporter is a ConsumerExporter that stores the metrics into a disk queue,
+// which forwards them to an OTLP collector exporter
+type diskQueueMetricsExporter struct {
+	*ConsumerExporter
+	queue *diskqueue.Metrics
+	exp   exporter.Metrics
+}
+
+func (i *MetricsExporterInstancer) diskQueueMetricsExporter(ctx context.Context) (*diskQueueMetricsExporter, error) {
+	exp, err := i.collectorMetricsExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := exp.Start(ctx, nopHost{}); err != nil {
+		return nil, fmt.Errorf("starting metrics exporter: %w", err)
+	}
+	queue, err := diskqueue.NewMetrics(&i.Cfg.DiskQueue, exp, i.InternalMetrics)
+	if err != nil {
+		_ = exp.Shutdown(ctx)
+		return nil, err
+	}
+	queue.Start()
+	return &diskQueueMetricsExporter{
+		ConsumerExporter: NewConsumerExporter(queue, i.Cfg.TemporalityPreference),
+		queue:            queue,
+		exp:              exp,
+	}, nil
+}
+
+func (d *diskQueueMetricsExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(d.queue.Shutdown(ctx), d.exp.Shutdown(ctx))
+}
+
+// collectorMetricsExporter returns an OTLP exporter that accepts the metrics in pmetric format,
+// with the queue and retries disabled, as they are managed by the disk queue
+func (i *MetricsExporterInstancer) collectorMetricsExporter(ctx context.Context) (exporter.Metrics, error) {
+	tlsCfg := i.Cfg.ClientTLS()
+	switch proto := i.Cfg.GetProtocol(); proto {
+	case ProtocolHTTPJSON, ProtocolHTTPProtobuf, "":
+		opts, err := httpMetricEndpointOptions(i.Cfg)
+		if err != nil {
+			return nil, err
+		}
+		urlPath := opts.URLPath
+		if urlPath == "" {
+			urlPath = "/v1/metrics"
+		}
+		factory := otlphttpexporter.NewFactory()
+		config := factory.CreateDefaultConfig().(*otlphttpexporter.Config)
+		config.QueueConfig = configoptional.None[exporterhelper.QueueBatchConfig]()
+		config.RetryConfig.Enabled = false
+		config.MetricsEndpoint = opts.Scheme + "://" + opts.Endpoint + urlPath
+		config.ClientConfig.Endpoint = opts.Scheme + "://" + opts.Endpoint
+		config.ClientConfig.TLS = tlsCfg.Collector(opts.Insecure, i.Cfg.InsecureSkipVerify)
+		config.ClientConfig.Headers = CollectorHeaders(opts.Headers)
+		config.ClientConfig.Compression = opts.Compression.Collector()
+		return factory.CreateMetrics(ctx, collectorSettings(factory.Type()), config)
+	case ProtocolGRPC:
+		opts, err := grpcMetricEndpointOptions(i.Cfg)
+		if err != nil {
+			return nil, err
+		}
+		factory := otlpexporter.NewFactory()
+		config := factory.CreateDefaultConfig().(*otlpexporter.Config)
+		config.QueueConfig = configoptional.None[exporterhelper.QueueBatchConfig]()
+		config.RetryConfig.Enabled = false
+		config.ClientConfig.Endpoint = opts.Endpoint
+		config.ClientConfig.TLS = tlsCfg.Collector(opts.Insecure, i.Cfg.InsecureSkipVerify)
+		config.ClientConfig.Headers = CollectorHeaders(opts.Headers)
+		config.ClientConfig.Compression = opts.Compression.Collector()
+		return factory.CreateMetrics(ctx, collectorSettings(factory.Type()), config)
+	default:
+		return nil, fmt.Errorf("invalid protocol value: %q. Accepted values are: %s, %s, %s",
+			proto, ProtocolGRPC, ProtocolHTTPJSON, ProtocolHTTPProtobuf)
+	}
+}
+
+func collectorSettings(dataType component.Type) exporter.Settings {
+	return exporter.Settings{
+		ID: component.NewIDWithName(dataType, "obi"),
+		TelemetrySettings: component.TelemetrySettings{
+			Logger:         zap.NewNop(),
+			MeterProvider:  metricnoop.NewMeterProvider(),
+			TracerProvider: tracenoop.NewTracerProvider(),
+			Resource:       pcommon.NewResource(),
+		},
+	}
+}
+
+// nopHost prevents nil pointer dereference after invoking the collector exporter's Start
+type nopHost struct{}
+
+func (nopHost) GetExtensions() map[component.ID]component.Component {
+	return nil
+}
@@ -12,6 +12,9 @@ import (
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
+	"go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
 )
 
 func meilog() *slog.Logger {
@@ -25,6 +28,10 @@ type MetricsExporterInstancer struct {
 	mutex    sync.Mutex
 	instance sdkmetric.Exporter
 	Cfg      *MetricsConfig
+	// InternalMetrics reports the status of the disk queue, if enabled. It must be set before
+	// the exporter is instantiated, so it is ignored when the internal metrics are exported
+	// through this same exporter.
+	InternalMetrics imetrics.Reporter
 }
 
 // Instantiate the OTLP HTTP or GRPC metrics exporter, or a consumer-based exporter
@@ -38,11 +45,27 @@ func (i *MetricsExporterInstancer) Instantiate(ctx context.Context) (sdkmetric.E
 	// If a MetricsConsumer is configured, use the ConsumerExporter
 	if i.Cfg.MetricsConsumer != nil {
 		meilog().Debug("instantiating Consumer MetricsReporter")
-		i.instance = NewConsumerExporter(i.Cfg.MetricsConsumer)
+		i.instance = NewConsumerExporter(i.Cfg.MetricsConsumer, i.Cfg.TemporalityPreference)
 		return i.instance, nil
 	}
 
 	var err error
+	if i.Cfg.File.Enabled() {
+		meilog().Debug("instantiating File MetricsReporter", "path", i.Cfg.File.Path)
+		if i.instance, err = newFileMetricsExporter(&i.Cfg.File, i.Cfg.TemporalityPreference); err != nil {
+			return nil, fmt.Errorf("can't instantiate OTEL file metrics exporter: %w", err)
+		}
+		return i.instance, nil
+	}
+
+	if i.Cfg.DiskQueue.Enabled() {
+		meilog().Debug("instantiating disk-queued MetricsReporter", "directory", i.Cfg.DiskQueue.Directory)
+		if i.instance, err = i.diskQueueMetricsExporter(ctx); err != nil {
+			return nil, fmt.Errorf("can't instantiate OTEL disk-queued metrics exporter: %w", err)
+		}
+		return i.instance, nil
+	}
+
 	switch proto := i.Cfg.GetProtocol(); proto {
 	case ProtocolHTTPJSON, ProtocolHTTPProtobuf, "": // zero value defaults to HTTP for backwards-compatibility
 		meilog().Debug("instantiating HTTP MetricsReporter", "protocol", proto)
@@ -61,6 +84,30 @@ func (i *MetricsExporterInstancer) Instantiate(ctx context.Context) (sdkmetric.E
 	return i.instance, nil
 }
 
+// fileMetricsExporter is a ConsumerExporter that writes the metrics into a file,
+// which is closed on shutdown
+type fileMetricsExporter struct {
+	*ConsumerExporter
+	writer *otlpfile.RotatingWriter
+}
+
+func newFileMetricsExporter(cfg *otlpfile.Config, temporality TemporalityPreference) (*fileMetricsExporter, error) {
+	writer, err := otlpfile.NewRotatingWriter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	mc, err := otlpfile.NewMetricsConsumer(writer)
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	return &fileMetricsExporter{ConsumerExporter: NewConsumerExporter(mc, temporality), writer: writer}, nil
+}
+
+func (f *fileMetricsExporter) Shutdown(_ context.Context) error {
+	return f.writer.Close()
+}
+
 func (i *MetricsExporterInstancer) httpMetricsExporter(ctx context.Context) (sdkmetric.Exporter, error) {
 	opts, err := httpMetricEndpointOptions(i.Cfg)
 	if err != nil {
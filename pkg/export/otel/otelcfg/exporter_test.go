@@ -4,10 +4,25 @@
 package otelcfg
 
 import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
+	"go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
 )
 
 const testTimeout = 5 * time.Second
@@ -52,3 +67,79 @@ func TestSingleton(t *testing.T) {
 		}
 	}
 }
+
+func TestFileMetricsExporter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metrics.jsonl")
+	cfg := &MetricsConfig{File: otlpfile.Config{Path: path}}
+	require.True(t, cfg.EndpointEnabled())
+
+	instancer := MetricsExporterInstancer{Cfg: cfg}
+	exp, err := instancer.Instantiate(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, exp.Export(t.Context(), &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Metrics: []metricdata.Metrics{{
+				Name: "obi.test",
+				Data: metricdata.Sum[int64]{
+					Temporality: metricdata.CumulativeTemporality,
+					IsMonotonic: true,
+					DataPoints:  []metricdata.DataPoint[int64]{{Value: 3}},
+				},
+			}},
+		}},
+	}))
+	require.NoError(t, exp.Shutdown(t.Context()))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	unmarshaler := pmetric.JSONUnmarshaler{}
+	md, err := unmarshaler.UnmarshalMetrics(bytes.TrimSuffix(content, []byte("\n")))
+	require.NoError(t, err)
+	m := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "obi.test", m.Name())
+	assert.Equal(t, int64(3), m.Sum().DataPoints().At(0).IntValue())
+}
+
+func TestDiskQueueMetricsExporter(t *testing.T) {
+	var mt sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := pmetricotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(body))
+		mt.Lock()
+		received = append(received, r.URL.Path+" "+req.Metrics().ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Name())
+		mt.Unlock()
+		resp, err := pmetricotlp.NewExportResponse().MarshalProto()
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	instancer := MetricsExporterInstancer{Cfg: &MetricsConfig{
+		MetricsEndpoint: server.URL + "/otlp/v1/metrics",
+		MetricsProtocol: ProtocolHTTPProtobuf,
+		DiskQueue:       diskqueue.DiskQueueConfig{Directory: dir},
+	}}
+	exp, err := instancer.Instantiate(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, exp.Export(t.Context(), &metricdata.ResourceMetrics{
+		ScopeMetrics: []metricdata.ScopeMetrics{{
+			Metrics: []metricdata.Metrics{{
+				Name: "obi.test",
+				Data: metricdata.Gauge[int64]{DataPoints: []metricdata.DataPoint[int64]{{Value: 3}}},
+			}},
+		}},
+	}))
+	require.Eventually(t, func() bool {
+		mt.Lock()
+		defer mt.Unlock()
+		return len(received) == 1
+	}, testTimeout, 10*time.Millisecond)
+	assert.Equal(t, []string{"/otlp/v1/metrics obi.test"}, received)
+	require.NoError(t, exp.Shutdown(t.Context()))
+	assert.DirExists(t, filepath.Join(dir, diskqueue.SignalMetrics))
+}
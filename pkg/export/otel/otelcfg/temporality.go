@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcfg // import "go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+
+import (
+	"strings"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// TemporalityPreference values for the OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE
+// standard configuration value
+type TemporalityPreference string
+
+const (
+	TemporalityUnset      TemporalityPreference = ""
+	TemporalityCumulative TemporalityPreference = "cumulative"
+	TemporalityDelta      TemporalityPreference = "delta"
+	TemporalityLowMemory  TemporalityPreference = "lowmemory"
+)
+
+// UnmarshalText accepts the temporality preference values in a case-insensitive manner,
+// as required by the OpenTelemetry specification for the environment variables
+func (t *TemporalityPreference) UnmarshalText(text []byte) error {
+	*t = TemporalityPreference(strings.ToLower(strings.TrimSpace(string(text))))
+	return nil
+}
+
+// Selector returns the temporality of each instrument kind for the given preference,
+// as defined by the OpenTelemetry OTLP exporter specification:
+//   - cumulative: all the instruments use cumulative temporality
+//   - delta: counters and histograms (synchronous or not) use delta temporality,
+//     while up-down counters use cumulative temporality
+//   - lowmemory: like delta, but observable counters use cumulative temporality,
+//     so they don't need to remember the previously observed values
+func (t TemporalityPreference) Selector() sdkmetric.TemporalitySelector {
+	switch t {
+	case TemporalityDelta:
+		return deltaTemporality
+	case TemporalityLowMemory:
+		return lowMemoryTemporality
+	default:
+		return sdkmetric.DefaultTemporalitySelector
+	}
+}
+
+func deltaTemporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindCounter,
+		sdkmetric.InstrumentKindObservableCounter,
+		sdkmetric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
+
+func lowMemoryTemporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	switch kind {
+	case sdkmetric.InstrumentKindCounter,
+		sdkmetric.InstrumentKindHistogram:
+		return metricdata.DeltaTemporality
+	default:
+		return metricdata.CumulativeTemporality
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcfg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/caarlos0/env/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+
+	"go.opentelemetry.io/obi/pkg/export/otel/metric"
+)
+
+func TestTemporalityPreference_Selector(t *testing.T) {
+	const (
+		cumul = metricdata.CumulativeTemporality
+		delta = metricdata.DeltaTemporality
+	)
+	type expected struct {
+		counter, upDown, histogram, obsCounter, obsUpDown, obsGauge, gauge metricdata.Temporality
+	}
+	for _, tc := range []struct {
+		pref TemporalityPreference
+		exp  expected
+	}{
+		{pref: TemporalityUnset, exp: expected{cumul, cumul, cumul, cumul, cumul, cumul, cumul}},
+		{pref: TemporalityCumulative, exp: expected{cumul, cumul, cumul, cumul, cumul, cumul, cumul}},
+		{pref: TemporalityDelta, exp: expected{delta, cumul, delta, delta, cumul, cumul, cumul}},
+		{pref: TemporalityLowMemory, exp: expected{delta, cumul, delta, cumul, cumul, cumul, cumul}},
+	} {
+		t.Run(string(tc.pref), func(t *testing.T) {
+			sel := tc.pref.Selector()
+			assert.Equal(t, tc.exp, expected{
+				counter:    sel(sdkmetric.InstrumentKindCounter),
+				upDown:     sel(sdkmetric.InstrumentKindUpDownCounter),
+				histogram:  sel(sdkmetric.InstrumentKindHistogram),
+				obsCounter: sel(sdkmetric.InstrumentKindObservableCounter),
+				obsUpDown:  sel(sdkmetric.InstrumentKindObservableUpDownCounter),
+				obsGauge:   sel(sdkmetric.InstrumentKindObservableGauge),
+				gauge:      sel(sdkmetric.InstrumentKindGauge),
+			})
+		})
+	}
+}
+
+func TestTemporalityPreference_Env(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "Delta")
+	cfg := MetricsConfig{}
+	require.NoError(t, env.Parse(&cfg))
+	assert.Equal(t, TemporalityDelta, cfg.TemporalityPreference)
+}
+
+func TestMetricsEndpointOptions_Temporality(t *testing.T) {
+	defer RestoreEnvAfterExecution()()
+	mcfg := MetricsConfig{
+		MetricsEndpoint:       "http://localhost:3131",
+		TemporalityPreference: TemporalityLowMemory,
+	}
+	opts, err := httpMetricEndpointOptions(&mcfg)
+	require.NoError(t, err)
+	assert.Equal(t, TemporalityLowMemory, opts.Temporality)
+
+	opts, err = grpcMetricEndpointOptions(&mcfg)
+	require.NoError(t, err)
+	assert.Equal(t, TemporalityLowMemory, opts.Temporality)
+}
+
+func TestConsumerExporter_Temporality(t *testing.T) {
+	for _, tc := range []struct {
+		pref                TemporalityPreference
+		expectedTemporality pmetric.AggregationTemporality
+		expectedValues      []int64
+	}{
+		{pref: TemporalityUnset, expectedTemporality: pmetric.AggregationTemporalityCumulative, expectedValues: []int64{3, 5}},
+		{pref: TemporalityCumulative, expectedTemporality: pmetric.AggregationTemporalityCumulative, expectedValues: []int64{3, 5}},
+		{pref: TemporalityDelta, expectedTemporality: pmetric.AggregationTemporalityDelta, expectedValues: []int64{3, 2}},
+		{pref: TemporalityLowMemory, expectedTemporality: pmetric.AggregationTemporalityDelta, expectedValues: []int64{3, 2}},
+	} {
+		t.Run(string(tc.pref), func(t *testing.T) {
+			var exported []pmetric.Metrics
+			mc, err := consumer.NewMetrics(func(_ context.Context, md pmetric.Metrics) error {
+				exported = append(exported, md)
+				return nil
+			})
+			require.NoError(t, err)
+
+			provider := metric.NewMeterProvider(
+				metric.WithReader(metric.NewPeriodicReader(NewConsumerExporter(mc, tc.pref))))
+			counter, err := provider.Meter("test").Int64Counter("obi.test.counter")
+			require.NoError(t, err)
+
+			counter.Add(t.Context(), 3)
+			require.NoError(t, provider.ForceFlush(t.Context()))
+			counter.Add(t.Context(), 2)
+			require.NoError(t, provider.ForceFlush(t.Context()))
+
+			require.Len(t, exported, 2)
+			for i, md := range exported {
+				sum := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Sum()
+				assert.Equal(t, tc.expectedTemporality, sum.AggregationTemporality())
+				assert.Equal(t, tc.expectedValues[i], sum.DataPoints().At(0).IntValue())
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcfg // import "go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/config/configcompression"
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// Compression values for the OTEL_EXPORTER_OTLP_COMPRESSION, OTEL_EXPORTER_OTLP_TRACES_COMPRESSION and
+// OTEL_EXPORTER_OTLP_METRICS_COMPRESSION standard configuration values
+type Compression string
+
+const (
+	CompressionUnset Compression = ""
+	CompressionNone  Compression = "none"
+	CompressionGzip  Compression = "gzip"
+)
+
+// resolveCompression returns the signal-specific compression, or the common compression if the former is unset
+func resolveCompression(specific, common Compression) Compression {
+	if specific != CompressionUnset {
+		return specific
+	}
+	return common
+}
+
+// Collector returns the equivalent compression type for the OpenTelemetry Collector exporters
+func (c Compression) Collector() configcompression.Type {
+	if c == CompressionGzip {
+		return configcompression.TypeGzip
+	}
+	return ""
+}
+
+// TLSConfig allows verifying the OTLP endpoint with a custom CA and presenting a client
+// certificate for mutual TLS.
+// The environment variables are prefixed by the OTEL_EXPORTER_OTLP_ and
+// OTEL_EXPORTER_OTLP_TRACES_/OTEL_EXPORTER_OTLP_METRICS_ standard prefixes.
+type TLSConfig struct {
+	// CAFile is the path to a PEM-encoded CA bundle used to verify the server certificate.
+	// If empty, the system root CAs are used.
+	CAFile string `yaml:"ca_file" env:"CERTIFICATE"`
+	// CertFile is the path to the PEM-encoded client certificate for mTLS
+	CertFile string `yaml:"cert_file" env:"CLIENT_CERTIFICATE"`
+	// KeyFile is the path to the PEM-encoded client private key for mTLS
+	KeyFile string `yaml:"key_file" env:"CLIENT_KEY"`
+	// ServerName overrides the server name that is used to verify the server certificate
+	ServerName string `yaml:"server_name"`
+	// ReloadInterval is taken from the TLSReloadInterval property of the traces and metrics
+	// configurations, which is not specific to the TLS standard configuration.
+	ReloadInterval time.Duration `yaml:"-" env:"-"`
+}
+
+func (t *TLSConfig) empty() bool {
+	return t.CAFile == "" && t.CertFile == "" && t.KeyFile == "" && t.ServerName == ""
+}
+
+// withCommon returns a copy of the TLS configuration where the unset file properties
+// are taken from the common configuration
+func (t *TLSConfig) withCommon(common *TLSConfig) TLSConfig {
+	res := *t
+	if res.CAFile == "" {
+		res.CAFile = common.CAFile
+	}
+	if res.CertFile == "" {
+		res.CertFile = common.CertFile
+	}
+	if res.KeyFile == "" {
+		res.KeyFile = common.KeyFile
+	}
+	return res
+}
+
+// Collector returns the equivalent TLS configuration for the OpenTelemetry Collector exporters
+func (t *TLSConfig) Collector(insecure, skipVerify bool) configtls.ClientConfig {
+	cfg := configtls.NewDefaultClientConfig()
+	cfg.Insecure = insecure
+	cfg.InsecureSkipVerify = skipVerify
+	cfg.CAFile = t.CAFile
+	cfg.CertFile = t.CertFile
+	cfg.KeyFile = t.KeyFile
+	cfg.ServerName = t.ServerName
+	cfg.ReloadInterval = t.ReloadInterval
+	return cfg
+}
+
+// load the TLS configuration for the OpenTelemetry SDK exporters. It returns nil if the
+// connection is insecure or there isn't any custom TLS configuration.
+// The client certificate is reloaded by the OpenTelemetry Collector TLS configuration. As the
+// root CAs of a tls.Config can't be replaced once it is in use, the server certificate is
+// verified against the last reloaded CA file, if any. The certificate must then be valid for
+// the configured server name or, if unset, for the host of the endpoint.
+func (t *TLSConfig) load(host string, insecure, skipVerify bool) (*tls.Config, error) {
+	if insecure || t.empty() {
+		return nil, nil
+	}
+	cfg := t.Collector(insecure, skipVerify)
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid TLS configuration: %w", err)
+	}
+	tlsCfg, err := cfg.LoadTLSConfig(context.Background())
+	if err != nil || skipVerify || t.ReloadInterval == 0 || t.CAFile == "" {
+		return tlsCfg, err
+	}
+	serverName := t.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+	if serverName == "" {
+		return nil, errors.New("can't reload the CA file: the endpoint host is unknown and the TLS server_name is not set")
+	}
+	cas := &caReloader{
+		file:       t.CAFile,
+		serverName: serverName,
+		interval:   t.ReloadInterval,
+		pool:       tlsCfg.RootCAs,
+		nextReload: time.Now().Add(t.ReloadInterval),
+	}
+	// the default verification would use the RootCAs that were loaded at startup
+	tlsCfg.InsecureSkipVerify = true
+	tlsCfg.VerifyConnection = cas.verifyConnection
+	return tlsCfg, nil
+}
+
+// caReloader reloads the CA file when the last reload happened more than the reload
+// interval ago. If the file can't be reloaded, the previous CAs are kept.
+type caReloader struct {
+	file string
+	// serverName that the server certificate must be valid for
+	serverName string
+	interval   time.Duration
+	mt         sync.Mutex
+	pool       *x509.CertPool
+	nextReload time.Time
+}
+
+func (r *caReloader) certPool() *x509.CertPool {
+	r.mt.Lock()
+	defer r.mt.Unlock()
+	if now := time.Now(); now.After(r.nextReload) {
+		r.nextReload = now.Add(r.interval)
+		pool, err := loadCAFile(r.file)
+		if err != nil {
+			slog.Warn("can't reload the OTLP endpoint CA file. Keeping the previous CAs",
+				"file", r.file, "error", err)
+			return r.pool
+		}
+		r.pool = pool
+	}
+	return r.pool
+}
+
+func (r *caReloader) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("the server did not provide any certificate")
+	}
+	opts := x509.VerifyOptions{
+		DNSName:       r.serverName,
+		Roots:         r.certPool(),
+		Intermediates: x509.NewCertPool(),
+	}
+	for _, cert := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+func loadCAFile(file string) (*x509.CertPool, error) {
+	caPEM, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid certificates in %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otelcfg
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/caarlos0/env/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configcompression"
+)
+
+func TestTLSConfig_Env(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_CERTIFICATE", "/common/ca.pem")
+	t.Setenv("OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE", "/common/cert.pem")
+	t.Setenv("OTEL_EXPORTER_OTLP_CLIENT_KEY", "/common/key.pem")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_CLIENT_CERTIFICATE", "/traces/cert.pem")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_CLIENT_KEY", "/traces/key.pem")
+	t.Setenv("OTEL_EXPORTER_OTLP_COMPRESSION", "gzip")
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_COMPRESSION", "none")
+	t.Setenv("OTEL_EBPF_TRACES_TLS_RELOAD_INTERVAL", "1m")
+
+	tcfg := TracesConfig{}
+	require.NoError(t, env.Parse(&tcfg))
+	assert.Equal(t, TLSConfig{
+		CAFile:         "/common/ca.pem",
+		CertFile:       "/traces/cert.pem",
+		KeyFile:        "/traces/key.pem",
+		ReloadInterval: time.Minute,
+	}, tcfg.ClientTLS())
+	assert.Equal(t, CompressionGzip, tcfg.GetCompression())
+
+	mcfg := MetricsConfig{TLS: TLSConfig{CAFile: "/metrics/ca.pem", ServerName: "collector"}}
+	require.NoError(t, env.Parse(&mcfg))
+	assert.Equal(t, TLSConfig{
+		CAFile:     "/metrics/ca.pem",
+		CertFile:   "/common/cert.pem",
+		KeyFile:    "/common/key.pem",
+		ServerName: "collector",
+	}, mcfg.ClientTLS())
+	assert.Equal(t, CompressionNone, mcfg.GetCompression())
+}
+
+func TestCompression_Collector(t *testing.T) {
+	assert.Equal(t, configcompression.TypeGzip, CompressionGzip.Collector())
+	assert.Empty(t, CompressionNone.Collector())
+	assert.Empty(t, CompressionUnset.Collector())
+}
+
+func TestTLSConfig_Collector(t *testing.T) {
+	tc := TLSConfig{
+		CAFile:         "ca.pem",
+		CertFile:       "cert.pem",
+		KeyFile:        "key.pem",
+		ServerName:     "collector",
+		ReloadInterval: time.Minute,
+	}
+	cc := tc.Collector(false, true)
+	assert.False(t, cc.Insecure)
+	assert.True(t, cc.InsecureSkipVerify)
+	assert.Equal(t, "ca.pem", cc.CAFile)
+	assert.Equal(t, "cert.pem", cc.CertFile)
+	assert.Equal(t, "key.pem", cc.KeyFile)
+	assert.Equal(t, "collector", cc.ServerName)
+	assert.Equal(t, time.Minute, cc.ReloadInterval)
+}
+
+func TestTLSConfig_Load(t *testing.T) {
+	dir := t.TempDir()
+	caFile, certFile, keyFile := writeTestCerts(t, dir)
+
+	t.Run("no custom TLS", func(t *testing.T) {
+		cfg, err := (&TLSConfig{}).load("collector", false, true)
+		require.NoError(t, err)
+		assert.Nil(t, cfg)
+	})
+	t.Run("insecure connection", func(t *testing.T) {
+		cfg, err := (&TLSConfig{CAFile: caFile}).load("collector", true, false)
+		require.NoError(t, err)
+		assert.Nil(t, cfg)
+	})
+	t.Run("mTLS", func(t *testing.T) {
+		cfg, err := (&TLSConfig{
+			CAFile: caFile, CertFile: certFile, KeyFile: keyFile, ServerName: "collector",
+		}).load("collector", false, false)
+		require.NoError(t, err)
+		require.NotNil(t, cfg)
+		assert.NotNil(t, cfg.RootCAs)
+		assert.Equal(t, "collector", cfg.ServerName)
+		assert.False(t, cfg.InsecureSkipVerify)
+		require.NotNil(t, cfg.GetClientCertificate)
+		cert, err := cfg.GetClientCertificate(nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, cert.Certificate)
+	})
+	t.Run("missing key", func(t *testing.T) {
+		_, err := (&TLSConfig{CertFile: certFile}).load("collector", false, false)
+		require.Error(t, err)
+	})
+	t.Run("missing CA file", func(t *testing.T) {
+		_, err := (&TLSConfig{CAFile: filepath.Join(dir, "nonexistent.pem")}).load("collector", false, false)
+		require.Error(t, err)
+	})
+}
+
+func TestTLSConfig_ReloadCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer server.Close()
+
+	// the CA file does not initially contain the server certificate
+	caFile, _, _ := writeTestCerts(t, t.TempDir())
+	cfg, err := (&TLSConfig{CAFile: caFile, ReloadInterval: time.Millisecond}).load("127.0.0.1", false, false)
+	require.NoError(t, err)
+	require.NotNil(t, cfg.VerifyConnection)
+
+	get := func() error {
+		client := http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+	require.Error(t, get())
+
+	// rotating the CA file makes the server certificate trusted without reloading the configuration
+	require.NoError(t, os.WriteFile(caFile,
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		assert.NoError(ct, get())
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestTLSConfig_ReloadCA_VerifiesServerName(t *testing.T) {
+	// self-signed CA certificate, which is only valid for the collector.example host
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "collector.example"},
+		DNSNames:              []string{"collector.example"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	server.StartTLS()
+	defer server.Close()
+
+	get := func(cfg *tls.Config) error {
+		client := http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// the server is accessed by its IP, which is not in the certificate
+	cfg, err := (&TLSConfig{CAFile: caFile, ReloadInterval: time.Minute}).load("127.0.0.1", false, false)
+	require.NoError(t, err)
+	require.Error(t, get(cfg))
+
+	cfg, err = (&TLSConfig{
+		CAFile: caFile, ServerName: "collector.example", ReloadInterval: time.Minute,
+	}).load("127.0.0.1", false, false)
+	require.NoError(t, err)
+	require.NoError(t, get(cfg))
+
+	// fails closed if the name to verify is unknown
+	_, err = (&TLSConfig{CAFile: caFile, ReloadInterval: time.Minute}).load("", false, false)
+	require.Error(t, err)
+}
+
+func TestMetricsEndpointOptions_TLS(t *testing.T) {
+	defer RestoreEnvAfterExecution()()
+	caFile, certFile, keyFile := writeTestCerts(t, t.TempDir())
+	mcfg := MetricsConfig{
+		MetricsEndpoint: "https://localhost:3131",
+		TLS:             TLSConfig{CAFile: caFile, CertFile: certFile, KeyFile: keyFile},
+		Compression:     CompressionGzip,
+	}
+	opts, err := httpMetricEndpointOptions(&mcfg)
+	require.NoError(t, err)
+	require.NotNil(t, opts.TLS)
+	assert.Equal(t, CompressionGzip, opts.Compression)
+
+	opts, err = grpcMetricEndpointOptions(&mcfg)
+	require.NoError(t, err)
+	require.NotNil(t, opts.TLS)
+	assert.Equal(t, CompressionGzip, opts.Compression)
+
+	// plain-text endpoints ignore the TLS configuration
+	mcfg.MetricsEndpoint = "http://localhost:3131"
+	opts, err = httpMetricEndpointOptions(&mcfg)
+	require.NoError(t, err)
+	assert.Nil(t, opts.TLS)
+}
+
+// writeTestCerts writes a self-signed CA, and a client certificate and key signed by it
+func writeTestCerts(t *testing.T, dir string) (caFile, certFile, keyFile string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "obi"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caTemplate, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	writePEM := func(name, blockType string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+		return path
+	}
+	return writePEM("ca.pem", "CERTIFICATE", caDER),
+		writePEM("cert.pem", "CERTIFICATE", clientDER),
+		writePEM("key.pem", "EC PRIVATE KEY", clientKeyDER)
+}
@@ -0,0 +1,82 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package otlpfile provides consumers that write traces and metrics as OTLP-JSON lines
+// into local files, using the same format as the OpenTelemetry Collector's file exporter.
+// The generated files can be replayed with the Collector's otlpjsonfile receiver.
+package otlpfile // import "go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func log() *slog.Logger {
+	return slog.With("component", "otlpfile.Writer")
+}
+
+// Config for the OTLP-JSON file exporter
+type Config struct {
+	// Path of the file where the OTLP-JSON lines are written. Rotated files are stored in
+	// the same directory. If empty, the file exporter is disabled.
+	Path string `yaml:"path" env:"PATH"`
+	// MaxSizeMB is the maximum size, in megabytes, of the file before it gets rotated.
+	// Zero means no size-based rotation.
+	MaxSizeMB int `yaml:"max_size_mb" env:"MAX_SIZE_MB" validate:"gte=0"`
+	// RotationInterval is the maximum age of the file before it gets rotated.
+	// Zero means no time-based rotation.
+	RotationInterval time.Duration `yaml:"rotation_interval" env:"ROTATION_INTERVAL"`
+	// MaxBackups is the maximum number of rotated files to keep. Zero keeps all of them.
+	MaxBackups int `yaml:"max_backups" env:"MAX_BACKUPS" validate:"gte=0"`
+	// Compress the rotated files with gzip
+	Compress bool `yaml:"compress" env:"COMPRESS"`
+}
+
+// Enabled returns whether a file path has been provided
+func (c *Config) Enabled() bool {
+	return c.Path != ""
+}
+
+func (c *Config) maxSizeBytes() int64 {
+	return int64(c.MaxSizeMB) * 1024 * 1024
+}
+
+// NewTracesConsumer returns a consumer that writes each received batch of traces as a single
+// OTLP-JSON line into the provided writer.
+func NewTracesConsumer(w io.Writer) (consumer.Traces, error) {
+	marshaler := ptrace.JSONMarshaler{}
+	return consumer.NewTraces(func(_ context.Context, td ptrace.Traces) error {
+		data, err := marshaler.MarshalTraces(td)
+		if err != nil {
+			return fmt.Errorf("marshaling traces: %w", err)
+		}
+		return writeLine(w, data)
+	})
+}
+
+// NewMetricsConsumer returns a consumer that writes each received batch of metrics as a single
+// OTLP-JSON line into the provided writer.
+func NewMetricsConsumer(w io.Writer) (consumer.Metrics, error) {
+	marshaler := pmetric.JSONMarshaler{}
+	return consumer.NewMetrics(func(_ context.Context, md pmetric.Metrics) error {
+		data, err := marshaler.MarshalMetrics(md)
+		if err != nil {
+			return fmt.Errorf("marshaling metrics: %w", err)
+		}
+		return writeLine(w, data)
+	})
+}
+
+func writeLine(w io.Writer, data []byte) error {
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing OTLP-JSON line: %w", err)
+	}
+	return nil
+}
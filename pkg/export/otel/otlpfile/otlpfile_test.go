@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpfile
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func TestTracesConsumer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tc, err := NewTracesConsumer(buf)
+	require.NoError(t, err)
+
+	for _, name := range []string{"GET /foo", "GET /bar"} {
+		td := ptrace.NewTraces()
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", "svc")
+		rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(name)
+		require.NoError(t, tc.ConsumeTraces(t.Context(), td))
+	}
+
+	// each batch is written as an OTLP-JSON line
+	var names []string
+	unmarshaler := ptrace.JSONUnmarshaler{}
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		td, err := unmarshaler.UnmarshalTraces(scanner.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, 1, td.SpanCount())
+		svc, ok := td.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+		require.True(t, ok)
+		assert.Equal(t, "svc", svc.Str())
+		names = append(names, td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	}
+	assert.Equal(t, []string{"GET /foo", "GET /bar"}, names)
+}
+
+func TestMetricsConsumer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mc, err := NewMetricsConsumer(buf)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("http.server.request.duration")
+	m.SetEmptyHistogram().DataPoints().AppendEmpty().SetCount(3)
+	require.NoError(t, mc.ConsumeMetrics(t.Context(), md))
+
+	line, err := buf.ReadBytes('\n')
+	require.NoError(t, err)
+	unmarshaler := pmetric.JSONUnmarshaler{}
+	got, err := unmarshaler.UnmarshalMetrics(line)
+	require.NoError(t, err)
+	gotMetric := got.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "http.server.request.duration", gotMetric.Name())
+	assert.Equal(t, uint64(3), gotMetric.Histogram().DataPoints().At(0).Count())
+	assert.Zero(t, buf.Len())
+}
@@ -0,0 +1,306 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpfile // import "go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	backupTimeFormat = "20060102T150405.000"
+	gzipExt          = ".gz"
+)
+
+// RotatingWriter is an io.Writer that appends data to a file and rotates it when it
+// reaches a maximum size or age. Each invocation to Write is considered a single record
+// and will never be split across two files.
+type RotatingWriter struct {
+	mu  sync.Mutex
+	cfg Config
+	// file is nil if it couldn't be reopened after a rotation. It is opened again in the next write
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	closed   bool
+	// rotated files are compressed and cleaned up serially in a background goroutine.
+	// The queue is unbounded, so the writes are never blocked by a slow compression
+	pendingMu     sync.Mutex
+	pending       []string
+	pendingClosed bool
+	wakeup        chan struct{}
+	done          chan struct{}
+
+	now      func() time.Time
+	rename   func(oldpath, newpath string) error
+	openFile func(name string, flag int, perm os.FileMode) (*os.File, error)
+}
+
+// NewRotatingWriter opens (or creates) the file in the configured path. If the file already
+// exists, new records are appended to it.
+func NewRotatingWriter(cfg *Config) (*RotatingWriter, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("file path can't be empty")
+	}
+	w := &RotatingWriter{
+		cfg:      *cfg,
+		now:      time.Now,
+		rename:   os.Rename,
+		openFile: os.OpenFile,
+		wakeup:   make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory for %s: %w", cfg.Path, err)
+	}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	go w.processBackups()
+	return w, nil
+}
+
+func (w *RotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+	if w.file == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.shouldRotate(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close the current file and wait for any pending compression of rotated files.
+func (w *RotatingWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	var err error
+	if w.file != nil {
+		err = w.file.Close()
+	}
+	w.mu.Unlock()
+
+	w.pendingMu.Lock()
+	w.pendingClosed = true
+	w.pendingMu.Unlock()
+	w.notifyPending()
+	<-w.done
+	return err
+}
+
+func (w *RotatingWriter) open() error {
+	f, err := w.openFile(w.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", w.cfg.Path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("accessing %s: %w", w.cfg.Path, err)
+	}
+	w.file = f
+	w.size = info.Size()
+	w.openedAt = w.now()
+	return nil
+}
+
+func (w *RotatingWriter) shouldRotate(incoming int64) bool {
+	// an empty file is never rotated, even if the incoming record is bigger than the maximum size
+	if w.size == 0 {
+		return false
+	}
+	if maxSize := w.cfg.maxSizeBytes(); maxSize > 0 && w.size+incoming > maxSize {
+		return true
+	}
+	return w.cfg.RotationInterval > 0 && w.now().Sub(w.openedAt) >= w.cfg.RotationInterval
+}
+
+// rotate the current file. If the file can't be renamed, the original file is reopened and
+// the records keep being appended to it. If the file can't be reopened after being closed,
+// it is opened again in the next write.
+func (w *RotatingWriter) rotate() error {
+	err := w.file.Close()
+	w.file = nil
+	if err != nil {
+		log().Warn("can't close the file to rotate it", "file", w.cfg.Path, "error", err)
+	}
+	backup := w.backupName(w.now())
+	if err := w.rename(w.cfg.Path, backup); err != nil {
+		log().Warn("can't rotate file. Appending to it", "file", w.cfg.Path, "error", err)
+		return w.open()
+	}
+	w.enqueuePending(backup)
+	return w.open()
+}
+
+func (w *RotatingWriter) enqueuePending(backup string) {
+	w.pendingMu.Lock()
+	w.pending = append(w.pending, backup)
+	w.pendingMu.Unlock()
+	w.notifyPending()
+}
+
+func (w *RotatingWriter) notifyPending() {
+	select {
+	case w.wakeup <- struct{}{}:
+	default:
+		// the background goroutine has been already notified
+	}
+}
+
+// processBackups compresses the rotated files and removes the oldest ones. Each rotated file
+// is fully processed before the next one, so the cleanup never sees a partially compressed file.
+func (w *RotatingWriter) processBackups() {
+	defer close(w.done)
+	for {
+		w.pendingMu.Lock()
+		pending, closed := w.pending, w.pendingClosed
+		w.pending = nil
+		w.pendingMu.Unlock()
+		for _, backup := range pending {
+			if w.cfg.Compress {
+				if err := compress(backup); err != nil {
+					log().Warn("can't compress rotated file", "file", backup, "error", err)
+				}
+			}
+			w.removeOldBackups()
+		}
+		if closed {
+			return
+		}
+		if len(pending) == 0 {
+			<-w.wakeup
+		}
+	}
+}
+
+// backupName returns the name of a rotated file, which is the original name with a
+// timestamp and sequence suffix before the extension. For example:
+// traces-20240102T150405.000-0000.jsonl
+// The sequence is increased when a file rotated at the same timestamp already exists,
+// so rotated files are never overwritten.
+func (w *RotatingWriter) backupName(t time.Time) string {
+	dir, base, ext := w.nameParts()
+	prefix := filepath.Join(dir, base+"-"+t.UTC().Format(backupTimeFormat))
+	for seq := 0; ; seq++ {
+		name := fmt.Sprintf("%s-%04d%s", prefix, seq, ext)
+		if !exists(name) && !exists(name+gzipExt) {
+			return name
+		}
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+func (w *RotatingWriter) nameParts() (dir, base, ext string) {
+	dir = filepath.Dir(w.cfg.Path)
+	name := filepath.Base(w.cfg.Path)
+	ext = filepath.Ext(name)
+	return dir, strings.TrimSuffix(name, ext), ext
+}
+
+// backups returns the rotated files, sorted from the oldest to the newest
+func (w *RotatingWriter) backups() ([]string, error) {
+	dir, base, ext := w.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), gzipExt)
+		if e.IsDir() || !strings.HasPrefix(name, base+"-") || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		if !isBackupSuffix(strings.TrimSuffix(strings.TrimPrefix(name, base+"-"), ext)) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, e.Name()))
+	}
+	// the timestamp and the zero-padded sequence sort lexicographically
+	slices.Sort(files)
+	return files, nil
+}
+
+// isBackupSuffix returns whether the suffix follows the timestamp-sequence format of the rotated files
+func isBackupSuffix(suffix string) bool {
+	ts, seq, ok := strings.Cut(suffix, "-")
+	if !ok || len(seq) < 4 || strings.Trim(seq, "0123456789") != "" {
+		return false
+	}
+	_, err := time.Parse(backupTimeFormat, ts)
+	return err == nil
+}
+
+func (w *RotatingWriter) removeOldBackups() {
+	if w.cfg.MaxBackups <= 0 {
+		return
+	}
+	files, err := w.backups()
+	if err != nil {
+		log().Warn("can't list rotated files", "error", err)
+		return
+	}
+	for len(files) > w.cfg.MaxBackups {
+		if err := os.Remove(files[0]); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log().Warn("can't remove rotated file", "file", files[0], "error", err)
+		}
+		files = files[1:]
+	}
+}
+
+func compress(src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	dst := src + gzipExt
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(out)
+	if _, err := io.Copy(gz, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,214 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package otlpfile
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingWriter_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("previous\n"), 0o644))
+
+	w, err := NewRotatingWriter(&Config{Path: path})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "previous\nnew\n", readFile(t, path))
+
+	_, err = w.Write([]byte("after close\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+}
+
+func TestRotatingWriter_SizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "traces.jsonl")
+	w, err := NewRotatingWriter(&Config{Path: path, MaxSizeMB: 1})
+	require.NoError(t, err)
+	clock := fakeClock(w)
+
+	line := []byte(strings.Repeat("a", 600*1024) + "\n")
+	_, err = w.Write(line)
+	require.NoError(t, err)
+	clock.advance(time.Second)
+	// the second line does not fit in the file, so it is rotated
+	_, err = w.Write(line)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, filepath.Join(dir, "traces-20240101T000001.000-0000.jsonl"), backups[0])
+	assert.Equal(t, string(line), readFile(t, backups[0]))
+	assert.Equal(t, string(line), readFile(t, path))
+}
+
+func TestRotatingWriter_TimeRotationAndMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.jsonl")
+	w, err := NewRotatingWriter(&Config{Path: path, RotationInterval: time.Minute, MaxBackups: 2})
+	require.NoError(t, err)
+	clock := fakeClock(w)
+
+	for _, l := range []string{"1\n", "2\n", "3\n", "4\n"} {
+		_, err = w.Write([]byte(l))
+		require.NoError(t, err)
+		clock.advance(time.Minute)
+	}
+	require.NoError(t, w.Close())
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	assert.Equal(t, "2\n", readFile(t, backups[0]))
+	assert.Equal(t, "3\n", readFile(t, backups[1]))
+	assert.Equal(t, "4\n", readFile(t, path))
+}
+
+func TestRotatingWriter_SameTimestamp(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "traces.jsonl")
+	w, err := NewRotatingWriter(&Config{Path: path, MaxSizeMB: 1, Compress: true})
+	require.NoError(t, err)
+	fakeClock(w)
+
+	// the clock does not advance, so all the files are rotated at the same timestamp
+	line := strings.Repeat("a", 600*1024) + "\n"
+	for _, l := range []string{"1" + line, "2" + line, "3" + line} {
+		_, err = w.Write([]byte(l))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "traces-20240101T000000.000-0000.jsonl.gz"),
+		filepath.Join(dir, "traces-20240101T000000.000-0001.jsonl.gz"),
+	}, backups)
+	assert.Equal(t, "1"+line, readGzipFile(t, backups[0]))
+	assert.Equal(t, "2"+line, readGzipFile(t, backups[1]))
+	assert.Equal(t, "3"+line, readFile(t, path))
+}
+
+func TestRotatingWriter_Compress(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "traces.jsonl")
+	w, err := NewRotatingWriter(&Config{Path: path, RotationInterval: time.Minute, Compress: true})
+	require.NoError(t, err)
+	clock := fakeClock(w)
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	clock.advance(time.Minute)
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	require.True(t, strings.HasSuffix(backups[0], ".jsonl.gz"))
+	assert.Equal(t, "first\n", readGzipFile(t, backups[0]))
+	assert.Equal(t, "second\n", readFile(t, path))
+}
+
+func TestRotatingWriter_RenameFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	w, err := NewRotatingWriter(&Config{Path: path, RotationInterval: time.Minute})
+	require.NoError(t, err)
+	clock := fakeClock(w)
+	w.rename = func(string, string) error { return errors.New("rename failed") }
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	clock.advance(time.Minute)
+	// the file is reopened and the records are appended to it
+	_, err = w.Write([]byte("second\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	assert.Empty(t, backups)
+	assert.Equal(t, "first\nsecond\n", readFile(t, path))
+}
+
+func TestRotatingWriter_ReopenFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	w, err := NewRotatingWriter(&Config{Path: path, RotationInterval: time.Minute})
+	require.NoError(t, err)
+	clock := fakeClock(w)
+	w.openFile = func(string, int, os.FileMode) (*os.File, error) { return nil, errors.New("open failed") }
+
+	_, err = w.Write([]byte("first\n"))
+	require.NoError(t, err)
+	clock.advance(time.Minute)
+	_, err = w.Write([]byte("second\n"))
+	require.Error(t, err)
+	_, err = w.Write([]byte("third\n"))
+	require.Error(t, err)
+
+	// the file is opened again in the next write
+	w.openFile = os.OpenFile
+	_, err = w.Write([]byte("fourth\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	backups, err := w.backups()
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "first\n", readFile(t, backups[0]))
+	assert.Equal(t, "fourth\n", readFile(t, path))
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// fakeClock overrides the clock of the writer, setting it to 2024-01-01T00:00:00Z
+func fakeClock(w *RotatingWriter) *testClock {
+	c := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	w.mu.Lock()
+	w.now = func() time.Time { return c.now }
+	w.openedAt = c.now
+	w.mu.Unlock()
+	return c
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func readGzipFile(t *testing.T, path string) string {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	return string(content)
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tailsampling provides a pipeline stage that buffers the spans of each trace during a
+// decision window, and only forwards the traces that match any of the configured sampling policies.
+package tailsampling // import "go.opentelemetry.io/obi/pkg/export/otel/tailsampling"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/appolly/services"
+)
+
+// Config for the tail-based sampling of traces. The environment variables are prefixed by
+// OTEL_EBPF_TRACES_TAIL_SAMPLING_
+type Config struct {
+	// Enabled activates the tail-based sampling of traces
+	Enabled bool `yaml:"enabled" env:"ENABLED"`
+
+	// DecisionWait is the time that the spans of a trace are buffered, since its first span is received,
+	// before deciding whether the trace is sampled.
+	DecisionWait time.Duration `yaml:"decision_wait" env:"DECISION_WAIT" validate:"gte=0"`
+
+	// MaxTraces is the maximum number of traces that are buffered at the same time. When it is reached,
+	// the oldest trace is decided before its decision window ends. It also limits the number of
+	// remembered decisions, which are applied to the spans of the trace that arrive late.
+	MaxTraces int `yaml:"max_traces" env:"MAX_TRACES" validate:"gte=0"`
+
+	// MaxSpansPerTrace is the maximum number of spans that are buffered for a single trace. When it is
+	// reached, the trace is decided before its decision window ends. Zero means unlimited.
+	MaxSpansPerTrace int `yaml:"max_spans_per_trace" env:"MAX_SPANS_PER_TRACE" validate:"gte=0"`
+
+	// Errors samples any trace containing at least a span with error status
+	Errors bool `yaml:"errors" env:"ERRORS"`
+
+	// LatencyThreshold samples any trace containing a span whose duration is above the threshold,
+	// for the spans that do not match any of the route-specific Latency policies. Zero disables it.
+	LatencyThreshold time.Duration `yaml:"latency_threshold" env:"LATENCY_THRESHOLD" validate:"gte=0"`
+
+	// Latency defines route-specific latency thresholds. For each span, the first matching policy is used.
+	Latency []LatencyPolicy `yaml:"latency"`
+
+	// Probabilistic is the ratio of traces, between 0 and 1, that are sampled when they don't match
+	// any other policy. The decision is taken from the trace ID, so it is consistent across agents.
+	Probabilistic float64 `yaml:"probabilistic" env:"PROBABILISTIC" validate:"gte=0,lte=1"`
+
+	// ServiceRateLimit is the maximum number of traces per second that are sampled for each service.
+	// The service of a trace is the service of its first received span. Zero means unlimited.
+	ServiceRateLimit int `yaml:"service_rate_limit" env:"SERVICE_RATE_LIMIT" validate:"gte=0"`
+}
+
+// LatencyPolicy samples the traces containing a span of the matching service and route
+// whose duration is above the threshold.
+type LatencyPolicy struct {
+	// Service glob to match the service name of the span. If unset, any service matches.
+	Service services.GlobAttr `yaml:"service"`
+	// Route glob to match the route of the span. If unset, any route matches.
+	Route services.GlobAttr `yaml:"route"`
+	// Threshold duration above which the trace is sampled. Zero means that the trace is not sampled
+	// by latency, even if the span matches.
+	Threshold time.Duration `yaml:"threshold"`
+}
+
+func (lp *LatencyPolicy) matches(serviceName, route string) bool {
+	return (!lp.Service.IsSet() || lp.Service.MatchString(serviceName)) &&
+		(!lp.Route.IsSet() || lp.Route.MatchString(route))
+}
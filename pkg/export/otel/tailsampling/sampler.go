@@ -0,0 +1,287 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling // import "go.opentelemetry.io/obi/pkg/export/otel/tailsampling"
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+// Values of the decision and policy labels of the tail sampling internal metrics
+const (
+	DecisionSampled = "sampled"
+	DecisionDropped = "dropped"
+
+	PolicyError         = "error"
+	PolicyLatency       = "latency"
+	PolicyProbabilistic = "probabilistic"
+	PolicyRateLimited   = "rate_limited"
+	PolicyNoMatch       = "no_match"
+)
+
+const (
+	minCheckPeriod = 100 * time.Millisecond
+	// default size of the cache of decisions if MaxTraces is unset
+	defaultMaxTraces = 10_000
+)
+
+func log() *slog.Logger {
+	return slog.With("component", "tailsampling.Sampler")
+}
+
+// traceBuffer stores the spans of a trace until it is decided
+type traceBuffer struct {
+	traceID   trace.TraceID
+	service   svc.ServiceNameNamespace
+	firstSeen time.Time
+	spans     []request.Span
+	elem      *list.Element
+}
+
+type sampler struct {
+	cfg     *Config
+	log     *slog.Logger
+	metrics imetrics.Reporter
+	in      <-chan []request.Span
+	out     *msg.Queue[[]request.Span]
+
+	pending map[trace.TraceID]*traceBuffer
+	// arrival order of the pending traces, from the oldest to the newest
+	order *list.List
+	// decided remembers whether a trace was sampled, to apply the same decision
+	// to the spans that arrive after the decision window
+	decided *simplelru.LRU[trace.TraceID, bool]
+
+	// per-service count of sampled traces in the current one-second window
+	rateWindow int64
+	rateCounts map[svc.ServiceNameNamespace]int
+
+	now func() time.Time
+}
+
+// Sampler node buffers the spans of each trace during the configured decision window and only
+// forwards to the output the traces that match any of the sampling policies.
+// If tail sampling is disabled, the input is directly forwarded to the output.
+func Sampler(cfg *Config, metrics imetrics.Reporter, input, output *msg.Queue[[]request.Span]) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled {
+			return swarm.Bypass(input, output)
+		}
+		s, err := newSampler(cfg, metrics, input.Subscribe(msg.SubscriberName("TailSampler")), output)
+		if err != nil {
+			return nil, err
+		}
+		return s.run, nil
+	}
+}
+
+func newSampler(cfg *Config, metrics imetrics.Reporter, in <-chan []request.Span, out *msg.Queue[[]request.Span]) (*sampler, error) {
+	maxTraces := cfg.MaxTraces
+	if maxTraces <= 0 {
+		maxTraces = defaultMaxTraces
+	}
+	decided, err := simplelru.NewLRU[trace.TraceID, bool](maxTraces, nil)
+	if err != nil {
+		return nil, err
+	}
+	if metrics == nil {
+		metrics = imetrics.NoopReporter{}
+	}
+	return &sampler{
+		cfg:        cfg,
+		log:        log(),
+		metrics:    metrics,
+		in:         in,
+		out:        out,
+		pending:    map[trace.TraceID]*traceBuffer{},
+		order:      list.New(),
+		decided:    decided,
+		rateCounts: map[svc.ServiceNameNamespace]int{},
+		now:        time.Now,
+	}, nil
+}
+
+func (s *sampler) run(ctx context.Context) {
+	defer s.out.Close()
+	s.log.Debug("starting tail sampler",
+		"decisionWait", s.cfg.DecisionWait, "maxTraces", s.cfg.MaxTraces)
+	ticker := time.NewTicker(max(s.cfg.DecisionWait/4, minCheckPeriod))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Debug("context done. Stopping")
+			return
+		case <-ticker.C:
+			s.send(ctx, s.decideExpired(nil))
+		case spans, ok := <-s.in:
+			if !ok {
+				s.log.Debug("input channel closed. Deciding the pending traces and stopping")
+				s.send(ctx, s.decideAll())
+				return
+			}
+			s.send(ctx, s.process(spans))
+		}
+	}
+}
+
+func (s *sampler) send(ctx context.Context, spans []request.Span) {
+	if len(spans) > 0 {
+		s.out.SendCtx(ctx, spans)
+	}
+	s.metrics.TailSamplingBufferedTraces(len(s.pending))
+}
+
+// process buffers the received spans and returns the spans that can be forwarded right now:
+// spans not belonging to any trace, spans from already sampled traces, and the traces that
+// needed to be decided early because of the memory limits
+func (s *sampler) process(spans []request.Span) []request.Span {
+	var out []request.Span
+	for i := range spans {
+		span := &spans[i]
+		if span.InternalSignal() || !span.TraceID.IsValid() {
+			out = append(out, *span)
+			continue
+		}
+		if sampled, ok := s.decided.Get(span.TraceID); ok {
+			if sampled {
+				out = append(out, *span)
+			}
+			continue
+		}
+		tb, ok := s.pending[span.TraceID]
+		if !ok {
+			if s.cfg.MaxTraces > 0 && len(s.pending) >= s.cfg.MaxTraces {
+				out = s.decide(out, s.order.Front().Value.(*traceBuffer))
+			}
+			tb = &traceBuffer{
+				traceID:   span.TraceID,
+				service:   span.Service.UID.NameNamespace(),
+				firstSeen: s.now(),
+			}
+			tb.elem = s.order.PushBack(tb)
+			s.pending[span.TraceID] = tb
+		}
+		tb.spans = append(tb.spans, *span)
+		if s.cfg.MaxSpansPerTrace > 0 && len(tb.spans) >= s.cfg.MaxSpansPerTrace {
+			out = s.decide(out, tb)
+		}
+	}
+	return out
+}
+
+// decideExpired decides all the traces whose decision window has ended, and appends
+// the spans of the sampled traces to the out slice
+func (s *sampler) decideExpired(out []request.Span) []request.Span {
+	now := s.now()
+	for front := s.order.Front(); front != nil; front = s.order.Front() {
+		tb := front.Value.(*traceBuffer)
+		if now.Sub(tb.firstSeen) < s.cfg.DecisionWait {
+			break
+		}
+		out = s.decide(out, tb)
+	}
+	return out
+}
+
+func (s *sampler) decideAll() []request.Span {
+	var out []request.Span
+	for front := s.order.Front(); front != nil; front = s.order.Front() {
+		out = s.decide(out, front.Value.(*traceBuffer))
+	}
+	return out
+}
+
+func (s *sampler) decide(out []request.Span, tb *traceBuffer) []request.Span {
+	s.order.Remove(tb.elem)
+	delete(s.pending, tb.traceID)
+
+	sampled, policy := s.evaluate(tb)
+	s.decided.Add(tb.traceID, sampled)
+	if sampled {
+		s.metrics.TailSamplingDecision(DecisionSampled, policy)
+		return append(out, tb.spans...)
+	}
+	s.metrics.TailSamplingDecision(DecisionDropped, policy)
+	return out
+}
+
+// evaluate returns whether the trace is sampled, and the policy that took the decision
+func (s *sampler) evaluate(tb *traceBuffer) (bool, string) {
+	policy := s.matchingPolicy(tb)
+	if policy == PolicyNoMatch {
+		return false, policy
+	}
+	if !s.allowedByRateLimit(tb.service) {
+		return false, PolicyRateLimited
+	}
+	return true, policy
+}
+
+func (s *sampler) matchingPolicy(tb *traceBuffer) string {
+	if s.cfg.Errors {
+		for i := range tb.spans {
+			if request.SpanStatusCode(&tb.spans[i]) == request.StatusCodeError {
+				return PolicyError
+			}
+		}
+	}
+	for i := range tb.spans {
+		if s.aboveLatencyThreshold(&tb.spans[i]) {
+			return PolicyLatency
+		}
+	}
+	if s.cfg.Probabilistic > 0 && sampledByRatio(tb.traceID, s.cfg.Probabilistic) {
+		return PolicyProbabilistic
+	}
+	return PolicyNoMatch
+}
+
+func (s *sampler) aboveLatencyThreshold(span *request.Span) bool {
+	threshold := s.cfg.LatencyThreshold
+	for i := range s.cfg.Latency {
+		if lp := &s.cfg.Latency[i]; lp.matches(span.Service.UID.Name, span.Route) {
+			threshold = lp.Threshold
+			break
+		}
+	}
+	return threshold > 0 && time.Duration(span.End-span.RequestStart) > threshold
+}
+
+func (s *sampler) allowedByRateLimit(service svc.ServiceNameNamespace) bool {
+	if s.cfg.ServiceRateLimit <= 0 {
+		return true
+	}
+	if second := s.now().Unix(); second != s.rateWindow {
+		s.rateWindow = second
+		clear(s.rateCounts)
+	}
+	if s.rateCounts[service] >= s.cfg.ServiceRateLimit {
+		return false
+	}
+	s.rateCounts[service]++
+	return true
+}
+
+// sampledByRatio follows the same approach as the OpenTelemetry SDK TraceIDRatioBased sampler,
+// so the same trace is consistently sampled across different agents
+func sampledByRatio(traceID trace.TraceID, ratio float64) bool {
+	if ratio >= 1 {
+		return true
+	}
+	bound := uint64(ratio * (1 << 63))
+	return binary.BigEndian.Uint64(traceID[8:16])>>1 < bound
+}
@@ -0,0 +1,240 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+const testTimeout = 5 * time.Second
+
+func TestErrorPolicy(t *testing.T) {
+	s, clock, metrics := testSampler(t, &Config{DecisionWait: time.Second, Errors: true})
+
+	assert.Empty(t, s.process([]request.Span{
+		httpSpan(1, "svc", "/ok", 200, time.Millisecond),
+		httpSpan(2, "svc", "/fail", 200, time.Millisecond),
+	}))
+	clock.advance(500 * time.Millisecond)
+	// trace 2 fails in a later span
+	assert.Empty(t, s.process([]request.Span{httpSpan(2, "svc", "/fail", 503, time.Millisecond)}))
+	assert.Empty(t, s.decideExpired(nil))
+
+	clock.advance(500 * time.Millisecond)
+	out := s.decideExpired(nil)
+	require.Len(t, out, 2)
+	assert.Equal(t, traceID(2), out[0].TraceID)
+	assert.Equal(t, traceID(2), out[1].TraceID)
+	assert.Empty(t, s.pending)
+	assert.Equal(t, map[[2]string]int{
+		{DecisionDropped, PolicyNoMatch}: 1,
+		{DecisionSampled, PolicyError}:   1,
+	}, metrics.decisions)
+
+	// late spans follow the previous decision
+	out = s.process([]request.Span{
+		httpSpan(1, "svc", "/ok", 200, time.Millisecond),
+		httpSpan(2, "svc", "/fail", 200, time.Millisecond),
+	})
+	require.Len(t, out, 1)
+	assert.Equal(t, traceID(2), out[0].TraceID)
+}
+
+func TestLatencyPolicy(t *testing.T) {
+	s, clock, _ := testSampler(t, &Config{
+		DecisionWait:     time.Second,
+		LatencyThreshold: time.Second,
+		Latency: []LatencyPolicy{
+			{Route: services.NewGlob("/api/*"), Threshold: 100 * time.Millisecond},
+			{Service: services.NewGlob("batch"), Threshold: 0},
+		},
+	})
+	s.process([]request.Span{
+		httpSpan(1, "svc", "/api/users", 200, 200*time.Millisecond),
+		httpSpan(2, "svc", "/api/users", 200, 50*time.Millisecond),
+		httpSpan(3, "svc", "/home", 200, 200*time.Millisecond),
+		httpSpan(4, "svc", "/home", 200, 2*time.Second),
+		httpSpan(5, "batch", "/job", 200, time.Minute),
+	})
+	clock.advance(time.Second)
+	assert.Equal(t, []trace.TraceID{traceID(1), traceID(4)}, traceIDs(s.decideExpired(nil)))
+}
+
+func TestProbabilisticPolicy(t *testing.T) {
+	s, clock, _ := testSampler(t, &Config{DecisionWait: time.Second, Probabilistic: 0.5})
+	var spans []request.Span
+	for i := range 1000 {
+		spans = append(spans, httpSpan(i+1, "svc", "/", 200, time.Millisecond))
+	}
+	s.process(spans)
+	clock.advance(time.Second)
+	sampled := traceIDs(s.decideExpired(nil))
+	assert.InDelta(t, 500, len(sampled), 100)
+
+	// the decision is consistent for the same trace IDs
+	for _, tid := range sampled {
+		assert.True(t, sampledByRatio(tid, 0.5))
+	}
+	assert.True(t, sampledByRatio(traceID(1), 1))
+	assert.False(t, sampledByRatio(traceID(1), 0))
+}
+
+func TestServiceRateLimit(t *testing.T) {
+	s, clock, metrics := testSampler(t, &Config{DecisionWait: time.Second, Errors: true, ServiceRateLimit: 2})
+	s.process([]request.Span{
+		httpSpan(1, "svc-a", "/", 500, time.Millisecond),
+		httpSpan(2, "svc-a", "/", 500, time.Millisecond),
+		httpSpan(3, "svc-a", "/", 500, time.Millisecond),
+		httpSpan(4, "svc-b", "/", 500, time.Millisecond),
+	})
+	clock.advance(time.Second)
+	assert.Equal(t, []trace.TraceID{traceID(1), traceID(2), traceID(4)}, traceIDs(s.decideExpired(nil)))
+	assert.Equal(t, 1, metrics.decisions[[2]string{DecisionDropped, PolicyRateLimited}])
+
+	// the limit is applied per second
+	s.process([]request.Span{httpSpan(5, "svc-a", "/", 500, time.Millisecond)})
+	clock.advance(time.Second)
+	assert.Equal(t, []trace.TraceID{traceID(5)}, traceIDs(s.decideExpired(nil)))
+}
+
+func TestMemoryLimits(t *testing.T) {
+	s, _, _ := testSampler(t, &Config{
+		DecisionWait: time.Minute, Errors: true, MaxTraces: 2, MaxSpansPerTrace: 3,
+	})
+	assert.Empty(t, s.process([]request.Span{
+		httpSpan(1, "svc", "/", 500, time.Millisecond),
+		httpSpan(2, "svc", "/", 200, time.Millisecond),
+	}))
+	// a third trace forces the decision of the oldest trace
+	out := s.process([]request.Span{httpSpan(3, "svc", "/", 200, time.Millisecond)})
+	assert.Equal(t, []trace.TraceID{traceID(1)}, traceIDs(out))
+	assert.Len(t, s.pending, 2)
+
+	// reaching the maximum number of spans forces the decision of the trace
+	out = s.process([]request.Span{
+		httpSpan(3, "svc", "/", 500, time.Millisecond),
+		httpSpan(3, "svc", "/", 200, time.Millisecond),
+		httpSpan(3, "svc", "/", 200, time.Millisecond),
+	})
+	assert.Equal(t, []trace.TraceID{traceID(3), traceID(3), traceID(3), traceID(3)}, traceIDs(out))
+	assert.Len(t, s.pending, 1)
+}
+
+func TestPassThroughSpans(t *testing.T) {
+	s, _, _ := testSampler(t, &Config{DecisionWait: time.Minute})
+	out := s.process([]request.Span{
+		{Type: request.EventTypeProcessAlive},
+		{Type: request.EventTypeHTTP, Status: 200},
+	})
+	assert.Len(t, out, 2)
+	assert.Empty(t, s.pending)
+}
+
+func TestSamplerNode(t *testing.T) {
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	outCh := output.Subscribe()
+	run, err := Sampler(&Config{Enabled: true, DecisionWait: time.Millisecond, Errors: true},
+		imetrics.NoopReporter{}, input, output)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+
+	input.Send([]request.Span{
+		httpSpan(1, "svc", "/", 200, time.Millisecond),
+		httpSpan(2, "svc", "/", 500, time.Millisecond),
+	})
+	select {
+	case spans := <-outCh:
+		assert.Equal(t, []trace.TraceID{traceID(2)}, traceIDs(spans))
+	case <-time.After(testTimeout):
+		t.Fatal("timeout waiting for sampled spans")
+	}
+}
+
+func TestSamplerNode_Disabled(t *testing.T) {
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	outCh := output.Subscribe()
+	run, err := Sampler(&Config{}, imetrics.NoopReporter{}, input, output)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+
+	input.Send([]request.Span{httpSpan(1, "svc", "/", 200, time.Millisecond)})
+	select {
+	case spans := <-outCh:
+		assert.Equal(t, []trace.TraceID{traceID(1)}, traceIDs(spans))
+	case <-time.After(testTimeout):
+		t.Fatal("timeout waiting for spans")
+	}
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+type testMetrics struct {
+	imetrics.NoopReporter
+	decisions map[[2]string]int
+}
+
+func (m *testMetrics) TailSamplingDecision(decision, policy string) {
+	m.decisions[[2]string{decision, policy}]++
+}
+
+func testSampler(t *testing.T, cfg *Config) (*sampler, *testClock, *testMetrics) {
+	t.Helper()
+	metrics := &testMetrics{decisions: map[[2]string]int{}}
+	s, err := newSampler(cfg, metrics, nil, nil)
+	require.NoError(t, err)
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s.now = func() time.Time { return clock.now }
+	return s, clock, metrics
+}
+
+func traceID(n int) trace.TraceID {
+	// spread the trace IDs to make the probabilistic sampling meaningful
+	id := uint64(n) * 0x9E3779B97F4A7C15
+	tid := trace.TraceID{}
+	for i := range 8 {
+		tid[i] = 1
+		tid[8+i] = byte(id >> (56 - 8*i))
+	}
+	return tid
+}
+
+func httpSpan(tid int, service, route string, status int, duration time.Duration) request.Span {
+	return request.Span{
+		Type:         request.EventTypeHTTP,
+		TraceID:      traceID(tid),
+		Service:      svc.Attrs{UID: svc.UID{Name: service}},
+		Route:        route,
+		Status:       status,
+		RequestStart: 1000,
+		Start:        1000,
+		End:          1000 + duration.Nanoseconds(),
+	}
+}
+
+func traceIDs(spans []request.Span) []trace.TraceID {
+	var ids []trace.TraceID
+	for i := range spans {
+		ids = append(ids, spans[i].TraceID)
+	}
+	return ids
+}
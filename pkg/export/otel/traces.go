@@ -5,6 +5,7 @@ package otel // import "go.opentelemetry.io/obi/pkg/export/otel"
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -16,11 +17,9 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
-	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configoptional"
 	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/config/configtelemetry"
-	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/debugexporter"
@@ -39,7 +38,9 @@ import (
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 	"go.opentelemetry.io/obi/pkg/export/imetrics"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
+	"go.opentelemetry.io/obi/pkg/export/otel/otlpfile"
 	"go.opentelemetry.io/obi/pkg/export/otel/tracesgen"
 	"go.opentelemetry.io/obi/pkg/pipe/global"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
@@ -210,6 +211,10 @@ func getTracesExporter(ctx context.Context, cfg otelcfg.TracesConfig, im imetric
 		exp = instrumentTracesExporter(im, exp)
 		return exp, nil
 	}
+	if cfg.File.Enabled() {
+		slog.Debug("instantiating File TracesReporter", "path", cfg.File.Path)
+		return getFileTracesExporter(ctx, cfg, im)
+	}
 	switch proto := cfg.GetProtocol(); proto {
 	case otelcfg.ProtocolHTTPJSON, otelcfg.ProtocolHTTPProtobuf, "": // zero value defaults to HTTP for backwards-compatibility
 		slog.Debug("instantiating HTTP TracesReporter", "protocol", proto)
@@ -220,17 +225,19 @@ func getTracesExporter(ctx context.Context, cfg otelcfg.TracesConfig, im imetric
 			slog.Error("can't get HTTP traces endpoint options", "error", err)
 			return nil, err
 		}
+		tlsCfg := cfg.ClientTLS()
 		factory := otlphttpexporter.NewFactory()
 		config := factory.CreateDefaultConfig().(*otlphttpexporter.Config)
 		config.QueueConfig = getQueueConfig(cfg)
 		config.RetryConfig = getRetrySettings(cfg)
+		if cfg.DiskQueue.Enabled() {
+			disableQueueAndRetry(&config.QueueConfig, &config.RetryConfig)
+		}
 		config.ClientConfig = confighttp.ClientConfig{
-			Endpoint: opts.Scheme + "://" + opts.Endpoint + opts.BaseURLPath,
-			TLS: configtls.ClientConfig{
-				Insecure:           opts.Insecure,
-				InsecureSkipVerify: cfg.InsecureSkipVerify,
-			},
-			Headers: convertHeaders(opts.Headers),
+			Endpoint:    opts.Scheme + "://" + opts.Endpoint + opts.BaseURLPath,
+			TLS:         tlsCfg.Collector(opts.Insecure, cfg.InsecureSkipVerify),
+			Headers:     otelcfg.CollectorHeaders(opts.Headers),
+			Compression: opts.Compression.Collector(),
 		}
 		slog.Debug("getTracesExporter: confighttp.ClientConfig created", "endpoint", config.ClientConfig.Endpoint)
 		set := getTraceSettings(factory.Type(), cfg.SDKLogLevel)
@@ -240,6 +247,9 @@ func getTracesExporter(ctx context.Context, cfg otelcfg.TracesConfig, im imetric
 			return nil, err
 		}
 		exp = instrumentTracesExporter(im, exp)
+		if cfg.DiskQueue.Enabled() {
+			return withDiskQueue(ctx, set, cfg, exp, im)
+		}
 		// TODO: remove this once the batcher helper is added to otlphttpexporter
 		return exporterhelper.NewTraces(ctx, set, cfg,
 			exp.ConsumeTraces,
@@ -261,17 +271,19 @@ func getTracesExporter(ctx context.Context, cfg otelcfg.TracesConfig, im imetric
 			slog.Error("can't parse GRPC traces endpoint", "error", err)
 			return nil, err
 		}
+		tlsCfg := cfg.ClientTLS()
 		factory := otlpexporter.NewFactory()
 		config := factory.CreateDefaultConfig().(*otlpexporter.Config)
 		config.QueueConfig = getQueueConfig(cfg)
 		config.RetryConfig = getRetrySettings(cfg)
+		if cfg.DiskQueue.Enabled() {
+			disableQueueAndRetry(&config.QueueConfig, &config.RetryConfig)
+		}
 		config.ClientConfig = configgrpc.ClientConfig{
-			Endpoint: endpoint.String(),
-			TLS: configtls.ClientConfig{
-				Insecure:           opts.Insecure,
-				InsecureSkipVerify: cfg.InsecureSkipVerify,
-			},
-			Headers: convertHeaders(opts.Headers),
+			Endpoint:    endpoint.String(),
+			TLS:         tlsCfg.Collector(opts.Insecure, cfg.InsecureSkipVerify),
+			Headers:     otelcfg.CollectorHeaders(opts.Headers),
+			Compression: opts.Compression.Collector(),
 		}
 		set := getTraceSettings(factory.Type(), cfg.SDKLogLevel)
 		exp, err := factory.CreateTraces(ctx, set, config)
@@ -279,6 +291,9 @@ func getTracesExporter(ctx context.Context, cfg otelcfg.TracesConfig, im imetric
 			return nil, err
 		}
 		exp = instrumentTracesExporter(im, exp)
+		if cfg.DiskQueue.Enabled() {
+			return withDiskQueue(ctx, set, cfg, exp, im)
+		}
 		return exp, nil
 	case otelcfg.ProtocolDebug:
 		slog.Debug("instantiating Debug TracesReporter", "protocol", proto)
@@ -299,6 +314,74 @@ func getTracesExporter(ctx context.Context, cfg otelcfg.TracesConfig, im imetric
 	}
 }
 
+// getFileTracesExporter returns an exporter that writes the traces as OTLP-JSON lines into
+// the configured file
+func getFileTracesExporter(ctx context.Context, cfg otelcfg.TracesConfig, im imetrics.Reporter) (exporter.Traces, error) {
+	newType, err := component.NewType("file")
+	if err != nil {
+		return nil, err
+	}
+	writer, err := otlpfile.NewRotatingWriter(&cfg.File)
+	if err != nil {
+		return nil, fmt.Errorf("can't create traces file: %w", err)
+	}
+	tc, err := otlpfile.NewTracesConsumer(writer)
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	set := getTraceSettings(newType, cfg.SDKLogLevel)
+	exp, err := exporterhelper.NewTraces(ctx, set, cfg,
+		tc.ConsumeTraces,
+		exporterhelper.WithShutdown(func(context.Context) error { return writer.Close() }),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		exporterhelper.WithQueue(getQueueConfig(cfg)),
+	)
+	if err != nil {
+		_ = writer.Close()
+		return nil, err
+	}
+	return instrumentTracesExporter(im, exp), nil
+}
+
+// withDiskQueue returns an exporter that batches the traces and stores them into the disk queue,
+// which forwards them to the provided OTLP exporter
+func withDiskQueue(
+	ctx context.Context, set exporter.Settings, cfg otelcfg.TracesConfig, exp exporter.Traces, im imetrics.Reporter,
+) (exporter.Traces, error) {
+	dq, err := diskqueue.NewTraces(&cfg.DiskQueue, exp, im)
+	if err != nil {
+		return nil, err
+	}
+	qexp, err := exporterhelper.NewTraces(ctx, set, cfg,
+		dq.ConsumeTraces,
+		exporterhelper.WithStart(func(ctx context.Context, host component.Host) error {
+			if err := exp.Start(ctx, host); err != nil {
+				return err
+			}
+			dq.Start()
+			return nil
+		}),
+		exporterhelper.WithShutdown(func(ctx context.Context) error {
+			return errors.Join(dq.Shutdown(ctx), exp.Shutdown(ctx))
+		}),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: false}),
+		exporterhelper.WithQueue(getQueueConfig(cfg)),
+	)
+	if err != nil {
+		_ = dq.Shutdown(ctx)
+		return nil, err
+	}
+	return qexp, nil
+}
+
+// disableQueueAndRetry of an OTLP exporter that sends the traces from the disk queue, which
+// already takes care of retrying the failed exports
+func disableQueueAndRetry(queue *configoptional.Optional[exporterhelper.QueueBatchConfig], retry *configretry.BackOffConfig) {
+	*queue = configoptional.None[exporterhelper.QueueBatchConfig]()
+	retry.Enabled = false
+}
+
 func getQueueConfig(cfg otelcfg.TracesConfig) configoptional.Optional[exporterhelper.QueueBatchConfig] {
 	// enable batching only if the queue config is enabled
 	if cfg.MaxQueueSize <= 0 && cfg.BatchTimeout <= 0 {
@@ -391,11 +474,3 @@ func getRetrySettings(cfg otelcfg.TracesConfig) configretry.BackOffConfig {
 	}
 	return backOffCfg
 }
-
-func convertHeaders(headers map[string]string) configopaque.MapList {
-	opaqueHeaders := make(configopaque.MapList, 0, len(headers))
-	for key, value := range headers {
-		opaqueHeaders = append(opaqueHeaders, configopaque.Pair{Name: key, Value: configopaque.String(value)})
-	}
-	return opaqueHeaders
-}
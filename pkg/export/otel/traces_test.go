@@ -6,8 +6,12 @@ package otel
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -20,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	"go.opentelemetry.io/otel/attribute"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.38.0"
@@ -30,7 +35,9 @@ import (
 	"go.opentelemetry.io/obi/pkg/appolly/meta"
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
+	"go.opentelemetry.io/obi/pkg/export/otel/diskqueue"
 	"go.opentelemetry.io/obi/pkg/export/otel/idgen"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/export/otel/tracesgen"
@@ -277,6 +284,65 @@ func TestGenerateTraces(t *testing.T) {
 		assert.NotEmpty(t, spans.At(0).SpanID().String())
 		assert.NotEmpty(t, spans.At(0).TraceID().String())
 	})
+
+	t.Run("test with span links", func(t *testing.T) {
+		start := time.Now()
+		link1, ok := request.SpanLinkFromTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		require.True(t, ok)
+		link2, ok := request.SpanLinkFromTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+		require.True(t, ok)
+		span := &request.Span{
+			Type:         request.EventTypeKafkaClient,
+			RequestStart: start.UnixNano(),
+			End:          start.Add(3 * time.Second).UnixNano(),
+			Method:       request.MessagingProcess,
+			Path:         "my-topic",
+			Links:        []request.SpanLink{link1, link2},
+		}
+		traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, hostID, groupFromSpanAndAttributes(span, []attribute.KeyValue{}), reporterName)
+
+		spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+		require.Equal(t, 1, spans.Len())
+		links := spans.At(0).Links()
+		require.Equal(t, 2, links.Len())
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", links.At(0).TraceID().String())
+		assert.Equal(t, "b7ad6b7169203331", links.At(0).SpanID().String())
+		assert.Equal(t, uint32(1), links.At(0).Flags())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", links.At(1).TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", links.At(1).SpanID().String())
+		assert.Equal(t, uint32(0), links.At(1).Flags())
+	})
+}
+
+func TestGenerateTraces_ExceptionEvents(t *testing.T) {
+	start := time.Now()
+	span := &request.Span{
+		Type:         request.EventTypeSQLClient,
+		RequestStart: start.UnixNano(),
+		End:          start.Add(time.Second).UnixNano(),
+		Method:       "SELECT",
+		Path:         "foo",
+		Status:       1,
+		SQLError:     &request.SQLError{Code: 1146, SQLState: "42S02", Message: "Table 'db.foo' doesn't exist"},
+	}
+	traces := tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, hostID, groupFromSpanAndAttributes(span, []attribute.KeyValue{}), reporterName)
+
+	spans := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	require.Equal(t, 1, spans.Len())
+	events := spans.At(0).Events()
+	require.Equal(t, 1, events.Len())
+	assert.Equal(t, "exception", events.At(0).Name())
+	assert.Equal(t, spans.At(0).EndTimestamp(), events.At(0).Timestamp())
+	assert.Equal(t, map[string]any{
+		"exception.type":          "SQLError",
+		"exception.message":       "Table 'db.foo' doesn't exist",
+		"db.response.status_code": "42S02",
+	}, events.At(0).Attributes().AsRaw())
+
+	// successful spans don't have events
+	span.Status, span.SQLError = 0, nil
+	traces = tracesgen.GenerateTracesWithAttributes(cache, &span.Service, []attribute.KeyValue{}, hostID, groupFromSpanAndAttributes(span, []attribute.KeyValue{}), reporterName)
+	assert.Equal(t, 0, traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Events().Len())
 }
 
 func TestGenerateTracesAttributes(t *testing.T) {
@@ -1172,6 +1238,7 @@ func TestGenerateTracesAttributes(t *testing.T) {
 			ResponseHeaders: map[string][]string{
 				"X-Response-Id": {"resp-456"},
 			},
+			Baggage: map[string]string{"tenant.id": "acme"},
 		}
 
 		tAttrs := tracesgen.TraceAttributesSelector(&span, map[attr.Name]struct{}{})
@@ -1185,6 +1252,7 @@ func TestGenerateTracesAttributes(t *testing.T) {
 		ensureTraceStrSliceAttr(t, attrs, "http.request.header.x-request-id", []string{"abc-123"})
 		ensureTraceStrSliceAttr(t, attrs, "http.response.header.x-response-id", []string{"resp-456"})
 		ensureTraceAttrNotExists(t, attrs, "http.request.header.authorization")
+		ensureTraceStrAttr(t, attrs, "baggage.tenant.id", "acme")
 	})
 	t.Run("test HTTP client span with extracted headers", func(t *testing.T) {
 		span := request.Span{
@@ -2254,3 +2322,49 @@ func (e TestExporter) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
 func (e TestExporter) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{}
 }
+
+func TestTracesExporter_DiskQueue(t *testing.T) {
+	var mt sync.Mutex
+	requests := 0
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mt.Lock()
+		defer mt.Unlock()
+		requests++
+		if requests == 1 {
+			// the first export fails, so the traces are kept in the queue and retried
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := ptraceotlp.NewExportRequest()
+		require.NoError(t, req.UnmarshalProto(body))
+		received = append(received, req.Traces().ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+		resp, err := ptraceotlp.NewExportResponse().MarshalProto()
+		require.NoError(t, err)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		_, _ = w.Write(resp)
+	}))
+	defer server.Close()
+
+	exp, err := getTracesExporter(t.Context(), otelcfg.TracesConfig{
+		TracesEndpoint: server.URL + "/v1/traces",
+		TracesProtocol: otelcfg.ProtocolHTTPProtobuf,
+		DiskQueue:      diskqueue.DiskQueueConfig{Directory: t.TempDir()},
+	}, imetrics.NoopReporter{})
+	require.NoError(t, err)
+	require.NoError(t, exp.Start(t.Context(), emptyHost{}))
+
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("queued span")
+	require.NoError(t, exp.ConsumeTraces(t.Context(), td))
+
+	require.Eventually(t, func() bool {
+		mt.Lock()
+		defer mt.Unlock()
+		return len(received) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"queued span"}, received)
+	require.NoError(t, exp.Shutdown(t.Context()))
+}
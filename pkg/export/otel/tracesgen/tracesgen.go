@@ -163,6 +163,12 @@ func GenerateTracesWithAttributes(
 		if span.ParentSpanID.IsValid() {
 			s.SetParentSpanID(pcommon.SpanID(span.ParentSpanID))
 		}
+		for _, link := range span.Links {
+			l := s.Links().AppendEmpty()
+			l.SetTraceID(pcommon.TraceID(link.TraceID))
+			l.SetSpanID(pcommon.SpanID(link.SpanID))
+			l.SetFlags(uint32(link.TraceFlags))
+		}
 
 		// Set span attributes
 		m := AttrsToMap(attrs)
@@ -175,6 +181,9 @@ func GenerateTracesWithAttributes(
 		if statusMessage != "" {
 			s.Status().SetMessage(statusMessage)
 		}
+		if exception, ok := request.SpanException(span); ok {
+			addExceptionEvent(&s, &exception, t.End)
+		}
 		s.SetEndTimestamp(pcommon.NewTimestampFromTime(t.End))
 	}
 	return traces
@@ -213,6 +222,21 @@ func createSubSpans(span *request.Span, parentSpanID pcommon.SpanID, traceID pco
 
 var emptyUID = svc.UID{}
 
+// addExceptionEvent adds the exception span event, as defined by the semantic conventions
+func addExceptionEvent(s *ptrace.Span, exception *request.Exception, timestamp time.Time) {
+	e := s.Events().AppendEmpty()
+	e.SetName(semconv.ExceptionEventName)
+	e.SetTimestamp(pcommon.NewTimestampFromTime(timestamp))
+	attrs := []attribute.KeyValue{
+		semconv.ExceptionType(exception.Type),
+		semconv.ExceptionMessage(exception.Message),
+	}
+	if exception.Code.Valid() {
+		attrs = append(attrs, exception.Code)
+	}
+	addAttrsToMap(attrs, e.Attributes())
+}
+
 func TraceAppResourceAttrs(cache *expirable2.LRU[svc.UID, []attribute.KeyValue], nodeMeta *meta.NodeMeta, service *svc.Attrs) []attribute.KeyValue {
 	// TODO: remove?
 	if service.UID == emptyUID {
@@ -309,8 +333,9 @@ var (
 // httpHeaderAttributes converts extracted HTTP headers to OTel span attributes
 // following the semantic convention: http.request.header.<key> and http.response.header.<key>
 // where <key> is the lowercased header field name. Values are string slices per the spec.
+// The selected W3C baggage entries are reported as baggage.<key>.
 func httpHeaderAttributes(span *request.Span) []attribute.KeyValue {
-	attrs := make([]attribute.KeyValue, 0, len(span.RequestHeaders)+len(span.ResponseHeaders))
+	attrs := make([]attribute.KeyValue, 0, len(span.RequestHeaders)+len(span.ResponseHeaders)+len(span.Baggage))
 	for name, values := range span.RequestHeaders {
 		key := "http.request.header." + strings.ToLower(name)
 		attrs = append(attrs, attribute.StringSlice(key, values))
@@ -319,6 +344,9 @@ func httpHeaderAttributes(span *request.Span) []attribute.KeyValue {
 		key := "http.response.header." + strings.ToLower(name)
 		attrs = append(attrs, attribute.StringSlice(key, values))
 	}
+	for key, value := range span.Baggage {
+		attrs = append(attrs, attr.Baggage(key).OTEL().String(value))
+	}
 	return attrs
 }
 
@@ -605,7 +633,7 @@ func TraceAttributesSelector(span *request.Span, optionalAttrs map[attr.Name]str
 			attrs = append(attrs, request.PeerService(request.PeerServiceFromSpan(span)))
 		}
 
-		if span.MessagingInfo != nil {
+		if span.MessagingInfo != nil && span.MessagingInfo.Partition != request.UnknownPartition {
 			attrs = append(attrs, request.MessagingPartition(span.MessagingInfo.Partition))
 			if span.Method == request.MessagingProcess {
 				attrs = append(attrs, request.MessagingKafkaOffset(span.MessagingInfo.Offset))
@@ -0,0 +1,85 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prom // import "go.opentelemetry.io/obi/pkg/export/prom"
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/obi/pkg/export"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
+)
+
+// overflowLabel is only set, with "true" value, in the series where the measurements
+// of a metric are aggregated once it reaches its cardinality limit
+var overflowLabel = attr.Name(export.OverflowAttribute).Prom()
+
+// cardinalityLimits applies the configured cardinality limits to the Prometheus metrics.
+// Since all the series of a Prometheus metric must have the same label names, the
+// limited metrics get an extra overflow label, which is left empty for the non-overflow series.
+type cardinalityLimits struct {
+	cfg     *export.CardinalityLimits
+	metrics imetrics.Reporter
+}
+
+type cardinalityLimit struct {
+	max        int
+	onOverflow func()
+}
+
+func newCardinalityLimits(cfg *export.CardinalityLimits, metrics imetrics.Reporter) *cardinalityLimits {
+	if metrics == nil {
+		metrics = imetrics.NoopReporter{}
+	}
+	return &cardinalityLimits{cfg: cfg, metrics: metrics}
+}
+
+// labelNames returns the label names of the provided metric, adding the overflow
+// label if the metric is limited
+func (cl *cardinalityLimits) labelNames(metric string, names []string) []string {
+	if cl.cfg.For(metric) <= 0 {
+		return names
+	}
+	return append(slices.Clip(names), overflowLabel)
+}
+
+// For returns the cardinality limit of the provided metric
+func (cl *cardinalityLimits) For(metric string) cardinalityLimit {
+	return cardinalityLimit{
+		max: cl.cfg.For(metric),
+		onOverflow: func() {
+			cl.metrics.MetricCardinalityOverflow("prometheus", metric)
+		},
+	}
+}
+
+// rejectedLabelSets remembers the label sets that were replaced by the overflow label set,
+// so the overflows are reported only once per label set. It is bounded to the cardinality
+// limit, and reset once it is full.
+type rejectedLabelSets struct {
+	mt   sync.Mutex
+	max  int
+	sets map[string]struct{}
+}
+
+func newRejectedLabelSets(limit int) *rejectedLabelSets {
+	return &rejectedLabelSets{max: limit, sets: map[string]struct{}{}}
+}
+
+// add returns true if the label set had not been rejected before
+func (r *rejectedLabelSets) add(lbls []string) bool {
+	key := strings.Join(lbls, ":")
+	r.mt.Lock()
+	defer r.mt.Unlock()
+	if _, ok := r.sets[key]; ok {
+		return false
+	}
+	if len(r.sets) >= r.max {
+		clear(r.sets)
+	}
+	r.sets[key] = struct{}{}
+	return true
+}
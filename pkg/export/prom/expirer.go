@@ -5,6 +5,7 @@ package prom // import "go.opentelemetry.io/obi/pkg/export/prom"
 
 import (
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -20,6 +21,15 @@ func plog() *slog.Logger {
 type Expirer[T prometheus.Metric] struct {
 	entries *expire.ExpiryMap[*MetricEntry[T]]
 	wrapped *prometheus.MetricVec
+	limit   cardinalityLimit
+	// label sets that were replaced by the overflow label set
+	rejected *rejectedLabelSets
+	// keep the label values at the given indices, if not nil
+	keep []int
+	// dropped is the only entry of a dropped metric, which is never collected
+	dropped *MetricEntry[T]
+	// onRemove is invoked with each removed entry
+	onRemove func(*MetricEntry[T])
 }
 
 type MetricEntry[T prometheus.Metric] struct {
@@ -41,6 +51,15 @@ func NewExpirer[T prometheus.Metric](wrapped *prometheus.MetricVec, clock func()
 // label values is accessed for the first time, a new Counter is created.
 // If not, a cached copy is returned and the "last access" cache time is updated.
 func (ex *Expirer[T]) WithLabelValues(lbls ...string) *MetricEntry[T] {
+	if ex.dropped != nil {
+		return ex.dropped
+	}
+	if ex.keep != nil {
+		lbls = keepLabelValues(lbls, ex.keep)
+	}
+	if ex.limit.max > 0 {
+		lbls = ex.limitLabelValues(lbls)
+	}
 	return ex.entries.GetOrCreate(lbls, func() *MetricEntry[T] {
 		plog().With("labelValues", lbls).Debug("storing new metric label set")
 		c, err := ex.wrapped.GetMetricWithLabelValues(lbls...)
@@ -56,17 +75,98 @@ func (ex *Expirer[T]) WithLabelValues(lbls ...string) *MetricEntry[T] {
 	})
 }
 
+// WithCardinalityLimit limits the number of label sets of the Expirer. Once the limit is
+// reached, the new label sets are replaced by the overflow label set. The wrapped metric
+// vector must have been created with the label names returned by cardinalityLimits.labelNames.
+func (ex *Expirer[T]) WithCardinalityLimit(limit cardinalityLimit) *Expirer[T] {
+	ex.limit = limit
+	if limit.max > 0 {
+		ex.rejected = newRejectedLabelSets(limit.max)
+	}
+	return ex
+}
+
+// OnRemove sets a function that is invoked with each entry that is removed, e.g. because it expired
+func (ex *Expirer[T]) OnRemove(fn func(*MetricEntry[T])) *Expirer[T] {
+	ex.onRemove = fn
+	return ex
+}
+
+// limitLabelValues appends the overflow label value to the provided label values. If adding
+// them would exceed the cardinality limit, the overflow label set is returned instead.
+func (ex *Expirer[T]) limitLabelValues(lbls []string) []string {
+	lbls = append(slices.Clip(lbls), "")
+	// as specified by OpenTelemetry, the limit accounts for the overflow label set
+	if ex.entries.Contains(lbls) || ex.entries.Len() < ex.limit.max-1 {
+		return lbls
+	}
+	// the overflows are accounted once per rejected label set, not per measurement
+	if ex.limit.onOverflow != nil && ex.rejected.add(lbls) {
+		ex.limit.onOverflow()
+	}
+	overflow := make([]string, len(lbls))
+	overflow[len(overflow)-1] = "true"
+	return overflow
+}
+
+// DeleteLabelValues removes the metric for the given slice of label values, if it exists.
+// The label sets that were replaced by the overflow label set are ignored.
+func (ex *Expirer[T]) DeleteLabelValues(lbls ...string) {
+	if ex.dropped != nil {
+		return
+	}
+	if ex.keep != nil {
+		lbls = keepLabelValues(lbls, ex.keep)
+	}
+	if ex.limit.max > 0 {
+		lbls = append(slices.Clip(lbls), "")
+	}
+	if old, ok := ex.entries.Delete(lbls); ok {
+		ex.wrapped.DeleteLabelValues(old.LabelVals...)
+		if ex.onRemove != nil {
+			ex.onRemove(old)
+		}
+	}
+}
+
+// dropAll makes the Expirer to not report any metric. All the label sets are
+// redirected to a single entry that is never collected.
+// The wrapped metric vector must have been created without labels, apart from the
+// overflow label of the cardinality limits.
+func (ex *Expirer[T]) dropAll() *Expirer[T] {
+	ex.keep = []int{}
+	ex.dropped = ex.WithLabelValues()
+	return ex
+}
+
+func keepLabelValues(lbls []string, keep []int) []string {
+	kept := make([]string, 0, len(keep))
+	for _, i := range keep {
+		kept = append(kept, lbls[i])
+	}
+	return kept
+}
+
 // Describe wraps prometheus.Collector Describe method
 func (ex *Expirer[T]) Describe(descs chan<- *prometheus.Desc) {
+	if ex.dropped != nil {
+		return
+	}
 	ex.wrapped.Describe(descs)
 }
 
 // Collect wraps prometheus.Collector Wrap method
 func (ex *Expirer[T]) Collect(metrics chan<- prometheus.Metric) {
+	if ex.dropped != nil {
+		return
+	}
 	log := plog()
 	for _, old := range ex.entries.DeleteExpired() {
 		ex.wrapped.DeleteLabelValues(old.LabelVals...)
 		log.With("labelValues", old).Debug("deleting old Prometheus metric")
+		if ex.onRemove != nil {
+			ex.onRemove(old)
+		}
 	}
 	for _, m := range ex.entries.All() {
 		metrics <- m.Metric
@@ -134,6 +134,28 @@ type PrometheusConfig struct {
 
 	Buckets export.Buckets `yaml:"buckets"`
 
+	// HistogramType defines how the histograms are exposed. Accepted values:
+	// "classic_and_native" (default): both the classic buckets and the Prometheus native
+	// (sparse) buckets are exposed. The scraper decides which ones are ingested.
+	// "native": only the native buckets are exposed. The Buckets configuration is ignored.
+	// "classic": only the classic buckets are exposed.
+	HistogramType HistogramType `yaml:"histogram_type" env:"OTEL_EBPF_PROMETHEUS_HISTOGRAM_TYPE" validate:"omitempty,oneof=classic native classic_and_native"`
+	// NativeHistogramBucketFactor is the maximum growth factor between the boundaries of two
+	// consecutive native histogram buckets. Lower values provide higher resolution at the cost
+	// of more buckets. Defaults to 1.1.
+	NativeHistogramBucketFactor float64 `yaml:"native_histogram_bucket_factor" env:"OTEL_EBPF_PROMETHEUS_NATIVE_HISTOGRAM_BUCKET_FACTOR" validate:"omitempty,gt=1"`
+	// NativeHistogramMaxBuckets is the maximum number of buckets of a native histogram. If it is
+	// exceeded, the resolution of the histogram is reduced. Defaults to 100.
+	NativeHistogramMaxBuckets uint32 `yaml:"native_histogram_max_buckets" env:"OTEL_EBPF_PROMETHEUS_NATIVE_HISTOGRAM_MAX_BUCKETS"`
+
+	// CardinalityLimits of each metric, by Prometheus metric name. Since the metrics of all the
+	// services are exposed together, the limits apply to the overall number of series of each metric.
+	CardinalityLimits export.CardinalityLimits `yaml:"cardinality_limits" envPrefix:"OTEL_EBPF_PROMETHEUS_CARDINALITY_LIMITS_"`
+
+	// Views override how the matching metrics, by Prometheus metric name, are reported: renaming them,
+	// restricting their labels, overriding their histogram buckets or dropping them.
+	Views export.MetricViews `yaml:"views" validate:"omitempty,dive"`
+
 	// TTL is the time since a metric was updated for the last time until it is
 	// removed from the metrics set.
 	TTL                         time.Duration `yaml:"ttl" env:"OTEL_EBPF_PROMETHEUS_TTL"`
@@ -162,6 +184,15 @@ type PrometheusConfig struct {
 	ExtraSpanResourceLabels []string `yaml:"extra_span_resource_attributes" env:"OTEL_EBPF_PROMETHEUS_EXTRA_SPAN_RESOURCE_ATTRIBUTES" envSeparator:","`
 }
 
+// HistogramType defines which kind of buckets are exposed for the Prometheus histograms
+type HistogramType string
+
+const (
+	HistogramTypeClassicAndNative HistogramType = "classic_and_native"
+	HistogramTypeNative           HistogramType = "native"
+	HistogramTypeClassic          HistogramType = "classic"
+)
+
 func mlog() *slog.Logger {
 	return slog.With("component", "prom.MetricsReporter")
 }
@@ -170,6 +201,27 @@ func (p *PrometheusConfig) EndpointEnabled() bool {
 	return p.Port != 0 || p.Registry != nil
 }
 
+// histogramOpts returns the options of a histogram with the provided classic buckets,
+// according to the configured histogram type.
+func (p *PrometheusConfig) histogramOpts(name, help string, buckets []float64) prometheus.HistogramOpts {
+	opts := prometheus.HistogramOpts{Name: name, Help: help}
+	if p.HistogramType != HistogramTypeNative {
+		opts.Buckets = buckets
+	}
+	if p.HistogramType != HistogramTypeClassic {
+		opts.NativeHistogramBucketFactor = defaultHistogramBucketFactor
+		if p.NativeHistogramBucketFactor > 1 {
+			opts.NativeHistogramBucketFactor = p.NativeHistogramBucketFactor
+		}
+		opts.NativeHistogramMaxBucketNumber = defaultHistogramMaxBucketNumber
+		if p.NativeHistogramMaxBuckets > 0 {
+			opts.NativeHistogramMaxBucketNumber = p.NativeHistogramMaxBuckets
+		}
+		opts.NativeHistogramMinResetDuration = defaultHistogramMinResetDuration
+	}
+	return opts
+}
+
 type metricsReporter struct {
 	cfg                     *PrometheusConfig
 	extraMetadataLabels     []attr.Name
@@ -184,22 +236,37 @@ type metricsReporter struct {
 	grpcDuration           *Expirer[prometheus.Histogram]
 	grpcClientDuration     *Expirer[prometheus.Histogram]
 	dbClientDuration       *Expirer[prometheus.Histogram]
+	dbServerDuration       *Expirer[prometheus.Histogram]
 	msgPublishDuration     *Expirer[prometheus.Histogram]
 	msgProcessDuration     *Expirer[prometheus.Histogram]
+	msgSentMessages        *Expirer[prometheus.Counter]
+	msgSentBytes           *Expirer[prometheus.Counter]
+	msgConsumedMessages    *Expirer[prometheus.Counter]
+	msgConsumedBytes       *Expirer[prometheus.Counter]
 	httpRequestSize        *Expirer[prometheus.Histogram]
 	httpResponseSize       *Expirer[prometheus.Histogram]
 	httpClientRequestSize  *Expirer[prometheus.Histogram]
 	httpClientResponseSize *Expirer[prometheus.Histogram]
 	targetInfo             *prometheus.GaugeVec
 
+	// in-flight requests
+	httpActiveRequests       *Expirer[prometheus.Gauge]
+	httpClientActiveRequests *Expirer[prometheus.Gauge]
+	activeRequests           *otel.ActiveRequestsTracker[prometheus.Gauge]
+
 	// user-selected attributes for the application-level metrics
 	attrHTTPDuration           []attributes.Field[*request.Span, string]
 	attrHTTPClientDuration     []attributes.Field[*request.Span, string]
 	attrGRPCDuration           []attributes.Field[*request.Span, string]
 	attrGRPCClientDuration     []attributes.Field[*request.Span, string]
 	attrDBClientDuration       []attributes.Field[*request.Span, string]
+	attrDBServerDuration       []attributes.Field[*request.Span, string]
 	attrMsgPublishDuration     []attributes.Field[*request.Span, string]
 	attrMsgProcessDuration     []attributes.Field[*request.Span, string]
+	attrMsgSentMessages        []attributes.Field[*request.Span, string]
+	attrMsgSentBytes           []attributes.Field[*request.Span, string]
+	attrMsgConsumedMessages    []attributes.Field[*request.Span, string]
+	attrMsgConsumedBytes       []attributes.Field[*request.Span, string]
 	attrHTTPRequestSize        []attributes.Field[*request.Span, string]
 	attrHTTPResponseSize       []attributes.Field[*request.Span, string]
 	attrHTTPClientRequestSize  []attributes.Field[*request.Span, string]
@@ -212,9 +279,13 @@ type metricsReporter struct {
 	attrCudaMemoryCopies       []attributes.Field[*request.Span, string]
 	attrSvcGraph               []attributes.Field[*request.Span, string]
 	attrDNSLookupDuration      []attributes.Field[*request.Span, string]
+	attrDNSLookupFailures      []attributes.Field[*request.Span, string]
+	attrConnectionFailures     []attributes.Field[*request.Span, string]
 	attrGenAIClientDuration    []attributes.Field[*request.Span, string]
 	attrGenAIInputTokenUsage   []attributes.Field[*request.Span, string]
 	attrGenAIOutputTokenUsage  []attributes.Field[*request.Span, string]
+	attrHTTPActiveRequests     []attributes.Field[*request.Span, string]
+	attrHTTPClientActive       []attributes.Field[*request.Span, string]
 
 	// trace span metrics
 	spanMetricsLatency           *Expirer[prometheus.Histogram]
@@ -240,6 +311,10 @@ type metricsReporter struct {
 
 	// dns related metrics
 	dnsLookupDuration *Expirer[prometheus.Histogram]
+	dnsLookupFailures *Expirer[prometheus.Counter]
+
+	// failed connections
+	connectionFailures *Expirer[prometheus.Counter]
 
 	// genAI related metrics
 	genAIClientDuration *Expirer[prometheus.Histogram]
@@ -326,6 +401,7 @@ func newReporter(
 	is := instrumentations.NewInstrumentationSelection(cfg.Instrumentations)
 
 	var attrHTTPDuration, attrHTTPClientDuration, attrHTTPRequestSize, attrHTTPResponseSize, attrHTTPClientRequestSize, attrHTTPClientResponseSize, attrSvcGraph []attributes.Field[*request.Span, string]
+	var attrHTTPActiveRequests, attrHTTPClientActive []attributes.Field[*request.Span, string]
 
 	attributeGetters := request.SpanPromGetters(unresolved)
 
@@ -342,6 +418,10 @@ func newReporter(
 			attrsProvider.For(attributes.HTTPClientRequestSize))
 		attrHTTPClientResponseSize = attributes.PrometheusGetters(attributeGetters,
 			attrsProvider.For(attributes.HTTPClientResponseSize))
+		attrHTTPActiveRequests = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.HTTPServerActiveRequests))
+		attrHTTPClientActive = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.HTTPClientActiveRequests))
 	}
 
 	var attrGRPCDuration, attrGRPCClientDuration []attributes.Field[*request.Span, string]
@@ -353,20 +433,32 @@ func newReporter(
 			attrsProvider.For(attributes.RPCClientDuration))
 	}
 
-	var attrDBClientDuration []attributes.Field[*request.Span, string]
+	var attrDBClientDuration, attrDBServerDuration []attributes.Field[*request.Span, string]
 
 	if is.DBEnabled() {
 		attrDBClientDuration = attributes.PrometheusGetters(attributeGetters,
 			attrsProvider.For(attributes.DBClientDuration))
+		attrDBServerDuration = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.DBServerDuration))
 	}
 
-	var attrMessagingProcessDuration, attrMessagingPublishDuration []attributes.Field[*request.Span, string]
+	var attrMessagingProcessDuration, attrMessagingPublishDuration,
+		attrMessagingSentMessages, attrMessagingSentBytes,
+		attrMessagingConsumedMessages, attrMessagingConsumedBytes []attributes.Field[*request.Span, string]
 
 	if is.MQEnabled() {
 		attrMessagingPublishDuration = attributes.PrometheusGetters(attributeGetters,
 			attrsProvider.For(attributes.MessagingPublishDuration))
 		attrMessagingProcessDuration = attributes.PrometheusGetters(attributeGetters,
 			attrsProvider.For(attributes.MessagingProcessDuration))
+		attrMessagingSentMessages = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.MessagingSentMessages))
+		attrMessagingSentBytes = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.MessagingSentBytes))
+		attrMessagingConsumedMessages = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.MessagingConsumedMessages))
+		attrMessagingConsumedBytes = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.MessagingConsumedBytes))
 	}
 
 	var attrCudaKernelLaunchCalls []attributes.Field[*request.Span, string]
@@ -391,13 +483,19 @@ func newReporter(
 			attrsProvider.For(attributes.GPUCudaMemoryCopies))
 	}
 
-	var attrDNSLookupDuration []attributes.Field[*request.Span, string]
+	var attrDNSLookupDuration, attrDNSLookupFailures []attributes.Field[*request.Span, string]
 
 	if is.DNSEnabled() {
 		attrDNSLookupDuration = attributes.PrometheusGetters(attributeGetters,
 			attrsProvider.For(attributes.DNSLookupDuration))
+		attrDNSLookupFailures = attributes.PrometheusGetters(attributeGetters,
+			attrsProvider.For(attributes.DNSLookupFailures))
 	}
 
+	// failed connections are not bound to any specific instrumentation
+	attrConnectionFailures := attributes.PrometheusGetters(attributeGetters,
+		attrsProvider.For(attributes.NetworkConnectionFailures))
+
 	var attrGenAIClientDuration []attributes.Field[*request.Span, string]
 	var attrGenAIInputTokenUsage []attributes.Field[*request.Span, string]
 	var attrGenAIOutputTokenUsage []attributes.Field[*request.Span, string]
@@ -423,6 +521,7 @@ func newReporter(
 	}
 
 	clock := expire.NewCachedClock(timeNow)
+	expirers := newExpirerFactory(cfg, ctxInfo.Metrics, clock.Time)
 
 	// If service name is not explicitly set, we take the service name as set by the
 	// executable inspector
@@ -449,8 +548,13 @@ func newReporter(
 		attrGRPCDuration:           attrGRPCDuration,
 		attrGRPCClientDuration:     attrGRPCClientDuration,
 		attrDBClientDuration:       attrDBClientDuration,
+		attrDBServerDuration:       attrDBServerDuration,
 		attrMsgPublishDuration:     attrMessagingPublishDuration,
 		attrMsgProcessDuration:     attrMessagingProcessDuration,
+		attrMsgSentMessages:        attrMessagingSentMessages,
+		attrMsgSentBytes:           attrMessagingSentBytes,
+		attrMsgConsumedMessages:    attrMessagingConsumedMessages,
+		attrMsgConsumedBytes:       attrMessagingConsumedBytes,
 		attrHTTPRequestSize:        attrHTTPRequestSize,
 		attrHTTPResponseSize:       attrHTTPResponseSize,
 		attrHTTPClientRequestSize:  attrHTTPClientRequestSize,
@@ -462,10 +566,15 @@ func newReporter(
 		attrCudaKernelBlockSize:    attrCudaKernelBlockSize,
 		attrCudaMemoryCopies:       attrCudaMemoryCopies,
 		attrDNSLookupDuration:      attrDNSLookupDuration,
+		attrDNSLookupFailures:      attrDNSLookupFailures,
+		attrConnectionFailures:     attrConnectionFailures,
 		attrGenAIClientDuration:    attrGenAIClientDuration,
 		attrGenAIInputTokenUsage:   attrGenAIInputTokenUsage,
 		attrGenAIOutputTokenUsage:  attrGenAIOutputTokenUsage,
 		attrSvcGraph:               attrSvcGraph,
+		attrHTTPActiveRequests:     attrHTTPActiveRequests,
+		attrHTTPClientActive:       attrHTTPClientActive,
+		activeRequests:             otel.NewActiveRequestsTracker[prometheus.Gauge](otel.ActiveRequestsTimeout),
 		obiInfo: NewExpirer[prometheus.Gauge](prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: attr.VendorPrefix + buildInfoSuffix,
 			Help: "A metric with a constant '1' value labeled by version, revision, branch, " +
@@ -480,142 +589,162 @@ func newReporter(
 			},
 		}, obiInfoLabelNames).MetricVec, clock.Time, cfg.TTL),
 		httpDuration: optionalHistogramProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.HTTPServerDuration.Prom,
-				Help:                            "duration of HTTP service calls from the server side, in seconds",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrHTTPDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.HTTPServerDuration.Prom,
+				"duration of HTTP service calls from the server side, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrHTTPDuration),
+			)
 		}),
 		httpClientDuration: optionalHistogramProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.HTTPClientDuration.Prom,
-				Help:                            "duration of HTTP service calls from the client side, in seconds",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrHTTPClientDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.HTTPClientDuration.Prom,
+				"duration of HTTP service calls from the client side, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrHTTPClientDuration),
+			)
 		}),
 		grpcDuration: optionalHistogramProvider(is.GRPCEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.RPCServerDuration.Prom,
-				Help:                            "duration of RCP service calls from the server side, in seconds",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrGRPCDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.RPCServerDuration.Prom,
+				"duration of RCP service calls from the server side, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrGRPCDuration),
+			)
 		}),
 		grpcClientDuration: optionalHistogramProvider(is.GRPCEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.RPCClientDuration.Prom,
-				Help:                            "duration of GRPC service calls from the client side, in seconds",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrGRPCClientDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.RPCClientDuration.Prom,
+				"duration of GRPC service calls from the client side, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrGRPCClientDuration),
+			)
 		}),
 		dbClientDuration: optionalHistogramProvider(is.DBEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.DBClientDuration.Prom,
-				Help:                            "duration of db client operations, in seconds",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrDBClientDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.DBClientDuration.Prom,
+				"duration of db client operations, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrDBClientDuration),
+			)
+		}),
+		dbServerDuration: optionalHistogramProvider(is.DBEnabled(), func() *Expirer[prometheus.Histogram] {
+			return expirers.histogram(
+				attributes.DBServerDuration.Prom,
+				"duration of db server operations, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrDBServerDuration),
+			)
 		}),
 		msgPublishDuration: optionalHistogramProvider(is.MQEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.MessagingPublishDuration.Prom,
-				Help:                            "duration of messaging client publish operations, in seconds",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrMessagingPublishDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.MessagingPublishDuration.Prom,
+				"duration of messaging client publish operations, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrMessagingPublishDuration),
+			)
 		}),
 		msgProcessDuration: optionalHistogramProvider(is.MQEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.MessagingProcessDuration.Prom,
-				Help:                            "duration of messaging client process operations, in seconds",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrMessagingProcessDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.MessagingProcessDuration.Prom,
+				"duration of messaging client process operations, in seconds",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrMessagingProcessDuration),
+			)
+		}),
+		msgSentMessages: optionalCounterProvider(is.MQEnabled(), func() *Expirer[prometheus.Counter] {
+			return expirers.counter(prometheus.CounterOpts{
+				Name: attributes.MessagingSentMessages.Prom,
+				Help: "number of messages sent to a messaging broker",
+			}, labelNames(attrMessagingSentMessages))
+		}),
+		msgSentBytes: optionalCounterProvider(is.MQEnabled(), func() *Expirer[prometheus.Counter] {
+			return expirers.counter(prometheus.CounterOpts{
+				Name: attributes.MessagingSentBytes.Prom,
+				Help: "size of the messages sent to a messaging broker, in bytes",
+			}, labelNames(attrMessagingSentBytes))
+		}),
+		msgConsumedMessages: optionalCounterProvider(is.MQEnabled(), func() *Expirer[prometheus.Counter] {
+			return expirers.counter(prometheus.CounterOpts{
+				Name: attributes.MessagingConsumedMessages.Prom,
+				Help: "number of messages consumed from a messaging broker",
+			}, labelNames(attrMessagingConsumedMessages))
+		}),
+		msgConsumedBytes: optionalCounterProvider(is.MQEnabled(), func() *Expirer[prometheus.Counter] {
+			return expirers.counter(prometheus.CounterOpts{
+				Name: attributes.MessagingConsumedBytes.Prom,
+				Help: "size of the messages consumed from a messaging broker, in bytes",
+			}, labelNames(attrMessagingConsumedBytes))
 		}),
 		httpRequestSize: optionalHistogramProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.HTTPServerRequestSize.Prom,
-				Help:                            "size, in bytes, of the HTTP request body as received at the server side",
-				Buckets:                         cfg.Buckets.RequestSizeHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrHTTPRequestSize)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.HTTPServerRequestSize.Prom,
+				"size, in bytes, of the HTTP request body as received at the server side",
+				cfg.Buckets.RequestSizeHistogram,
+				labelNames(attrHTTPRequestSize),
+			)
 		}),
 		httpResponseSize: optionalHistogramProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.HTTPServerResponseSize.Prom,
-				Help:                            "size, in bytes, of the HTTP response body as received at the server side",
-				Buckets:                         cfg.Buckets.ResponseSizeHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrHTTPResponseSize)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.HTTPServerResponseSize.Prom,
+				"size, in bytes, of the HTTP response body as received at the server side",
+				cfg.Buckets.ResponseSizeHistogram,
+				labelNames(attrHTTPResponseSize),
+			)
 		}),
 		httpClientRequestSize: optionalHistogramProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.HTTPClientRequestSize.Prom,
-				Help:                            "size, in bytes, of the HTTP request body as sent from the client side",
-				Buckets:                         cfg.Buckets.RequestSizeHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrHTTPClientRequestSize)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.HTTPClientRequestSize.Prom,
+				"size, in bytes, of the HTTP request body as sent from the client side",
+				cfg.Buckets.RequestSizeHistogram,
+				labelNames(attrHTTPClientRequestSize),
+			)
 		}),
 		httpClientResponseSize: optionalHistogramProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.HTTPClientResponseSize.Prom,
-				Help:                            "size, in bytes, of the HTTP response body as sent from the client side",
-				Buckets:                         cfg.Buckets.ResponseSizeHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrHTTPClientResponseSize)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.HTTPClientResponseSize.Prom,
+				"size, in bytes, of the HTTP response body as sent from the client side",
+				cfg.Buckets.ResponseSizeHistogram,
+				labelNames(attrHTTPClientResponseSize),
+			)
+		}),
+		httpActiveRequests: optionalGaugeProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Gauge] {
+			return expirers.gauge(prometheus.GaugeOpts{
+				Name: attributes.HTTPServerActiveRequests.Prom,
+				Help: "number of HTTP server requests that have started but not finished yet",
+			}, labelNames(attrHTTPActiveRequests))
+		}),
+		httpClientActiveRequests: optionalGaugeProvider(is.HTTPEnabled(), func() *Expirer[prometheus.Gauge] {
+			return expirers.gauge(prometheus.GaugeOpts{
+				Name: attributes.HTTPClientActiveRequests.Prom,
+				Help: "number of HTTP client requests that have started but not finished yet",
+			}, labelNames(attrHTTPClientActive))
 		}),
 		spanMetricsLatency: optionalHistogramProvider(jointMetricsConfig.Features.SpanMetrics(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            spanMetricsLatencyName(jointMetricsConfig),
-				Help:                            "duration of service calls (client and server), in seconds, in trace span metrics format",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNamesSpans(extraSpanMetadataLabels)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				spanMetricsLatencyName(jointMetricsConfig),
+				"duration of service calls (client and server), in seconds, in trace span metrics format",
+				cfg.Buckets.DurationHistogram,
+				labelNamesSpans(extraSpanMetadataLabels),
+			)
 		}),
 		spanMetricsCallsTotal: optionalCounterProvider(jointMetricsConfig.Features.SpanMetrics(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: spanMetricsCallsName(jointMetricsConfig),
 				Help: "number of service calls in trace span metrics format",
-			}, labelNamesSpans(extraSpanMetadataLabels)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesSpans(extraSpanMetadataLabels))
 		}),
 		spanMetricsRequestSizeTotal: optionalCounterProvider(jointMetricsConfig.Features.SpanSizes(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: SpanMetricsRequestSizes,
 				Help: "size of service calls, in bytes, in trace span metrics format",
-			}, labelNamesSpans(extraSpanMetadataLabels)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesSpans(extraSpanMetadataLabels))
 		}),
 		spanMetricsResponseSizeTotal: optionalCounterProvider(jointMetricsConfig.Features.SpanSizes(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: SpanMetricsResponseSizes,
 				Help: "size of service responses, in bytes, in trace span metrics format",
-			}, labelNamesSpans(extraSpanMetadataLabels)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesSpans(extraSpanMetadataLabels))
 		}),
 		tracesTargetInfo: optionalDirectGaugeProvider(jointMetricsConfig.Features.AnySpanMetrics(), func() *prometheus.GaugeVec {
 			return prometheus.NewGaugeVec(prometheus.GaugeOpts{
@@ -630,119 +759,113 @@ func newReporter(
 			}, hostInfoLabelNames).MetricVec, clock.Time, cfg.TTL)
 		}),
 		serviceGraphClient: optionalHistogramProvider(jointMetricsConfig.Features.ServiceGraph(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            ServiceGraphClient,
-				Help:                            "duration of client service calls, in seconds, in trace service graph metrics format",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNamesSvcGraph(attrSvcGraph)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				ServiceGraphClient,
+				"duration of client service calls, in seconds, in trace service graph metrics format",
+				cfg.Buckets.DurationHistogram,
+				labelNamesSvcGraph(attrSvcGraph),
+			)
 		}),
 		serviceGraphServer: optionalHistogramProvider(jointMetricsConfig.Features.ServiceGraph(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            ServiceGraphServer,
-				Help:                            "duration of server service calls, in seconds, in trace service graph metrics format",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNamesSvcGraph(attrSvcGraph)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				ServiceGraphServer,
+				"duration of server service calls, in seconds, in trace service graph metrics format",
+				cfg.Buckets.DurationHistogram,
+				labelNamesSvcGraph(attrSvcGraph),
+			)
 		}),
 		serviceGraphFailed: optionalCounterProvider(jointMetricsConfig.Features.ServiceGraph(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: ServiceGraphFailed,
 				Help: "number of failed service calls in trace service graph metrics format",
-			}, labelNamesSvcGraph(attrSvcGraph)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesSvcGraph(attrSvcGraph))
 		}),
 		serviceGraphTotal: optionalCounterProvider(jointMetricsConfig.Features.ServiceGraph(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: ServiceGraphTotal,
 				Help: "number of service calls in trace service graph metrics format",
-			}, labelNamesSvcGraph(attrSvcGraph)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNamesSvcGraph(attrSvcGraph))
 		}),
 		targetInfo: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Name: TargetInfo,
 			Help: "attributes associated to a given monitored entity",
 		}, labelNamesTargetInfo(kubeEnabled, dockerEnabled, &ctxInfo.NodeMeta, extraMetadataLabels)),
 		cudaKernelCallsTotal: optionalCounterProvider(is.GPUEnabled(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: attributes.GPUCudaKernelLaunchCalls.Prom,
 				Help: "number of NVIDIA GPU cuda kernel launches",
-			}, labelNames(attrCudaKernelLaunchCalls)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNames(attrCudaKernelLaunchCalls))
 		}),
 		cudaGraphCallsTotal: optionalCounterProvider(is.GPUEnabled(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: attributes.GPUCudaGraphLaunchCalls.Prom,
 				Help: "number of NVIDIA GPU cuda graph launches",
-			}, labelNames(attrCudaGraphLaunchCalls)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNames(attrCudaGraphLaunchCalls))
 		}),
 		cudaMemoryAllocsTotal: optionalCounterProvider(is.GPUEnabled(), func() *Expirer[prometheus.Counter] {
-			return NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+			return expirers.counter(prometheus.CounterOpts{
 				Name: attributes.GPUCudaMemoryAllocations.Prom,
 				Help: "amount of NVIDIA GPU cuda allocated memory in bytes",
-			}, labelNames(attrCudaMemoryAllocations)).MetricVec, clock.Time, cfg.TTL)
+			}, labelNames(attrCudaMemoryAllocations))
 		}),
 		cudaKernelGridSize: optionalHistogramProvider(is.GPUEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.GPUCudaKernelGridSize.Prom,
-				Help:                            "number of blocks in the NVIDIA GPU cuda kernel grid",
-				Buckets:                         cfg.Buckets.RequestSizeHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrCudaKernelGridSize)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.GPUCudaKernelGridSize.Prom,
+				"number of blocks in the NVIDIA GPU cuda kernel grid",
+				cfg.Buckets.RequestSizeHistogram,
+				labelNames(attrCudaKernelGridSize),
+			)
 		}),
 		cudaKernelBlockSize: optionalHistogramProvider(is.GPUEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.GPUCudaKernelBlockSize.Prom,
-				Help:                            "number of threads in the NVIDIA GPU cuda kernel block",
-				Buckets:                         cfg.Buckets.RequestSizeHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrCudaKernelBlockSize)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.GPUCudaKernelBlockSize.Prom,
+				"number of threads in the NVIDIA GPU cuda kernel block",
+				cfg.Buckets.RequestSizeHistogram,
+				labelNames(attrCudaKernelBlockSize),
+			)
 		}),
 		cudaMemoryCopySize: optionalHistogramProvider(is.GPUEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.GPUCudaMemoryCopies.Prom,
-				Help:                            "amount of NVIDIA GPU cuda to and from memory copies",
-				Buckets:                         cfg.Buckets.RequestSizeHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrCudaMemoryCopies)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.GPUCudaMemoryCopies.Prom,
+				"amount of NVIDIA GPU cuda to and from memory copies",
+				cfg.Buckets.RequestSizeHistogram,
+				labelNames(attrCudaMemoryCopies),
+			)
 		}),
 		dnsLookupDuration: optionalHistogramProvider(is.DNSEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.DNSLookupDuration.Prom,
-				Help:                            "measures the time taken to perform a DNS lookup",
-				Buckets:                         cfg.Buckets.DurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrDNSLookupDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.DNSLookupDuration.Prom,
+				"measures the time taken to perform a DNS lookup",
+				cfg.Buckets.DurationHistogram,
+				labelNames(attrDNSLookupDuration),
+			)
 		}),
+		dnsLookupFailures: optionalCounterProvider(is.DNSEnabled(), func() *Expirer[prometheus.Counter] {
+			return expirers.counter(prometheus.CounterOpts{
+				Name: attributes.DNSLookupFailures.Prom,
+				Help: "number of DNS lookups that didn't return a successful response code",
+			}, labelNames(attrDNSLookupFailures))
+		}),
+		connectionFailures: expirers.counter(prometheus.CounterOpts{
+			Name: attributes.NetworkConnectionFailures.Prom,
+			Help: "number of outgoing TCP connections that couldn't be established",
+		}, labelNames(attrConnectionFailures)),
 		genAIClientDuration: optionalHistogramProvider(is.GenAIEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.GenAIClientOperationDuration.Prom,
-				Help:                            "measures the time taken to perform a GenAI client request",
-				Buckets:                         cfg.Buckets.GenAIClientDurationHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrGenAIClientDuration)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.GenAIClientOperationDuration.Prom,
+				"measures the time taken to perform a GenAI client request",
+				cfg.Buckets.GenAIClientDurationHistogram,
+				labelNames(attrGenAIClientDuration),
+			)
 		}),
 		// We make only one metric series, the input and output have the same name and attribute keys
 		genAITokenUsage: optionalHistogramProvider(is.GenAIEnabled(), func() *Expirer[prometheus.Histogram] {
-			return NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-				Name:                            attributes.GenAIClientInputTokenUsage.Prom,
-				Help:                            "number of input and output tokens used for a GenAI client request",
-				Buckets:                         cfg.Buckets.GenAITokenUsageHistogram,
-				NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-				NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-				NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-			}, labelNames(attrGenAIInputTokenUsage)).MetricVec, clock.Time, cfg.TTL)
+			return expirers.histogram(
+				attributes.GenAIClientInputTokenUsage.Prom,
+				"number of input and output tokens used for a GenAI client request",
+				cfg.Buckets.GenAITokenUsageHistogram,
+				labelNames(attrGenAIInputTokenUsage),
+			)
 		}),
 	}
 
@@ -750,6 +873,15 @@ func newReporter(
 	mr.deleteEventMetrics = mr.deleteTargetInfoMetrics
 	mr.createEventMetrics = mr.createTargetInfos
 
+	// discounting the tracked requests of the expired gauges would update gauges that aren't collected anymore
+	for _, active := range []*Expirer[prometheus.Gauge]{mr.httpActiveRequests, mr.httpClientActiveRequests} {
+		if active != nil {
+			active.OnRemove(func(e *MetricEntry[prometheus.Gauge]) {
+				mr.activeRequests.Forget(e.Metric)
+			})
+		}
+	}
+
 	registeredMetrics := []prometheus.Collector{mr.targetInfo}
 
 	if !mr.cfg.DisableBuildInfo {
@@ -765,6 +897,8 @@ func newReporter(
 				mr.httpRequestSize,
 				mr.httpResponseSize,
 				mr.httpDuration,
+				mr.httpClientActiveRequests,
+				mr.httpActiveRequests,
 			)
 		}
 
@@ -778,6 +912,7 @@ func newReporter(
 		if is.DBEnabled() {
 			registeredMetrics = append(registeredMetrics,
 				mr.dbClientDuration,
+				mr.dbServerDuration,
 			)
 		}
 
@@ -785,13 +920,19 @@ func newReporter(
 			registeredMetrics = append(registeredMetrics,
 				mr.msgProcessDuration,
 				mr.msgPublishDuration,
+				mr.msgSentMessages,
+				mr.msgSentBytes,
+				mr.msgConsumedMessages,
+				mr.msgConsumedBytes,
 			)
 		}
 
 		if is.DNSEnabled() {
-			registeredMetrics = append(registeredMetrics, mr.dnsLookupDuration)
+			registeredMetrics = append(registeredMetrics, mr.dnsLookupDuration, mr.dnsLookupFailures)
 		}
 
+		registeredMetrics = append(registeredMetrics, mr.connectionFailures)
+
 		if is.GenAIEnabled() {
 			registeredMetrics = append(registeredMetrics, mr.genAIClientDuration)
 			registeredMetrics = append(registeredMetrics, mr.genAITokenUsage)
@@ -898,6 +1039,7 @@ func (r *metricsReporter) reportMetrics(ctx context.Context) {
 
 func (r *metricsReporter) collectMetrics(ctx context.Context) {
 	go r.watchForProcessEvents(ctx)
+	go r.expireActiveRequestsPeriodically(ctx)
 	swarms.ForEachInput(ctx, r.input, nil, func(spans []request.Span) {
 		// clock needs to be updated to let the expirer
 		// remove the old metrics
@@ -908,6 +1050,43 @@ func (r *metricsReporter) collectMetrics(ctx context.Context) {
 	})
 }
 
+// expireActiveRequestsPeriodically discounts the requests that aren't reported
+// in-flight anymore, but whose finished span wasn't received
+func (r *metricsReporter) expireActiveRequestsPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(otel.ActiveRequestsTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.activeRequests.Expire(func(g prometheus.Gauge) {
+				g.Dec()
+			})
+		}
+	}
+}
+
+// observeInFlight accounts an in-flight request the first time it is reported
+func (r *metricsReporter) observeInFlight(span *request.Span) {
+	if !r.otelMetricsObserved(span) || !r.is.HTTPEnabled() {
+		return
+	}
+	var gauge prometheus.Gauge
+	// accessing the gauge on each report keeps it from expiring while the request is active
+	switch span.Type {
+	case request.EventTypeHTTP:
+		gauge = r.httpActiveRequests.WithLabelValues(labelValues(span, r.attrHTTPActiveRequests)...).Metric
+	case request.EventTypeHTTPClient:
+		gauge = r.httpClientActiveRequests.WithLabelValues(labelValues(span, r.attrHTTPClientActive)...).Metric
+	default:
+		return
+	}
+	if r.activeRequests.Start(span, gauge) {
+		gauge.Inc()
+	}
+}
+
 func (r *metricsReporter) otelMetricsObserved(span *request.Span) bool {
 	return span.Service.Features.AppRED() && !span.Service.ExportsOTelMetrics()
 }
@@ -961,6 +1140,33 @@ func (r *metricsReporter) observeHistogram(h prometheus.Histogram, value float64
 }
 
 // addCounter adds a value to a counter, attaching an exemplar when applicable.
+// addMessages accounts the messages and payload bytes that are sent or
+// consumed by a messaging span, if the protocol parser could extract them.
+func (r *metricsReporter) addMessages(span *request.Span) {
+	info := span.MessagingInfo
+	if info == nil {
+		return
+	}
+	var messages, bytes *Expirer[prometheus.Counter]
+	var attrMessages, attrBytes []attributes.Field[*request.Span, string]
+	switch span.Method {
+	case request.MessagingPublish:
+		messages, attrMessages = r.msgSentMessages, r.attrMsgSentMessages
+		bytes, attrBytes = r.msgSentBytes, r.attrMsgSentBytes
+	case request.MessagingProcess:
+		messages, attrMessages = r.msgConsumedMessages, r.attrMsgConsumedMessages
+		bytes, attrBytes = r.msgConsumedBytes, r.attrMsgConsumedBytes
+	default:
+		return
+	}
+	if info.Messages > 0 {
+		r.addCounter(messages.WithLabelValues(labelValues(span, attrMessages)...).Metric, float64(info.Messages), span)
+	}
+	if info.Bytes > 0 {
+		r.addCounter(bytes.WithLabelValues(labelValues(span, attrBytes)...).Metric, float64(info.Bytes), span)
+	}
+}
+
 func (r *metricsReporter) addCounter(c prometheus.Counter, value float64, span *request.Span) {
 	if r.shouldAddExemplar(span) {
 		if adder, ok := c.(prometheus.ExemplarAdder); ok {
@@ -974,6 +1180,16 @@ func (r *metricsReporter) addCounter(c prometheus.Counter, value float64, span *
 
 //nolint:cyclop
 func (r *metricsReporter) observe(span *request.Span) {
+	if span.InFlight {
+		if !request.IgnoreMetrics(span) && span.Service.ExportModes.CanExportMetrics() {
+			r.observeInFlight(span)
+		}
+		return
+	}
+	// the finished requests are discounted even if their metrics are filtered
+	if gauge, ok := r.activeRequests.End(span); ok {
+		gauge.Dec()
+	}
 	if r.otelSpanFiltered(span) {
 		return
 	}
@@ -1019,10 +1235,21 @@ func (r *metricsReporter) observe(span *request.Span) {
 			if r.is.GRPCEnabled() {
 				r.observeHistogram(r.grpcClientDuration.WithLabelValues(labelValues(span, r.attrGRPCClientDuration)...).Metric, duration, span)
 			}
-		case request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeRedisServer, request.EventTypeMongoClient, request.EventTypeCouchbaseClient, request.EventTypeMemcachedClient, request.EventTypeMemcachedServer:
+		case request.EventTypeRedisClient, request.EventTypeSQLClient, request.EventTypeMongoClient, request.EventTypeCouchbaseClient, request.EventTypeMemcachedClient:
 			if r.is.DBEnabled() {
 				r.observeHistogram(r.dbClientDuration.WithLabelValues(labelValues(span, r.attrDBClientDuration)...).Metric, duration, span)
 			}
+		case request.EventTypeRedisServer, request.EventTypeMemcachedServer:
+			if r.is.DBEnabled() {
+				// Redis and Memcached server operations have been historically reported as
+				// db_client_operation_duration_seconds, so we keep them there for backwards compatibility
+				r.observeHistogram(r.dbClientDuration.WithLabelValues(labelValues(span, r.attrDBClientDuration)...).Metric, duration, span)
+				r.observeHistogram(r.dbServerDuration.WithLabelValues(labelValues(span, r.attrDBServerDuration)...).Metric, duration, span)
+			}
+		case request.EventTypeSQLServer:
+			if r.is.DBEnabled() {
+				r.observeHistogram(r.dbServerDuration.WithLabelValues(labelValues(span, r.attrDBServerDuration)...).Metric, duration, span)
+			}
 		case request.EventTypeKafkaClient, request.EventTypeKafkaServer:
 			if r.is.KafkaEnabled() {
 				switch span.Method {
@@ -1031,6 +1258,7 @@ func (r *metricsReporter) observe(span *request.Span) {
 				case request.MessagingProcess:
 					r.observeHistogram(r.msgProcessDuration.WithLabelValues(labelValues(span, r.attrMsgProcessDuration)...).Metric, duration, span)
 				}
+				r.addMessages(span)
 			}
 		case request.EventTypeMQTTClient, request.EventTypeMQTTServer:
 			if r.is.MQTTEnabled() {
@@ -1040,6 +1268,7 @@ func (r *metricsReporter) observe(span *request.Span) {
 				case request.MessagingProcess:
 					r.observeHistogram(r.msgProcessDuration.WithLabelValues(labelValues(span, r.attrMsgProcessDuration)...).Metric, duration, span)
 				}
+				r.addMessages(span)
 			}
 		case request.EventTypeGPUCudaKernelLaunch:
 			if r.is.GPUEnabled() {
@@ -1062,7 +1291,12 @@ func (r *metricsReporter) observe(span *request.Span) {
 		case request.EventTypeDNS:
 			if r.is.DNSEnabled() {
 				r.observeHistogram(r.dnsLookupDuration.WithLabelValues(labelValues(span, r.attrDNSLookupDuration)...).Metric, duration, span)
+				if span.IsFailedDNSLookup() {
+					r.addCounter(r.dnsLookupFailures.WithLabelValues(labelValues(span, r.attrDNSLookupFailures)...).Metric, 1, span)
+				}
 			}
+		case request.EventTypeFailedConnect:
+			r.addCounter(r.connectionFailures.WithLabelValues(labelValues(span, r.attrConnectionFailures)...).Metric, 1, span)
 		}
 	}
 
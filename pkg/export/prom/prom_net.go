@@ -89,6 +89,7 @@ func newNetReporter(
 	}
 
 	clock := expire.NewCachedClock(timeNow)
+	expirers := newExpirerFactory(cfg.Config, ctxInfo.Metrics, clock.Time)
 	// If service name is not explicitly set, we take the service name as set by the
 	// executable inspector
 	mr := &netMetricsReporter{
@@ -107,10 +108,10 @@ func newNetReporter(
 			ebpf.RecordStringGetters(recordGettersConfig),
 			provider.For(attributes.NetworkFlow))
 
-		mr.flowBytes = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+		mr.flowBytes = expirers.counter(prometheus.CounterOpts{
 			Name: attributes.NetworkFlow.Prom,
 			Help: "bytes submitted from a source network endpoint to a destination network endpoint",
-		}, labelNames(mr.flowAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+		}, labelNames(mr.flowAttrs))
 		register = append(register, mr.flowBytes)
 	}
 
@@ -120,10 +121,10 @@ func newNetReporter(
 			ebpf.RecordStringGetters(recordGettersConfig),
 			provider.For(attributes.NetworkInterZone))
 
-		mr.interZone = NewExpirer[prometheus.Counter](prometheus.NewCounterVec(prometheus.CounterOpts{
+		mr.interZone = expirers.counter(prometheus.CounterOpts{
 			Name: attributes.NetworkInterZone.Prom,
 			Help: "bytes submitted between different cloud availability zones",
-		}, labelNames(mr.interZoneAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+		}, labelNames(mr.interZoneAttrs))
 		register = append(register, mr.interZone)
 	}
 
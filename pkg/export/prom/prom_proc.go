@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prom // import "go.opentelemetry.io/obi/pkg/export/prom"
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/export/connector"
+	"go.opentelemetry.io/obi/pkg/export/expire"
+	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/internal/process"
+	"go.opentelemetry.io/obi/pkg/pipe/global"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+// process metrics are labeled with the same job and instance as the application metrics, so they
+// can be joined with the target_info metric, which contains the rest of the resource attributes
+var procLabelNames = []string{
+	attr.Job.Prom(),
+	attr.Instance.Prom(),
+	attr.ServiceName.Prom(),
+	attr.ServiceNamespace.Prom(),
+	attr.ProcessPID.Prom(),
+}
+
+// ProcPrometheusConfig for process metrics just wraps the global PrometheusConfig as provided by the user
+type ProcPrometheusConfig struct {
+	Config    *PrometheusConfig
+	CommonCfg *perapp.MetricsConfig
+}
+
+// Enabled returns whether the node needs to be activated
+func (p ProcPrometheusConfig) Enabled() bool {
+	return p.Config != nil && p.Config.EndpointEnabled() && p.CommonCfg.Features.ProcessMetrics()
+}
+
+type procMetricsReporter struct {
+	cfg *PrometheusConfig
+
+	cpuTime       *Expirer[prometheus.Counter]
+	memoryUsage   *Expirer[prometheus.Gauge]
+	memoryVirtual *Expirer[prometheus.Gauge]
+	diskIO        *Expirer[prometheus.Counter]
+	openFDs       *Expirer[prometheus.Gauge]
+	threads       *Expirer[prometheus.Gauge]
+
+	promConnect *connector.PrometheusManager
+
+	clock *expire.CachedClock
+
+	input <-chan []*process.Status
+}
+
+func ProcPrometheusEndpoint(
+	ctxInfo *global.ContextInfo,
+	cfg *ProcPrometheusConfig,
+	input *msg.Queue[[]*process.Status],
+) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled() {
+			// This node is not going to be instantiated. Let the swarm library just ignore it.
+			return swarm.EmptyRunFunc()
+		}
+		reporter := newProcReporter(ctxInfo, cfg, input)
+		if cfg.Config.Registry != nil {
+			return reporter.collectMetrics, nil
+		}
+		return reporter.reportMetrics, nil
+	}
+}
+
+func newProcReporter(
+	ctxInfo *global.ContextInfo,
+	cfg *ProcPrometheusConfig,
+	input *msg.Queue[[]*process.Status],
+) *procMetricsReporter {
+	slog.With("component", "prom.ProcEndpoint").Debug("registering process metrics")
+	clock := expire.NewCachedClock(timeNow)
+	expirers := newExpirerFactory(cfg.Config, ctxInfo.Metrics, clock.Time)
+	mr := &procMetricsReporter{
+		cfg:         cfg.Config,
+		promConnect: ctxInfo.Prometheus,
+		clock:       clock,
+		cpuTime: expirers.counter(prometheus.CounterOpts{
+			Name: attributes.ProcessCPUTime.Prom,
+			Help: "Total CPU seconds consumed by the process, broken down by CPU mode",
+		}, slices.Concat(procLabelNames, []string{attr.CPUMode.Prom()})),
+		memoryUsage: expirers.gauge(prometheus.GaugeOpts{
+			Name: attributes.ProcessMemoryUsage.Prom,
+			Help: "The amount of physical memory in use by the process, in bytes",
+		}, procLabelNames),
+		memoryVirtual: expirers.gauge(prometheus.GaugeOpts{
+			Name: attributes.ProcessMemoryVirtual.Prom,
+			Help: "The amount of committed virtual memory of the process, in bytes",
+		}, procLabelNames),
+		diskIO: expirers.counter(prometheus.CounterOpts{
+			Name: attributes.ProcessDiskIO.Prom,
+			Help: "Disk bytes transferred by the process, broken down by direction",
+		}, slices.Concat(procLabelNames, []string{attr.DiskIODirection.Prom()})),
+		openFDs: expirers.gauge(prometheus.GaugeOpts{
+			Name: attributes.ProcessOpenFileDescriptors.Prom,
+			Help: "Number of file descriptors in use by the process",
+		}, procLabelNames),
+		threads: expirers.gauge(prometheus.GaugeOpts{
+			Name: attributes.ProcessThreads.Prom,
+			Help: "Process threads count",
+		}, procLabelNames),
+	}
+
+	register := []prometheus.Collector{
+		mr.cpuTime, mr.memoryUsage, mr.memoryVirtual, mr.diskIO, mr.openFDs, mr.threads,
+	}
+	if cfg.Config.Registry != nil {
+		cfg.Config.Registry.MustRegister(register...)
+	} else {
+		mr.promConnect.Register(cfg.Config.Port, cfg.Config.Path, register...)
+	}
+
+	mr.input = input.Subscribe(msg.SubscriberName("prom.ProcReporterInput"))
+	return mr
+}
+
+func (r *procMetricsReporter) reportMetrics(ctx context.Context) {
+	go r.promConnect.StartHTTP(ctx)
+	r.collectMetrics(ctx)
+}
+
+func (r *procMetricsReporter) collectMetrics(_ context.Context) {
+	for statuses := range r.input {
+		// clock needs to be updated to let the expirer
+		// remove the old metrics
+		r.clock.Update()
+		for _, s := range statuses {
+			r.observe(s)
+		}
+	}
+}
+
+func (r *procMetricsReporter) observe(s *process.Status) {
+	// the expirers keep the label values slices, so each invocation requires its own slice
+	r.cpuTime.WithLabelValues(procLabelValues(s, "user")...).Metric.Add(s.CPUTimeUserDelta)
+	r.cpuTime.WithLabelValues(procLabelValues(s, "system")...).Metric.Add(s.CPUTimeSystemDelta)
+	r.diskIO.WithLabelValues(procLabelValues(s, "read")...).Metric.Add(float64(s.IOReadBytesDelta))
+	r.diskIO.WithLabelValues(procLabelValues(s, "write")...).Metric.Add(float64(s.IOWriteBytesDelta))
+	r.memoryUsage.WithLabelValues(procLabelValues(s)...).Metric.Set(float64(s.MemoryRSSBytes))
+	r.memoryVirtual.WithLabelValues(procLabelValues(s)...).Metric.Set(float64(s.MemoryVirtualBytes))
+	r.openFDs.WithLabelValues(procLabelValues(s)...).Metric.Set(float64(s.OpenFileDescriptors))
+	r.threads.WithLabelValues(procLabelValues(s)...).Metric.Set(float64(s.Threads))
+}
+
+// procLabelValues returns the values in the same order as procLabelNames, followed by
+// the extra values (e.g. CPU mode or disk I/O direction)
+func procLabelValues(s *process.Status, extra ...string) []string {
+	lv := make([]string, 0, len(procLabelNames)+len(extra))
+	lv = append(lv,
+		s.Service.Job(),
+		s.Service.UID.Instance,
+		s.Service.UID.Name,
+		s.Service.UID.Namespace,
+		strconv.Itoa(int(s.PID)),
+	)
+	return append(lv, extra...)
+}
@@ -0,0 +1,103 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/export"
+	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/internal/process"
+	"go.opentelemetry.io/obi/pkg/pipe/global"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+func TestProcMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]*process.Status](msg.ChannelBufferLen(10))
+	cfg := &ProcPrometheusConfig{
+		Config:    &PrometheusConfig{Registry: registry, TTL: time.Hour},
+		CommonCfg: &perapp.MetricsConfig{Features: export.FeatureProcess},
+	}
+	require.True(t, cfg.Enabled())
+	run, err := ProcPrometheusEndpoint(&global.ContextInfo{}, cfg, input)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+
+	service := &svc.Attrs{UID: svc.UID{Name: "foo", Namespace: "bar", Instance: "foo-1"}}
+	status := &process.Status{
+		Service: service, PID: 123,
+		CPUTimeUserDelta: 1.5, CPUTimeSystemDelta: 0.5,
+		MemoryRSSBytes: 1000, MemoryVirtualBytes: 5000,
+		IOReadBytesDelta: 10, IOWriteBytesDelta: 20,
+		OpenFileDescriptors: 7, Threads: 3,
+	}
+	input.Send([]*process.Status{status})
+	input.Send([]*process.Status{{
+		Service: service, PID: 123,
+		CPUTimeUserDelta: 1, IOWriteBytesDelta: 5,
+		MemoryRSSBytes: 2000, OpenFileDescriptors: 8, Threads: 4,
+	}})
+
+	labels := map[string]string{
+		"job": "bar/foo", "instance": "foo-1", "service_name": "foo", "service_namespace": "bar", "process_pid": "123",
+	}
+	with := func(name, value string) map[string]string {
+		l := map[string]string{name: value}
+		for k, v := range labels {
+			l[k] = v
+		}
+		return l
+	}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		metrics := gatherMetrics(ct, registry)
+		assert.InDelta(ct, 2.5, metrics.value("process_cpu_time_seconds_total", with("cpu_mode", "user")), 0.0001)
+		assert.InDelta(ct, 0.5, metrics.value("process_cpu_time_seconds_total", with("cpu_mode", "system")), 0.0001)
+		assert.InDelta(ct, 10, metrics.value("process_disk_io_bytes_total", with("disk_io_direction", "read")), 0.0001)
+		assert.InDelta(ct, 25, metrics.value("process_disk_io_bytes_total", with("disk_io_direction", "write")), 0.0001)
+		assert.InDelta(ct, 2000, metrics.value("process_memory_usage_bytes", labels), 0.0001)
+		assert.InDelta(ct, 8, metrics.value("process_open_file_descriptor_count", labels), 0.0001)
+		assert.InDelta(ct, 4, metrics.value("process_thread_count", labels), 0.0001)
+	}, timeout, 10*time.Millisecond)
+}
+
+type gatheredMetrics []*dto.MetricFamily
+
+func gatherMetrics(t require.TestingT, registry *prometheus.Registry) gatheredMetrics {
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	return families
+}
+
+// value returns the value of the counter or gauge with the given name and labels, or -1 if not found
+func (g gatheredMetrics) value(name string, labels map[string]string) float64 {
+	for _, family := range g {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, m := range family.Metric {
+			if len(m.Label) != len(labels) {
+				continue
+			}
+			for _, l := range m.Label {
+				if labels[l.GetName()] != l.GetValue() {
+					continue metrics
+				}
+			}
+			if m.Counter != nil {
+				return m.Counter.GetValue()
+			}
+			return m.Gauge.GetValue()
+		}
+	}
+	return -1
+}
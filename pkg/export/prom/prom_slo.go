@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prom // import "go.opentelemetry.io/obi/pkg/export/prom"
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/export/attributes"
+	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
+	"go.opentelemetry.io/obi/pkg/export/connector"
+	"go.opentelemetry.io/obi/pkg/export/expire"
+	"go.opentelemetry.io/obi/pkg/export/slo"
+	"go.opentelemetry.io/obi/pkg/pipe/global"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+var sloLabelNames = []string{
+	attr.Job.Prom(),
+	attr.Instance.Prom(),
+	attr.ServiceName.Prom(),
+	attr.ServiceNamespace.Prom(),
+	attr.SLOName.Prom(),
+}
+
+// SLOPrometheusConfig for SLO metrics just wraps the global PrometheusConfig as provided by the user
+type SLOPrometheusConfig struct {
+	Config *PrometheusConfig
+	SLO    *slo.Config
+}
+
+// Enabled returns whether the node needs to be activated
+func (p SLOPrometheusConfig) Enabled() bool {
+	return p.Config != nil && p.Config.EndpointEnabled() && p.SLO.Enabled()
+}
+
+type sloMetricsReporter struct {
+	cfg *PrometheusConfig
+
+	events     *Expirer[prometheus.Counter]
+	goodEvents *Expirer[prometheus.Counter]
+	apdex      *sloApdexCollector
+
+	tracker *slo.Tracker
+
+	promConnect *connector.PrometheusManager
+
+	clock *expire.CachedClock
+
+	input <-chan []request.Span
+}
+
+func SLOPrometheusEndpoint(
+	ctxInfo *global.ContextInfo,
+	cfg *SLOPrometheusConfig,
+	input *msg.Queue[[]request.Span],
+) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled() {
+			// This node is not going to be instantiated. Let the swarm library just ignore it.
+			return swarm.EmptyRunFunc()
+		}
+		reporter := newSLOReporter(ctxInfo, cfg, input)
+		if cfg.Config.Registry != nil {
+			return reporter.collectMetrics, nil
+		}
+		return reporter.reportMetrics, nil
+	}
+}
+
+func newSLOReporter(
+	ctxInfo *global.ContextInfo,
+	cfg *SLOPrometheusConfig,
+	input *msg.Queue[[]request.Span],
+) *sloMetricsReporter {
+	slog.With("component", "prom.SLOEndpoint").Debug("registering SLO metrics")
+	clock := expire.NewCachedClock(timeNow)
+	expirers := newExpirerFactory(cfg.Config, ctxInfo.Metrics, clock.Time)
+	mr := &sloMetricsReporter{
+		cfg:         cfg.Config,
+		promConnect: ctxInfo.Prometheus,
+		clock:       clock,
+		tracker:     slo.NewTracker(cfg.SLO, timeNow),
+		events: expirers.counter(prometheus.CounterOpts{
+			Name: attributes.SLOEvents.Prom,
+			Help: "Total number of requests evaluated by the SLO",
+		}, sloLabelNames),
+		goodEvents: expirers.counter(prometheus.CounterOpts{
+			Name: attributes.SLOGoodEvents.Prom,
+			Help: "Number of successful requests below the latency threshold of the SLO",
+		}, sloLabelNames),
+	}
+	mr.apdex = &sloApdexCollector{
+		tracker: mr.tracker,
+		gauge: expirers.gauge(prometheus.GaugeOpts{
+			Name: attributes.SLOApdex.Prom,
+			Help: "Apdex score of the SLO, from 0 (all users frustrated) to 1 (all users satisfied)",
+		}, sloLabelNames),
+		reported: map[string][]string{},
+	}
+
+	register := []prometheus.Collector{mr.events, mr.goodEvents, mr.apdex}
+	if cfg.Config.Registry != nil {
+		cfg.Config.Registry.MustRegister(register...)
+	} else {
+		mr.promConnect.Register(cfg.Config.Port, cfg.Config.Path, register...)
+	}
+
+	mr.input = input.Subscribe(msg.SubscriberName("prom.SLOReporterInput"))
+	return mr
+}
+
+func (r *sloMetricsReporter) reportMetrics(ctx context.Context) {
+	go r.promConnect.StartHTTP(ctx)
+	r.collectMetrics(ctx)
+}
+
+func (r *sloMetricsReporter) collectMetrics(_ context.Context) {
+	for spans := range r.input {
+		// clock needs to be updated to let the expirer
+		// remove the old metrics
+		r.clock.Update()
+		for i := range spans {
+			r.observe(&spans[i])
+		}
+	}
+}
+
+func (r *sloMetricsReporter) observe(span *request.Span) {
+	for _, ev := range r.tracker.Track(span) {
+		// the expirers keep the label values slices, so each invocation requires its own slice
+		r.events.WithLabelValues(sloLabelValues(&span.Service, ev.Definition)...).Metric.Inc()
+		if ev.Good {
+			r.goodEvents.WithLabelValues(sloLabelValues(&span.Service, ev.Definition)...).Metric.Inc()
+		}
+	}
+}
+
+// sloApdexCollector updates the Apdex scores from the SLO tracker on each collection, so
+// they are kept up to date for the services that stop receiving requests. The scores that
+// are forgotten by the tracker are removed.
+type sloApdexCollector struct {
+	tracker *slo.Tracker
+	gauge   *Expirer[prometheus.Gauge]
+
+	mt sync.Mutex
+	// label values of the scores reported in the previous collection
+	reported map[string][]string
+}
+
+func (c *sloApdexCollector) Describe(descs chan<- *prometheus.Desc) {
+	c.gauge.Describe(descs)
+}
+
+func (c *sloApdexCollector) Collect(metrics chan<- prometheus.Metric) {
+	c.mt.Lock()
+	defer c.mt.Unlock()
+	current := map[string][]string{}
+	for _, score := range c.tracker.Scores() {
+		lbls := sloLabelValues(score.Service, score.Definition)
+		current[strings.Join(lbls, ":")] = lbls
+		c.gauge.WithLabelValues(lbls...).Metric.Set(score.Apdex)
+	}
+	for key, lbls := range c.reported {
+		if _, ok := current[key]; !ok {
+			c.gauge.DeleteLabelValues(lbls...)
+		}
+	}
+	c.reported = current
+	c.gauge.Collect(metrics)
+}
+
+// sloLabelValues returns the values in the same order as sloLabelNames
+func sloLabelValues(service *svc.Attrs, def *slo.Definition) []string {
+	return []string{
+		service.Job(),
+		service.UID.Instance,
+		service.UID.Name,
+		service.UID.Namespace,
+		def.Name,
+	}
+}
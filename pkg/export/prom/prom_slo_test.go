@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
+	"go.opentelemetry.io/obi/pkg/export"
+	"go.opentelemetry.io/obi/pkg/export/slo"
+	"go.opentelemetry.io/obi/pkg/pipe/global"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+func TestSLOMetrics(t *testing.T) {
+	now := syncedClock{now: time.Now()}
+	timeNow = now.Now
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	cfg := &SLOPrometheusConfig{
+		Config: &PrometheusConfig{Registry: registry, TTL: time.Hour},
+		SLO: &slo.Config{ApdexWindow: time.Minute, Definitions: []slo.Definition{{
+			Name:             "checkout",
+			Route:            services.NewGlob("/checkout"),
+			LatencyThreshold: 100 * time.Millisecond,
+		}}},
+	}
+	require.True(t, cfg.Enabled())
+	run, err := SLOPrometheusEndpoint(&global.ContextInfo{}, cfg, input)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+
+	service := svc.Attrs{
+		UID:      svc.UID{Name: "foo", Namespace: "bar", Instance: "foo-1"},
+		Features: export.FeatureApplicationRED,
+	}
+	span := func(route string, status int, duration time.Duration) request.Span {
+		return request.Span{
+			Service: service, Type: request.EventTypeHTTP, Method: "GET", Route: route, Status: status,
+			RequestStart: 0, End: duration.Nanoseconds(),
+		}
+	}
+	ignored := span("/checkout", 200, time.Millisecond)
+	request.SetIgnoreMetrics(&ignored)
+	input.Send([]request.Span{
+		ignored,
+		span("/checkout", 200, 50*time.Millisecond),
+		span("/checkout", 200, 200*time.Millisecond),
+		span("/checkout", 500, 10*time.Millisecond),
+		span("/checkout", 201, 10*time.Millisecond),
+		span("/other", 500, time.Second),
+	})
+
+	labels := map[string]string{
+		"job": "bar/foo", "instance": "foo-1", "service_name": "foo", "service_namespace": "bar", "slo_name": "checkout",
+	}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		metrics := gatherMetrics(ct, registry)
+		assert.InDelta(ct, 4, metrics.value("obi_slo_events_total", labels), 0.0001)
+		assert.InDelta(ct, 2, metrics.value("obi_slo_good_events_total", labels), 0.0001)
+		// (2 satisfied + 1 tolerating / 2) / 4
+		assert.InDelta(ct, 0.625, metrics.value("obi_slo_apdex", labels), 0.0001)
+	}, timeout, 10*time.Millisecond)
+
+	// the Apdex score is removed once there are no requests during the current and previous windows
+	now.Advance(65 * time.Second)
+	assert.InDelta(t, 0.625, gatherMetrics(t, registry).value("obi_slo_apdex", labels), 0.0001)
+	now.Advance(time.Minute)
+	metrics := gatherMetrics(t, registry)
+	assert.Equal(t, -1.0, metrics.value("obi_slo_apdex", labels))
+	assert.InDelta(t, 4, metrics.value("obi_slo_events_total", labels), 0.0001)
+}
@@ -85,6 +85,7 @@ func newStatsReporter(
 	}
 
 	clock := expire.NewCachedClock(timeNow)
+	expirers := newExpirerFactory(cfg.Config, ctxInfo.Metrics, clock.Time)
 	// If service name is not explicitly set, we take the service name as set by the
 	// executable inspector
 	mr := &statMetricsReporter{
@@ -101,15 +102,13 @@ func newStatsReporter(
 			ebpf.StatStringGetters,
 			provider.For(attributes.StatTCPRtt))
 
-		mr.tcpRtt = NewExpirer[prometheus.Histogram](prometheus.NewHistogramVec(prometheus.HistogramOpts{
-			Name: attributes.StatTCPRtt.Prom,
-			Help: "measures the smoothed TCP RTT as calculated by the kernel in seconds",
+		mr.tcpRtt = expirers.histogram(
+			attributes.StatTCPRtt.Prom,
+			"measures the smoothed TCP RTT as calculated by the kernel in seconds",
 			// TODO define a default bucket for stat metrics when we have enough metrics to have something standard
-			Buckets:                         []float64{0.0005, 0.001, 0.002, 0.005, 0.010, 0.025, 0.050, 0.100, 0.250, 0.500, 1.0},
-			NativeHistogramBucketFactor:     defaultHistogramBucketFactor,
-			NativeHistogramMaxBucketNumber:  defaultHistogramMaxBucketNumber,
-			NativeHistogramMinResetDuration: defaultHistogramMinResetDuration,
-		}, labelNames(mr.statsAttrs)).MetricVec, clock.Time, cfg.Config.TTL)
+			[]float64{0.0005, 0.001, 0.002, 0.005, 0.010, 0.025, 0.050, 0.100, 0.250, 0.500, 1.0},
+			labelNames(mr.statsAttrs),
+		)
 		register = append(register, mr.tcpRtt)
 
 	}
@@ -34,6 +34,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/attributes"
 	attr "go.opentelemetry.io/obi/pkg/export/attributes/names"
 	"go.opentelemetry.io/obi/pkg/export/connector"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
 	"go.opentelemetry.io/obi/pkg/export/otel"
 	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
@@ -210,6 +211,7 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 				"rpc_server_duration_seconds",
 				"rpc_client_duration_seconds",
 				"db_client_operation_duration_seconds",
+				"db_server_operation_duration_seconds",
 				"messaging_client_operation_duration_seconds",
 				"messaging_process_duration_seconds",
 				"gpu_cuda_kernel_launch_calls_total",
@@ -259,6 +261,7 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 			expected: []string{
 				"db_client_operation_duration_seconds",
 				"db_client_operation_duration_seconds",
+				"db_server_operation_duration_seconds",
 			},
 			unexpected: []string{
 				"http_server_request_duration_seconds",
@@ -333,6 +336,7 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 			instr: []instrumentations.Instrumentation{instrumentations.InstrumentationSQL, instrumentations.InstrumentationRedis},
 			expected: []string{
 				"db_client_operation_duration_seconds",
+				"db_server_operation_duration_seconds",
 			},
 			unexpected: []string{
 				"http_server_request_duration_seconds",
@@ -422,6 +426,508 @@ func TestAppMetrics_ByInstrumentation(t *testing.T) {
 	}
 }
 
+func TestAppMetrics_ActiveRequests(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	exporter, err := PrometheusEndpoint(
+		&global.ContextInfo{},
+		&PrometheusConfig{
+			Registry:                    registry,
+			TTL:                         time.Hour,
+			SpanMetricsServiceCacheSize: 10,
+			Instrumentations:            []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		},
+		&perapp.MetricsConfig{Features: export.FeatureApplicationRED},
+		&attributes.SelectorConfig{},
+		request.UnresolvedNames{},
+		input,
+		msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10)),
+	)(t.Context())
+	require.NoError(t, err)
+	go exporter(t.Context())
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Instance: "foo"}}
+	span := func(tp request.EventType, start int64, inFlight bool) request.Span {
+		return request.Span{
+			Service: service, Type: tp, Method: "GET", Path: "/foo",
+			Pid: request.PidInfo{HostPID: 123}, Start: start, RequestStart: start, End: start + 10, InFlight: inFlight,
+		}
+	}
+	input.Send([]request.Span{
+		span(request.EventTypeHTTP, 10, true),
+		span(request.EventTypeHTTP, 20, true),
+		span(request.EventTypeHTTP, 30, true),
+		span(request.EventTypeHTTPClient, 40, true),
+	})
+	// requests reported again while they are in-flight are accounted once
+	input.Send([]request.Span{
+		span(request.EventTypeHTTP, 10, true),
+		span(request.EventTypeHTTPClient, 40, true),
+	})
+	// finished requests are discounted, unless they were never reported in-flight
+	input.Send([]request.Span{
+		span(request.EventTypeHTTP, 20, false),
+		span(request.EventTypeHTTP, 50, false),
+		span(request.EventTypeHTTPClient, 40, false),
+	})
+
+	activeRequests := func(ct *assert.CollectT, name string) float64 {
+		families, err := registry.Gather()
+		require.NoError(ct, err)
+		for _, family := range families {
+			if family.GetName() == name {
+				require.Len(ct, family.Metric, 1)
+				return family.Metric[0].Gauge.GetValue()
+			}
+		}
+		return -1
+	}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		assert.InDelta(ct, 2, activeRequests(ct, "http_server_active_requests"), 0.001)
+		assert.InDelta(ct, 0, activeRequests(ct, "http_client_active_requests"), 0.001)
+	}, timeout, 10*time.Millisecond)
+}
+
+func TestAppMetrics_Failures(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	exporter, err := PrometheusEndpoint(
+		&global.ContextInfo{},
+		&PrometheusConfig{
+			Registry:                    registry,
+			TTL:                         time.Hour,
+			SpanMetricsServiceCacheSize: 10,
+			Instrumentations:            []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		},
+		&perapp.MetricsConfig{Features: export.FeatureApplicationRED},
+		&attributes.SelectorConfig{},
+		request.UnresolvedNames{},
+		input,
+		msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10)),
+	)(t.Context())
+	require.NoError(t, err)
+	go exporter(t.Context())
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Name: "foo", Instance: "foo-1"}}
+	input.Send([]request.Span{
+		{Service: service, Type: request.EventTypeFailedConnect, Host: "10.0.0.1", HostName: "db", HostPort: 5432, Status: 111},
+		{Service: service, Type: request.EventTypeFailedConnect, Host: "10.0.0.1", HostName: "db", HostPort: 5432, Status: 111},
+		{Service: service, Type: request.EventTypeFailedConnect, Host: "10.0.0.2", HostPort: 80, Status: 110},
+		{Service: service, Type: request.EventTypeDNS, Path: "foo.example.com", Status: 3},
+		{Service: service, Type: request.EventTypeDNS, Path: "bar.example.com"},
+	})
+
+	// returns the value of the counter whose labels contain the provided ones
+	counter := func(ct *assert.CollectT, name string, labels map[string]string) float64 {
+		families, err := registry.Gather()
+		require.NoError(ct, err)
+		for _, family := range families {
+			if family.GetName() != name {
+				continue
+			}
+		metrics:
+			for _, m := range family.Metric {
+				values := map[string]string{}
+				for _, l := range m.Label {
+					values[l.GetName()] = l.GetValue()
+				}
+				for k, v := range labels {
+					if values[k] != v {
+						continue metrics
+					}
+				}
+				return m.Counter.GetValue()
+			}
+		}
+		return -1
+	}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		assert.InDelta(ct, 2, counter(ct, "obi_network_connection_failures_total", map[string]string{
+			"service_name": "foo", "server_address": "db", "server_port": "5432", "error_type": "ECONNREFUSED",
+		}), 0.001)
+		assert.InDelta(ct, 1, counter(ct, "obi_network_connection_failures_total", map[string]string{
+			"service_name": "foo", "server_address": "10.0.0.2", "server_port": "80", "error_type": "ETIMEDOUT",
+		}), 0.001)
+		assert.InDelta(ct, 1, counter(ct, "obi_dns_lookup_failures_total", map[string]string{
+			"service_name": "foo", "dns_question_name": "foo.example.com", "error_type": "NXDomain",
+		}), 0.001)
+		// successful lookups are not counted as failures
+		assert.InDelta(ct, -1, counter(ct, "obi_dns_lookup_failures_total", map[string]string{
+			"dns_question_name": "bar.example.com",
+		}), 0.001)
+	}, timeout, 10*time.Millisecond)
+}
+
+func TestAppMetrics_DBServer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	exporter, err := PrometheusEndpoint(
+		&global.ContextInfo{},
+		&PrometheusConfig{
+			Registry:                    registry,
+			TTL:                         time.Hour,
+			SpanMetricsServiceCacheSize: 10,
+			Instrumentations:            []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		},
+		&perapp.MetricsConfig{Features: export.FeatureApplicationRED},
+		&attributes.SelectorConfig{},
+		request.UnresolvedNames{},
+		input,
+		msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10)),
+	)(t.Context())
+	require.NoError(t, err)
+	go exporter(t.Context())
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Name: "foo", Instance: "foo-1"}}
+	input.Send([]request.Span{
+		{Service: service, Type: request.EventTypeSQLServer, Method: "SELECT", Path: "accounts", RequestStart: 100, End: 200},
+		{Service: service, Type: request.EventTypeRedisServer, Method: "GET", RequestStart: 100, End: 200},
+	})
+
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		families, err := registry.Gather()
+		require.NoError(ct, err)
+		labels := map[string][]map[string]string{}
+		for _, family := range families {
+			for _, m := range family.Metric {
+				values := map[string]string{}
+				for _, l := range m.Label {
+					values[l.GetName()] = l.GetValue()
+				}
+				labels[family.GetName()] = append(labels[family.GetName()], values)
+			}
+		}
+		// Redis server operations are still reported as client operations for backwards compatibility
+		require.Len(ct, labels["db_client_operation_duration_seconds"], 1)
+		assert.Equal(ct, "redis", labels["db_client_operation_duration_seconds"][0]["db_system_name"])
+		require.Len(ct, labels["db_server_operation_duration_seconds"], 2)
+		for _, l := range labels["db_server_operation_duration_seconds"] {
+			switch l["db_system_name"] {
+			case "redis":
+				assert.Equal(ct, "GET", l["db_operation_name"])
+			default:
+				assert.Equal(ct, "SELECT", l["db_operation_name"])
+				assert.Equal(ct, "accounts", l["db_collection_name"])
+			}
+		}
+	}, timeout, 10*time.Millisecond)
+}
+
+func TestAppMetrics_MessagingThroughput(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	exporter, err := PrometheusEndpoint(
+		&global.ContextInfo{},
+		&PrometheusConfig{
+			Registry:                    registry,
+			TTL:                         time.Hour,
+			SpanMetricsServiceCacheSize: 10,
+			Instrumentations:            []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+		},
+		&perapp.MetricsConfig{Features: export.FeatureApplicationRED},
+		&attributes.SelectorConfig{},
+		request.UnresolvedNames{},
+		input,
+		msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10)),
+	)(t.Context())
+	require.NoError(t, err)
+	go exporter(t.Context())
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Name: "foo", Instance: "foo-1"}}
+	input.Send([]request.Span{
+		{
+			Service: service, Type: request.EventTypeKafkaClient, Method: request.MessagingPublish, Path: "orders",
+			RequestStart: 100, End: 200, MessagingInfo: &request.MessagingInfo{Partition: 3, Messages: 10, Bytes: 1000},
+		},
+		{
+			Service: service, Type: request.EventTypeKafkaClient, Method: request.MessagingProcess, Path: "orders",
+			RequestStart: 100, End: 200, MessagingInfo: &request.MessagingInfo{Partition: 3, ConsumerGroup: "billing", Messages: 7, Bytes: 700},
+		},
+		{
+			Service: service, Type: request.EventTypeMQTTClient, Method: request.MessagingPublish, Path: "sensors",
+			RequestStart: 100, End: 200, MessagingInfo: &request.MessagingInfo{Partition: request.UnknownPartition, Messages: 1, Bytes: 20},
+		},
+	})
+
+	type series struct {
+		labels map[string]string
+		value  float64
+	}
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		families, err := registry.Gather()
+		require.NoError(ct, err)
+		got := map[string][]series{}
+		for _, family := range families {
+			for _, m := range family.Metric {
+				labels := map[string]string{}
+				for _, l := range m.Label {
+					labels[l.GetName()] = l.GetValue()
+				}
+				got[family.GetName()] = append(got[family.GetName()], series{labels: labels, value: m.Counter.GetValue()})
+			}
+		}
+		require.Len(ct, got["messaging_client_sent_messages_total"], 2)
+		for _, s := range got["messaging_client_sent_messages_total"] {
+			switch s.labels["messaging_destination_name"] {
+			case "orders":
+				assert.InDelta(ct, 10, s.value, 0.01)
+				assert.Equal(ct, "3", s.labels["messaging_destination_partition_id"])
+			case "sensors":
+				assert.InDelta(ct, 1, s.value, 0.01)
+				assert.Empty(ct, s.labels["messaging_destination_partition_id"])
+			default:
+				assert.Failf(ct, "unexpected destination", "%v", s.labels)
+			}
+		}
+		require.Len(ct, got["obi_messaging_client_sent_bytes_total"], 2)
+		require.Len(ct, got["messaging_client_consumed_messages_total"], 1)
+		consumed := got["messaging_client_consumed_messages_total"][0]
+		assert.InDelta(ct, 7, consumed.value, 0.01)
+		assert.Equal(ct, "billing", consumed.labels["messaging_consumer_group_name"])
+		require.Len(ct, got["obi_messaging_client_consumed_bytes_total"], 1)
+		assert.InDelta(ct, 700, got["obi_messaging_client_consumed_bytes_total"][0].value, 0.01)
+	}, timeout, 10*time.Millisecond)
+}
+
+func TestAppMetrics_HistogramType(t *testing.T) {
+	for _, tc := range []struct {
+		histogramType HistogramType
+		expectClassic bool
+		expectNative  bool
+	}{
+		{histogramType: "", expectClassic: true, expectNative: true},
+		{histogramType: HistogramTypeClassicAndNative, expectClassic: true, expectNative: true},
+		{histogramType: HistogramTypeNative, expectNative: true},
+		{histogramType: HistogramTypeClassic, expectClassic: true},
+	} {
+		t.Run(string(tc.histogramType), func(t *testing.T) {
+			registry := prometheus.NewRegistry()
+			input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+			exporter, err := PrometheusEndpoint(
+				&global.ContextInfo{},
+				&PrometheusConfig{
+					Registry:                    registry,
+					TTL:                         time.Hour,
+					SpanMetricsServiceCacheSize: 10,
+					Buckets:                     export.DefaultBuckets,
+					HistogramType:               tc.histogramType,
+					Instrumentations:            []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+				},
+				&perapp.MetricsConfig{Features: export.FeatureApplicationRED},
+				&attributes.SelectorConfig{},
+				request.UnresolvedNames{},
+				input,
+				msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10)),
+			)(t.Context())
+			require.NoError(t, err)
+			go exporter(t.Context())
+
+			input.Send([]request.Span{{
+				Service: svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Name: "foo", Instance: "foo-1"}},
+				Type:    request.EventTypeHTTP, Method: "GET", Route: "/foo", Status: 200,
+				RequestStart: 100, End: 5_000_100,
+			}})
+
+			require.EventuallyWithT(t, func(ct *assert.CollectT) {
+				families, err := registry.Gather()
+				require.NoError(ct, err)
+				var histogram *dto.Histogram
+				for _, family := range families {
+					if family.GetName() == attributes.HTTPServerDuration.Prom {
+						require.Len(ct, family.Metric, 1)
+						histogram = family.Metric[0].Histogram
+					}
+				}
+				require.NotNil(ct, histogram)
+				assert.EqualValues(ct, 1, histogram.GetSampleCount())
+				if tc.expectClassic {
+					assert.Len(ct, histogram.Bucket, len(export.DefaultBuckets.DurationHistogram))
+				} else {
+					assert.Empty(ct, histogram.Bucket)
+				}
+				if tc.expectNative {
+					assert.NotEmpty(ct, histogram.PositiveSpan)
+				} else {
+					assert.Empty(ct, histogram.PositiveSpan)
+				}
+			}, timeout, 10*time.Millisecond)
+		})
+	}
+}
+
+func TestHistogramOpts(t *testing.T) {
+	cfg := PrometheusConfig{}
+	opts := cfg.histogramOpts("foo", "bar", []float64{1, 2, 3})
+	assert.Equal(t, []float64{1, 2, 3}, opts.Buckets)
+	assert.InDelta(t, defaultHistogramBucketFactor, opts.NativeHistogramBucketFactor, 0.0001)
+	assert.Equal(t, defaultHistogramMaxBucketNumber, opts.NativeHistogramMaxBucketNumber)
+	assert.Equal(t, defaultHistogramMinResetDuration, opts.NativeHistogramMinResetDuration)
+
+	cfg = PrometheusConfig{HistogramType: HistogramTypeNative, NativeHistogramBucketFactor: 1.05, NativeHistogramMaxBuckets: 200}
+	opts = cfg.histogramOpts("foo", "bar", []float64{1, 2, 3})
+	assert.Empty(t, opts.Buckets)
+	assert.InDelta(t, 1.05, opts.NativeHistogramBucketFactor, 0.0001)
+	assert.Equal(t, uint32(200), opts.NativeHistogramMaxBucketNumber)
+
+	cfg = PrometheusConfig{HistogramType: HistogramTypeClassic, NativeHistogramBucketFactor: 1.05}
+	opts = cfg.histogramOpts("foo", "bar", []float64{1, 2, 3})
+	assert.Equal(t, []float64{1, 2, 3}, opts.Buckets)
+	assert.Zero(t, opts.NativeHistogramBucketFactor)
+	assert.Zero(t, opts.NativeHistogramMaxBucketNumber)
+}
+
+func TestAppMetrics_CardinalityLimit(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	overflows := &overflowsReporter{}
+	exporter, err := PrometheusEndpoint(
+		&global.ContextInfo{Metrics: overflows},
+		&PrometheusConfig{
+			Registry:                    registry,
+			TTL:                         time.Hour,
+			SpanMetricsServiceCacheSize: 10,
+			Instrumentations:            []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+			CardinalityLimits: export.CardinalityLimits{
+				Metrics: map[string]int{attributes.HTTPServerDuration.Prom: 3},
+			},
+		},
+		&perapp.MetricsConfig{Features: export.FeatureApplicationRED},
+		&attributes.SelectorConfig{
+			SelectionCfg: attributes.Selection{
+				attributes.HTTPServerDuration.Section: attributes.InclusionLists{Include: []string{"url.path"}},
+			},
+		},
+		request.UnresolvedNames{},
+		input,
+		msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10)),
+	)(t.Context())
+	require.NoError(t, err)
+	go exporter(t.Context())
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Name: "foo", Instance: "foo-1"}}
+	var spans []request.Span
+	// the overflows are reported once per rejected attribute set
+	for _, path := range []string{"/a", "/b", "/c", "/d", "/c", "/a"} {
+		spans = append(spans, request.Span{
+			Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: path, Status: 200,
+			RequestStart: 100, End: 200,
+		})
+	}
+	input.Send(spans)
+
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		families, err := registry.Gather()
+		require.NoError(ct, err)
+		series := map[string]uint64{}
+		for _, family := range families {
+			if family.GetName() != attributes.HTTPServerDuration.Prom {
+				continue
+			}
+			for _, m := range family.Metric {
+				labels := map[string]string{}
+				for _, l := range m.Label {
+					labels[l.GetName()] = l.GetValue()
+				}
+				if labels["otel_metric_overflow"] == "true" {
+					series["overflow"] = m.Histogram.GetSampleCount()
+				} else {
+					series[labels["url_path"]] = m.Histogram.GetSampleCount()
+				}
+			}
+		}
+		// the limit accounts for the overflow series
+		assert.Equal(ct, map[string]uint64{"/a": 2, "/b": 1, "overflow": 3}, series)
+	}, timeout, 10*time.Millisecond)
+	assert.Equal(t, 2, overflows.count("prometheus", attributes.HTTPServerDuration.Prom))
+}
+
+func TestAppMetrics_Views(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	exporter, err := PrometheusEndpoint(
+		&global.ContextInfo{},
+		&PrometheusConfig{
+			Registry:                    registry,
+			TTL:                         time.Hour,
+			SpanMetricsServiceCacheSize: 10,
+			Buckets:                     export.DefaultBuckets,
+			HistogramType:               HistogramTypeClassic,
+			Instrumentations:            []instrumentations.Instrumentation{instrumentations.InstrumentationALL},
+			Views: export.MetricViews{{
+				Instrument:    export.NewInstrumentPattern(attributes.HTTPServerDuration.Prom),
+				Rename:        "http_latency_seconds",
+				AttributeKeys: []string{"http_request_method"},
+				Buckets:       []float64{0.5, 1},
+			}, {
+				Instrument: export.NewInstrumentPattern("http_*_body_size_bytes"),
+				Drop:       true,
+			}},
+		},
+		&perapp.MetricsConfig{Features: export.FeatureApplicationRED},
+		&attributes.SelectorConfig{
+			SelectionCfg: attributes.Selection{
+				attributes.HTTPServerDuration.Section: attributes.InclusionLists{Include: []string{"url.path", "http.request.method"}},
+			},
+		},
+		request.UnresolvedNames{},
+		input,
+		msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10)),
+	)(t.Context())
+	require.NoError(t, err)
+	go exporter(t.Context())
+
+	service := svc.Attrs{Features: export.FeatureApplicationRED, UID: svc.UID{Name: "foo", Instance: "foo-1"}}
+	input.Send([]request.Span{{
+		Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: "/a", Status: 200,
+		RequestStart: 100, End: 200, ContentLength: 10,
+	}, {
+		Service: service, Type: request.EventTypeHTTP, Method: "GET", Path: "/b", Status: 200,
+		RequestStart: 100, End: 200, ContentLength: 10,
+	}})
+
+	require.EventuallyWithT(t, func(ct *assert.CollectT) {
+		families, err := registry.Gather()
+		require.NoError(ct, err)
+		names := map[string]*dto.MetricFamily{}
+		for _, family := range families {
+			names[family.GetName()] = family
+		}
+		assert.NotContains(ct, names, attributes.HTTPServerDuration.Prom)
+		assert.NotContains(ct, names, attributes.HTTPServerRequestSize.Prom)
+		assert.NotContains(ct, names, attributes.HTTPServerResponseSize.Prom)
+		// requests to different paths are aggregated into the same series
+		require.Contains(ct, names, "http_latency_seconds")
+		family := names["http_latency_seconds"]
+		require.Len(ct, family.Metric, 1)
+		require.Len(ct, family.Metric[0].Label, 1)
+		assert.Equal(ct, "http_request_method", family.Metric[0].Label[0].GetName())
+		assert.Equal(ct, "GET", family.Metric[0].Label[0].GetValue())
+		assert.EqualValues(ct, 2, family.Metric[0].Histogram.GetSampleCount())
+		assert.Len(ct, family.Metric[0].Histogram.Bucket, 2)
+	}, timeout, 10*time.Millisecond)
+}
+
+type overflowsReporter struct {
+	imetrics.NoopReporter
+	mt        sync.Mutex
+	overflows map[string]int
+}
+
+func (o *overflowsReporter) MetricCardinalityOverflow(exporter, metric string) {
+	o.mt.Lock()
+	defer o.mt.Unlock()
+	if o.overflows == nil {
+		o.overflows = map[string]int{}
+	}
+	o.overflows[exporter+":"+metric]++
+}
+
+func (o *overflowsReporter) count(exporter, metric string) int {
+	o.mt.Lock()
+	defer o.mt.Unlock()
+	return o.overflows[exporter+":"+metric]
+}
+
 func TestMetricsDiscarded(t *testing.T) {
 	mr := metricsReporter{
 		cfg: &PrometheusConfig{},
@@ -0,0 +1,114 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prom // import "go.opentelemetry.io/obi/pkg/export/prom"
+
+import (
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"go.opentelemetry.io/obi/pkg/export"
+	"go.opentelemetry.io/obi/pkg/export/imetrics"
+)
+
+// expirerFactory creates the Expirers of the Prometheus metrics, applying the
+// user-provided views and cardinality limits
+type expirerFactory struct {
+	cfg    *PrometheusConfig
+	limits *cardinalityLimits
+	clock  func() time.Time
+}
+
+func newExpirerFactory(cfg *PrometheusConfig, metrics imetrics.Reporter, clock func() time.Time) *expirerFactory {
+	return &expirerFactory{
+		cfg:    cfg,
+		limits: newCardinalityLimits(&cfg.CardinalityLimits, metrics),
+		clock:  clock,
+	}
+}
+
+func (f *expirerFactory) counter(opts prometheus.CounterOpts, labels []string) *Expirer[prometheus.Counter] {
+	view := f.cfg.Views.For(opts.Name)
+	name := opts.Name
+	opts.Name = viewName(view, name)
+	labels, keep := viewLabels(view, labels)
+	vec := prometheus.NewCounterVec(opts, f.limits.labelNames(name, labels))
+	ex := NewExpirer[prometheus.Counter](vec.MetricVec, f.clock, f.cfg.TTL).
+		WithCardinalityLimit(f.limits.For(name))
+	return withView(ex, view, keep)
+}
+
+func (f *expirerFactory) gauge(opts prometheus.GaugeOpts, labels []string) *Expirer[prometheus.Gauge] {
+	view := f.cfg.Views.For(opts.Name)
+	name := opts.Name
+	opts.Name = viewName(view, name)
+	labels, keep := viewLabels(view, labels)
+	vec := prometheus.NewGaugeVec(opts, f.limits.labelNames(name, labels))
+	ex := NewExpirer[prometheus.Gauge](vec.MetricVec, f.clock, f.cfg.TTL).
+		WithCardinalityLimit(f.limits.For(name))
+	return withView(ex, view, keep)
+}
+
+func (f *expirerFactory) histogram(name, help string, buckets []float64, labels []string) *Expirer[prometheus.Histogram] {
+	view := f.cfg.Views.For(name)
+	if view != nil && len(view.Buckets) > 0 {
+		buckets = view.Buckets
+	}
+	opts := f.cfg.histogramOpts(viewName(view, name), help, buckets)
+	if view != nil && view.ExponentialScale != nil && opts.NativeHistogramBucketFactor > 0 {
+		// the growth factor of the exponential histogram buckets is 2^(2^-scale)
+		opts.NativeHistogramBucketFactor = math.Pow(2, math.Pow(2, -float64(*view.ExponentialScale)))
+	}
+	labels, keep := viewLabels(view, labels)
+	vec := prometheus.NewHistogramVec(opts, f.limits.labelNames(name, labels))
+	ex := NewExpirer[prometheus.Histogram](vec.MetricVec, f.clock, f.cfg.TTL).
+		WithCardinalityLimit(f.limits.For(name))
+	return withView(ex, view, keep)
+}
+
+// viewName returns the name of the metric after applying the view, if any
+func viewName(view *export.MetricView, name string) string {
+	if view == nil || view.Rename == "" {
+		return name
+	}
+	if !view.IsRename() {
+		slog.Warn("ignoring the rename of a view with wildcards",
+			"component", "prom.Views", "instrument", view.Instrument, "rename", view.Rename)
+		return name
+	}
+	return view.Rename
+}
+
+// viewLabels returns the label names that are kept by the view, as well as
+// their indices in the original label names. If the view doesn't restrict the
+// labels, the original names and a nil indices slice are returned. Dropped
+// metrics don't keep any label.
+func viewLabels(view *export.MetricView, labels []string) ([]string, []int) {
+	switch {
+	case view == nil || (len(view.AttributeKeys) == 0 && !view.Drop):
+		return labels, nil
+	case view.Drop:
+		return nil, []int{}
+	}
+	kept := []string{}
+	keep := []int{}
+	for i, l := range labels {
+		if slices.Contains(view.AttributeKeys, l) {
+			kept = append(kept, l)
+			keep = append(keep, i)
+		}
+	}
+	return kept, keep
+}
+
+func withView[T prometheus.Metric](ex *Expirer[T], view *export.MetricView, keep []int) *Expirer[T] {
+	if view != nil && view.Drop {
+		return ex.dropAll()
+	}
+	ex.keep = keep
+	return ex
+}
@@ -0,0 +1,67 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package slo evaluates the HTTP server spans against the user-defined Service Level Objectives,
+// classifying them as good or bad events and calculating the Apdex score of each SLO.
+package slo // import "go.opentelemetry.io/obi/pkg/export/slo"
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
+)
+
+// Config for the SLO metrics. The environment variables are prefixed by OTEL_EBPF_SLO_
+// The SLO metrics are only reported for the services with the application metrics feature enabled.
+type Config struct {
+	// Definitions of the SLOs. The SLO metrics are only reported if at least one SLO is defined.
+	Definitions []Definition `yaml:"definitions" validate:"omitempty,dive"`
+
+	// ApdexWindow is the time window over which the Apdex score is calculated. The reported score
+	// accounts for the events of the current window and the previous window.
+	ApdexWindow time.Duration `yaml:"apdex_window" env:"APDEX_WINDOW" validate:"gte=0"`
+}
+
+// Enabled returns whether any SLO is defined
+func (c *Config) Enabled() bool {
+	return len(c.Definitions) > 0
+}
+
+// Definition of an SLO over the HTTP server requests of the matching services and routes.
+type Definition struct {
+	// Name of the SLO, reported as the slo.name metric attribute
+	Name string `yaml:"name" validate:"required"`
+	// Service glob to match the service name of the span. If unset, any service matches.
+	Service services.GlobAttr `yaml:"service"`
+	// Namespace glob to match the service namespace of the span. If unset, any namespace matches.
+	Namespace services.GlobAttr `yaml:"namespace"`
+	// Route glob to match the route of the span. If unset, any route matches.
+	Route services.GlobAttr `yaml:"route"`
+	// LatencyThreshold is the maximum duration of a good request. It is also the Apdex threshold T:
+	// requests below T are satisfied, requests below 4T are tolerating, and the rest are frustrated.
+	LatencyThreshold time.Duration `yaml:"latency_threshold" validate:"gt=0"`
+	// SuccessStatusClasses are the HTTP status classes (e.g. 2xx) of the successful requests.
+	// Unsuccessful requests are always bad events and frustrated in the Apdex score.
+	// If unset, 2xx and 3xx are considered successful.
+	SuccessStatusClasses []string `yaml:"success_status_classes" validate:"omitempty,dive,oneof=1xx 2xx 3xx 4xx 5xx"`
+}
+
+var defaultSuccessStatusClasses = []string{"2xx", "3xx"}
+
+func (d *Definition) matches(span *request.Span) bool {
+	return span.Type == request.EventTypeHTTP &&
+		(!d.Service.IsSet() || d.Service.MatchString(span.Service.UID.Name)) &&
+		(!d.Namespace.IsSet() || d.Namespace.MatchString(span.Service.UID.Namespace)) &&
+		(!d.Route.IsSet() || d.Route.MatchString(span.Route))
+}
+
+func (d *Definition) successful(span *request.Span) bool {
+	classes := d.SuccessStatusClasses
+	if len(classes) == 0 {
+		classes = defaultSuccessStatusClasses
+	}
+	return slices.Contains(classes, strconv.Itoa(span.Status/100)+"xx")
+}
@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+)
+
+const defaultApdexWindow = 5 * time.Minute
+
+// Event is the result of evaluating a span against a matching SLO
+type Event struct {
+	// Definition of the SLO
+	Definition *Definition
+	// Good is true if the request was successful and below the latency threshold
+	Good bool
+}
+
+// Score is the Apdex score of an SLO for a given service
+type Score struct {
+	Definition *Definition
+	Service    *svc.Attrs
+	Apdex      float64
+}
+
+type scoreKey struct {
+	definition int
+	service    svc.UID
+}
+
+// apdexCounts of a given time window
+type apdexCounts struct {
+	satisfied  int
+	tolerating int
+	total      int
+}
+
+// apdexScore keeps the Apdex counts of the current and previous windows
+type apdexScore struct {
+	service     svc.Attrs
+	windowStart time.Time
+	previous    apdexCounts
+	current     apdexCounts
+}
+
+// Tracker evaluates the spans against the SLO definitions and keeps the Apdex score of each
+// SLO and service. The scores are calculated when they are requested, so they are kept up to date
+// for the services that stop receiving requests. It is safe for concurrent use.
+type Tracker struct {
+	definitions []Definition
+	window      time.Duration
+	now         func() time.Time
+
+	mt        sync.Mutex
+	scores    map[scoreKey]*apdexScore
+	lastSweep time.Time
+}
+
+func NewTracker(cfg *Config, now func() time.Time) *Tracker {
+	window := cfg.ApdexWindow
+	if window == 0 {
+		window = defaultApdexWindow
+	}
+	return &Tracker{
+		definitions: cfg.Definitions,
+		window:      window,
+		now:         now,
+		scores:      map[scoreKey]*apdexScore{},
+		lastSweep:   now(),
+	}
+}
+
+// Track evaluates the span against all the SLO definitions, returning an Event for each
+// matching SLO. As the rest of the application metrics, the internal spans, the spans whose
+// metrics are ignored and the spans of the services that don't export application metrics
+// are not evaluated.
+func (t *Tracker) Track(span *request.Span) []Event {
+	if span.InternalSignal() || request.IgnoreMetrics(span) ||
+		!span.Service.ExportModes.CanExportMetrics() || !span.Service.Features.AppRED() {
+		return nil
+	}
+	t.mt.Lock()
+	defer t.mt.Unlock()
+	now := t.now()
+	t.sweep(now)
+	var events []Event
+	for i := range t.definitions {
+		def := &t.definitions[i]
+		if !def.matches(span) {
+			continue
+		}
+		good, satisfied, tolerating := def.classify(span)
+		score := t.scoreFor(scoreKey{definition: i, service: span.Service.UID}, &span.Service, now)
+		score.current.total++
+		if satisfied {
+			score.current.satisfied++
+		} else if tolerating {
+			score.current.tolerating++
+		}
+		events = append(events, Event{Definition: def, Good: good})
+	}
+	return events
+}
+
+// Scores returns the current Apdex score of each SLO and service. The scores of the services
+// that didn't receive any matching request during the current and previous windows are forgotten.
+func (t *Tracker) Scores() []Score {
+	t.mt.Lock()
+	defer t.mt.Unlock()
+	now := t.now()
+	scores := make([]Score, 0, len(t.scores))
+	for key, score := range t.scores {
+		if !score.roll(now, t.window) {
+			delete(t.scores, key)
+			continue
+		}
+		scores = append(scores, Score{
+			Definition: &t.definitions[key.definition],
+			Service:    &score.service,
+			Apdex:      score.value(),
+		})
+	}
+	return scores
+}
+
+// ServiceScores returns the current Apdex score of each SLO for the provided service
+func (t *Tracker) ServiceScores(service svc.UID) []Score {
+	t.mt.Lock()
+	defer t.mt.Unlock()
+	now := t.now()
+	var scores []Score
+	for i := range t.definitions {
+		key := scoreKey{definition: i, service: service}
+		score, ok := t.scores[key]
+		if !ok {
+			continue
+		}
+		if !score.roll(now, t.window) {
+			delete(t.scores, key)
+			continue
+		}
+		scores = append(scores, Score{
+			Definition: &t.definitions[i],
+			Service:    &score.service,
+			Apdex:      score.value(),
+		})
+	}
+	return scores
+}
+
+// classify the span as a good or bad event, and as a satisfied, tolerating or frustrated
+// request according to the Apdex definition
+func (d *Definition) classify(span *request.Span) (good, satisfied, tolerating bool) {
+	if !d.successful(span) {
+		return false, false, false
+	}
+	t := span.Timings()
+	duration := t.End.Sub(t.RequestStart)
+	satisfied = duration <= d.LatencyThreshold
+	tolerating = !satisfied && duration <= 4*d.LatencyThreshold
+	return satisfied, satisfied, tolerating
+}
+
+func (t *Tracker) scoreFor(key scoreKey, service *svc.Attrs, now time.Time) *apdexScore {
+	score, ok := t.scores[key]
+	if !ok || !score.roll(now, t.window) {
+		score = &apdexScore{service: *service, windowStart: now}
+		t.scores[key] = score
+	}
+	return score
+}
+
+// sweep forgets the scores that didn't receive any event during the last two windows
+func (t *Tracker) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < t.window {
+		return
+	}
+	t.lastSweep = now
+	for key, score := range t.scores {
+		if !score.roll(now, t.window) {
+			delete(t.scores, key)
+		}
+	}
+}
+
+// roll moves the current window to the previous window if it ended. It returns false
+// if there weren't events during the current and previous windows, so the score can be forgotten.
+func (s *apdexScore) roll(now time.Time, window time.Duration) bool {
+	if now.Sub(s.windowStart) >= 2*window {
+		return false
+	}
+	if now.Sub(s.windowStart) >= window {
+		s.previous, s.current = s.current, apdexCounts{}
+		s.windowStart = s.windowStart.Add(window)
+	}
+	return s.previous.total+s.current.total > 0
+}
+
+func (s *apdexScore) value() float64 {
+	total := s.previous.total + s.current.total
+	if total == 0 {
+		return 0
+	}
+	satisfied := s.previous.satisfied + s.current.satisfied
+	tolerating := s.previous.tolerating + s.current.tolerating
+	return (float64(satisfied) + float64(tolerating)/2) / float64(total)
+}
@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package slo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
+	"go.opentelemetry.io/obi/pkg/export"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func httpSpan(service, route string, status int, duration time.Duration) *request.Span {
+	return &request.Span{
+		Type: request.EventTypeHTTP,
+		Service: svc.Attrs{
+			UID:      svc.UID{Name: service, Namespace: "shop", Instance: service + "-1"},
+			Features: export.FeatureApplicationRED,
+		},
+		Route:        route,
+		Status:       status,
+		RequestStart: 0,
+		End:          duration.Nanoseconds(),
+	}
+}
+
+func track(tr *Tracker, span *request.Span) map[string]Event {
+	events := map[string]Event{}
+	for _, ev := range tr.Track(span) {
+		events[ev.Definition.Name] = ev
+	}
+	return events
+}
+
+// apdex returns the current score of the SLO for the service, or -1 if it is not tracked
+func apdex(tr *Tracker, service, slo string) float64 {
+	for _, score := range tr.ServiceScores(svc.UID{Name: service, Namespace: "shop", Instance: service + "-1"}) {
+		if score.Definition.Name == slo {
+			return score.Apdex
+		}
+	}
+	return -1
+}
+
+func TestTracker_Matching(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	tr := NewTracker(&Config{Definitions: []Definition{
+		{Name: "all", LatencyThreshold: time.Second},
+		{Name: "checkout-api", Service: services.NewGlob("checkout*"), Route: services.NewGlob("/api/*"), LatencyThreshold: time.Second},
+		{Name: "other-namespace", Namespace: services.NewGlob("other"), LatencyThreshold: time.Second},
+	}}, clock.Now)
+
+	assert.Equal(t, []string{"all", "checkout-api"}, names(track(tr, httpSpan("checkout", "/api/cart", 200, time.Millisecond))))
+	assert.Equal(t, []string{"all"}, names(track(tr, httpSpan("checkout", "/health", 200, time.Millisecond))))
+	assert.Equal(t, []string{"all"}, names(track(tr, httpSpan("payment", "/api/pay", 200, time.Millisecond))))
+
+	// only HTTP server spans are evaluated
+	clientSpan := httpSpan("checkout", "/api/cart", 200, time.Millisecond)
+	clientSpan.Type = request.EventTypeHTTPClient
+	assert.Empty(t, track(tr, clientSpan))
+}
+
+func TestTracker_Filters(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	tr := NewTracker(&Config{Definitions: []Definition{{Name: "all", LatencyThreshold: time.Second}}}, clock.Now)
+
+	ignored := httpSpan("svc", "/", 200, time.Millisecond)
+	request.SetIgnoreMetrics(ignored)
+	assert.Empty(t, track(tr, ignored))
+
+	noAppMetrics := httpSpan("svc", "/", 200, time.Millisecond)
+	noAppMetrics.Service.Features = export.FeatureNetwork
+	assert.Empty(t, track(tr, noAppMetrics))
+
+	noMetricsExport := httpSpan("svc", "/", 200, time.Millisecond)
+	noMetricsExport.Service.ExportModes = services.NewExportModes()
+	noMetricsExport.Service.ExportModes.AllowTraces()
+	assert.Empty(t, track(tr, noMetricsExport))
+
+	assert.Empty(t, tr.Scores())
+}
+
+func TestTracker_GoodEventsAndApdex(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	tr := NewTracker(&Config{Definitions: []Definition{
+		{Name: "default", LatencyThreshold: 100 * time.Millisecond},
+		{Name: "4xx-ok", LatencyThreshold: 100 * time.Millisecond, SuccessStatusClasses: []string{"2xx", "4xx"}},
+	}}, clock.Now)
+
+	// satisfied
+	evs := track(tr, httpSpan("svc", "/", 200, 50*time.Millisecond))
+	assert.Equal(t, Event{Definition: evs["default"].Definition, Good: true}, evs["default"])
+	assert.InDelta(t, 1, apdex(tr, "svc", "default"), 0.0001)
+	// tolerating: (1 + 0.5) / 2
+	evs = track(tr, httpSpan("svc", "/", 302, 300*time.Millisecond))
+	assert.False(t, evs["default"].Good)
+	assert.InDelta(t, 0.75, apdex(tr, "svc", "default"), 0.0001)
+	// frustrated because of the latency: (1 + 0.5) / 3
+	evs = track(tr, httpSpan("svc", "/", 200, time.Second))
+	assert.False(t, evs["default"].Good)
+	assert.InDelta(t, 0.5, apdex(tr, "svc", "default"), 0.0001)
+	// frustrated because of the status code, except for the SLO that accepts 4xx: (1 + 0.5) / 4
+	evs = track(tr, httpSpan("svc", "/", 404, time.Millisecond))
+	assert.False(t, evs["default"].Good)
+	assert.InDelta(t, 0.375, apdex(tr, "svc", "default"), 0.0001)
+	assert.True(t, evs["4xx-ok"].Good)
+	// 3xx are not successful for the 4xx-ok SLO: (1 + 1) / 4
+	assert.InDelta(t, 0.5, apdex(tr, "svc", "4xx-ok"), 0.0001)
+
+	// the score is tracked separately for each service
+	evs = track(tr, httpSpan("other", "/", 500, time.Millisecond))
+	assert.False(t, evs["default"].Good)
+	assert.Zero(t, apdex(tr, "other", "default"))
+	assert.Len(t, tr.Scores(), 4)
+}
+
+func TestTracker_ApdexWindow(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	tr := NewTracker(&Config{
+		ApdexWindow: time.Minute,
+		Definitions: []Definition{{Name: "slo", LatencyThreshold: time.Second}},
+	}, clock.Now)
+
+	track(tr, httpSpan("svc", "/", 500, time.Millisecond))
+	clock.now = clock.now.Add(30 * time.Second)
+	track(tr, httpSpan("svc", "/", 200, time.Millisecond))
+	assert.InDelta(t, 0.5, apdex(tr, "svc", "slo"), 0.0001)
+
+	// the previous window is still accounted
+	clock.now = clock.now.Add(40 * time.Second)
+	track(tr, httpSpan("svc", "/", 200, time.Millisecond))
+	assert.InDelta(t, 2.0/3, apdex(tr, "svc", "slo"), 0.0001)
+
+	// the first window is discarded
+	clock.now = clock.now.Add(60 * time.Second)
+	track(tr, httpSpan("svc", "/", 200, time.Millisecond))
+	assert.InDelta(t, 1, apdex(tr, "svc", "slo"), 0.0001)
+
+	// after two windows without events, the score is reset
+	clock.now = clock.now.Add(3 * time.Minute)
+	track(tr, httpSpan("svc", "/", 500, time.Millisecond))
+	assert.Zero(t, apdex(tr, "svc", "slo"))
+	assert.Len(t, tr.scores, 1)
+}
+
+func TestTracker_IdleService(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	tr := NewTracker(&Config{
+		ApdexWindow: time.Minute,
+		Definitions: []Definition{{Name: "slo", LatencyThreshold: time.Second}},
+	}, clock.Now)
+
+	track(tr, httpSpan("svc", "/", 500, time.Millisecond))
+	clock.now = clock.now.Add(70 * time.Second)
+	track(tr, httpSpan("svc", "/", 200, time.Millisecond))
+	assert.InDelta(t, 0.5, apdex(tr, "svc", "slo"), 0.0001)
+
+	// the score is updated without receiving new requests, discarding the first window
+	clock.now = clock.now.Add(55 * time.Second)
+	scores := tr.Scores()
+	require.Len(t, scores, 1)
+	assert.Equal(t, "svc", scores[0].Service.UID.Name)
+	assert.Equal(t, "slo", scores[0].Definition.Name)
+	assert.InDelta(t, 1, scores[0].Apdex, 0.0001)
+
+	// the score is forgotten after a whole window without requests
+	clock.now = clock.now.Add(time.Minute)
+	assert.Empty(t, tr.Scores())
+	assert.Equal(t, -1.0, apdex(tr, "svc", "slo"))
+}
+
+func names(events map[string]Event) []string {
+	var n []string
+	for _, def := range []string{"all", "checkout-api", "other-namespace"} {
+		if _, ok := events[def]; ok {
+			n = append(n, def)
+		}
+	}
+	return n
+}
@@ -0,0 +1,97 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package export // import "go.opentelemetry.io/obi/pkg/export"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/invopop/jsonschema"
+)
+
+// MetricView overrides how the metrics matching its instrument name are reported.
+// Only the non-zero fields are applied, on top of the default metric definition.
+type MetricView struct {
+	// Instrument is the name of the matched metrics, as reported by the exporter (e.g.
+	// http.server.request.duration for OTEL or http_server_request_duration_seconds for Prometheus).
+	// It accepts the * and ? wildcards.
+	Instrument InstrumentPattern `yaml:"instrument" validate:"required"`
+	// Rename the metric. It can't be used along with wildcards in the Instrument name.
+	Rename string `yaml:"rename"`
+	// AttributeKeys restricts the attributes of the metric to the provided keys (label names in Prometheus).
+	// The rest of attributes are removed and their measurements aggregated. If unset, all the attributes are kept.
+	AttributeKeys []string `yaml:"attribute_keys"`
+	// Buckets overrides the explicit bucket boundaries of a histogram
+	Buckets []float64 `yaml:"buckets"`
+	// ExponentialScale reports a histogram as an exponential histogram with the given maximum scale,
+	// between -10 and 20. In Prometheus, it sets the bucket factor of the native histograms, if enabled.
+	ExponentialScale *int32 `yaml:"exponential_scale" validate:"omitempty,gte=-10,lte=20"`
+	// Drop the metric, so it is not reported
+	Drop bool `yaml:"drop"`
+}
+
+// MetricViews are applied in order, so only the first view matching a given instrument is used.
+type MetricViews []MetricView
+
+// Matches returns whether the provided metric name matches the Instrument name pattern
+func (v *MetricView) Matches(name string) bool {
+	return v.Instrument.glob != nil && v.Instrument.glob.Match(name)
+}
+
+// IsRename returns whether the view renames a single instrument
+func (v *MetricView) IsRename() bool {
+	return v.Rename != "" && !strings.ContainsAny(v.Instrument.str, "*?")
+}
+
+// InstrumentPattern is the instrument name pattern of a MetricView. It is compiled when the
+// configuration is loaded, so it is not compiled again each time a metric name is matched.
+type InstrumentPattern struct {
+	str  string
+	glob glob.Glob
+}
+
+func NewInstrumentPattern(pattern string) InstrumentPattern {
+	return InstrumentPattern{str: pattern, glob: glob.MustCompile(pattern)}
+}
+
+func (InstrumentPattern) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type:        "string",
+		Description: "Instrument name, accepting the * and ? wildcards",
+		Examples:    []any{"http.server.request.duration", "http.*.body.size"},
+	}
+}
+
+func (p *InstrumentPattern) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*p = InstrumentPattern{}
+		return nil
+	}
+	g, err := glob.Compile(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid instrument pattern %q: %w", string(text), err)
+	}
+	p.str = string(text)
+	p.glob = g
+	return nil
+}
+
+func (p InstrumentPattern) MarshalText() ([]byte, error) {
+	return []byte(p.str), nil
+}
+
+func (p InstrumentPattern) String() string {
+	return p.str
+}
+
+// For returns the first view matching the provided metric name, or nil if none matches
+func (vs MetricViews) For(name string) *MetricView {
+	for i := range vs {
+		if vs[i].Matches(name) {
+			return &vs[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package export
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMetricViews(t *testing.T) {
+	views := MetricViews{
+		{Instrument: NewInstrumentPattern("http.server.request.duration"), Rename: "http.latency"},
+		{Instrument: NewInstrumentPattern("http.*.body.size"), Rename: "ignored", Drop: true},
+		{Instrument: NewInstrumentPattern("http.*")},
+	}
+
+	v := views.For("http.server.request.duration")
+	assert.Same(t, &views[0], v)
+	assert.True(t, v.IsRename())
+
+	v = views.For("http.client.request.body.size")
+	assert.Same(t, &views[1], v)
+	assert.False(t, v.IsRename())
+
+	assert.Same(t, &views[2], views.For("http.client.request.duration"))
+	assert.Nil(t, views.For("rpc.server.duration"))
+	assert.Nil(t, MetricViews(nil).For("http.server.request.duration"))
+}
+
+func TestMetricViews_Unmarshal(t *testing.T) {
+	var views MetricViews
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- instrument: "http.*.body.size"
+  drop: true
+- instrument: http.server.request.duration
+  rename: http.latency
+`), &views))
+	require.Len(t, views, 2)
+	assert.Same(t, &views[0], views.For("http.server.request.body.size"))
+	assert.Same(t, &views[1], views.For("http.server.request.duration"))
+	assert.True(t, views[1].IsRename())
+	assert.Nil(t, views.For("http.server.request.duration.other"))
+
+	require.Error(t, yaml.Unmarshal([]byte(`[{instrument: "http.[server"}]`), &views))
+}
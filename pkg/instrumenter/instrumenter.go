@@ -208,6 +208,7 @@ func BuildCommonContextInfo(
 	if err != nil {
 		return nil, fmt.Errorf("can't create internal metrics: %w", err)
 	}
+	ctxInfo.OTELMetricsExporter.InternalMetrics = ctxInfo.Metrics
 
 	ctxInfo.K8sInformer = kube.NewMetadataProvider(kube.MetadataConfig{
 		Enable:                   config.Attributes.Kubernetes.Enable,
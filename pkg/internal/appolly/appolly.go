@@ -9,6 +9,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"path"
 	"sync"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/ebpf"
 	ebpfcommon "go.opentelemetry.io/obi/pkg/ebpf/common"
 	msg2 "go.opentelemetry.io/obi/pkg/internal/helpers/msg"
+	"go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
 	"go.opentelemetry.io/obi/pkg/obi"
 	"go.opentelemetry.io/obi/pkg/pipe/global"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
@@ -242,6 +245,77 @@ func (i *Instrumenter) handleAndDispatchProcessEvent(pe exec.ProcessEvent) {
 func setupFeatureContextInfo(ctx context.Context, ctxInfo *global.ContextInfo, config *obi.Config) {
 	ctxInfo.AppO11y.ReportRoutes = config.Routes != nil
 	setupKubernetes(ctx, ctxInfo)
+	setupRouteTemplates(ctx, ctxInfo, config)
+}
+
+const (
+	defaultRouteTemplatesFlushInterval = time.Minute
+	routeTemplatesHTTPPath             = "/route-templates"
+)
+
+// setupRouteTemplates creates the store of the route templates learned by the low-cardinality
+// unmatch mode, when they need to be persisted or inspected through HTTP
+func setupRouteTemplates(ctx context.Context, ctxInfo *global.ContextInfo, config *obi.Config) {
+	rc := config.Routes
+	if rc == nil || rc.Unmatch != transform.UnmatchLowCardinality ||
+		(!rc.Persistence.Enabled() && rc.Persistence.Port == 0) {
+		return
+	}
+	log := slog.With("component", "obi.RouteTemplates")
+
+	var storage clusterurl.Storage
+	switch {
+	case rc.Persistence.File != "":
+		storage = &clusterurl.FileStorage{Path: rc.Persistence.File}
+	case rc.Persistence.BPFFS:
+		bpfStorage, err := clusterurl.NewBPFMapStorage(path.Join(config.EBPF.BPFFSPath, "otel"))
+		if err != nil {
+			log.Warn("can't store the route templates in the BPF filesystem. They won't be persisted",
+				"bpffs_path", config.EBPF.BPFFSPath, "error", err)
+		} else {
+			storage = bpfStorage
+		}
+	}
+
+	wildcard := byte('*')
+	if rc.WildcardChar != "" {
+		wildcard = rc.WildcardChar[0]
+	}
+	store := clusterurl.NewTrieStore(storage, rc.MaxPathSegmentCardinality, wildcard)
+	if err := store.Load(); err != nil {
+		log.Warn("can't load the stored route templates. They will be learned again", "error", err)
+	}
+	ctxInfo.AppO11y.RouteTemplates = store
+
+	if storage != nil {
+		interval := rc.Persistence.FlushInterval
+		if interval == 0 {
+			interval = defaultRouteTemplatesFlushInterval
+		}
+		go store.FlushPeriodically(ctx, interval)
+	}
+	if rc.Persistence.Port != 0 {
+		serveRouteTemplates(ctx, log, rc.Persistence.EndpointAddr(), store)
+	}
+}
+
+func serveRouteTemplates(ctx context.Context, log *slog.Logger, addr string, store *clusterurl.TrieStore) {
+	mux := http.NewServeMux()
+	mux.Handle(routeTemplatesHTTPPath, store)
+	server := http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	log = log.With("address", addr, "path", routeTemplatesHTTPPath)
+	log.Info("opening route templates endpoint")
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("route templates endpoint ended unexpectedly", "error", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		if err := server.Close(); err != nil {
+			log.Warn("error closing route templates endpoint", "error", err)
+		}
+	}()
 }
 
 // setupKubernetes sets up common Kubernetes database and API clients that need to be accessed
@@ -571,12 +571,16 @@ func kernelTime(ktime uint64) time.Time {
 
 //nolint:cyclop
 func (p *Tracer) lookForTimeouts(ctx context.Context, parseCtx *ebpfcommon.EBPFParseContext, ticker *time.Ticker, eventsChan *msg.Queue[[]request.Span]) {
+	// the in-flight requests are only required by the active requests metrics
+	reportInFlight := p.cfg.Metrics.Features.AppRED() &&
+		(p.cfg.OTELMetrics.EndpointEnabled() || p.cfg.Prometheus.EndpointEnabled())
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case t := <-ticker.C:
 			if p.bpfObjects.OngoingHttp != nil {
+				var inFlight []request.Span
 				i := p.bpfObjects.OngoingHttp.Iterate()
 				var k BpfPidConnectionInfoT
 				var v BpfHttpInfoT
@@ -612,8 +616,14 @@ func (p *Tracer) lookForTimeouts(ctx context.Context, parseCtx *ebpfcommon.EBPFP
 						if err := p.bpfObjects.OngoingHttp.Delete(k); err != nil {
 							p.log.Debug("Error deleting ongoing request", "error", err)
 						}
+					} else if v.EndMonotimeNs == 0 && v.StartMonotimeNs != 0 && reportInFlight {
+						// the requests that are still running are reported to account the active requests
+						inFlight = append(inFlight, ebpfcommon.HTTPInfoInFlightToSpan((*ebpfcommon.BPFHTTPInfo)(unsafe.Pointer(&v))))
 					}
 				}
+				if len(inFlight) > 0 {
+					eventsChan.SendCtx(ctx, p.pidsFilter.Filter(inFlight))
+				}
 			}
 		}
 	}
@@ -30,9 +30,10 @@ const (
 type KafkaAPIKey int8
 
 const (
-	APIKeyProduce  KafkaAPIKey = 0
-	APIKeyFetch    KafkaAPIKey = 1
-	APIKeyMetadata KafkaAPIKey = 3
+	APIKeyProduce      KafkaAPIKey = 0
+	APIKeyFetch        KafkaAPIKey = 1
+	APIKeyMetadata     KafkaAPIKey = 3
+	APIKeyOffsetCommit KafkaAPIKey = 8
 )
 
 type UUID [UUIDLen]byte
@@ -255,6 +256,10 @@ func (h KafkaRequestHeader) validate() error {
 		if h.APIVersion() < 10 || h.APIVersion() > 13 { // latest: Metadata Request (Version: 13), only versions 10-13 contain topic_id which we are interested in
 			return errors.New("invalid Kafka request header: unsupported API key version for Metadata")
 		}
+	case APIKeyOffsetCommit:
+		if h.APIVersion() > 9 { // latest: OffsetCommit Request (Version: 9)
+			return errors.New("invalid Kafka request header: unsupported API key version for OffsetCommit")
+		}
 	default:
 		return errors.New("invalid Kafka request header: unsupported API key")
 	}
@@ -296,6 +301,9 @@ func isFlexible(header KafkaRequestHeader) bool {
 	// https://github.com/apache/kafka/blob/9983331d917fe8f57c37c88f0749b757e5af0c87/clients/src/main/resources/common/message/MetadataRequest.json#L22
 	case APIKeyMetadata:
 		return ver >= 9
+	// https://github.com/apache/kafka/blob/9983331d917fe8f57c37c88f0749b757e5af0c87/clients/src/main/resources/common/message/OffsetCommitRequest.json
+	case APIKeyOffsetCommit:
+		return ver >= 8
 	default:
 		return false
 	}
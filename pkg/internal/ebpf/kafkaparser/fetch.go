@@ -268,3 +268,169 @@ func skipFetchPartitions(r *largebuf.LargeBufferReader, header KafkaRequestHeade
 	}
 	return r.Skip(fetchPartitionLen * partitionCount)
 }
+
+type FetchResponsePartition struct {
+	Partition int
+	Records   *RecordsInfo
+}
+
+type FetchResponseTopic struct {
+	Name       string
+	UUID       *UUID
+	Partitions []*FetchResponsePartition
+}
+
+type FetchResponse struct {
+	Topics []*FetchResponseTopic
+}
+
+// ParseFetchResponse parses the topics and partitions of a fetch response, accounting
+// the fetched records. As long as the response topics and partitions can be read,
+// they are returned, even if the buffer is truncated.
+func ParseFetchResponse(r *largebuf.LargeBufferReader, header KafkaRequestHeader) (*FetchResponse, error) {
+	/*
+		Fetch Response (Version: 7-17) => throttle_time_ms error_code session_id [responses] _tagged_fields
+		  throttle_time_ms => INT32
+		  error_code => INT16
+		  session_id => INT32
+	*/
+	skipLen := 0
+	if header.APIVersion() >= 1 {
+		skipLen += Int32Len // throttle_time_ms
+	}
+	if header.APIVersion() >= 7 {
+		skipLen += Int16Len + // error_code
+			Int32Len // session_id
+	}
+	if err := r.Skip(skipLen); err != nil {
+		return nil, err
+	}
+	topicsLen, err := readArrayLength(r, header)
+	if err != nil {
+		return nil, err
+	}
+	var topics []*FetchResponseTopic
+	for range topicsLen {
+		topic, err := parseFetchResponseTopic(r, header)
+		if topic != nil {
+			topics = append(topics, topic)
+		}
+		if err != nil {
+			// return the Topics parsed so far, even if one topic failed
+			break
+		}
+	}
+	if len(topics) == 0 {
+		return nil, errors.New("no Topics found in fetch response")
+	}
+	return &FetchResponse{Topics: topics}, nil
+}
+
+func parseFetchResponseTopic(r *largebuf.LargeBufferReader, header KafkaRequestHeader) (*FetchResponseTopic, error) {
+	/*
+	  responses => topic / topic_id [partitions] _tagged_fields
+	    topic => STRING / COMPACT_STRING (up to version 12)
+	    topic_id => UUID (version 13+)
+	*/
+	var topic FetchResponseTopic
+	if header.APIVersion() >= 13 {
+		topicUUID, err := readUUID(r)
+		if err != nil {
+			return nil, err
+		}
+		topic.UUID = topicUUID
+	} else {
+		topicName, err := readString(r, header, false)
+		if err != nil {
+			return nil, err
+		}
+		topic.Name = topicName
+	}
+	partitionCount, err := readArrayLength(r, header)
+	if err != nil {
+		return &topic, err
+	}
+	for range partitionCount {
+		partition, err := parseFetchResponsePartition(r, header)
+		if partition != nil {
+			topic.Partitions = append(topic.Partitions, partition)
+		}
+		if err != nil {
+			return &topic, err
+		}
+	}
+	return &topic, skipTaggedFields(r, header)
+}
+
+func parseFetchResponsePartition(r *largebuf.LargeBufferReader, header KafkaRequestHeader) (*FetchResponsePartition, error) {
+	/*
+	  partitions => partition_index error_code high_watermark last_stable_offset log_start_offset [aborted_transactions] preferred_read_replica records _tagged_fields
+	    partition_index => INT32
+	    error_code => INT16
+	    high_watermark => INT64
+	    last_stable_offset => INT64 (version 4+)
+	    log_start_offset => INT64 (version 5+)
+	    aborted_transactions => producer_id first_offset _tagged_fields (version 4+)
+	      producer_id => INT64
+	      first_offset => INT64
+	    preferred_read_replica => INT32 (version 11+)
+	    records => RECORDS / COMPACT_RECORDS
+	*/
+	partition, err := readInt32(r)
+	if err != nil {
+		return nil, err
+	}
+	skipLen := Int16Len + // error_code
+		Int64Len // high_watermark
+	if header.APIVersion() >= 4 {
+		skipLen += Int64Len // last_stable_offset
+	}
+	if header.APIVersion() >= 5 {
+		skipLen += Int64Len // log_start_offset
+	}
+	if err = r.Skip(skipLen); err != nil {
+		return nil, err
+	}
+	if header.APIVersion() >= 4 {
+		if err = skipAbortedTransactions(r, header); err != nil {
+			return nil, err
+		}
+	}
+	if header.APIVersion() >= 11 {
+		if err = r.Skip(Int32Len); err != nil { // preferred_read_replica
+			return nil, err
+		}
+	}
+	records, err := readRecords(r, header)
+	if err != nil {
+		return nil, err
+	}
+	p := &FetchResponsePartition{Partition: partition, Records: records}
+	return p, skipTaggedFields(r, header)
+}
+
+func skipAbortedTransactions(r *largebuf.LargeBufferReader, header KafkaRequestHeader) error {
+	var count int
+	if isFlexible(header) {
+		var err error
+		if count, err = readArrayLength(r, header); err != nil {
+			return err
+		}
+	} else {
+		// nullable array, where -1 means null
+		size, err := r.ReadI32BE()
+		if err != nil {
+			return err
+		}
+		count = max(int(size), 0)
+	}
+	for range count {
+		if err := r.Skip(Int64Len + Int64Len); err != nil { // producer_id + first_offset
+			return err
+		}
+		if err := skipTaggedFields(r, header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
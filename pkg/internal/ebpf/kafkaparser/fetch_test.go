@@ -462,3 +462,69 @@ func createValidFetchPacket(version int16) []byte {
 
 	return pkt[:offset]
 }
+
+func TestParseFetchResponse(t *testing.T) {
+	batch := func(records int32) []byte { return recordBatch(records, 10) }
+	be16 := func(b []byte, v int16) []byte { return binary.BigEndian.AppendUint16(b, uint16(v)) }
+	be32 := func(b []byte, v int32) []byte { return binary.BigEndian.AppendUint32(b, uint32(v)) }
+	be64 := func(b []byte, v int64) []byte { return binary.BigEndian.AppendUint64(b, uint64(v)) }
+	uvarint := func(b []byte, v int) []byte { return binary.AppendUvarint(b, uint64(v)) }
+
+	t.Run("version 4", func(t *testing.T) {
+		var pkt []byte
+		pkt = be32(pkt, 0)                       // throttle_time_ms
+		pkt = be32(pkt, 1)                       // responses
+		pkt = append(be16(pkt, 6), "orders"...)  // topic
+		pkt = be32(pkt, 2)                       // partitions
+		pkt = be32(pkt, 0)                       // partition_index
+		pkt = be64(be64(be16(pkt, 0), 100), 100) // error_code, high_watermark, last_stable_offset
+		pkt = be32(pkt, -1)                      // null aborted_transactions
+		pkt = append(be32(pkt, int32(len(batch(3)))), batch(3)...)
+		pkt = be32(pkt, 1)                       // partition_index
+		pkt = be64(be64(be16(pkt, 0), 100), 100) // error_code, high_watermark, last_stable_offset
+		pkt = be64(be64(be32(pkt, 1), 123), 456) // aborted_transactions
+		pkt = append(be32(pkt, int32(2*len(batch(2)))), append(batch(2), batch(4)...)...)
+
+		r := largebuf.NewLargeBufferFrom(pkt).NewReader()
+		resp, err := ParseFetchResponse(&r, newTestHeader(APIKeyFetch, 4))
+		require.NoError(t, err)
+		require.Len(t, resp.Topics, 1)
+		assert.Equal(t, "orders", resp.Topics[0].Name)
+		assert.Equal(t, []*FetchResponsePartition{
+			{Partition: 0, Records: &RecordsInfo{Bytes: len(batch(3)), Messages: 3}},
+			{Partition: 1, Records: &RecordsInfo{Bytes: 2 * len(batch(2)), Messages: 6}},
+		}, resp.Topics[0].Partitions)
+	})
+
+	t.Run("flexible version 13 with truncated buffer", func(t *testing.T) {
+		var pkt []byte
+		pkt = be32(pkt, 0)                            // throttle_time_ms
+		pkt = be32(be16(pkt, 0), 0)                   // error_code, session_id
+		pkt = uvarint(pkt, 2)                         // responses
+		pkt = append(pkt, make([]byte, UUIDLen)...)   // topic_id
+		pkt = uvarint(pkt, 3)                         // partitions
+		pkt = be32(pkt, 7)                            // partition_index
+		pkt = be64(be64(be64(be16(pkt, 0), 1), 1), 1) // error_code, high_watermark, last_stable_offset, log_start_offset
+		pkt = uvarint(pkt, 0)                         // null aborted_transactions
+		pkt = be32(pkt, -1)                           // preferred_read_replica
+		records := append(batch(5), batch(1)...)
+		pkt = append(uvarint(pkt, len(records)+1), records...)
+		pkt = append(pkt, 0) // tagged fields
+
+		// the second record batch and partition are not in the buffer
+		r := largebuf.NewLargeBufferFrom(pkt[:len(pkt)-recordBatchHeaderLen]).NewReader()
+		resp, err := ParseFetchResponse(&r, newTestHeader(APIKeyFetch, 13))
+		require.NoError(t, err)
+		require.Len(t, resp.Topics, 1)
+		assert.NotNil(t, resp.Topics[0].UUID)
+		assert.Equal(t, []*FetchResponsePartition{
+			{Partition: 7, Records: &RecordsInfo{Bytes: len(records), Messages: 5}},
+		}, resp.Topics[0].Partitions)
+	})
+
+	t.Run("no topics", func(t *testing.T) {
+		r := largebuf.NewLargeBufferFrom(be32(be32(nil, 0), 0)).NewReader()
+		_, err := ParseFetchResponse(&r, newTestHeader(APIKeyFetch, 4))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,26 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaparser // import "go.opentelemetry.io/obi/pkg/internal/ebpf/kafkaparser"
+
+import (
+	"go.opentelemetry.io/obi/pkg/internal/largebuf"
+)
+
+type OffsetCommitRequest struct {
+	GroupID string
+}
+
+// ParseOffsetCommitRequest only reads the consumer group of the request, as it is
+// the only field we are interested in.
+func ParseOffsetCommitRequest(r *largebuf.LargeBufferReader, header KafkaRequestHeader) (*OffsetCommitRequest, error) {
+	/*
+		OffsetCommit Request (Version: 0-9) => group_id generation_id_or_member_epoch member_id ... [topics] _tagged_fields
+		  group_id => STRING / COMPACT_STRING
+	*/
+	groupID, err := readString(r, header, false)
+	if err != nil {
+		return nil, err
+	}
+	return &OffsetCommitRequest{GroupID: groupID}, nil
+}
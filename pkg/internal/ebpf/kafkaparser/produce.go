@@ -12,6 +12,7 @@ import (
 type ProduceTopic struct {
 	Name      string
 	Partition *int
+	Records   *RecordsInfo
 }
 
 type ProduceRequest struct {
@@ -59,21 +60,22 @@ func parseProduceTopics(r *largebuf.LargeBufferReader, header KafkaRequestHeader
 		return nil, err
 	}
 	var topics []*ProduceTopic
-	if topicsLen <= 0 {
-		return topics, nil
-	}
-	// read single topic for now, because skipping records is complicated
-	topic, err := parseProduceTopic(r, header)
-	if err != nil {
-		// return the Topics parsed so far, even if one topic failed
-		return topics, nil
-	}
-	if topic != nil {
-		topics = append(topics, topic)
+	for range topicsLen {
+		topic, err := parseProduceTopic(r, header)
+		if topic != nil {
+			topics = append(topics, topic)
+		}
+		if err != nil {
+			// return the Topics parsed so far, even if one topic failed
+			break
+		}
 	}
 	return topics, nil
 }
 
+// parseProduceTopic returns the topic and the records of all its partitions. As long as
+// the topic name can be read, the topic is returned, even if the buffer is truncated.
+// The returned error means that the reader isn't placed after the topic data.
 func parseProduceTopic(r *largebuf.LargeBufferReader, header KafkaRequestHeader) (*ProduceTopic, error) {
 	var topic ProduceTopic
 	/*
@@ -88,17 +90,35 @@ func parseProduceTopic(r *largebuf.LargeBufferReader, header KafkaRequestHeader)
 	partitionsLen, err := readArrayLength(r, header)
 	if err != nil {
 		// return the topic even if partitions can't be read
-		return &topic, nil
+		return &topic, err
 	}
-	if partitionsLen != 1 {
+	for i := range partitionsLen {
+		/*
+		  partition_data => index records _tagged_fields
+		    index => INT32
+		    records => RECORDS / COMPACT_RECORDS
+		*/
+		partition, err := readInt32(r)
+		if err != nil {
+			return &topic, err
+		}
 		// if more than 1 Partition, we just won't report Partition
-		return &topic, nil
-	}
-	// read single Partition for now, because skipping records is complicated
-	firstPartition, err := readInt32(r)
-	if err != nil {
-		return &topic, nil
+		if i == 0 && partitionsLen == 1 {
+			topic.Partition = &partition
+		}
+		records, err := readRecords(r, header)
+		if records != nil {
+			if topic.Records == nil {
+				topic.Records = &RecordsInfo{}
+			}
+			topic.Records.Add(records)
+		}
+		if err != nil {
+			return &topic, err
+		}
+		if err := skipTaggedFields(r, header); err != nil {
+			return &topic, err
+		}
 	}
-	topic.Partition = &firstPartition
-	return &topic, nil
+	return &topic, skipTaggedFields(r, header)
 }
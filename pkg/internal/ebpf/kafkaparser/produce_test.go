@@ -634,3 +634,74 @@ func TestParseProduceRequestEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestParseProduceRequest_RecordsOfAllTopics(t *testing.T) {
+	partition := func(index uint32, records []byte, flexible bool) []byte {
+		pkt := binary.BigEndian.AppendUint32(nil, index)
+		if flexible {
+			// compact records, followed by the partition tagged fields
+			pkt = binary.AppendUvarint(pkt, uint64(len(records)+1))
+			pkt = append(pkt, records...)
+			return append(pkt, 0)
+		}
+		return append(pkt, int32Records(records)...)
+	}
+	topic := func(name string, flexible bool, partitions ...[]byte) []byte {
+		var pkt []byte
+		if flexible {
+			pkt = binary.AppendUvarint(pkt, uint64(len(name)+1))
+			pkt = append(pkt, name...)
+			pkt = binary.AppendUvarint(pkt, uint64(len(partitions)+1))
+		} else {
+			pkt = binary.BigEndian.AppendUint16(pkt, uint16(len(name)))
+			pkt = append(pkt, name...)
+			pkt = binary.BigEndian.AppendUint32(pkt, uint32(len(partitions)))
+		}
+		for _, p := range partitions {
+			pkt = append(pkt, p...)
+		}
+		if flexible {
+			pkt = append(pkt, 0) // topic tagged fields
+		}
+		return pkt
+	}
+
+	for _, version := range []int16{7, 9} {
+		t.Run(fmt.Sprintf("version_%d", version), func(t *testing.T) {
+			flexible := version >= 9
+			var pkt []byte
+			if flexible {
+				pkt = append(pkt, 0) // null compact transactional_id
+			} else {
+				pkt = binary.BigEndian.AppendUint16(pkt, uint16(negativeLength))
+			}
+			pkt = binary.BigEndian.AppendUint16(pkt, 1)     // acks
+			pkt = binary.BigEndian.AppendUint32(pkt, 30000) // timeout_ms
+			if flexible {
+				pkt = binary.AppendUvarint(pkt, 3)
+			} else {
+				pkt = binary.BigEndian.AppendUint32(pkt, 2)
+			}
+			pkt = append(pkt, topic("topic1", flexible,
+				partition(0, recordBatch(3, 10), flexible),
+				partition(1, recordBatch(2, 10), flexible))...)
+			pkt = append(pkt, topic("topic2", flexible,
+				partition(0, recordBatch(4, 10), flexible))...)
+
+			r := largebuf.NewLargeBufferFrom(pkt).NewReader()
+			req, err := ParseProduceRequest(&r, newTestHeader(APIKeyProduce, version))
+			require.NoError(t, err)
+			require.Len(t, req.Topics, 2)
+
+			assert.Equal(t, "topic1", req.Topics[0].Name)
+			// the partition is not reported when there are many of them
+			assert.Nil(t, req.Topics[0].Partition)
+			assert.Equal(t, &RecordsInfo{Bytes: 2 * (recordBatchHeaderLen + 10), Messages: 5}, req.Topics[0].Records)
+
+			assert.Equal(t, "topic2", req.Topics[1].Name)
+			require.NotNil(t, req.Topics[1].Partition)
+			assert.Equal(t, 0, *req.Topics[1].Partition)
+			assert.Equal(t, &RecordsInfo{Bytes: recordBatchHeaderLen + 10, Messages: 4}, req.Topics[1].Records)
+		})
+	}
+}
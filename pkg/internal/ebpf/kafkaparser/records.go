@@ -0,0 +1,225 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaparser // import "go.opentelemetry.io/obi/pkg/internal/ebpf/kafkaparser"
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"go.opentelemetry.io/obi/pkg/internal/largebuf"
+)
+
+const (
+	// offset(8) + length(4) + partition_leader_epoch or crc(4) + magic(1)
+	batchMagicLen = Int64Len + Int32Len + Int32Len + Int8Len
+	// magic v2 record batch header, up to the records count
+	recordBatchHeaderLen = // 61
+	Int64Len +           // baseOffset
+		Int32Len + // batchLength
+		Int32Len + // partitionLeaderEpoch
+		Int8Len + // magic
+		Int32Len + // crc
+		Int16Len + // attributes
+		Int32Len + // lastOffsetDelta
+		Int64Len + // baseTimestamp
+		Int64Len + // maxTimestamp
+		Int64Len + // producerId
+		Int16Len + // producerEpoch
+		Int32Len + // baseSequence
+		Int32Len // records count
+	recordBatchMagicV2 = 2
+	// offset of the attributes in the magic v2 record batch header
+	recordBatchAttributesOffset = Int64Len + Int32Len + Int32Len + Int8Len + Int32Len
+	// the three lower bits of the batch attributes define the compression codec
+	recordBatchCompressionMask = 0x07
+
+	traceparentHeader = "traceparent"
+	// maxTraceparents bounds the number of traceparent headers extracted from the
+	// records of a produce request or fetch response
+	maxTraceparents = 128
+)
+
+// RecordsInfo summarizes the record batches of a produce request or a fetch response partition.
+type RecordsInfo struct {
+	// Bytes is the size of the record batches, as declared in the protocol
+	Bytes int
+	// Messages is the number of records declared in the batch headers. Since the
+	// captured packets might be truncated, only the batches whose header is in the
+	// buffer are accounted, so it might be lower than the actual number of records.
+	Messages int
+	// Traceparents contains the values of the traceparent headers of the records. Only the
+	// uncompressed record batches whose records are in the captured buffer are inspected.
+	Traceparents []string
+}
+
+// Add accounts the records of another records field. The traceparents are still bounded
+// by the maximum number of traceparents of a single field.
+func (ri *RecordsInfo) Add(other *RecordsInfo) {
+	if other == nil {
+		return
+	}
+	ri.Bytes += other.Bytes
+	ri.Messages += other.Messages
+	if free := maxTraceparents - len(ri.Traceparents); free > 0 {
+		ri.Traceparents = append(ri.Traceparents, other.Traceparents[:min(free, len(other.Traceparents))]...)
+	}
+}
+
+// readRecords reads a RECORDS / COMPACT_RECORDS field, accounting the records in
+// its batches, and leaves the reader after the records field, or at the end of the
+// buffer if it is truncated.
+func readRecords(r *largebuf.LargeBufferReader, header KafkaRequestHeader) (*RecordsInfo, error) {
+	size, err := readRecordsLength(r, header)
+	if err != nil {
+		return nil, err
+	}
+	info := &RecordsInfo{Bytes: size}
+	consumed := 0
+batches:
+	for size-consumed >= batchMagicLen {
+		hdr, err := r.Peek(min(recordBatchHeaderLen, size-consumed, r.Remaining()))
+		if err != nil || len(hdr) < batchMagicLen {
+			break
+		}
+		batchLen := int(int32(binary.BigEndian.Uint32(hdr[Int64Len:])))
+		if batchLen <= 0 {
+			break
+		}
+		switch hdr[batchMagicLen-Int8Len] {
+		case recordBatchMagicV2:
+			if len(hdr) < recordBatchHeaderLen {
+				break batches
+			}
+			info.Messages += int(int32(binary.BigEndian.Uint32(hdr[recordBatchHeaderLen-Int32Len:])))
+			attrs := binary.BigEndian.Uint16(hdr[recordBatchAttributesOffset:])
+			if attrs&recordBatchCompressionMask == 0 && len(info.Traceparents) < maxTraceparents {
+				// the declared batch length might be shorter than the batch header in malformed packets
+				batch, err := r.Peek(min(Int64Len+Int32Len+batchLen, size-consumed, r.Remaining()))
+				if err == nil && len(batch) >= recordBatchHeaderLen {
+					info.Traceparents = appendTraceparents(info.Traceparents, batch[recordBatchHeaderLen:])
+				}
+			}
+		case 0, 1:
+			// legacy message sets contain a single message per entry
+			info.Messages++
+		default:
+			// unknown format. Don't try to account anything else
+			return info, r.Skip(min(size-consumed, r.Remaining()))
+		}
+		step := min(Int64Len+Int32Len+batchLen, size-consumed)
+		if err := r.Skip(step); err != nil {
+			break
+		}
+		consumed += step
+	}
+	// skip the rest of the records that are available in the buffer
+	if err := r.Skip(min(size-consumed, r.Remaining())); err != nil {
+		return info, err
+	}
+	return info, nil
+}
+
+func readRecordsLength(r *largebuf.LargeBufferReader, header KafkaRequestHeader) (int, error) {
+	if isFlexible(header) {
+		size, err := readUnsignedVarint(r)
+		if err != nil {
+			return 0, err
+		}
+		if size == 0 {
+			return 0, nil // null records
+		}
+		return size - 1, nil
+	}
+	size, err := r.ReadI32BE()
+	if err != nil {
+		return 0, errors.New("packet too short for records length")
+	}
+	if size < 0 {
+		return 0, nil // null records
+	}
+	return int(size), nil
+}
+
+// appendTraceparents appends the traceparent headers of the provided records, stopping
+// at the first record that is truncated or malformed.
+func appendTraceparents(dst []string, records []byte) []string {
+	for len(records) > 0 && len(dst) < maxTraceparents {
+		length, n := binary.Varint(records)
+		if n <= 0 || length <= 0 || length > int64(len(records)-n) {
+			return dst
+		}
+		dst = appendRecordTraceparents(dst, records[n:n+int(length)])
+		records = records[n+int(length):]
+	}
+	return dst
+}
+
+// appendRecordTraceparents appends the traceparent headers of a single record, whose format is:
+// attributes(int8) timestampDelta(varlong) offsetDelta(varint) key(varbytes) value(varbytes) [headers]
+// where each header is: key(varbytes) value(varbytes)
+func appendRecordTraceparents(dst []string, record []byte) []string {
+	rr := recordReader{buf: record}
+	rr.skip(Int8Len)
+	rr.varint()
+	rr.varint()
+	rr.bytes()
+	rr.bytes()
+	headers := rr.varint()
+	for range headers {
+		key, value := rr.bytes(), rr.bytes()
+		if rr.err != nil || len(dst) >= maxTraceparents {
+			break
+		}
+		if string(key) == traceparentHeader {
+			dst = append(dst, string(value))
+		}
+	}
+	return dst
+}
+
+// recordReader reads the zigzag-encoded varint fields of a record. After the first
+// error, all the subsequent reads return zero values.
+type recordReader struct {
+	buf []byte
+	err error
+}
+
+func (rr *recordReader) skip(n int) {
+	if rr.err != nil {
+		return
+	}
+	if n > len(rr.buf) {
+		rr.err = errors.New("record too short")
+		return
+	}
+	rr.buf = rr.buf[n:]
+}
+
+func (rr *recordReader) varint() int64 {
+	if rr.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(rr.buf)
+	if n <= 0 {
+		rr.err = errors.New("invalid record varint")
+		return 0
+	}
+	rr.buf = rr.buf[n:]
+	return v
+}
+
+// bytes reads a varint-prefixed byte array, where a negative length means null
+func (rr *recordReader) bytes() []byte {
+	length := rr.varint()
+	if rr.err != nil || length <= 0 {
+		return nil
+	}
+	if length > int64(len(rr.buf)) {
+		rr.err = errors.New("record too short")
+		return nil
+	}
+	b := rr.buf[:length]
+	rr.buf = rr.buf[length:]
+	return b
+}
@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaparser
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/internal/largebuf"
+)
+
+// recordBatch returns a magic v2 record batch with the given number of records
+// and a body of the provided size after the batch header
+func recordBatch(records int32, bodyLen int) []byte {
+	batch := make([]byte, recordBatchHeaderLen+bodyLen)
+	binary.BigEndian.PutUint32(batch[8:], uint32(len(batch)-12)) // batchLength
+	batch[16] = recordBatchMagicV2
+	binary.BigEndian.PutUint32(batch[57:], uint32(records))
+	return batch
+}
+
+// recordBatchOf returns a magic v2 record batch containing the provided records
+func recordBatchOf(compression byte, records ...[]byte) []byte {
+	batch := recordBatch(int32(len(records)), 0)
+	batch[recordBatchAttributesOffset+1] = compression
+	for _, r := range records {
+		batch = append(batch, r...)
+	}
+	binary.BigEndian.PutUint32(batch[8:], uint32(len(batch)-12)) // batchLength
+	return batch
+}
+
+// record returns a magic v2 record with the provided header key/value pairs
+func record(headers ...string) []byte {
+	appendBytes := func(dst []byte, b string) []byte {
+		return append(binary.AppendVarint(dst, int64(len(b))), b...)
+	}
+	body := []byte{0}                    // attributes
+	body = binary.AppendVarint(body, 0)  // timestampDelta
+	body = binary.AppendVarint(body, 0)  // offsetDelta
+	body = binary.AppendVarint(body, -1) // null key
+	body = appendBytes(body, "some value")
+	body = binary.AppendVarint(body, int64(len(headers)/2))
+	for i := 0; i+1 < len(headers); i += 2 {
+		body = appendBytes(body, headers[i])
+		body = appendBytes(body, headers[i+1])
+	}
+	return append(binary.AppendVarint(nil, int64(len(body))), body...)
+}
+
+// legacyMessage returns a magic v1 message set entry
+func legacyMessage(bodyLen int) []byte {
+	msg := make([]byte, batchMagicLen+bodyLen)
+	binary.BigEndian.PutUint32(msg[8:], uint32(len(msg)-12)) // message_size
+	msg[16] = 1
+	return msg
+}
+
+func int32Records(batches ...[]byte) []byte {
+	var body []byte
+	for _, b := range batches {
+		body = append(body, b...)
+	}
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(body))), body...)
+}
+
+func TestReadRecords(t *testing.T) {
+	produceV7 := newTestHeader(APIKeyProduce, 7)
+	produceV9 := newTestHeader(APIKeyProduce, 9)
+	twoBatches := append(recordBatch(3, 20), recordBatch(2, 10)...)
+
+	tests := []struct {
+		name      string
+		header    KafkaRequestHeader
+		packet    []byte
+		expected  *RecordsInfo
+		remaining int
+	}{{
+		name:      "multiple batches",
+		header:    produceV7,
+		packet:    append(int32Records(recordBatch(3, 20), recordBatch(2, 10)), 0xAA, 0xBB),
+		expected:  &RecordsInfo{Bytes: 2*recordBatchHeaderLen + 30, Messages: 5},
+		remaining: 2,
+	}, {
+		name:     "compact records",
+		header:   produceV9,
+		packet:   append(binary.AppendUvarint(nil, uint64(len(twoBatches)+1)), twoBatches...),
+		expected: &RecordsInfo{Bytes: len(twoBatches), Messages: 5},
+	}, {
+		name:     "legacy message set",
+		header:   produceV7,
+		packet:   int32Records(legacyMessage(5), legacyMessage(8), legacyMessage(0)),
+		expected: &RecordsInfo{Bytes: 3*batchMagicLen + 13, Messages: 3},
+	}, {
+		name:     "null records",
+		header:   produceV7,
+		packet:   []byte{0xFF, 0xFF, 0xFF, 0xFF},
+		expected: &RecordsInfo{},
+	}, {
+		name:   "truncated buffer only accounts the visible batch headers",
+		header: produceV7,
+		packet: int32Records(recordBatch(3, 20), recordBatch(2, 10), recordBatch(7, 10))[:4+2*recordBatchHeaderLen+40],
+		// the size is still reported from the records length
+		expected: &RecordsInfo{Bytes: 3*recordBatchHeaderLen + 40, Messages: 5},
+	}, {
+		name:     "unknown format",
+		header:   produceV7,
+		packet:   int32Records(make([]byte, 30)),
+		expected: &RecordsInfo{Bytes: 30},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := largebuf.NewLargeBufferFrom(tt.packet).NewReader()
+			info, err := readRecords(&r, tt.header)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, info)
+			assert.Equal(t, tt.remaining, r.Remaining())
+		})
+	}
+}
+
+func TestReadRecords_Traceparents(t *testing.T) {
+	const (
+		tp1 = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+		tp2 = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+	)
+	produceV7 := newTestHeader(APIKeyProduce, 7)
+	uncompressed := recordBatchOf(0,
+		record("traceparent", tp1, "foo", "bar"),
+		record(),
+		record("tracestate", "a=b", "traceparent", tp2))
+	truncated := recordBatchOf(0, record("traceparent", tp1), record("traceparent", tp2))
+
+	tests := []struct {
+		name     string
+		packet   []byte
+		expected []string
+	}{{
+		name:     "uncompressed batch",
+		packet:   int32Records(uncompressed),
+		expected: []string{tp1, tp2},
+	}, {
+		name:   "compressed batch",
+		packet: int32Records(recordBatchOf(1, record("traceparent", tp1))),
+	}, {
+		name:     "multiple batches",
+		packet:   int32Records(recordBatchOf(0, record("traceparent", tp2)), uncompressed),
+		expected: []string{tp2, tp1, tp2},
+	}, {
+		name: "batch length shorter than the batch header",
+		packet: func() []byte {
+			batch := recordBatchOf(0, record("traceparent", tp1))
+			binary.BigEndian.PutUint32(batch[8:], 10) // batchLength
+			return int32Records(batch)
+		}(),
+	}, {
+		name:     "truncated records",
+		packet:   int32Records(truncated)[:len(truncated)-10],
+		expected: []string{tp1},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := largebuf.NewLargeBufferFrom(tt.packet).NewReader()
+			info, err := readRecords(&r, produceV7)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, info.Traceparents)
+		})
+	}
+}
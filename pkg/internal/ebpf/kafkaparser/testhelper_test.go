@@ -19,6 +19,8 @@ func newTestHeader(apiKey KafkaAPIKey, apiVersion int16) KafkaRequestHeader {
 		flexible = apiVersion >= 12
 	case APIKeyMetadata:
 		flexible = apiVersion >= 9
+	case APIKeyOffsetCommit:
+		flexible = apiVersion >= 8
 	}
 	size := MinKafkaRequestLen
 	if flexible {
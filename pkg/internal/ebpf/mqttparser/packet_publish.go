@@ -5,8 +5,27 @@ package mqttparser // import "go.opentelemetry.io/obi/pkg/internal/ebpf/mqttpars
 
 import (
 	"errors"
+	"fmt"
 )
 
+// MQTT 5.0 property identifiers that can be present in a PUBLISH packet.
+const (
+	propPayloadFormatIndicator = 0x01
+	propMessageExpiryInterval  = 0x02
+	propContentType            = 0x03
+	propResponseTopic          = 0x08
+	propCorrelationData        = 0x09
+	propSubscriptionIdentifier = 0x0B
+	propTopicAlias             = 0x23
+	propUserProperty           = 0x26
+)
+
+// UserProperty is an MQTT 5.0 user property: a UTF-8 string key-value pair.
+type UserProperty struct {
+	Key   string
+	Value string
+}
+
 // PublishPacket represents a parsed MQTT PUBLISH packet.
 //
 // PUBLISH packets carry application messages from publishers to subscribers.
@@ -25,6 +44,9 @@ type PublishPacket struct {
 
 	// PacketID is present for QoS levels 1 and 2 only, used for message acknowledgment.
 	PacketID uint16
+
+	// UserProperties of MQTT 5.0 packets (e.g. for context propagation).
+	UserProperties []UserProperty
 }
 
 // ParsePublishPacket parses an MQTT PUBLISH packet.
@@ -63,9 +85,16 @@ func ParsePublishPacket(pkt []byte, offset Offset, flags uint8) (*PublishPacket,
 	}
 
 	// N.B. context propagation for MQTT:
-	// - MQTT 5.0, properties could be parsed here
+	// - MQTT 5.0, it goes through the user properties. Since the protocol version is not
+	//   known from the PUBLISH packet, the properties are parsed from a copy of the reader,
+	//   so the returned offset is the same for both versions. An MQTT 3.1 payload is very
+	//   unlikely to be parsed as valid properties.
 	// - MQTT 3.1, propagation would need to go through the payload - but would
 	//   likely need to be careful with performance implications.
+	props := *r
+	if userProps, err := props.ReadUserProperties(); err == nil {
+		publish.UserProperties = userProps
+	}
 	return &publish, r.Offset(), nil
 }
 
@@ -90,3 +119,51 @@ func (r *PublishPacketReader) ReadTopicName() (string, error) {
 func (r *PublishPacketReader) ReadPacketID() (uint16, error) {
 	return r.ReadUint16()
 }
+
+// ReadUserProperties reads the MQTT 5.0 properties from the PUBLISH packet variable header
+// and returns the user properties. It fails if any property is unknown or malformed.
+func (r *PublishPacketReader) ReadUserProperties() ([]UserProperty, error) {
+	propLen, err := r.ReadVariableByteInteger()
+	if err != nil {
+		return nil, err
+	}
+	if propLen > r.Remaining() {
+		return nil, errors.New("not enough data for PUBLISH properties")
+	}
+	end := r.Offset() + propLen
+	var userProps []UserProperty
+	for r.Offset() < end {
+		id, err := r.ReadUint8()
+		if err != nil {
+			return nil, err
+		}
+		switch id {
+		case propPayloadFormatIndicator:
+			err = r.Skip(1)
+		case propTopicAlias:
+			err = r.Skip(2)
+		case propMessageExpiryInterval:
+			err = r.Skip(4)
+		case propContentType, propResponseTopic, propCorrelationData:
+			// binary data is encoded the same way as strings
+			_, err = r.ReadString()
+		case propSubscriptionIdentifier:
+			_, err = r.ReadVariableByteInteger()
+		case propUserProperty:
+			var up UserProperty
+			if up.Key, err = r.ReadString(); err == nil {
+				up.Value, err = r.ReadString()
+			}
+			userProps = append(userProps, up)
+		default:
+			return nil, fmt.Errorf("unknown PUBLISH property: %#x", id)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.Offset() != end {
+		return nil, errors.New("PUBLISH properties exceed the declared length")
+	}
+	return userProps, nil
+}
@@ -49,6 +49,8 @@ func TestParsePublishPacketWithRealPacket(t *testing.T) {
 	assert.False(t, publish.Dup)
 	assert.False(t, publish.Retain)
 	assert.Equal(t, uint16(1), publish.PacketID)
+	// the MQTT 3.1.1 payload is not interpreted as properties
+	assert.Empty(t, publish.UserProperties)
 
 	// Verify offset is correct (header + variable header, payload not parsed)
 	expectedOffset := parsed.FixedHeader.Length + 2 + 10 + 2 // fixed header + topic len + topic + packet ID
@@ -223,6 +225,53 @@ func TestParsePublishPacket(t *testing.T) {
 	}
 }
 
+func TestParsePublishPacketUserProperties(t *testing.T) {
+	str := func(s string) []byte {
+		return append([]byte{byte(len(s) >> 8), byte(len(s))}, s...)
+	}
+	var props []byte
+	props = append(props, propPayloadFormatIndicator, 0x01)
+	props = append(props, propUserProperty)
+	props = append(props, str("traceparent")...)
+	props = append(props, str("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")...)
+	props = append(props, propContentType)
+	props = append(props, str("text/plain")...)
+	props = append(props, propUserProperty)
+	props = append(props, str("foo")...)
+	props = append(props, str("bar")...)
+
+	packet := append(str("test"), 0x00, 0x01) // topic and packet ID
+	packet = append(packet, byte(len(props)))
+	packet = append(packet, props...)
+	packet = append(packet, []byte("hello")...)
+
+	publish, offset, err := ParsePublishPacket(packet, 0, 0x02)
+	require.NoError(t, err)
+	assert.Equal(t, "test", publish.TopicName)
+	assert.Equal(t, uint16(1), publish.PacketID)
+	assert.Equal(t, []UserProperty{
+		{Key: "traceparent", Value: "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		{Key: "foo", Value: "bar"},
+	}, publish.UserProperties)
+	// the offset is not moved after the properties, as for MQTT 3.1.1
+	assert.Equal(t, 8, offset)
+
+	t.Run("unknown property", func(t *testing.T) {
+		packet := append(str("test"), 0x00, 0x01, 0x02, 0x7F, 0x00)
+		publish, _, err := ParsePublishPacket(packet, 0, 0x02)
+		require.NoError(t, err)
+		assert.Empty(t, publish.UserProperties)
+	})
+
+	t.Run("truncated properties", func(t *testing.T) {
+		packet := append(str("test"), 0x00, 0x01, byte(len(props)))
+		packet = append(packet, props[:len(props)-2]...)
+		publish, _, err := ParsePublishPacket(packet, 0, 0x02)
+		require.NoError(t, err)
+		assert.Empty(t, publish.UserProperties)
+	})
+}
+
 func TestPublishPacketReaderReadTopicName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -375,6 +375,18 @@ func (ctx *ElfContext) SectionAddress(sectionName string) uint64 {
 	return s.Addr
 }
 
+// SectionData returns the contents of the named section, or nil if the section
+// does not exist or does not occupy space in the file (e.g. .bss)
+func (ctx *ElfContext) SectionData(sectionName string) []byte {
+	s := ctx.section(sectionName)
+
+	if s == nil || s.Type == SHT_NOBITS || s.Offset+s.Size > uint64(len(ctx.Data)) {
+		return nil
+	}
+
+	return ctx.Data[s.Offset : s.Offset+s.Size]
+}
+
 func (ctx *ElfContext) shstrtabData() []byte {
 	if int(ctx.Hdr.Shstrndx) >= len(ctx.Sections) {
 		return nil
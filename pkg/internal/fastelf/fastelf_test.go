@@ -37,6 +37,10 @@ func testFastElf(t *testing.T, ctx *ElfContext) {
 	require.True(t, ctx.HasSection(".gnu_debuglink"))
 	require.False(t, ctx.HasSection(".invalid"))
 
+	require.NotEmpty(t, ctx.SectionData(".gnu_debuglink"))
+	require.Nil(t, ctx.SectionData(".bss"))
+	require.Nil(t, ctx.SectionData(".invalid"))
+
 	require.NoError(t, ctx.Close())
 }
 
@@ -140,6 +140,32 @@ func findFuncOffset(f *gosym.Func, elfF *elf.File) (FuncOffsets, bool, error) {
 	return FuncOffsets{}, false, nil
 }
 
+// FindFunctions returns the entry address of the functions whose name starts with any of the
+// provided prefixes. Since it uses the Go symbol table, it also works for stripped executables.
+func FindFunctions(elfF *elf.File, prefixes ...string) (map[string]uint64, error) {
+	symTab, err := findGoSymbolTable(elfF)
+	if err != nil {
+		return nil, err
+	}
+
+	functions := map[string]uint64{}
+	for _, f := range symTab.Funcs {
+		fName := f.Name
+		// fetch short path of function for vendor scene
+		if paths := strings.Split(fName, "/vendor/"); len(paths) > 1 {
+			fName = paths[1]
+		}
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(fName, prefix) {
+				functions[fName] = f.Entry
+				break
+			}
+		}
+	}
+
+	return functions, nil
+}
+
 func findGoSymbolTable(elfF *elf.File) (*gosym.Table, error) {
 	var err error
 	var pclndat []byte
@@ -167,7 +193,10 @@ func findGoSymbolTable(elfF *elf.File) (*gosym.Table, error) {
 		default:
 			return nil, errors.New("unknown .gopclntab text ptr size")
 		}
-	} else {
+	}
+
+	// some Go linker versions leave the textStart header field empty
+	if runtimeText == 0 {
 		txtSection := elfF.Section(".text")
 		if txtSection == nil {
 			return nil, errors.New("can't find .text section in ELF file")
@@ -0,0 +1,131 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/discover/exec"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+)
+
+const defaultInterval = 5 * time.Second
+
+func clog() *slog.Logger {
+	return slog.With("component", "process.Collector")
+}
+
+// CollectConfig for the process metrics collector
+type CollectConfig struct {
+	// Interval between two samples of the instrumented processes
+	Interval time.Duration
+	// ProcRoot allows overriding the /proc filesystem location, for testing purposes
+	ProcRoot string
+}
+
+type tracked struct {
+	service *svc.Attrs
+	last    *snapshot
+}
+
+type collector struct {
+	log      *slog.Logger
+	interval time.Duration
+	reader   *reader
+	events   <-chan exec.ProcessEvent
+	out      *msg.Queue[[]*Status]
+	procs    map[app.PID]*tracked
+}
+
+// Collector periodically samples the /proc filesystem of the instrumented processes,
+// as notified by the process events, and forwards their Status.
+func Collector(
+	cfg *CollectConfig,
+	processEvents *msg.Queue[exec.ProcessEvent],
+	out *msg.Queue[[]*Status],
+) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		interval := cfg.Interval
+		if interval <= 0 {
+			interval = defaultInterval
+		}
+		procRoot := cfg.ProcRoot
+		if procRoot == "" {
+			procRoot = "/proc"
+		}
+		c := &collector{
+			log:      clog(),
+			interval: interval,
+			reader:   newReader(procRoot),
+			events:   processEvents.Subscribe(msg.SubscriberName("process.Collector")),
+			out:      out,
+			procs:    map[app.PID]*tracked{},
+		}
+		return c.run, nil
+	}
+}
+
+func (c *collector) run(ctx context.Context) {
+	defer c.out.Close()
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			c.log.Debug("context done. Stopping")
+			return
+		case pe, ok := <-c.events:
+			if !ok {
+				c.log.Debug("process events channel closed. Stopping")
+				return
+			}
+			c.onProcessEvent(&pe)
+		case <-ticker.C:
+			if statuses := c.collect(); len(statuses) > 0 {
+				c.out.Send(statuses)
+			}
+		}
+	}
+}
+
+func (c *collector) onProcessEvent(pe *exec.ProcessEvent) {
+	pid := pe.File.Pid
+	switch pe.Type {
+	case exec.ProcessEventCreated:
+		if !pe.File.Service.Features.ProcessMetrics() {
+			delete(c.procs, pid)
+			return
+		}
+		service := pe.File.Service
+		if t, ok := c.procs[pid]; ok {
+			// metadata update for an already tracked process. Keep the last snapshot
+			// to keep reporting deltas
+			t.service = &service
+			return
+		}
+		c.procs[pid] = &tracked{service: &service}
+	case exec.ProcessEventTerminated:
+		delete(c.procs, pid)
+	}
+}
+
+func (c *collector) collect() []*Status {
+	statuses := make([]*Status, 0, len(c.procs))
+	for pid, t := range c.procs {
+		snap, err := c.reader.read(pid)
+		if err != nil {
+			// the process might have finished before its termination event is received
+			c.log.Debug("can't read process status", "pid", pid, "error", err)
+			continue
+		}
+		statuses = append(statuses, status(t.service, pid, t.last, snap))
+		t.last = snap
+	}
+	return statuses
+}
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/discover/exec"
+	"go.opentelemetry.io/obi/pkg/export"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+const timeout = 5 * time.Second
+
+func TestReader(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, 123, 250, 120, 7, 3)
+
+	r := newReader(root)
+	r.clockTicks = 100
+	snap, err := r.read(123)
+	require.NoError(t, err)
+	assert.Equal(t, &snapshot{
+		cpuUserSeconds:   2.5,
+		cpuSystemSeconds: 1.2,
+		rssBytes:         2048 * 1024,
+		vmsBytes:         10240 * 1024,
+		ioReadBytes:      4096,
+		ioWriteBytes:     8192,
+		openFDs:          3,
+		threads:          7,
+	}, snap)
+
+	_, err = r.read(456)
+	require.Error(t, err)
+}
+
+func TestReader_MissingOptionalFiles(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, 123, 250, 120, 7, 3)
+	// io and fd files are not accessible without the required capabilities
+	require.NoError(t, os.Remove(filepath.Join(root, "123", "io")))
+	require.NoError(t, os.RemoveAll(filepath.Join(root, "123", "fd")))
+
+	r := newReader(root)
+	r.clockTicks = 100
+	snap, err := r.read(123)
+	require.NoError(t, err)
+	assert.InDelta(t, 2.5, snap.cpuUserSeconds, 0.0001)
+	assert.Zero(t, snap.ioReadBytes)
+	assert.Zero(t, snap.openFDs)
+}
+
+func TestCollector(t *testing.T) {
+	root := t.TempDir()
+	writeProc(t, root, 123, 250, 120, 7, 3)
+	writeProc(t, root, 456, 100, 100, 1, 1)
+
+	events := msg.NewQueue[exec.ProcessEvent](msg.ChannelBufferLen(10))
+	out := msg.NewQueue[[]*Status](msg.ChannelBufferLen(10))
+	statuses := out.Subscribe()
+	run, err := Collector(&CollectConfig{Interval: 10 * time.Millisecond, ProcRoot: root}, events, out)(t.Context())
+	require.NoError(t, err)
+	go run(t.Context())
+
+	events.Send(exec.ProcessEvent{Type: exec.ProcessEventCreated, File: &exec.FileInfo{
+		Pid: 123, Service: svc.Attrs{UID: svc.UID{Name: "foo"}, Features: export.FeatureProcess},
+	}})
+	// processes without the process feature are ignored
+	events.Send(exec.ProcessEvent{Type: exec.ProcessEventCreated, File: &exec.FileInfo{
+		Pid: 456, Service: svc.Attrs{UID: svc.UID{Name: "bar"}, Features: export.FeatureApplicationRED},
+	}})
+
+	first := nextStatus(t, statuses)
+	assert.Equal(t, "foo", first.Service.UID.Name)
+	assert.EqualValues(t, 123, first.PID)
+	// first sample reports all the consumed CPU since the process started
+	assert.InDelta(t, 2.5, first.CPUTimeUserDelta, 0.0001)
+	assert.InDelta(t, 1.2, first.CPUTimeSystemDelta, 0.0001)
+	assert.EqualValues(t, 4096, first.IOReadBytesDelta)
+	assert.EqualValues(t, 2048*1024, first.MemoryRSSBytes)
+	assert.EqualValues(t, 3, first.OpenFileDescriptors)
+	assert.EqualValues(t, 7, first.Threads)
+
+	// following samples report the deltas
+	writeProc(t, root, 123, 300, 120, 8, 4)
+	require.Eventually(t, func() bool {
+		s := nextStatus(t, statuses)
+		return s.Threads == 8 && s.CPUTimeUserDelta > 0.49 && s.CPUTimeUserDelta < 0.51
+	}, timeout, time.Millisecond)
+
+	// terminated processes are not reported anymore
+	events.Send(exec.ProcessEvent{Type: exec.ProcessEventTerminated, File: &exec.FileInfo{Pid: 123}})
+	require.Eventually(t, func() bool {
+		select {
+		case <-statuses:
+			return false
+		case <-time.After(50 * time.Millisecond):
+			return true
+		}
+	}, timeout, time.Millisecond)
+}
+
+func nextStatus(t *testing.T, statuses <-chan []*Status) *Status {
+	t.Helper()
+	select {
+	case s := <-statuses:
+		require.Len(t, s, 1)
+		return s[0]
+	case <-time.After(timeout):
+		require.Fail(t, "timeout while waiting for process statuses")
+		return nil
+	}
+}
+
+// writeProc creates a fake /proc/<pid> directory with the stat, status, io and fd entries
+func writeProc(t *testing.T, root string, pid app.PID, utime, stime, threads, fds int) {
+	t.Helper()
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "fd"), 0o755))
+	// the command name contains spaces and parentheses to verify the parsing
+	stat := fmt.Sprintf("%d (my (weird) cmd) S 1 %d %d 0 -1 4194560 1000 0 0 0 %d %d 0 0 20 0 %d 0 12345 10485760 512 "+
+		"18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 0 0 0 0 0 0\n",
+		pid, pid, pid, utime, stime, threads)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0o644))
+	status := fmt.Sprintf("Name:\tmy cmd\nState:\tS (sleeping)\nVmSize:\t   10240 kB\nVmRSS:\t    2048 kB\nThreads:\t%d\n", threads)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "status"), []byte(status), 0o644))
+	io := "rchar: 100\nwchar: 200\nread_bytes: 4096\nwrite_bytes: 8192\ncancelled_write_bytes: 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "io"), []byte(io), 0o644))
+	for i := range fds {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "fd", fmt.Sprint(i)), nil, 0o644))
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package process samples the resource usage of the instrumented processes from the
+// /proc filesystem, to be exported as process metrics.
+package process // import "go.opentelemetry.io/obi/pkg/internal/process"
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/tklauser/go-sysconf"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+)
+
+// Status of the resource usage of an instrumented process, as sampled from the /proc filesystem.
+// Cumulative values (CPU time, disk I/O) are provided as deltas since the previous sample
+// of the same process, so they can be directly added to the exported counters.
+type Status struct {
+	Service *svc.Attrs
+	PID     app.PID
+
+	CPUTimeUserDelta   float64
+	CPUTimeSystemDelta float64
+
+	MemoryRSSBytes     int64
+	MemoryVirtualBytes int64
+
+	IOReadBytesDelta  int64
+	IOWriteBytesDelta int64
+
+	OpenFileDescriptors int64
+	Threads             int64
+}
+
+// snapshot of the cumulative values of a process, as read from the /proc filesystem
+type snapshot struct {
+	cpuUserSeconds   float64
+	cpuSystemSeconds float64
+	rssBytes         int64
+	vmsBytes         int64
+	ioReadBytes      int64
+	ioWriteBytes     int64
+	openFDs          int64
+	threads          int64
+}
+
+// reader of process snapshots from the /proc filesystem
+type reader struct {
+	procRoot   string
+	clockTicks float64
+}
+
+func newReader(procRoot string) *reader {
+	clkTck, err := sysconf.Sysconf(sysconf.SC_CLK_TCK)
+	if err != nil || clkTck <= 0 {
+		clkTck = 100 // default for Linux
+	}
+	return &reader{procRoot: procRoot, clockTicks: float64(clkTck)}
+}
+
+// read the snapshot of the given process. Only the failure reading /proc/<pid>/stat is
+// reported as an error, since the other files might not be accessible, depending on the
+// capabilities of OBI
+func (r *reader) read(pid app.PID) (*snapshot, error) {
+	dir := filepath.Join(r.procRoot, strconv.Itoa(int(pid)))
+	snap := &snapshot{}
+	if err := r.readStat(dir, snap); err != nil {
+		return nil, err
+	}
+	// missing information would be reported as zero
+	_ = readStatus(dir, snap)
+	_ = readIO(dir, snap)
+	if entries, err := os.ReadDir(filepath.Join(dir, "fd")); err == nil {
+		snap.openFDs = int64(len(entries))
+	}
+	return snap, nil
+}
+
+// readStat parses the /proc/<pid>/stat file. See proc_pid_stat(5) for the fields' description
+func (r *reader) readStat(dir string, snap *snapshot) error {
+	content, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return err
+	}
+	// the command name might contain spaces and parentheses, so we start parsing
+	// after the last closing parenthesis
+	end := bytes.LastIndexByte(content, ')')
+	if end < 0 {
+		return errors.New("unexpected stat format: missing command name")
+	}
+	// fields[0] is the 3rd field of the file (state)
+	fields := strings.Fields(string(content[end+1:]))
+	const (
+		utimeField      = 14 - 3
+		stimeField      = 15 - 3
+		numThreadsField = 20 - 3
+		vsizeField      = 23 - 3
+	)
+	if len(fields) <= vsizeField {
+		return fmt.Errorf("unexpected stat format: %d fields", len(fields))
+	}
+	utime, err := strconv.ParseUint(fields[utimeField], 10, 64)
+	if err != nil {
+		return fmt.Errorf("parsing utime: %w", err)
+	}
+	stime, err := strconv.ParseUint(fields[stimeField], 10, 64)
+	if err != nil {
+		return fmt.Errorf("parsing stime: %w", err)
+	}
+	snap.cpuUserSeconds = float64(utime) / r.clockTicks
+	snap.cpuSystemSeconds = float64(stime) / r.clockTicks
+	snap.threads, _ = strconv.ParseInt(fields[numThreadsField], 10, 64)
+	snap.vmsBytes, _ = strconv.ParseInt(fields[vsizeField], 10, 64)
+	return nil
+}
+
+// readStatus parses the /proc/<pid>/status file, which provides the memory usage
+// in bytes without requiring to know the memory page size
+func readStatus(dir string, snap *snapshot) error {
+	return readKeyValues(filepath.Join(dir, "status"), func(key, value string) {
+		switch key {
+		case "VmRSS":
+			snap.rssBytes = parseKB(value)
+		case "VmSize":
+			snap.vmsBytes = parseKB(value)
+		case "Threads":
+			snap.threads, _ = strconv.ParseInt(value, 10, 64)
+		}
+	})
+}
+
+// readIO parses the /proc/<pid>/io file, which requires the same permissions as ptrace
+func readIO(dir string, snap *snapshot) error {
+	return readKeyValues(filepath.Join(dir, "io"), func(key, value string) {
+		switch key {
+		case "read_bytes":
+			snap.ioReadBytes, _ = strconv.ParseInt(value, 10, 64)
+		case "write_bytes":
+			snap.ioWriteBytes, _ = strconv.ParseInt(value, 10, 64)
+		}
+	})
+}
+
+func readKeyValues(path string, fn func(key, value string)) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fn(key, strings.TrimSpace(value))
+	}
+	return scanner.Err()
+}
+
+// parseKB parses values like "1234 kB" and returns them in bytes
+func parseKB(value string) int64 {
+	kb, err := strconv.ParseInt(strings.TrimSpace(strings.TrimSuffix(value, "kB")), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return kb * 1024
+}
+
+// status calculates the process Status from the current snapshot and the previous one,
+// which might be nil if this is the first sample of the process
+func status(service *svc.Attrs, pid app.PID, prev, cur *snapshot) *Status {
+	if prev == nil {
+		prev = &snapshot{}
+	}
+	return &Status{
+		Service:             service,
+		PID:                 pid,
+		CPUTimeUserDelta:    max(cur.cpuUserSeconds-prev.cpuUserSeconds, 0),
+		CPUTimeSystemDelta:  max(cur.cpuSystemSeconds-prev.cpuSystemSeconds, 0),
+		MemoryRSSBytes:      cur.rssBytes,
+		MemoryVirtualBytes:  cur.vmsBytes,
+		IOReadBytesDelta:    max(cur.ioReadBytes-prev.ioReadBytes, 0),
+		IOWriteBytesDelta:   max(cur.ioWriteBytes-prev.ioWriteBytes, 0),
+		OpenFileDescriptors: cur.openFDs,
+		Threads:             cur.threads,
+	}
+}
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterurl // import "go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+
+	"github.com/cilium/ebpf"
+)
+
+// Storage persists the serialized route templates across restarts
+type Storage interface {
+	// Load returns the last stored data, or nil if nothing has been stored yet
+	Load() ([]byte, error)
+	Store(data []byte) error
+	Close() error
+}
+
+// FileStorage stores the route templates in a local file
+type FileStorage struct {
+	Path string
+}
+
+func (fs *FileStorage) Load() ([]byte, error) {
+	data, err := os.ReadFile(fs.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Store writes the data into a temporary file that replaces the previous one, so a crash
+// during the write doesn't corrupt the stored templates
+func (fs *FileStorage) Store(data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(fs.Path), 0o755); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	tmp := fs.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, fs.Path)
+}
+
+func (fs *FileStorage) Close() error {
+	return nil
+}
+
+const (
+	bpfStorageMapName = "obi_route_tmpl"
+	// the first entry of the array map is a header that describes the stored data. The rest of
+	// entries are split in two slots, and each new data alternates between them. The data is
+	// split in chunks of bpfStorageChunkSize bytes, stored in consecutive entries of its slot.
+	// The header is written after the data, so a partial write never replaces the previous data.
+	bpfStorageChunkSize  = 4096
+	bpfStorageMaxEntries = 256
+	bpfStorageSlotChunks = (bpfStorageMaxEntries - 1) / 2
+)
+
+// bpfStorageHeader describes the data stored in the BPF map
+type bpfStorageHeader struct {
+	// generation is increased on each write. Zero means that nothing has been stored yet.
+	generation uint64
+	slot       uint32
+	length     uint32
+	// checksum is the CRC32 (IEEE) of the data
+	checksum uint32
+}
+
+func (h *bpfStorageHeader) marshal(chunk []byte) {
+	clear(chunk)
+	binary.LittleEndian.PutUint64(chunk, h.generation)
+	binary.LittleEndian.PutUint32(chunk[8:], h.slot)
+	binary.LittleEndian.PutUint32(chunk[12:], h.length)
+	binary.LittleEndian.PutUint32(chunk[16:], h.checksum)
+}
+
+func (h *bpfStorageHeader) unmarshal(chunk []byte) error {
+	h.generation = binary.LittleEndian.Uint64(chunk)
+	h.slot = binary.LittleEndian.Uint32(chunk[8:])
+	h.length = binary.LittleEndian.Uint32(chunk[12:])
+	h.checksum = binary.LittleEndian.Uint32(chunk[16:])
+	if h.generation == 0 {
+		return nil
+	}
+	if h.slot > 1 {
+		return fmt.Errorf("invalid stored data slot: %d", h.slot)
+	}
+	if h.length > bpfStorageChunkSize*bpfStorageSlotChunks {
+		return fmt.Errorf("invalid stored data length: %d", h.length)
+	}
+	return nil
+}
+
+// firstKey of the entries of a slot
+func (h *bpfStorageHeader) firstKey() uint32 {
+	return 1 + h.slot*bpfStorageSlotChunks
+}
+
+// BPFMapStorage stores the route templates in a BPF array map pinned in the BPF filesystem,
+// which survives the restarts of OBI without requiring a writable volume.
+type BPFMapStorage struct {
+	m arrayMap
+}
+
+// arrayMap is the subset of the *ebpf.Map methods used by the BPFMapStorage
+type arrayMap interface {
+	Lookup(key, valueOut any) error
+	Update(key, value any, flags ebpf.MapUpdateFlags) error
+	Close() error
+}
+
+// NewBPFMapStorage creates the pinned map in the provided directory of the BPF filesystem,
+// or opens it if it was created by a previous execution
+func NewBPFMapStorage(pinPath string) (*BPFMapStorage, error) {
+	if err := os.MkdirAll(pinPath, 0o1700); err != nil {
+		return nil, fmt.Errorf("creating bpffs path: %w", err)
+	}
+	m, err := ebpf.NewMapWithOptions(&ebpf.MapSpec{
+		Name:       bpfStorageMapName,
+		Type:       ebpf.Array,
+		KeySize:    4,
+		ValueSize:  bpfStorageChunkSize,
+		MaxEntries: bpfStorageMaxEntries,
+		Pinning:    ebpf.PinByName,
+	}, ebpf.MapOptions{PinPath: pinPath})
+	if err != nil {
+		return nil, fmt.Errorf("creating pinned map: %w", err)
+	}
+	return &BPFMapStorage{m: m}, nil
+}
+
+func (bs *BPFMapStorage) header(chunk []byte) (bpfStorageHeader, error) {
+	var h bpfStorageHeader
+	if err := bs.m.Lookup(uint32(0), &chunk); err != nil {
+		return h, err
+	}
+	return h, h.unmarshal(chunk)
+}
+
+// Load returns the data described by the header, after verifying its checksum
+func (bs *BPFMapStorage) Load() ([]byte, error) {
+	chunk := make([]byte, bpfStorageChunkSize)
+	h, err := bs.header(chunk)
+	if err != nil {
+		return nil, err
+	}
+	if h.generation == 0 {
+		return nil, nil
+	}
+
+	buf := make([]byte, 0, int(h.length)+bpfStorageChunkSize)
+	for key := h.firstKey(); len(buf) < int(h.length); key++ {
+		if err := bs.m.Lookup(key, &chunk); err != nil {
+			return nil, err
+		}
+		buf = append(buf, chunk...)
+	}
+	buf = buf[:h.length]
+	if crc32.ChecksumIEEE(buf) != h.checksum {
+		return nil, errors.New("the stored data does not match its checksum")
+	}
+	return buf, nil
+}
+
+// Store writes the data in the slot that isn't used by the current data, and then
+// updates the header to point to it
+func (bs *BPFMapStorage) Store(data []byte) error {
+	if len(data) > bpfStorageChunkSize*bpfStorageSlotChunks {
+		return fmt.Errorf("route templates size (%d bytes) exceeds the BPF map capacity", len(data))
+	}
+	chunk := make([]byte, bpfStorageChunkSize)
+	h, err := bs.header(chunk)
+	if err != nil {
+		// the corrupted header is overwritten
+		h = bpfStorageHeader{}
+	}
+	if h.generation != 0 {
+		h.slot = 1 - h.slot
+	}
+	h.generation++
+	h.length = uint32(len(data))
+	h.checksum = crc32.ChecksumIEEE(data)
+
+	for offset, key := 0, h.firstKey(); offset < len(data); offset, key = offset+bpfStorageChunkSize, key+1 {
+		clear(chunk)
+		copy(chunk, data[offset:])
+		if err := bs.m.Update(key, chunk, ebpf.UpdateAny); err != nil {
+			return err
+		}
+	}
+	h.marshal(chunk)
+	return bs.m.Update(uint32(0), chunk, ebpf.UpdateAny)
+}
+
+func (bs *BPFMapStorage) Close() error {
+	return bs.m.Close()
+}
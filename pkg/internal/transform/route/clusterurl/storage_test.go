@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterurl
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/cilium/ebpf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeArrayMap mimics a BPF array map, whose entries are zero-initialized
+type fakeArrayMap struct {
+	entries map[uint32][]byte
+	updates int
+	// if positive, the updates fail after the given number of updates
+	failAfter int
+}
+
+func (m *fakeArrayMap) Lookup(key, valueOut any) error {
+	out := valueOut.(*[]byte)
+	clear(*out)
+	copy(*out, m.entries[key.(uint32)])
+	return nil
+}
+
+func (m *fakeArrayMap) Update(key, value any, _ ebpf.MapUpdateFlags) error {
+	if m.failAfter > 0 && m.updates >= m.failAfter {
+		return errors.New("update failed")
+	}
+	m.entries[key.(uint32)] = bytes.Clone(value.([]byte))
+	m.updates++
+	return nil
+}
+
+func (m *fakeArrayMap) Close() error {
+	return nil
+}
+
+func TestBPFMapStorage(t *testing.T) {
+	fake := &fakeArrayMap{entries: map[uint32][]byte{}}
+	storage := &BPFMapStorage{m: fake}
+
+	data, err := storage.Load()
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	// the data is split in several chunks, followed by the header
+	large := bytes.Repeat([]byte("0123456789"), 1000)
+	require.NoError(t, storage.Store(large))
+	assert.Equal(t, 4, fake.updates)
+	data, err = storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, large, data)
+
+	// replacing the data with a shorter one ignores the remaining chunks
+	require.NoError(t, storage.Store([]byte(`{"services":{}}`)))
+	data, err = storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, `{"services":{}}`, string(data))
+
+	// exactly filling the slot capacity
+	full := bytes.Repeat([]byte{'a'}, bpfStorageChunkSize*bpfStorageSlotChunks)
+	require.NoError(t, storage.Store(full))
+	data, err = storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, full, data)
+	require.NoError(t, storage.Store(full))
+	data, err = storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, full, data)
+}
+
+func TestBPFMapStorage_SizeLimit(t *testing.T) {
+	fake := &fakeArrayMap{entries: map[uint32][]byte{}}
+	storage := &BPFMapStorage{m: fake}
+	require.NoError(t, storage.Store([]byte("previous")))
+	fake.updates = 0
+
+	require.Error(t, storage.Store(make([]byte, bpfStorageChunkSize*bpfStorageSlotChunks+1)))
+	assert.Zero(t, fake.updates)
+	// the previous data is kept
+	data, err := storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "previous", string(data))
+}
+
+func TestBPFMapStorage_PartialWrite(t *testing.T) {
+	fake := &fakeArrayMap{entries: map[uint32][]byte{}}
+	storage := &BPFMapStorage{m: fake}
+	previous := bytes.Repeat([]byte("previous"), 1000)
+	require.NoError(t, storage.Store(previous))
+
+	// a write that is interrupted before updating the header keeps the previous data,
+	// as the new data is written in the other slot
+	fake.updates = 0
+	fake.failAfter = 2
+	require.Error(t, storage.Store(bytes.Repeat([]byte("new data"), 1000)))
+	data, err := storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, previous, data)
+
+	// the next write succeeds
+	fake.failAfter = 0
+	require.NoError(t, storage.Store([]byte("new data")))
+	data, err = storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "new data", string(data))
+}
+
+func TestBPFMapStorage_Corrupted(t *testing.T) {
+	fake := &fakeArrayMap{entries: map[uint32][]byte{}}
+	storage := &BPFMapStorage{m: fake}
+	require.NoError(t, storage.Store([]byte("previous")))
+
+	// data that does not match the checksum is not loaded
+	fake.entries[1][0] = 'P'
+	_, err := storage.Load()
+	require.Error(t, err)
+
+	// an invalid length is not loaded
+	header := bytes.Clone(fake.entries[0])
+	binary.LittleEndian.PutUint32(header[12:], bpfStorageChunkSize*bpfStorageMaxEntries)
+	fake.entries[0] = header
+	_, err = storage.Load()
+	require.Error(t, err)
+
+	// the data of the previous format, which started with its length, is not loaded
+	fake.entries[0] = append(binary.LittleEndian.AppendUint32(nil, 8), "previous"...)
+	_, err = storage.Load()
+	require.Error(t, err)
+
+	// a corrupted header is replaced on the next write
+	require.NoError(t, storage.Store([]byte("new data")))
+	data, err := storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "new data", string(data))
+}
+
+func TestFileStorage(t *testing.T) {
+	storage := &FileStorage{Path: filepath.Join(t.TempDir(), "routes", "templates.json")}
+	data, err := storage.Load()
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	require.NoError(t, storage.Store([]byte("first")))
+	require.NoError(t, storage.Store([]byte("second")))
+	data, err = storage.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(data))
+	assert.NoFileExists(t, storage.Path+".tmp")
+}
@@ -0,0 +1,210 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterurl // import "go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TrieStore keeps the PathTrie of each service, so all the instances of a service share
+// the same learned route templates, and optionally persists them across restarts.
+type TrieStore struct {
+	log            *slog.Logger
+	storage        Storage
+	maxCardinality int
+	replacement    byte
+
+	mu    sync.Mutex
+	tries map[string]*PathTrie
+	// last flushed data, to avoid rewriting the storage when nothing changed
+	flushed []byte
+}
+
+// storedTemplates is the format of the persisted data
+type storedTemplates struct {
+	Services map[string]*PathTrie `json:"services"`
+}
+
+// NewTrieStore creates a TrieStore. The storage can be nil, when the learned templates
+// don't need to be persisted.
+func NewTrieStore(storage Storage, maxCardinality int, replacement byte) *TrieStore {
+	return &TrieStore{
+		log:            slog.With("component", "clusterurl.TrieStore"),
+		storage:        storage,
+		maxCardinality: maxCardinality,
+		replacement:    replacement,
+		tries:          map[string]*PathTrie{},
+	}
+}
+
+// Get returns the PathTrie of the service, creating it if it does not exist
+func (ts *TrieStore) Get(service string) *PathTrie {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	trie, ok := ts.tries[service]
+	if !ok {
+		trie = NewPathTrie(ts.maxCardinality, ts.replacement)
+		ts.tries[service] = trie
+	}
+	return trie
+}
+
+// Load the persisted route templates. It must be invoked before any service trie is requested.
+func (ts *TrieStore) Load() error {
+	if ts.storage == nil {
+		return nil
+	}
+	data, err := ts.storage.Load()
+	if err != nil {
+		return fmt.Errorf("loading route templates: %w", err)
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	// the tries are decoded separately, as they need to be created with the store settings
+	var stored struct {
+		Services map[string]json.RawMessage `json:"services"`
+	}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("parsing route templates: %w", err)
+	}
+	for service, trieData := range stored.Services {
+		trie, ok := ts.tries[service]
+		if !ok {
+			trie = NewPathTrie(ts.maxCardinality, ts.replacement)
+		}
+		if err := trie.UnmarshalJSON(trieData); err != nil {
+			return fmt.Errorf("parsing route templates of service %s: %w", service, err)
+		}
+		ts.tries[service] = trie
+	}
+	ts.flushed = data
+	ts.log.Debug("loaded route templates", "services", len(stored.Services))
+	return nil
+}
+
+// Flush persists the route templates, if they changed since the last flush
+func (ts *TrieStore) Flush() error {
+	if ts.storage == nil {
+		return nil
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	data, err := json.Marshal(storedTemplates{Services: ts.tries})
+	if err != nil {
+		return fmt.Errorf("serializing route templates: %w", err)
+	}
+	if bytes.Equal(data, ts.flushed) {
+		return nil
+	}
+	if err := ts.storage.Store(data); err != nil {
+		return fmt.Errorf("storing route templates: %w", err)
+	}
+	ts.flushed = data
+	return nil
+}
+
+// FlushPeriodically persists the route templates every interval, and a last time when the
+// context is cancelled.
+func (ts *TrieStore) FlushPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if err := ts.Flush(); err != nil {
+				ts.log.Warn("can't flush route templates", "error", err)
+			}
+			if ts.storage != nil {
+				if err := ts.storage.Close(); err != nil {
+					ts.log.Debug("error closing route templates storage", "error", err)
+				}
+			}
+			return
+		case <-ticker.C:
+			if err := ts.Flush(); err != nil {
+				ts.log.Warn("can't flush route templates", "error", err)
+			}
+		}
+	}
+}
+
+// Templates returns the learned route templates of each service
+func (ts *TrieStore) Templates() map[string][]string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	templates := make(map[string][]string, len(ts.tries))
+	for service, trie := range ts.tries {
+		templates[service] = trie.Templates()
+	}
+	return templates
+}
+
+// Delete removes a learned route template from a service. If the template is empty,
+// all the templates of the service are removed. It returns false if the service or
+// the template do not exist.
+func (ts *TrieStore) Delete(service, template string) bool {
+	ts.mu.Lock()
+	trie, ok := ts.tries[service]
+	ts.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if template == "" {
+		// the trie is kept, as it is still referenced by the running service instances
+		trie.Reset()
+		return true
+	}
+	return trie.Delete(template)
+}
+
+// ServeHTTP allows inspecting the learned route templates with GET requests, optionally
+// filtered by the service query parameter, and deleting them with DELETE requests providing
+// the service and, optionally, the template query parameters.
+func (ts *TrieStore) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	service := req.URL.Query().Get("service")
+	switch req.Method {
+	case http.MethodGet:
+		templates := ts.Templates()
+		if service != "" {
+			serviceTemplates, ok := templates[service]
+			if !ok {
+				http.Error(rw, "service not found", http.StatusNotFound)
+				return
+			}
+			templates = map[string][]string{service: serviceTemplates}
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(rw).Encode(templates); err != nil {
+			ts.log.Debug("error writing route templates response", "error", err)
+		}
+	case http.MethodDelete:
+		if service == "" {
+			http.Error(rw, "missing service query parameter", http.StatusBadRequest)
+			return
+		}
+		if !ts.Delete(service, req.URL.Query().Get("template")) {
+			http.Error(rw, "route template not found", http.StatusNotFound)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.Header().Set("Allow", "GET, DELETE")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
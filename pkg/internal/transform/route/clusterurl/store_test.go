@@ -0,0 +1,147 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clusterurl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingStorage struct {
+	FileStorage
+	stores int
+}
+
+func (cs *countingStorage) Store(data []byte) error {
+	cs.stores++
+	return cs.FileStorage.Store(data)
+}
+
+func TestTrieStore_Persistence(t *testing.T) {
+	storage := &countingStorage{FileStorage: FileStorage{Path: filepath.Join(t.TempDir(), "routes", "templates.json")}}
+
+	store := NewTrieStore(storage, 2, '*')
+	require.NoError(t, store.Load())
+	users := store.Get("ns/users")
+	assert.Same(t, users, store.Get("ns/users"))
+	users.Insert("/users/1")
+	users.Insert("/users/2")
+	users.Insert("/users/3")
+	store.Get("orders").Insert("/orders")
+
+	require.NoError(t, store.Flush())
+	// nothing changed since the last flush
+	require.NoError(t, store.Flush())
+	assert.Equal(t, 1, storage.stores)
+
+	restarted := NewTrieStore(storage, 2, '*')
+	require.NoError(t, restarted.Load())
+	assert.Equal(t, map[string][]string{
+		"ns/users": {"/users/*"},
+		"orders":   {"/orders"},
+	}, restarted.Templates())
+	assert.Equal(t, "/users/*", restarted.Get("ns/users").Insert("/users/4"))
+
+	require.NoError(t, restarted.Flush())
+	assert.Equal(t, 1, storage.stores)
+}
+
+func TestTrieStore_LoadErrors(t *testing.T) {
+	store := NewTrieStore(&FileStorage{Path: filepath.Join(t.TempDir(), "missing.json")}, 2, '*')
+	require.NoError(t, store.Load())
+	assert.Empty(t, store.Templates())
+
+	file := filepath.Join(t.TempDir(), "corrupted.json")
+	require.NoError(t, os.WriteFile(file, []byte(`{"services":`), 0o644))
+	store = NewTrieStore(&FileStorage{Path: file}, 2, '*')
+	require.Error(t, store.Load())
+}
+
+type failingStorage struct{ FileStorage }
+
+func (*failingStorage) Store([]byte) error { return errors.New("disk full") }
+
+func TestTrieStore_FlushError(t *testing.T) {
+	store := NewTrieStore(&failingStorage{}, 2, '*')
+	store.Get("svc").Insert("/items")
+	require.Error(t, store.Flush())
+	// the failed data is stored again in the next flush
+	require.Error(t, store.Flush())
+}
+
+func TestTrieStore_FlushPeriodically(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "templates.json")
+	store := NewTrieStore(&FileStorage{Path: file}, 2, '*')
+	store.Get("svc").Insert("/items")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		store.FlushPeriodically(ctx, 10*time.Millisecond)
+		close(done)
+	}()
+	require.Eventually(t, func() bool {
+		_, err := os.Stat(file)
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the templates are flushed a last time on shutdown
+	store.Get("svc").Insert("/carts")
+	cancel()
+	<-done
+	restored := NewTrieStore(&FileStorage{Path: file}, 2, '*')
+	require.NoError(t, restored.Load())
+	assert.Equal(t, []string{"/carts", "/items"}, restored.Templates()["svc"])
+}
+
+func TestTrieStore_ServeHTTP(t *testing.T) {
+	store := NewTrieStore(nil, 2, '*')
+	store.Get("users").Insert("/users")
+	store.Get("users").Insert("/users/1")
+	store.Get("orders").Insert("/orders")
+
+	request := func(method, target string) *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		store.ServeHTTP(rw, httptest.NewRequest(method, target, nil))
+		return rw
+	}
+	templates := func(rw *httptest.ResponseRecorder) map[string][]string {
+		var out map[string][]string
+		require.NoError(t, json.NewDecoder(rw.Body).Decode(&out))
+		return out
+	}
+
+	rw := request(http.MethodGet, "/route-templates")
+	require.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, map[string][]string{
+		"users":  {"/users", "/users/1"},
+		"orders": {"/orders"},
+	}, templates(rw))
+
+	rw = request(http.MethodGet, "/route-templates?service=orders")
+	require.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, map[string][]string{"orders": {"/orders"}}, templates(rw))
+	assert.Equal(t, http.StatusNotFound, request(http.MethodGet, "/route-templates?service=carts").Code)
+
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodDelete, "/route-templates").Code)
+	assert.Equal(t, http.StatusNotFound, request(http.MethodDelete, "/route-templates?service=users&template=/carts").Code)
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/route-templates?service=users&template=/users/1").Code)
+	assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "/route-templates?service=orders").Code)
+	assert.Equal(t, map[string][]string{
+		"users":  {"/users"},
+		"orders": {},
+	}, store.Templates())
+
+	assert.Equal(t, http.StatusMethodNotAllowed, request(http.MethodPost, "/route-templates").Code)
+}
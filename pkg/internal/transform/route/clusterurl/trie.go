@@ -4,6 +4,8 @@
 package clusterurl // import "go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
 
 import (
+	"encoding/json"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -25,6 +27,9 @@ type PathNode struct {
 
 	// isWildcard indicates if this represents a "*" wildcard
 	isWildcard bool
+
+	// terminal indicates if an inserted path ends in this node
+	terminal bool
 }
 
 // PathTrie manages the dynamic collapsing trie structure
@@ -146,6 +151,7 @@ func (pt *PathTrie) insertSegments(segments []string) string {
 		result = append(result, segment)
 		current = child
 	}
+	current.terminal = true
 
 	return "/" + strings.Join(result, "/")
 }
@@ -192,6 +198,7 @@ func (pt *PathTrie) collapseNode(node *PathNode) {
 // mergeChildren merges children from source into target
 // This is called during collapse to combine all child paths
 func (pt *PathTrie) mergeChildren(target, source *PathNode) {
+	target.terminal = target.terminal || source.terminal
 	for segment, child := range source.children {
 		if existing, exists := target.children[segment]; exists {
 			// Child already exists, recursively merge their children
@@ -203,3 +210,130 @@ func (pt *PathTrie) mergeChildren(target, source *PathNode) {
 		}
 	}
 }
+
+// Templates returns the normalized paths that have been inserted into the trie
+func (pt *PathTrie) Templates() []string {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	templates := []string{}
+	var walk func(node *PathNode, prefix string)
+	walk = func(node *PathNode, prefix string) {
+		if node.terminal {
+			templates = append(templates, prefix)
+		}
+		for segment, child := range node.children {
+			walk(child, prefix+"/"+segment)
+		}
+	}
+	for segment, child := range pt.root.children {
+		walk(child, "/"+segment)
+	}
+	sort.Strings(templates)
+	return templates
+}
+
+// Delete removes a template, as returned by the Templates method, from the trie. The nodes
+// that are not part of any other template are removed, so their segments are learned again.
+// It returns false if the template is not in the trie.
+func (pt *PathTrie) Delete(template string) bool {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	segments := strings.Split(strings.Trim(template, "/"), "/")
+	path := []*PathNode{pt.root}
+	for _, segment := range segments {
+		child, ok := path[len(path)-1].children[segment]
+		if !ok {
+			return false
+		}
+		path = append(path, child)
+	}
+	node := path[len(path)-1]
+	if !node.terminal {
+		return false
+	}
+	node.terminal = false
+
+	// prune the branch up to the first node that is still in use
+	for i := len(path) - 1; i > 0; i-- {
+		node, parent := path[i], path[i-1]
+		if node.terminal || len(node.children) > 0 {
+			break
+		}
+		delete(parent.children, segments[i-1])
+		parent.cardinality--
+		// a node without children can learn its segments again
+		if len(parent.children) == 0 {
+			parent.collapsed = false
+		}
+	}
+	return true
+}
+
+// Reset removes all the templates from the trie
+func (pt *PathTrie) Reset() {
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.root = &PathNode{children: make(map[string]*PathNode)}
+}
+
+// pathNodeJSON is the serialized form of a PathNode. The segment is the key in the
+// children map of its parent, and the cardinality is the number of children
+type pathNodeJSON struct {
+	Collapsed bool                     `json:"collapsed,omitempty"`
+	Terminal  bool                     `json:"terminal,omitempty"`
+	Children  map[string]*pathNodeJSON `json:"children,omitempty"`
+}
+
+func (pt *PathTrie) toJSON(node *PathNode) *pathNodeJSON {
+	out := &pathNodeJSON{Collapsed: node.collapsed, Terminal: node.terminal}
+	if len(node.children) > 0 {
+		out.Children = make(map[string]*pathNodeJSON, len(node.children))
+		for segment, child := range node.children {
+			out.Children[segment] = pt.toJSON(child)
+		}
+	}
+	return out
+}
+
+func (pt *PathTrie) fromJSON(segment string, in *pathNodeJSON) *PathNode {
+	node := &PathNode{
+		segment:     segment,
+		children:    make(map[string]*PathNode, len(in.Children)),
+		collapsed:   in.Collapsed,
+		cardinality: len(in.Children),
+		isWildcard:  segment == pt.replaceWith,
+		terminal:    in.Terminal,
+	}
+	for childSegment, child := range in.Children {
+		if child != nil {
+			node.children[childSegment] = pt.fromJSON(childSegment, child)
+		}
+	}
+	return node
+}
+
+// MarshalJSON serializes the trie nodes, so the learned templates can be persisted
+func (pt *PathTrie) MarshalJSON() ([]byte, error) {
+	pt.mu.RLock()
+	defer pt.mu.RUnlock()
+
+	return json.Marshal(pt.toJSON(pt.root))
+}
+
+// UnmarshalJSON replaces the trie nodes by the serialized ones. The max cardinality and the
+// wildcard character of the trie are kept.
+func (pt *PathTrie) UnmarshalJSON(data []byte) error {
+	var root pathNodeJSON
+	if err := json.Unmarshal(data, &root); err != nil {
+		return err
+	}
+
+	pt.mu.Lock()
+	defer pt.mu.Unlock()
+
+	pt.root = pt.fromJSON("", &root)
+	return nil
+}
@@ -4,11 +4,13 @@
 package clusterurl
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPathTrie_BasicInsertAndLookup(t *testing.T) {
@@ -246,3 +248,57 @@ func (pt *PathTrie) lookupSegments(segments []string) string {
 
 	return "/" + strings.Join(result, "/")
 }
+
+func TestPathTrie_Templates(t *testing.T) {
+	trie := NewPathTrie(2, '*')
+	trie.Insert("/api/users")
+	trie.Insert("/api/users/1")
+	trie.Insert("/api/users/2")
+	trie.Insert("/api/users/3")
+	trie.Insert("/health")
+
+	assert.Equal(t, []string{"/api/users", "/api/users/*", "/health"}, trie.Templates())
+	assert.Empty(t, NewPathTrie(2, '*').Templates())
+}
+
+func TestPathTrie_Delete(t *testing.T) {
+	trie := NewPathTrie(2, '*')
+	trie.Insert("/api/users/1")
+	trie.Insert("/api/users/2")
+	trie.Insert("/api/users/3")
+	trie.Insert("/api/users")
+	assert.Equal(t, "/api/users/*", trie.Insert("/api/users/4"))
+
+	assert.False(t, trie.Delete("/api/unknown"))
+	assert.False(t, trie.Delete("/api"))
+
+	// the collapsed segment is learned again after deleting its template
+	assert.True(t, trie.Delete("/api/users/*"))
+	assert.Equal(t, []string{"/api/users"}, trie.Templates())
+	assert.Equal(t, "/api/users/5", trie.Insert("/api/users/5"))
+
+	assert.True(t, trie.Delete("/api/users/5"))
+	assert.True(t, trie.Delete("/api/users"))
+	assert.Empty(t, trie.Templates())
+	assert.Empty(t, trie.root.children)
+}
+
+func TestPathTrie_JSON(t *testing.T) {
+	trie := NewPathTrie(2, '*')
+	trie.Insert("/api/users/1/orders")
+	trie.Insert("/api/users/2/orders")
+	trie.Insert("/api/users/3/orders")
+	trie.Insert("/api/items")
+
+	data, err := json.Marshal(trie)
+	require.NoError(t, err)
+
+	restored := NewPathTrie(2, '*')
+	require.NoError(t, json.Unmarshal(data, restored))
+	assert.Equal(t, trie.Templates(), restored.Templates())
+
+	// the restored trie keeps collapsing the same segments
+	assert.Equal(t, "/api/users/*/orders", restored.Insert("/api/users/4/orders"))
+	assert.Equal(t, "/api/items/1", restored.Insert("/api/items/1"))
+	assert.Equal(t, "/api/*", restored.Insert("/api/carts"))
+}
@@ -0,0 +1,470 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest // import "go.opentelemetry.io/obi/pkg/internal/transform/route/harvest"
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+	"go.opentelemetry.io/obi/pkg/internal/fastelf"
+	"go.opentelemetry.io/obi/pkg/internal/goexec"
+)
+
+const (
+	// maximum distance, in bytes, between the instruction loading the address of a string
+	// literal and the instruction loading its length
+	goStringLenWindow = 32
+	// maximum distance, in bytes, between the instruction loading the address of a route
+	// and the call to the router function that receives it
+	goRouterCallWindow = 256
+	// longer string literals are not considered routes
+	goMaxRouteLen = 512
+)
+
+// goRouteSyntax is the route template syntax accepted by a Go router
+type goRouteSyntax uint8
+
+const (
+	// gin and echo: /users/:id/*path
+	goColonSyntax goRouteSyntax = iota + 1
+	// chi and gorilla/mux: /users/{id}, /users/{id:[0-9]+}, /files/*
+	goBraceSyntax
+	// net/http ServeMux since Go 1.22: GET /users/{id}, /files/{path...}, /{$}
+	goServeMuxSyntax
+)
+
+type goRouter struct {
+	name string
+	// fully qualified names of the router functions that receive the routes as arguments
+	functions []string
+	syntax    goRouteSyntax
+}
+
+// goMethods returns the fully qualified names of the given methods for each package and receiver
+func goMethods(pkgs, receivers, methods []string) []string {
+	names := make([]string, 0, len(pkgs)*len(receivers)*len(methods))
+	for _, pkg := range pkgs {
+		for _, recv := range receivers {
+			for _, method := range methods {
+				names = append(names, pkg+".("+recv+")."+method)
+			}
+		}
+	}
+	return names
+}
+
+var goRouters = []goRouter{
+	{
+		name: "gin",
+		// the HTTP method functions might be inlined, so we also look for the internal handle function
+		functions: goMethods([]string{"github.com/gin-gonic/gin"}, []string{"*RouterGroup"},
+			[]string{"handle", "Handle", "Any", "Match", "GET", "POST", "DELETE", "PATCH", "PUT", "OPTIONS", "HEAD"}),
+		syntax: goColonSyntax,
+	},
+	{
+		name: "echo",
+		functions: goMethods([]string{"github.com/labstack/echo", "github.com/labstack/echo/v4"}, []string{"*Echo", "*Group"},
+			[]string{"add", "Add", "Any", "Match", "CONNECT", "DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT", "TRACE"}),
+		syntax: goColonSyntax,
+	},
+	{
+		name: "chi",
+		functions: goMethods([]string{"github.com/go-chi/chi", "github.com/go-chi/chi/v5"}, []string{"*Mux"},
+			[]string{"handle", "Handle", "HandleFunc", "Method", "MethodFunc",
+				"Connect", "Delete", "Get", "Head", "Options", "Patch", "Post", "Put", "Trace"}),
+		syntax: goBraceSyntax,
+	},
+	{
+		name: "gorilla/mux",
+		functions: append(
+			goMethods([]string{"github.com/gorilla/mux"}, []string{"*Router"}, []string{"Handle", "HandleFunc", "Path", "PathPrefix"}),
+			goMethods([]string{"github.com/gorilla/mux"}, []string{"*Route"}, []string{"Path", "PathPrefix"})...),
+		syntax: goBraceSyntax,
+	},
+	{
+		name: "net/http",
+		functions: append(
+			goMethods([]string{"net/http"}, []string{"*ServeMux"}, []string{"register", "Handle", "HandleFunc"}),
+			"net/http.Handle", "net/http.HandleFunc"),
+		syntax: goServeMuxSyntax,
+	},
+}
+
+var (
+	goStaticSegment = regexp.MustCompile(`^[A-Za-z0-9\-._~]+$`)
+	goColonParam    = regexp.MustCompile(`^:\w+$`)
+	goCatchAll      = regexp.MustCompile(`^\*\w*$`)
+	goBraceParam    = regexp.MustCompile(`^\{(\w+)(?::[^{}/]+)?}$`)
+	goServeMuxParam = regexp.MustCompile(`^\{(\w+)(\.\.\.)?}$`)
+	goHTTPMethod    = regexp.MustCompile(`^(GET|HEAD|POST|PUT|PATCH|DELETE|CONNECT|OPTIONS|TRACE) +`)
+)
+
+// goStringRef is a string literal whose address and length are loaded by the code,
+// e.g. to pass it as a function argument
+type goStringRef struct {
+	// offset of the instruction loading the string address in the .text section
+	offset int
+	addr   uint64
+	// candidate lengths loaded by the instructions following the address load
+	lengths []uint64
+}
+
+// goCall is a direct function call
+type goCall struct {
+	// offset of the call instruction in the .text section
+	offset int
+	target uint64
+}
+
+// goCodeScanner finds the string references and calls in the machine code of an architecture
+type goCodeScanner interface {
+	stringRefs(text []byte, textAddr uint64, isString func(addr uint64) bool) []goStringRef
+	calls(text []byte, textAddr uint64, from, to int) []goCall
+}
+
+// GoRouteExtractor finds the routes of a Go executable. Go does not terminate the string
+// literals, which are stored back to back in the .rodata section, so the routes can't be
+// reliably delimited from the section data. Instead, the extractor looks in the code for the
+// string literals (address and length) that are passed as arguments to the functions of the
+// routers found in the symbol table, and validates them against the syntax of each router.
+type GoRouteExtractor struct {
+	log *slog.Logger
+	// router functions by entry address
+	routers map[uint64]*goRouter
+
+	text       []byte
+	textAddr   uint64
+	rodata     []byte
+	rodataAddr uint64
+	scanner    goCodeScanner
+
+	routes []RoutePattern
+}
+
+// findGoRouters returns the functions of the supported routers, by entry address
+func findGoRouters(elfF *elf.File) (map[uint64]*goRouter, error) {
+	byName := map[string]*goRouter{}
+	var names []string
+	for i := range goRouters {
+		for _, fn := range goRouters[i].functions {
+			byName[fn] = &goRouters[i]
+			names = append(names, fn)
+		}
+	}
+	functions, err := goexec.FindFunctions(elfF, names...)
+	if err != nil {
+		return nil, err
+	}
+
+	routers := map[uint64]*goRouter{}
+	for name, addr := range functions {
+		// FindFunctions matches by prefix, so e.g. Handle would also match HandleFunc or
+		// any function of a nested package
+		if r, ok := byName[name]; ok {
+			routers[addr] = r
+		}
+	}
+	return routers, nil
+}
+
+func newGoRouteExtractor(ctx *fastelf.ElfContext, routers map[uint64]*goRouter) (*GoRouteExtractor, error) {
+	e := &GoRouteExtractor{
+		log:        slog.With("component", "route.harvester.go"),
+		routers:    routers,
+		text:       ctx.SectionData(".text"),
+		textAddr:   ctx.SectionAddress(".text"),
+		rodata:     ctx.SectionData(".rodata"),
+		rodataAddr: ctx.SectionAddress(".rodata"),
+	}
+	if e.text == nil || e.rodata == nil {
+		return nil, errors.New("missing .text or .rodata sections")
+	}
+
+	switch elf.Machine(ctx.Hdr.Machine) {
+	case elf.EM_X86_64:
+		e.scanner = amd64Scanner{}
+	case elf.EM_AARCH64:
+		e.scanner = arm64Scanner{}
+	default:
+		return nil, fmt.Errorf("unsupported architecture: %s", elf.Machine(ctx.Hdr.Machine))
+	}
+	return e, nil
+}
+
+func (e *GoRouteExtractor) isRodata(addr uint64) bool {
+	return addr >= e.rodataAddr && addr < e.rodataAddr+uint64(len(e.rodata))
+}
+
+// rodataString returns the string of the given length at the address, or false if it
+// exceeds the .rodata section
+func (e *GoRouteExtractor) rodataString(addr, length uint64) (string, bool) {
+	start := addr - e.rodataAddr
+	if start+length > uint64(len(e.rodata)) {
+		return "", false
+	}
+	return string(e.rodata[start : start+length]), true
+}
+
+// routerCall returns the first router function called after the string reference, if any
+func (e *GoRouteExtractor) routerCall(ref *goStringRef) *goRouter {
+	for _, call := range e.scanner.calls(e.text, e.textAddr, ref.offset, ref.offset+goRouterCallWindow) {
+		if r, ok := e.routers[call.target]; ok {
+			return r
+		}
+	}
+	return nil
+}
+
+func (e *GoRouteExtractor) Scan() {
+	for _, ref := range e.scanner.stringRefs(e.text, e.textAddr, e.isRodata) {
+		// quick check before looking for the router calls
+		if c := e.rodata[ref.addr-e.rodataAddr]; c != '/' && (c < 'A' || c > 'Z') {
+			continue
+		}
+		router := e.routerCall(&ref)
+		if router == nil {
+			continue
+		}
+		for _, length := range ref.lengths {
+			str, ok := e.rodataString(ref.addr, length)
+			if !ok {
+				continue
+			}
+			if template, ok := goRouteTemplate(str, router.syntax); ok {
+				e.log.Debug("found route", "router", router.name, "route", str)
+				e.routes = append(e.routes, RoutePattern{
+					Method: goRouteMethod(str),
+					Path:   template,
+				})
+				break
+			}
+		}
+	}
+}
+
+func (e *GoRouteExtractor) GetRoutes() []RoutePattern {
+	return e.routes
+}
+
+func (e *GoRouteExtractor) GetHarvestedRoutes() []string {
+	dedup := map[string]struct{}{}
+	for _, r := range e.routes {
+		if r.Path != "/" {
+			dedup[r.Path] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(dedup))
+	for k := range dedup {
+		result = append(result, k)
+	}
+	return result
+}
+
+func goRouteMethod(route string) string {
+	if m := goHTTPMethod.FindStringSubmatch(route); m != nil {
+		return m[1]
+	}
+	return "ALL"
+}
+
+// goRouteTemplate validates the route towards the syntax of the router and converts it to
+// a path template, where the named parameters are kept and the catch-all parameters are
+// replaced by the * wildcard.
+// Example: "GET /files/{dir}/{path...}" -> "/files/{dir}/*"
+func goRouteTemplate(route string, syntax goRouteSyntax) (string, bool) {
+	if syntax == goServeMuxSyntax {
+		if m := goHTTPMethod.FindString(route); m != "" {
+			route = route[len(m):]
+		}
+	}
+	if !strings.HasPrefix(route, "/") {
+		return "", false
+	}
+
+	segments := strings.Split(route[1:], "/")
+	keep := make([]string, 0, len(segments))
+	for i, s := range segments {
+		last := i == len(segments)-1
+		switch {
+		case s == "":
+			// only trailing slashes are allowed
+			if !last {
+				return "", false
+			}
+		case goStaticSegment.MatchString(s):
+			keep = append(keep, s)
+		case syntax == goColonSyntax && goColonParam.MatchString(s):
+			keep = append(keep, s)
+		case syntax != goServeMuxSyntax && goCatchAll.MatchString(s) && last:
+			keep = append(keep, "*")
+		case syntax == goBraceSyntax && goBraceParam.MatchString(s):
+			keep = append(keep, "{"+goBraceParam.FindStringSubmatch(s)[1]+"}")
+		case syntax == goServeMuxSyntax && s == "{$}" && last:
+			// exact match of the path with a trailing slash
+		case syntax == goServeMuxSyntax && goServeMuxParam.MatchString(s):
+			m := goServeMuxParam.FindStringSubmatch(s)
+			if m[2] == "" {
+				keep = append(keep, "{"+m[1]+"}")
+			} else if last {
+				keep = append(keep, "*")
+			} else {
+				return "", false
+			}
+		default:
+			return "", false
+		}
+	}
+	return "/" + strings.Join(keep, "/"), true
+}
+
+// amd64Scanner looks for the Go register-based calling convention patterns, e.g.:
+//
+//	LEAQ go:string."/users/:id"(SB), BX  // 48 8d 1d <rel32>
+//	MOVL $10, CX                          // b9 0a 00 00 00
+//	CALL github.com/gin-gonic/gin.(*RouterGroup).handle(SB)  // e8 <rel32>
+type amd64Scanner struct{}
+
+func (amd64Scanner) stringRefs(text []byte, textAddr uint64, isString func(addr uint64) bool) []goStringRef {
+	var refs []goStringRef
+	for i := 0; i+7 <= len(text); i++ {
+		// REX.W LEA with RIP-relative addressing
+		if (text[i] != 0x48 && text[i] != 0x4c) || text[i+1] != 0x8d || text[i+2]&0xc7 != 0x05 {
+			continue
+		}
+		next := i + 7
+		addr := textAddr + uint64(next) + uint64(int64(int32(binary.LittleEndian.Uint32(text[i+3:]))))
+		if !isString(addr) {
+			continue
+		}
+		ref := goStringRef{offset: i, addr: addr}
+		// MOVL $imm32, reg
+		for j := next; j+5 <= len(text) && j < next+goStringLenWindow; j++ {
+			if text[j] >= 0xb8 && text[j] <= 0xbf {
+				if length := uint64(binary.LittleEndian.Uint32(text[j+1:])); length > 0 && length <= goMaxRouteLen {
+					ref.lengths = append(ref.lengths, length)
+				}
+			}
+		}
+		if len(ref.lengths) > 0 {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func (amd64Scanner) calls(text []byte, textAddr uint64, from, to int) []goCall {
+	var calls []goCall
+	for i := from; i+5 <= len(text) && i < to; i++ {
+		if text[i] == 0xe8 {
+			target := textAddr + uint64(i+5) + uint64(int64(int32(binary.LittleEndian.Uint32(text[i+1:]))))
+			calls = append(calls, goCall{offset: i, target: target})
+		}
+	}
+	return calls
+}
+
+// arm64Scanner looks for the Go register-based calling convention patterns, e.g.:
+//
+//	ADRP go:string."/users/:id"(SB), R1
+//	ADD  $lo12, R1, R1
+//	MOVD $10, R2   // MOVZ
+//	BL   github.com/gin-gonic/gin.(*RouterGroup).handle(SB)
+type arm64Scanner struct{}
+
+const arm64InstructionSize = 4
+
+func (arm64Scanner) stringRefs(text []byte, textAddr uint64, isString func(addr uint64) bool) []goStringRef {
+	var refs []goStringRef
+	for i := 0; i+2*arm64InstructionSize <= len(text); i += arm64InstructionSize {
+		adrp := binary.LittleEndian.Uint32(text[i:])
+		if adrp&0x9f000000 != 0x90000000 {
+			continue
+		}
+		add := binary.LittleEndian.Uint32(text[i+arm64InstructionSize:])
+		// 64-bit ADD immediate, without shift, whose source is the ADRP destination
+		rd := adrp & 0x1f
+		if add&0xffc00000 != 0x91000000 || (add>>5)&0x1f != rd {
+			continue
+		}
+		imm := int64(((adrp>>5)&0x7ffff)<<2|(adrp>>29)&0x3) << 43 >> 31 // sign-extended, << 12
+		pc := textAddr + uint64(i)
+		addr := (pc &^ 0xfff) + uint64(imm) + uint64((add>>10)&0xfff)
+		if !isString(addr) {
+			continue
+		}
+		ref := goStringRef{offset: i, addr: addr}
+		next := i + 2*arm64InstructionSize
+		for j := next; j+arm64InstructionSize <= len(text) && j < next+goStringLenWindow; j += arm64InstructionSize {
+			// MOVZ without shift, 32 or 64 bits
+			if ins := binary.LittleEndian.Uint32(text[j:]); ins&0x7fe00000 == 0x52800000 {
+				if length := uint64((ins >> 5) & 0xffff); length > 0 && length <= goMaxRouteLen {
+					ref.lengths = append(ref.lengths, length)
+				}
+			}
+		}
+		if len(ref.lengths) > 0 {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func (arm64Scanner) calls(text []byte, textAddr uint64, from, to int) []goCall {
+	var calls []goCall
+	for i := from; i+arm64InstructionSize <= len(text) && i < to; i += arm64InstructionSize {
+		if ins := binary.LittleEndian.Uint32(text[i:]); ins&0xfc000000 == 0x94000000 {
+			offset := int64(ins&0x3ffffff) << 38 >> 36 // sign-extended, * 4
+			calls = append(calls, goCall{offset: i, target: textAddr + uint64(int64(i)+offset)})
+		}
+	}
+	return calls
+}
+
+// ExtractGoRoutesFromFile returns the routes registered in the supported routers by the
+// Go executable. It does not return any route if the executable does not use any of them.
+// The routes are partial, as the prefixes of the router groups and sub-routers are
+// registered separately and can't be resolved from the machine code.
+func ExtractGoRoutesFromFile(path string) (*RouteHarvesterResult, error) {
+	elfF, err := elf.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening executable: %w", err)
+	}
+	defer elfF.Close()
+
+	routers, err := findGoRouters(elfF)
+	if err != nil {
+		return nil, fmt.Errorf("finding router functions: %w", err)
+	}
+	if len(routers) == 0 {
+		return &RouteHarvesterResult{Kind: PartialRoutes}, nil
+	}
+
+	ctx, err := fastelf.NewElfContextFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer ctx.Close()
+
+	extractor, err := newGoRouteExtractor(ctx, routers)
+	if err != nil {
+		return nil, err
+	}
+	extractor.Scan()
+
+	return &RouteHarvesterResult{
+		Routes: extractor.GetHarvestedRoutes(),
+		Kind:   PartialRoutes,
+	}, nil
+}
+
+func ExtractGoRoutes(pid app.PID) (*RouteHarvesterResult, error) {
+	return ExtractGoRoutesFromFile(fmt.Sprintf("/proc/%d/exe", pid))
+}
@@ -0,0 +1,194 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"log/slog"
+	"net/http"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/internal/goexec"
+)
+
+func TestGoRouteTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    string
+		syntax   goRouteSyntax
+		expected string
+		valid    bool
+	}{
+		{name: "gin params", route: "/users/:id/posts/:postId", syntax: goColonSyntax, expected: "/users/:id/posts/:postId", valid: true},
+		{name: "gin catch-all", route: "/static/*filepath", syntax: goColonSyntax, expected: "/static/*", valid: true},
+		{name: "gin trailing slash", route: "/users/", syntax: goColonSyntax, expected: "/users", valid: true},
+		{name: "gin braces", route: "/users/{id}", syntax: goColonSyntax},
+		{name: "chi params", route: "/users/{id}/files/*", syntax: goBraceSyntax, expected: "/users/{id}/files/*", valid: true},
+		{name: "gorilla regex", route: "/articles/{category}/{id:[0-9]+}", syntax: goBraceSyntax, expected: "/articles/{category}/{id}", valid: true},
+		{name: "chi colons", route: "/users/:id", syntax: goBraceSyntax},
+		{name: "servemux method", route: "GET /items/{id}", syntax: goServeMuxSyntax, expected: "/items/{id}", valid: true},
+		{name: "servemux wildcard", route: "/files/{dir}/{path...}", syntax: goServeMuxSyntax, expected: "/files/{dir}/*", valid: true},
+		{name: "servemux exact", route: "POST /orders/{$}", syntax: goServeMuxSyntax, expected: "/orders", valid: true},
+		{name: "servemux wildcard not last", route: "/files/{path...}/x", syntax: goServeMuxSyntax},
+		{name: "servemux method in other routers", route: "GET /items", syntax: goBraceSyntax},
+		{name: "servemux host", route: "example.com/items", syntax: goServeMuxSyntax},
+		{name: "empty segment", route: "/a//b", syntax: goColonSyntax},
+		{name: "not a route", route: "/invalid path", syntax: goColonSyntax},
+		{name: "no slash", route: "application/json", syntax: goColonSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, valid := goRouteTemplate(tt.route, tt.syntax)
+			assert.Equal(t, tt.valid, valid)
+			assert.Equal(t, tt.expected, template)
+		})
+	}
+}
+
+const (
+	testTextAddr   = 0x401000
+	testRodataAddr = 0x800000
+)
+
+var testRouters = map[uint64]*goRouter{
+	0x402000: &goRouters[0], // gin
+	0x403000: &goRouters[4], // net/http
+}
+
+// Go strings are stored back to back, without separators
+var testRodata = []byte("GET /items/{id}/users/:id/users/:idpostapplication/json")
+
+type testCode struct {
+	code []byte
+}
+
+func (c *testCode) pc() uint64 {
+	return testTextAddr + uint64(len(c.code))
+}
+
+func (c *testCode) le32(v uint32) {
+	c.code = binary.LittleEndian.AppendUint32(c.code, v)
+}
+
+// amd64: LEAQ str(SB), BX; MOVL $length, CX; NOPs; CALL fn
+func (c *testCode) amd64Call(str, length int, fn uint64) {
+	rel := int64(testRodataAddr+str) - int64(c.pc()+7)
+	c.code = append(c.code, 0x48, 0x8d, 0x1d)
+	c.le32(uint32(int32(rel)))
+	c.code = append(c.code, 0xb9)
+	c.le32(uint32(length))
+	c.code = append(c.code, 0x90, 0x90, 0x90)
+	rel = int64(fn) - int64(c.pc()+5)
+	c.code = append(c.code, 0xe8)
+	c.le32(uint32(int32(rel)))
+}
+
+// arm64: ADRP str, R1; ADD $lo12, R1, R1; MOVD $length, R2; BL fn
+func (c *testCode) arm64Call(str, length int, fn uint64) {
+	addr := uint64(testRodataAddr + str)
+	page := int64(addr&^0xfff-c.pc()&^0xfff) >> 12
+	c.le32(0x90000001 | uint32(page&0x3)<<29 | uint32(page>>2&0x7ffff)<<5)
+	c.le32(0x91000021 | uint32(addr&0xfff)<<10)
+	c.le32(0xd2800002 | uint32(length)<<5)
+	c.le32(0x94000000 | uint32((int64(fn)-int64(c.pc()))>>2)&0x3ffffff)
+}
+
+func testExtractor(scanner goCodeScanner, text []byte) *GoRouteExtractor {
+	return &GoRouteExtractor{
+		log:        slog.Default(),
+		routers:    testRouters,
+		text:       text,
+		textAddr:   testTextAddr,
+		rodata:     testRodata,
+		rodataAddr: testRodataAddr,
+		scanner:    scanner,
+	}
+}
+
+func TestGoRouteExtractor(t *testing.T) {
+	expected := []RoutePattern{
+		{Method: "GET", Path: "/items/{id}"},
+		{Method: "ALL", Path: "/users/:id"},
+	}
+
+	t.Run("amd64", func(t *testing.T) {
+		code := testCode{}
+		code.amd64Call(0, 15, 0x403000)  // net/http: GET /items/{id}
+		code.amd64Call(15, 10, 0x402000) // gin: /users/:id
+		code.amd64Call(25, 10, 0x403000) // net/http: /users/:id is not valid
+		code.amd64Call(39, 16, 0x402000) // gin: application/json is not valid
+		code.amd64Call(15, 10, 0x404000) // not a router function
+		e := testExtractor(amd64Scanner{}, code.code)
+		e.Scan()
+		assert.Equal(t, expected, e.GetRoutes())
+	})
+
+	t.Run("arm64", func(t *testing.T) {
+		code := testCode{}
+		code.arm64Call(0, 15, 0x403000)
+		code.arm64Call(15, 10, 0x402000)
+		code.arm64Call(25, 10, 0x403000)
+		code.arm64Call(39, 16, 0x402000)
+		code.arm64Call(15, 10, 0x404000)
+		e := testExtractor(arm64Scanner{}, code.code)
+		e.Scan()
+		assert.Equal(t, expected, e.GetRoutes())
+	})
+}
+
+func registerTestRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /obi-harvest/items/{itemId}", func(http.ResponseWriter, *http.Request) {})
+	mux.HandleFunc("/obi-harvest/files/{path...}", func(http.ResponseWriter, *http.Request) {})
+}
+
+func TestExtractGoRoutesFromFile(t *testing.T) {
+	if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
+		t.Skip("unsupported architecture")
+	}
+	// makes sure the routes are registered in the test executable
+	registerTestRoutes(http.NewServeMux())
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+
+	result, err := ExtractGoRoutesFromFile(exe)
+	require.NoError(t, err)
+	assert.Equal(t, PartialRoutes, result.Kind)
+	assert.Contains(t, result.Routes, "/obi-harvest/items/{itemId}")
+	assert.Contains(t, result.Routes, "/obi-harvest/files/*")
+}
+
+func TestFindGoRouters(t *testing.T) {
+	// makes sure the router functions are linked in the test executable
+	registerTestRoutes(http.NewServeMux())
+	http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}).ServeHTTP(nil, nil)
+
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	elfF, err := elf.Open(exe)
+	require.NoError(t, err)
+	defer elfF.Close()
+
+	routers, err := findGoRouters(elfF)
+	require.NoError(t, err)
+
+	functions, err := goexec.FindFunctions(elfF, "net/http.")
+	require.NoError(t, err)
+
+	// registration functions
+	require.Contains(t, functions, "net/http.(*ServeMux).HandleFunc")
+	assert.Contains(t, routers, functions["net/http.(*ServeMux).HandleFunc"])
+	// functions sharing a prefix with the registration functions
+	for _, fn := range []string{"net/http.HandlerFunc.ServeHTTP", "net/http.(*ServeMux).Handler"} {
+		if addr, ok := functions[fn]; ok {
+			assert.NotContains(t, routers, addr, fn)
+		}
+	}
+}
@@ -31,6 +31,10 @@ type RouteHarvester struct {
 	// testing related
 	javaExtractRoutes func(pid app.PID) (*RouteHarvesterResult, error)
 	nodeExtractRoutes func(pid app.PID) (*RouteHarvesterResult, error)
+	pyExtractRoutes   func(pid app.PID) (*RouteHarvesterResult, error)
+	goExtractRoutes   func(pid app.PID) (*RouteHarvesterResult, error)
+	rbExtractRoutes   func(pid app.PID) (*RouteHarvesterResult, error)
+	phpExtractRoutes  func(pid app.PID) (*RouteHarvesterResult, error)
 }
 
 type RouteHarvesterResultKind uint8
@@ -63,6 +67,18 @@ func NewRouteHarvester(cfg *services.RouteHarvestingConfig, disabled []services.
 		if lang == services.RouteHarvesterLanguageNodejs {
 			dMap[svc.InstrumentableNodejs] = struct{}{}
 		}
+		if lang == services.RouteHarvesterLanguageGo {
+			dMap[svc.InstrumentableGolang] = struct{}{}
+		}
+		if lang == services.RouteHarvesterLanguagePython {
+			dMap[svc.InstrumentablePython] = struct{}{}
+		}
+		if lang == services.RouteHarvesterLanguageRuby {
+			dMap[svc.InstrumentableRuby] = struct{}{}
+		}
+		if lang == services.RouteHarvesterLanguagePHP {
+			dMap[svc.InstrumentablePHP] = struct{}{}
+		}
 	}
 
 	h := &RouteHarvester{
@@ -76,6 +92,10 @@ func NewRouteHarvester(cfg *services.RouteHarvestingConfig, disabled []services.
 
 	h.javaExtractRoutes = h.java.ExtractRoutes
 	h.nodeExtractRoutes = ExtractNodejsRoutes
+	h.pyExtractRoutes = ExtractPythonRoutes
+	h.goExtractRoutes = ExtractGoRoutes
+	h.rbExtractRoutes = ExtractRubyRoutes
+	h.phpExtractRoutes = ExtractPHPRoutes
 
 	return h
 }
@@ -135,6 +155,58 @@ func (h *RouteHarvester) HarvestRoutes(fileInfo *exec.FileInfo) (*RouteHarvester
 				}
 				h.log.Debug("found node js application routes", "routes", r.Routes)
 
+				resultChan <- result{r: r}
+			} else {
+				resultChan <- result{r: nil}
+			}
+		case svc.InstrumentablePython:
+			if _, ok := h.disabled[svc.InstrumentablePython]; !ok {
+				r, err := h.pyExtractRoutes(fileInfo.Pid)
+				if err != nil {
+					resultChan <- result{err: err}
+					return
+				}
+				h.log.Debug("found python application routes", "routes", r.Routes)
+
+				resultChan <- result{r: r}
+			} else {
+				resultChan <- result{r: nil}
+			}
+		case svc.InstrumentableGolang:
+			if _, ok := h.disabled[svc.InstrumentableGolang]; !ok {
+				r, err := h.goExtractRoutes(fileInfo.Pid)
+				if err != nil {
+					resultChan <- result{err: err}
+					return
+				}
+				h.log.Debug("found go application routes", "routes", r.Routes)
+
+				resultChan <- result{r: r}
+			} else {
+				resultChan <- result{r: nil}
+			}
+		case svc.InstrumentableRuby:
+			if _, ok := h.disabled[svc.InstrumentableRuby]; !ok {
+				r, err := h.rbExtractRoutes(fileInfo.Pid)
+				if err != nil {
+					resultChan <- result{err: err}
+					return
+				}
+				h.log.Debug("found ruby application routes", "routes", r.Routes)
+
+				resultChan <- result{r: r}
+			} else {
+				resultChan <- result{r: nil}
+			}
+		case svc.InstrumentablePHP:
+			if _, ok := h.disabled[svc.InstrumentablePHP]; !ok {
+				r, err := h.phpExtractRoutes(fileInfo.Pid)
+				if err != nil {
+					resultChan <- result{err: err}
+					return
+				}
+				h.log.Debug("found php application routes", "routes", r.Routes)
+
 				resultChan <- result{r: r}
 			} else {
 				resultChan <- result{r: nil}
@@ -205,3 +277,50 @@ func FindScriptDirectory(root, firstArg, cwd string) string {
 
 	return result
 }
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// FindAppRoot returns the first of the provided directories, or any of their parents,
+// that contains all the marker files (e.g. config/routes.rb for Rails applications).
+// The directories are relative to the root directory of the process.
+func FindAppRoot(root string, dirs []string, markers ...string) string {
+	for _, dir := range dirs {
+		if !strings.HasPrefix(dir, "/") {
+			continue
+		}
+		for dir = filepath.Clean(dir); ; dir = filepath.Dir(dir) {
+			found := true
+			for _, marker := range markers {
+				if !fileExists(filepath.Join(root, dir, marker)) {
+					found = false
+					break
+				}
+			}
+			if found {
+				return filepath.Join(root, dir) + string(filepath.Separator)
+			}
+			if dir == "/" {
+				break
+			}
+		}
+	}
+	return ""
+}
+
+// singularize returns the singular form of a resource name, which is used by the
+// Rails and Laravel resource routes to name the path parameters, e.g. photos -> photo
+func singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"),
+		strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	}
+	return word
+}
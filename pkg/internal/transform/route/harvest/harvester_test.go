@@ -177,7 +177,7 @@ func TestHarvestRoutes_NonJavaLanguage(t *testing.T) {
 		return nil, nil
 	}
 
-	fileInfo := createTestFileInfo(svc.InstrumentableGolang)
+	fileInfo := createTestFileInfo(svc.InstrumentableRust)
 
 	result, err := harvester.HarvestRoutes(fileInfo)
 
@@ -231,6 +231,102 @@ func TestHarvestNodejsRoutes_Error(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed to connect to Java process")
 }
 
+func TestHarvestPythonRoutes(t *testing.T) {
+	harvester := NewRouteHarvester(&services.RouteHarvestingConfig{}, []services.RouteHarvesterLanguage{}, 1*time.Second)
+	harvester.pyExtractRoutes = slowButSuccessfulExtractRoutes
+
+	result, err := harvester.HarvestRoutes(createTestFileInfo(svc.InstrumentablePython))
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"/api/slow"}, result.Routes)
+	assert.Equal(t, PartialRoutes, result.Kind)
+}
+
+func TestHarvestPythonRoutes_Disabled(t *testing.T) {
+	harvester := NewRouteHarvester(&services.RouteHarvestingConfig{},
+		[]services.RouteHarvesterLanguage{services.RouteHarvesterLanguagePython}, 1*time.Second)
+	harvester.pyExtractRoutes = func(_ app.PID) (*RouteHarvesterResult, error) {
+		t.Fatal("pyExtractRoutes should not be called when the python harvester is disabled")
+		return nil, nil
+	}
+
+	result, err := harvester.HarvestRoutes(createTestFileInfo(svc.InstrumentablePython))
+
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestHarvestGoRoutes(t *testing.T) {
+	harvester := NewRouteHarvester(&services.RouteHarvestingConfig{}, []services.RouteHarvesterLanguage{}, 1*time.Second)
+	harvester.goExtractRoutes = successfulExtractRoutes
+
+	result, err := harvester.HarvestRoutes(createTestFileInfo(svc.InstrumentableGolang))
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"/api/users", "/api/orders"}, result.Routes)
+}
+
+func TestHarvestGoRoutes_Disabled(t *testing.T) {
+	harvester := NewRouteHarvester(&services.RouteHarvestingConfig{},
+		[]services.RouteHarvesterLanguage{services.RouteHarvesterLanguageGo}, 1*time.Second)
+	harvester.goExtractRoutes = func(_ app.PID) (*RouteHarvesterResult, error) {
+		t.Fatal("goExtractRoutes should not be called when the go harvester is disabled")
+		return nil, nil
+	}
+
+	result, err := harvester.HarvestRoutes(createTestFileInfo(svc.InstrumentableGolang))
+
+	require.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestHarvestRubyRoutes(t *testing.T) {
+	harvester := NewRouteHarvester(&services.RouteHarvestingConfig{}, []services.RouteHarvesterLanguage{}, 1*time.Second)
+	harvester.rbExtractRoutes = successfulExtractRoutes
+
+	result, err := harvester.HarvestRoutes(createTestFileInfo(svc.InstrumentableRuby))
+
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, []string{"/api/users", "/api/orders"}, result.Routes)
+}
+
+func TestHarvestPHPRoutes_Timeout(t *testing.T) {
+	harvester := NewRouteHarvester(&services.RouteHarvestingConfig{}, []services.RouteHarvesterLanguage{}, 100*time.Millisecond)
+	harvester.phpExtractRoutes = timeoutExtractRoutes
+
+	result, err := harvester.HarvestRoutes(createTestFileInfo(svc.InstrumentablePHP))
+
+	require.Error(t, err)
+	assert.Nil(t, result)
+
+	var harvestErr *HarvestError
+	require.ErrorAs(t, err, &harvestErr)
+	assert.Equal(t, "route harvesting timed out", harvestErr.Message)
+}
+
+func TestHarvestRubyAndPHPRoutes_Disabled(t *testing.T) {
+	harvester := NewRouteHarvester(&services.RouteHarvestingConfig{},
+		[]services.RouteHarvesterLanguage{services.RouteHarvesterLanguageRuby, services.RouteHarvesterLanguagePHP}, 1*time.Second)
+	harvester.rbExtractRoutes = func(_ app.PID) (*RouteHarvesterResult, error) {
+		t.Fatal("rbExtractRoutes should not be called when the ruby harvester is disabled")
+		return nil, nil
+	}
+	harvester.phpExtractRoutes = func(_ app.PID) (*RouteHarvesterResult, error) {
+		t.Fatal("phpExtractRoutes should not be called when the php harvester is disabled")
+		return nil, nil
+	}
+
+	for _, lang := range []svc.InstrumentableType{svc.InstrumentableRuby, svc.InstrumentablePHP} {
+		result, err := harvester.HarvestRoutes(createTestFileInfo(lang))
+
+		require.NoError(t, err)
+		assert.Nil(t, result)
+	}
+}
+
 func TestFindScriptDirectory(t *testing.T) {
 	// Create a temporary directory structure for testing
 	tempDir := t.TempDir()
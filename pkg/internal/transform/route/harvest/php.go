@@ -0,0 +1,332 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest // import "go.opentelemetry.io/obi/pkg/internal/transform/route/harvest"
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+)
+
+// phpAppDirs are the usual locations of the PHP applications, which are checked when the
+// application root can't be found from the process command line or working directory
+// (e.g. PHP-FPM workers run from the root directory)
+var phpAppDirs = []string{"/var/www/html", "/var/www", "/app", "/srv/app"}
+
+const (
+	laravelRoutesDir = "routes"
+	laravelArtisan   = "artisan"
+	// prefix of the routes defined in routes/api.php
+	laravelAPIPrefix = "api"
+)
+
+// LaravelPatterns holds regex patterns for the Laravel routing API
+type LaravelPatterns struct {
+	// Route::get('/users/{id}', ...), Route::any('/x', ...), Route::view('/welcome', 'welcome')
+	Route *regexp.Regexp
+	// Route::match(['get', 'post'], '/x', ...)
+	Match *regexp.Regexp
+	// Route::resource('photos', PhotoController::class)->only(['index', 'show'])
+	Resource   *regexp.Regexp
+	OnlyExcept *regexp.Regexp
+	Word       *regexp.Regexp
+	// Route::prefix('admin')->group(function () {, Route::group(['prefix' => 'admin'], function () {
+	Group       *regexp.Regexp
+	Prefix      *regexp.Regexp
+	ArrayPrefix *regexp.Regexp
+
+	// path cleanup
+	Param                *regexp.Regexp
+	MultipleSlashPattern *regexp.Regexp
+}
+
+func newLaravelPatterns() *LaravelPatterns {
+	return &LaravelPatterns{
+		Route:      regexp.MustCompile(`(?:Route::|\$router->)(get|post|put|patch|delete|options|any|view|redirect|permanentRedirect)\s*\(\s*['"]([^'"]*)['"]`),
+		Match:      regexp.MustCompile(`(?:Route::|\$router->)match\s*\(\s*\[[^\]]*\]\s*,\s*['"]([^'"]*)['"]`),
+		Resource:   regexp.MustCompile(`Route::(resource|apiResource)\s*\(\s*['"]([^'"]*)['"]`),
+		OnlyExcept: regexp.MustCompile(`->(only|except)\s*\(\s*\[([^\]]*)\]`),
+		Word:       regexp.MustCompile(`\w+`),
+
+		Group:       regexp.MustCompile(`(?:->|Route::|\$router->)group\s*\(`),
+		Prefix:      regexp.MustCompile(`(?:->|Route::)prefix\s*\(\s*['"]([^'"]*)['"]`),
+		ArrayPrefix: regexp.MustCompile(`['"]prefix['"]\s*=>\s*['"]([^'"]*)['"]`),
+
+		Param:                regexp.MustCompile(`^\{(\w+)\??}$`),
+		MultipleSlashPattern: regexp.MustCompile(`//+`),
+	}
+}
+
+// laravelGroup is a route group, whose routes are prefixed by the group prefix
+type laravelGroup struct {
+	prefix string
+	// braces depth at which the group was opened
+	depth int
+}
+
+type LaravelRouteExtractor struct {
+	log      *slog.Logger
+	patterns *LaravelPatterns
+	routes   []RoutePattern
+}
+
+func NewLaravelRouteExtractor() *LaravelRouteExtractor {
+	return &LaravelRouteExtractor{
+		log:      slog.With("component", "route.harvester.php"),
+		patterns: newLaravelPatterns(),
+		routes:   []RoutePattern{},
+	}
+}
+
+// phpBracesDelta returns the difference between the opened and closed braces of a line,
+// ignoring the braces within strings (e.g. route parameters)
+func phpBracesDelta(line string) int {
+	delta := 0
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '{':
+			delta++
+		case c == '}':
+			delta--
+		}
+	}
+	return delta
+}
+
+func (e *LaravelRouteExtractor) addRoute(method, path, file string, line int) {
+	e.routes = append(e.routes, RoutePattern{Method: method, Path: path, File: file, Line: line})
+}
+
+// resourceRoutes adds the routes of a resource controller. Nested resources are
+// separated by dots, e.g. photos.comments -> photos/{photo}/comments/{comment}
+func (e *LaravelRouteExtractor) resourceRoutes(prefix, kind, name, options, file string, line int) {
+	all := []string{"index", "store", "create", "show", "update", "destroy", "edit"}
+	if kind == "apiResource" {
+		all = []string{"index", "store", "show", "update", "destroy"}
+	}
+	actions := map[string]bool{}
+	m := e.patterns.OnlyExcept.FindStringSubmatch(options)
+	listed := map[string]bool{}
+	if m != nil {
+		for _, w := range e.patterns.Word.FindAllString(m[2], -1) {
+			listed[w] = true
+		}
+	}
+	for _, a := range all {
+		if m == nil || (m[1] == "only") == listed[a] {
+			actions[a] = true
+		}
+	}
+
+	parts := strings.Split(name, ".")
+	collection := prefix
+	for i, p := range parts {
+		collection = joinRoutePath(collection, p)
+		if i < len(parts)-1 {
+			collection = joinRoutePath(collection, "{"+singularize(p)+"}")
+		}
+	}
+	member := joinRoutePath(collection, "{"+singularize(parts[len(parts)-1])+"}")
+
+	if actions["index"] || actions["store"] {
+		e.addRoute("ALL", collection, file, line)
+	}
+	if actions["create"] {
+		e.addRoute("GET", joinRoutePath(collection, "create"), file, line)
+	}
+	if actions["show"] || actions["update"] || actions["destroy"] {
+		e.addRoute("ALL", member, file, line)
+	}
+	if actions["edit"] {
+		e.addRoute("GET", joinRoutePath(member, "edit"), file, line)
+	}
+}
+
+func (e *LaravelRouteExtractor) handleRoutes(prefix, file, line string, lineNum int) {
+	for _, m := range e.patterns.Route.FindAllStringSubmatch(line, -1) {
+		method := strings.ToUpper(m[1])
+		switch m[1] {
+		case "any":
+			method = "ALL"
+		case "view", "redirect", "permanentRedirect":
+			method = "GET"
+		}
+		e.addRoute(method, joinRoutePath(prefix, m[2]), file, lineNum)
+	}
+	for _, m := range e.patterns.Match.FindAllStringSubmatch(line, -1) {
+		e.addRoute("ALL", joinRoutePath(prefix, m[1]), file, lineNum)
+	}
+	for _, m := range e.patterns.Resource.FindAllStringSubmatchIndex(line, -1) {
+		e.resourceRoutes(prefix, line[m[2]:m[3]], line[m[4]:m[5]], line[m[1]:], file, lineNum)
+	}
+}
+
+func (e *LaravelRouteExtractor) groupPrefix(line string) string {
+	if m := e.patterns.Prefix.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	if m := e.patterns.ArrayPrefix.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// scanFile parses the routes of a file, whose routes are all prefixed by the provided prefix
+func (e *LaravelRouteExtractor) scanFile(filePath, prefix string) error {
+	groups := []laravelGroup{{prefix: joinRoutePath(prefix), depth: -1}}
+	depth := 0
+	lineNum := 0
+
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	inBlockComment := false
+	pendingPrefix := ""
+	for _, line := range strings.Split(string(file), "\n") {
+		lineNum++
+		line = strings.TrimSpace(line)
+		if inBlockComment {
+			inBlockComment = !strings.Contains(line, "*/")
+			continue
+		}
+		if strings.HasPrefix(line, "/*") {
+			inBlockComment = !strings.Contains(line, "*/")
+			continue
+		}
+		if line == "" || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// the group attributes might be chained in the previous lines
+		groupPrefix := e.groupPrefix(line)
+		if groupPrefix == "" {
+			groupPrefix = pendingPrefix
+		}
+		if e.patterns.Group.MatchString(line) {
+			groups = append(groups, laravelGroup{
+				prefix: joinRoutePath(groups[len(groups)-1].prefix, groupPrefix),
+				depth:  depth,
+			})
+			pendingPrefix = ""
+		} else if strings.HasSuffix(line, ";") {
+			pendingPrefix = ""
+		} else {
+			pendingPrefix = groupPrefix
+		}
+		e.handleRoutes(groups[len(groups)-1].prefix, filePath, line, lineNum)
+
+		depth += phpBracesDelta(line)
+		for len(groups) > 1 && depth <= groups[len(groups)-1].depth {
+			groups = groups[:len(groups)-1]
+		}
+	}
+	return nil
+}
+
+// ScanDirectory parses all the route files in the routes directory of a Laravel application
+func (e *LaravelRouteExtractor) ScanDirectory(appRoot string) error {
+	routesDir := filepath.Join(appRoot, laravelRoutesDir)
+	return filepath.Walk(routesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || filepath.Ext(path) != ".php" {
+			return nil
+		}
+		prefix := ""
+		if path == filepath.Join(routesDir, laravelAPIPrefix+".php") {
+			prefix = laravelAPIPrefix
+		}
+		if err := e.scanFile(path, prefix); err != nil {
+			e.log.Debug("error processing file", "file", path, "error", err)
+		}
+		return nil
+	})
+}
+
+func (e *LaravelRouteExtractor) GetRoutes() []RoutePattern {
+	return e.routes
+}
+
+// CleanupPath converts the optional route parameters to regular parameters, since
+// optional segments can't be expressed in the route templates.
+// Example: "/users/{name?}" -> "/users/{name}"
+func (e *LaravelRouteExtractor) CleanupPath(path string) string {
+	path = e.patterns.MultipleSlashPattern.ReplaceAllString(path, "/")
+
+	parts := strings.Split(path, "/")
+	keep := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		if m := e.patterns.Param.FindStringSubmatch(p); m != nil {
+			p = "{" + m[1] + "}"
+		}
+		keep = append(keep, p)
+	}
+	return "/" + strings.Join(keep, "/")
+}
+
+func (e *LaravelRouteExtractor) GetHarvestedRoutes() []string {
+	dedup := map[string]struct{}{}
+	for _, r := range e.routes {
+		if route := e.CleanupPath(r.Path); route != "/" {
+			dedup[route] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(dedup))
+	for k := range dedup {
+		result = append(result, k)
+	}
+	return result
+}
+
+// FindLaravelAppRoot locates the root directory of a Laravel application, which contains
+// the artisan script and the routes directory
+func FindLaravelAppRoot(pid app.PID) (string, error) {
+	dirs, err := appCandidateDirs(pid, phpAppDirs)
+	if err != nil {
+		return "", err
+	}
+	root := FindAppRoot(rootDirForPID(pid), dirs, laravelArtisan, laravelRoutesDir)
+	if root == "" {
+		return "", fmt.Errorf("failed to find a Laravel application for pid %d, searched in %v", pid, dirs)
+	}
+	return root, nil
+}
+
+func ExtractPHPRoutes(pid app.PID) (*RouteHarvesterResult, error) {
+	root, err := FindLaravelAppRoot(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor := NewLaravelRouteExtractor()
+	if err := extractor.ScanDirectory(root); err != nil {
+		return nil, fmt.Errorf("error scanning directory, error %w", err)
+	}
+
+	return &RouteHarvesterResult{
+		Routes: extractor.GetHarvestedRoutes(),
+		Kind:   CompleteRoutes,
+	}, nil
+}
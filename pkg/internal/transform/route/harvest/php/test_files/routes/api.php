@@ -0,0 +1,6 @@
+<?php
+
+use Illuminate\Support\Facades\Route;
+
+Route::apiResource('orders', OrderController::class);
+Route::post('/webhooks/{provider}', WebhookController::class);
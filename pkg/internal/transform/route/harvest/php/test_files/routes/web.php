@@ -0,0 +1,38 @@
+<?php
+
+use App\Http\Controllers\PhotoController;
+use Illuminate\Support\Facades\Route;
+
+Route::get('/', function () {
+    return view('welcome');
+});
+
+Route::get('/users/{id}', [UserController::class, 'show']);
+Route::match(['get', 'post'], '/search/{term?}', SearchController::class);
+
+// Route::get('/commented', fn () => 'ignored');
+
+/*
+Route::get('/also-commented', fn () => 'ignored');
+*/
+
+Route::resource('photos', PhotoController::class)->only(['index', 'show']);
+Route::resource('photos.comments', CommentController::class)->except(['create', 'edit']);
+
+Route::prefix('admin')->group(function () {
+    Route::view('/dashboard', 'admin.dashboard');
+    Route::group(['prefix' => 'settings'], function () {
+        Route::put('/{key}', [SettingsController::class, 'update']);
+    });
+    Route::get('/logs/{date}', function (string $date) {
+        return "{$date}";
+    });
+});
+
+Route::middleware('auth')
+    ->prefix('account')
+    ->group(function () {
+        Route::get('/profile', [ProfileController::class, 'edit']);
+    });
+
+Route::get('/about', [AboutController::class, 'show']);
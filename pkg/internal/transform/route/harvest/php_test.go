@@ -0,0 +1,118 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+)
+
+func TestLaravelRouteExtractor(t *testing.T) {
+	extractor := NewLaravelRouteExtractor()
+	require.NoError(t, extractor.ScanDirectory(filepath.Join("php", "test_files")))
+
+	assert.Contains(t, extractor.GetRoutes(), RoutePattern{
+		Method: "PUT", Path: "/admin/settings/{key}",
+		File: filepath.Join("php", "test_files", "routes", "web.php"), Line: 25,
+	})
+
+	assert.ElementsMatch(t, []string{
+		"/users/{id}",
+		"/search/{term}",
+		"/photos",
+		"/photos/{photo}",
+		"/photos/{photo}/comments",
+		"/photos/{photo}/comments/{comment}",
+		"/admin/dashboard",
+		"/admin/settings/{key}",
+		"/admin/logs/{date}",
+		"/account/profile",
+		"/about",
+		"/api/orders",
+		"/api/orders/{order}",
+		"/api/webhooks/{provider}",
+	}, extractor.GetHarvestedRoutes())
+}
+
+func TestLaravelCleanupPath(t *testing.T) {
+	extractor := NewLaravelRouteExtractor()
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/", expected: "/"},
+		{path: "users/{id}", expected: "/users/{id}"},
+		{path: "/users/{name?}", expected: "/users/{name}"},
+		{path: "//double//slash/", expected: "/double/slash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractor.CleanupPath(tt.path))
+		})
+	}
+}
+
+func TestPHPBracesDelta(t *testing.T) {
+	assert.Equal(t, 1, phpBracesDelta(`Route::prefix('x')->group(function () {`))
+	assert.Equal(t, -1, phpBracesDelta(`});`))
+	assert.Equal(t, 0, phpBracesDelta(`Route::get('/users/{id}', fn ($id) => "{$id}");`))
+	assert.Equal(t, 1, phpBracesDelta(`Route::get('/it\'s/{id}', function () {`))
+}
+
+func TestExtractPHPRoutes(t *testing.T) {
+	origRootDir := rootDirForPID
+	origCmdline := cmdlineForPID
+	origCwd := cwdForPID
+
+	defer func() {
+		rootDirForPID = origRootDir
+		cmdlineForPID = origCmdline
+		cwdForPID = origCwd
+	}()
+
+	tempDir := t.TempDir()
+	appDir := filepath.Join(tempDir, "var", "www", "html")
+	require.NoError(t, os.MkdirAll(filepath.Join(appDir, "routes"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(appDir, "public"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "artisan"), nil, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "routes", "api.php"), []byte(`<?php
+Route::get('/users/{user}', [UserController::class, 'show']);
+`), 0o644))
+
+	rootDirForPID = func(_ app.PID) string {
+		return tempDir
+	}
+	cwdForPID = func(_ app.PID) (string, error) {
+		return "/", nil
+	}
+
+	t.Run("server script", func(t *testing.T) {
+		cmdlineForPID = func(_ app.PID) (string, []string, error) {
+			return "php", []string{"-S", "0.0.0.0:8000", "/var/www/html/public/index.php"}, nil
+		}
+
+		result, err := ExtractPHPRoutes(12345)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, CompleteRoutes, result.Kind)
+		assert.Equal(t, []string{"/api/users/{user}"}, result.Routes)
+	})
+
+	t.Run("fpm worker", func(t *testing.T) {
+		cmdlineForPID = func(_ app.PID) (string, []string, error) {
+			return "php-fpm", []string{"pool", "www"}, nil
+		}
+
+		result, err := ExtractPHPRoutes(12345)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, []string{"/api/users/{user}"}, result.Routes)
+	})
+}
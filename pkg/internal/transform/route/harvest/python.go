@@ -0,0 +1,420 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest // import "go.opentelemetry.io/obi/pkg/internal/transform/route/harvest"
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+)
+
+// pythonSkipDirs complements skipDirs with the Python directories that never contain
+// application routes
+var pythonSkipDirs = map[string]string{
+	"__pycache__":   "Python bytecode cache",
+	"site-packages": "Installed Python packages",
+	"dist-packages": "Debian installed Python packages",
+	".venv":         "Python virtual environment",
+	"venv":          "Python virtual environment",
+	".tox":          "tox environments",
+	".mypy_cache":   "mypy cache",
+	".pytest_cache": "pytest cache",
+	"migrations":    "Django database migrations",
+}
+
+// PythonPatterns holds regex patterns for different Python web frameworks
+type PythonPatterns struct {
+	// Flask and FastAPI decorators: @app.route('/path'), @bp.get('/path'), @router.post("/items/{id}")
+	Decorator *regexp.Regexp
+	// Flask: app.add_url_rule('/path', view_func=...)
+	AddURLRule *regexp.Regexp
+	// Flask-RESTful: api.add_resource(UserResource, '/users/<int:id>')
+	AddResource *regexp.Regexp
+	// Flask blueprints and FastAPI routers defined with a prefix:
+	// bp = Blueprint('users', __name__, url_prefix='/users'), router = APIRouter(prefix="/items")
+	Router *regexp.Regexp
+	// Cross-file prefixes: app.register_blueprint(bp, url_prefix='/v1'), app.include_router(router, prefix='/v1')
+	IncludeRouter *regexp.Regexp
+	Prefix        *regexp.Regexp
+	// Django URL configurations: path('users/<int:id>/', ...), re_path(r'^users/(?P<id>\d+)/$', ...)
+	Django *regexp.Regexp
+
+	// Path cleanup regexes
+	// Param matches Flask and Django <converter:name> and FastAPI {name:converter} parameters
+	Param *regexp.Regexp
+	// RegexGroup matches the start of a Django regex named group: (?P<name>
+	RegexGroup           *regexp.Regexp
+	MultipleSlashPattern *regexp.Regexp
+	ValidPathChars       *regexp.Regexp
+}
+
+func newPythonPatterns() *PythonPatterns {
+	return &PythonPatterns{
+		Decorator: regexp.MustCompile(`@(\w+)\.(route|get|post|put|patch|delete|head|options|api_route|websocket)\s*\(\s*(?:path\s*=\s*|rule\s*=\s*)?[rRuU]?['"]([^'"]*)['"]`),
+
+		AddURLRule: regexp.MustCompile(`(\w+)\.add_url_rule\s*\(\s*(?:rule\s*=\s*)?[rRuU]?['"]([^'"]*)['"]`),
+
+		AddResource: regexp.MustCompile(`\.add_resource\s*\(\s*[\w.]+\s*,\s*[rRuU]?['"]([^'"]*)['"]`),
+
+		Router: regexp.MustCompile(`(\w+)\s*(?::\s*[\w.]+\s*)?=\s*(?:[\w.]+\.)?(?:APIRouter|Blueprint)\s*\(([^)]*)\)`),
+
+		IncludeRouter: regexp.MustCompile(`\.(?:register_blueprint|include_router)\s*\(([^)]*)\)`),
+		Prefix:        regexp.MustCompile(`\b(?:url_)?prefix\s*=\s*[rRuU]?['"]([^'"]*)['"]`),
+
+		Django: regexp.MustCompile(`(?:^|[^.\w])(path|re_path|url)\s*\(\s*(?:route\s*=\s*)?([rR])?['"]([^'"]*)['"]`),
+
+		Param:                regexp.MustCompile(`<(?:(\w+):)?(\w+)>|\{(\w+)(?::(\w+))?}`),
+		RegexGroup:           regexp.MustCompile(`^\(\?P<(\w+)>`),
+		MultipleSlashPattern: regexp.MustCompile(`//+`),
+		ValidPathChars:       regexp.MustCompile(`^[A-Za-z0-9\-._~]+$`),
+	}
+}
+
+type PythonRouteExtractor struct {
+	log      *slog.Logger
+	patterns *PythonPatterns
+	routes   []RoutePattern
+	// partial is true when some routes are mounted under a prefix defined somewhere else
+	// (e.g. Django include() or Flask blueprints registered with a url_prefix), so the
+	// routes can only be matched as partial routes
+	partial bool
+}
+
+func NewPythonRouteExtractor() *PythonRouteExtractor {
+	return &PythonRouteExtractor{
+		patterns: newPythonPatterns(),
+		routes:   []RoutePattern{},
+		log:      slog.With("component", "route.harvester.python"),
+	}
+}
+
+// stripPythonComments blanks the full-line comments, keeping the line numbering
+func stripPythonComments(content string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func lineNumber(content string, offset int) int {
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+func (e *PythonRouteExtractor) addRoute(method, path, file, content string, offset int) {
+	e.routes = append(e.routes, RoutePattern{
+		Method: method,
+		Path:   path,
+		File:   file,
+		Line:   lineNumber(content, offset),
+	})
+}
+
+// routerPrefixes returns the prefixes of the Flask blueprints and FastAPI routers defined
+// in the file, by variable name
+func (e *PythonRouteExtractor) routerPrefixes(content string) map[string]string {
+	prefixes := map[string]string{}
+	for _, m := range e.patterns.Router.FindAllStringSubmatch(content, -1) {
+		if p := e.patterns.Prefix.FindStringSubmatch(m[2]); p != nil {
+			prefixes[m[1]] = p[1]
+		}
+	}
+	return prefixes
+}
+
+func (e *PythonRouteExtractor) handleDecorators(filePath, content string, prefixes map[string]string) {
+	for _, m := range e.patterns.Decorator.FindAllStringSubmatchIndex(content, -1) {
+		variable, method, path := content[m[2]:m[3]], content[m[4]:m[5]], content[m[6]:m[7]]
+		switch method {
+		case "route", "api_route", "websocket":
+			method = "ALL"
+		default:
+			method = strings.ToUpper(method)
+		}
+		e.addRoute(method, prefixes[variable]+path, filePath, content, m[0])
+	}
+}
+
+func (e *PythonRouteExtractor) handleURLRules(filePath, content string, prefixes map[string]string) {
+	for _, m := range e.patterns.AddURLRule.FindAllStringSubmatchIndex(content, -1) {
+		variable, path := content[m[2]:m[3]], content[m[4]:m[5]]
+		e.addRoute("ALL", prefixes[variable]+path, filePath, content, m[0])
+	}
+	for _, m := range e.patterns.AddResource.FindAllStringSubmatchIndex(content, -1) {
+		e.addRoute("ALL", content[m[2]:m[3]], filePath, content, m[0])
+	}
+}
+
+// handleIncludedRouters registers the prefixes of the blueprints and routers that are
+// included from other files. Their routes are only known relative to the prefix.
+func (e *PythonRouteExtractor) handleIncludedRouters(filePath, content string) {
+	for _, m := range e.patterns.IncludeRouter.FindAllStringSubmatchIndex(content, -1) {
+		if p := e.patterns.Prefix.FindStringSubmatch(content[m[2]:m[3]]); p != nil {
+			e.addRoute("ALL", p[1], filePath, content, m[0])
+			e.partial = true
+		}
+	}
+}
+
+// handleDjango extracts the routes of the Django URL configurations. Since any of them
+// can be included from another configuration under a prefix, the routes are partial.
+func (e *PythonRouteExtractor) handleDjango(filePath, content string) {
+	if !strings.Contains(content, "urlpatterns") {
+		return
+	}
+	for _, m := range e.patterns.Django.FindAllStringSubmatchIndex(content, -1) {
+		function, path := content[m[2]:m[3]], content[m[6]:m[7]]
+		if strings.Contains(path, "://") {
+			continue
+		}
+		if function != "path" {
+			// re_path and the legacy url functions accept regular expressions
+			path = e.regexToTemplate(path)
+		}
+		e.addRoute("ALL", path, filePath, content, m[0])
+		e.partial = true
+	}
+}
+
+func (e *PythonRouteExtractor) scanFile(filePath string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	content := stripPythonComments(string(data))
+	prefixes := e.routerPrefixes(content)
+
+	e.handleDecorators(filePath, content, prefixes)
+	e.handleURLRules(filePath, content, prefixes)
+	e.handleIncludedRouters(filePath, content)
+	e.handleDjango(filePath, content)
+
+	return nil
+}
+
+func (e *PythonRouteExtractor) ScanDirectory(root string) error {
+	return WalkPythonFiles(root, func(path string) error {
+		if err := e.scanFile(path); err != nil {
+			e.log.Debug("error processing file", "file", path, "error", err)
+		}
+		return nil
+	})
+}
+
+func (e *PythonRouteExtractor) GetRoutes() []RoutePattern {
+	return e.routes
+}
+
+// regexToTemplate converts a Django regular expression route to a path template, where the
+// named groups are replaced by their name and any other group by an id placeholder.
+// Example: "^articles/(?P<year>[0-9]{4})/(\d+)/$" -> "articles/<year>/<id>"
+func (e *PythonRouteExtractor) regexToTemplate(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "^")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	var sb strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '\\':
+			// escaped literals, e.g. \. or \-
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteByte(pattern[i])
+			}
+		case '(':
+			name := "id"
+			if m := e.patterns.RegexGroup.FindStringSubmatch(pattern[i:]); m != nil {
+				name = m[1]
+			}
+			i = closingParen(pattern, i)
+			sb.WriteString("<" + name + ">")
+		case '?':
+			// optional characters, e.g. the trailing slash in ^users/?$
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// closingParen returns the position of the parenthesis that closes the group opened at start
+func closingParen(pattern string, start int) int {
+	depth := 0
+	for i := start; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(pattern) - 1
+}
+
+// CleanupPath converts the Flask, Django and FastAPI route definitions to path templates
+// where the path parameters are replaced by {name} placeholders and the path converters
+// (e.g. <path:file> or {file:path}) by the * wildcard.
+// Example: "users/<int:user_id>/files/<path:name>" -> "/users/{user_id}/files/*"
+func (e *PythonRouteExtractor) CleanupPath(path string) string {
+	path = e.patterns.MultipleSlashPattern.ReplaceAllString(path, "/")
+
+	parts := strings.Split(path, "/")
+	keep := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if m := e.patterns.Param.FindStringSubmatch(p); m != nil {
+			// the first and second groups are the Flask/Django converter and name,
+			// the third and fourth groups are the FastAPI name and converter
+			name, converter := m[2], m[1]
+			if name == "" {
+				name, converter = m[3], m[4]
+			}
+			if converter == "path" {
+				keep = append(keep, "*")
+				break
+			}
+			keep = append(keep, "{"+name+"}")
+			continue
+		}
+		if !e.patterns.ValidPathChars.MatchString(p) {
+			p = "{id}"
+		}
+		keep = append(keep, p)
+	}
+
+	return "/" + strings.Join(keep, "/")
+}
+
+func (e *PythonRouteExtractor) GetHarvestedRoutes() []string {
+	dedup := map[string]struct{}{}
+
+	for _, r := range e.routes {
+		route := e.CleanupPath(r.Path)
+		if route != "/" {
+			dedup[route] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(dedup))
+	for k := range dedup {
+		result = append(result, k)
+	}
+
+	return result
+}
+
+// PythonScriptArg returns the script passed to the Python interpreter, or empty if the
+// application is started as a module (python -m), a command (python -c) or through a
+// launcher script (e.g. gunicorn or uvicorn), in which case the application is usually
+// located in the working directory.
+func PythonScriptArg(args []string) string {
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		switch {
+		case a == "-m" || a == "-c":
+			return ""
+		case a == "-W" || a == "-X":
+			// skip the option value
+			i++
+			continue
+		case a == "" || a[0] == '-':
+			continue
+		}
+		if strings.HasSuffix(a, ".py") {
+			return a
+		}
+		return ""
+	}
+	return ""
+}
+
+// FindPythonAppDir locates the root directory of a Python application by
+// reading its command line and working directory from /proc.
+func FindPythonAppDir(pid app.PID) (string, error) {
+	rootDir := rootDirForPID(pid)
+	_, args, err := cmdlineForPID(pid)
+	if err != nil {
+		return "", fmt.Errorf("error finding cmd line: %w", err)
+	}
+	workdir, err := cwdForPID(pid)
+	if err != nil {
+		return "", fmt.Errorf("error finding cwd: %w", err)
+	}
+
+	script := PythonScriptArg(args)
+
+	dir := FindScriptDirectory(rootDir, script, workdir)
+	if dir == "" {
+		return "", fmt.Errorf("failed to find script directory for pid %d, script %s, cwd %s", pid, script, workdir)
+	}
+	return dir, nil
+}
+
+// WalkPythonFiles walks a directory tree, skipping known non-application directories
+// (virtual environments, installed packages, system dirs, etc.), and calls fn for each
+// Python source file found.
+func WalkPythonFiles(root string, fn func(path string) error) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if name == "root" && path != root {
+				return filepath.SkipDir
+			}
+			if _, ok := skipDirs[name]; ok {
+				return filepath.SkipDir
+			}
+			if _, ok := pythonSkipDirs[name]; ok {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) == ".py" {
+			return fn(path)
+		}
+
+		return nil
+	})
+}
+
+func ExtractPythonRoutes(pid app.PID) (*RouteHarvesterResult, error) {
+	dir, err := FindPythonAppDir(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor := NewPythonRouteExtractor()
+	if err := extractor.ScanDirectory(dir); err != nil {
+		return nil, fmt.Errorf("error scanning directory, error %w", err)
+	}
+
+	kind := CompleteRoutes
+	if extractor.partial {
+		kind = PartialRoutes
+	}
+
+	return &RouteHarvesterResult{
+		Routes: extractor.GetHarvestedRoutes(),
+		Kind:   kind,
+	}, nil
+}
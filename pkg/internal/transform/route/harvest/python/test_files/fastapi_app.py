@@ -0,0 +1,26 @@
+from fastapi import APIRouter, FastAPI
+
+app = FastAPI()
+router = APIRouter(prefix="/items", tags=["items"])
+
+
+@app.get("/status")
+async def status():
+    return {"status": "ok"}
+
+
+@router.get("/{item_id}")
+async def read_item(item_id: int):
+    return {"item_id": item_id}
+
+
+@router.delete(
+    "/{item_id}/tags/{tag}"
+)
+async def delete_tag(item_id: int, tag: str):
+    return {}
+
+
+@app.api_route("/static/{file_path:path}", methods=["GET", "HEAD"])
+async def static(file_path: str):
+    return {}
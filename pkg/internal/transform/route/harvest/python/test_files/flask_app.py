@@ -0,0 +1,40 @@
+from flask import Blueprint, Flask
+from flask_restful import Api
+
+app = Flask(__name__)
+api = Api(app)
+admin = Blueprint("admin", __name__, url_prefix="/admin")
+
+
+@app.route("/")
+def index():
+    return "ok"
+
+
+@app.route("/users/<int:user_id>", methods=["GET", "PUT"])
+def user(user_id):
+    return str(user_id)
+
+
+@app.post("/users")
+def create_user():
+    return "created"
+
+
+@app.get("/files/<path:filename>")
+def download(filename):
+    return filename
+
+
+# @app.route("/commented")
+@admin.route("/settings/<key>")
+def settings(key):
+    return key
+
+
+def health():
+    return "ok"
+
+
+app.add_url_rule("/health", view_func=health)
+api.add_resource(UserResource, "/api/users/<string:username>")
@@ -0,0 +1,6 @@
+import os
+from pathlib import Path as path
+
+BASE_DIR = os.path.dirname(os.path.abspath(__file__))
+# not a route, since this file does not define any URL patterns
+STATIC_ROOT = path("static/")
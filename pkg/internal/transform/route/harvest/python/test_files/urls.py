@@ -0,0 +1,15 @@
+from django.urls import include, path, re_path
+
+from . import views
+
+urlpatterns = [
+    path("", views.index),
+    path("articles/<int:year>/", views.year_archive),
+    path(
+        "articles/<int:year>/<slug:slug>/",
+        views.article_detail,
+    ),
+    re_path(r"^archive/(?P<year>[0-9]{4})/(\d+)/$", views.month_archive),
+    re_path(r"^robots\.txt$", views.robots),
+    path("api/", include("api.urls")),
+]
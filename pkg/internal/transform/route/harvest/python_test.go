@@ -0,0 +1,232 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+)
+
+func TestPythonRouteExtractor_FlaskApp(t *testing.T) {
+	extractor := NewPythonRouteExtractor()
+	require.NoError(t, extractor.scanFile(filepath.Join("python", "test_files", "flask_app.py")))
+
+	expectedRoutes := []RoutePattern{
+		{Method: "ALL", Path: "/", Line: 9},
+		{Method: "ALL", Path: "/users/<int:user_id>", Line: 14},
+		{Method: "POST", Path: "/users", Line: 19},
+		{Method: "GET", Path: "/files/<path:filename>", Line: 24},
+		{Method: "ALL", Path: "/admin/settings/<key>", Line: 30},
+		{Method: "ALL", Path: "/health", Line: 39},
+		{Method: "ALL", Path: "/api/users/<string:username>", Line: 40},
+	}
+	routes := extractor.GetRoutes()
+	require.Len(t, routes, len(expectedRoutes))
+	for i, expected := range expectedRoutes {
+		assert.Equal(t, expected.Method, routes[i].Method)
+		assert.Equal(t, expected.Path, routes[i].Path)
+		assert.Equal(t, expected.Line, routes[i].Line)
+	}
+	assert.False(t, extractor.partial)
+
+	assert.ElementsMatch(t, []string{
+		"/users/{user_id}",
+		"/users",
+		"/files/*",
+		"/admin/settings/{key}",
+		"/health",
+		"/api/users/{username}",
+	}, extractor.GetHarvestedRoutes())
+}
+
+func TestPythonRouteExtractor_FastAPIApp(t *testing.T) {
+	extractor := NewPythonRouteExtractor()
+	require.NoError(t, extractor.scanFile(filepath.Join("python", "test_files", "fastapi_app.py")))
+
+	assert.False(t, extractor.partial)
+	assert.ElementsMatch(t, []string{
+		"/status",
+		"/items/{item_id}",
+		"/items/{item_id}/tags/{tag}",
+		"/static/*",
+	}, extractor.GetHarvestedRoutes())
+}
+
+func TestPythonRouteExtractor_DjangoURLs(t *testing.T) {
+	extractor := NewPythonRouteExtractor()
+	require.NoError(t, extractor.scanFile(filepath.Join("python", "test_files", "urls.py")))
+
+	assert.True(t, extractor.partial)
+	assert.ElementsMatch(t, []string{
+		"/articles/{year}",
+		"/articles/{year}/{slug}",
+		"/archive/{year}/{id}",
+		"/robots.txt",
+		"/api",
+	}, extractor.GetHarvestedRoutes())
+}
+
+func TestPythonRouteExtractor_NoURLPatterns(t *testing.T) {
+	extractor := NewPythonRouteExtractor()
+	require.NoError(t, extractor.scanFile(filepath.Join("python", "test_files", "settings.py")))
+
+	assert.Empty(t, extractor.GetRoutes())
+}
+
+func TestPythonRouteExtractor_IncludedRouters(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "main.py")
+	require.NoError(t, os.WriteFile(file, []byte(`
+app.register_blueprint(users.bp, url_prefix="/v1/users")
+app.include_router(items.router, prefix="/v2")
+app.include_router(health.router)
+`), 0o644))
+
+	extractor := NewPythonRouteExtractor()
+	require.NoError(t, extractor.scanFile(file))
+
+	assert.True(t, extractor.partial)
+	assert.ElementsMatch(t, []string{"/v1/users", "/v2"}, extractor.GetHarvestedRoutes())
+}
+
+func TestPythonCleanupPath(t *testing.T) {
+	extractor := NewPythonRouteExtractor()
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/", expected: "/"},
+		{path: "users/", expected: "/users"},
+		{path: "/users/<id>", expected: "/users/{id}"},
+		{path: "/users/<int:id>/posts/<uuid:post_id>", expected: "/users/{id}/posts/{post_id}"},
+		{path: "/items/{item_id}", expected: "/items/{item_id}"},
+		{path: "/files/{file_path:path}", expected: "/files/*"},
+		{path: "/files/<path:name>/ignored", expected: "/files/*"},
+		{path: "/reports/<name>.pdf", expected: "/reports/{name}"},
+		{path: "//double//slash", expected: "/double/slash"},
+		{path: "/search/[a-z]+", expected: "/search/{id}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractor.CleanupPath(tt.path))
+		})
+	}
+}
+
+func TestPythonRegexToTemplate(t *testing.T) {
+	extractor := NewPythonRouteExtractor()
+	tests := []struct {
+		regex    string
+		expected string
+	}{
+		{regex: `^articles/$`, expected: "articles/"},
+		{regex: `^articles/(?P<year>[0-9]{4})/$`, expected: "articles/<year>/"},
+		{regex: `^articles/(\d+)/(?P<slug>[\w-]+)/?$`, expected: "articles/<id>/<slug>/"},
+		{regex: `^nested/(?P<code>(?:[a-z]{2})(?:-[A-Z]{2})?)/$`, expected: "nested/<code>/"},
+		{regex: `^robots\.txt$`, expected: "robots.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.regex, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractor.regexToTemplate(tt.regex))
+		})
+	}
+}
+
+func TestPythonScriptArg(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "script", args: []string{"/app/main.py"}, expected: "/app/main.py"},
+		{name: "flags", args: []string{"-u", "-X", "dev", "-W", "ignore", "manage.py", "runserver"}, expected: "manage.py"},
+		{name: "module", args: []string{"-m", "uvicorn", "main:app"}, expected: ""},
+		{name: "command", args: []string{"-c", "import app; app.run()"}, expected: ""},
+		{name: "launcher", args: []string{"/usr/local/bin/gunicorn", "app:app"}, expected: ""},
+		{name: "empty", args: nil, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, PythonScriptArg(tt.args))
+		})
+	}
+}
+
+func TestExtractPythonRoutes(t *testing.T) {
+	origRootDir := rootDirForPID
+	origCmdline := cmdlineForPID
+	origCwd := cwdForPID
+	origIsDir := isDirFunc
+
+	defer func() {
+		rootDirForPID = origRootDir
+		cmdlineForPID = origCmdline
+		cwdForPID = origCwd
+		isDirFunc = origIsDir
+	}()
+	// other tests might have replaced it
+	isDirFunc = isDir
+
+	tempDir := t.TempDir()
+	appDir := filepath.Join(tempDir, "app")
+	require.NoError(t, os.MkdirAll(filepath.Join(appDir, "api"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "main.py"), []byte(`
+@app.get("/users/{user_id}")
+async def user(user_id: int):
+    return {}
+`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "api", "orders.py"), []byte(`
+@router.post("/orders")
+async def create_order():
+    return {}
+`), 0o644))
+
+	// routes from the installed packages must be ignored
+	venvDir := filepath.Join(appDir, ".venv", "lib", "site-packages", "starlette")
+	require.NoError(t, os.MkdirAll(venvDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(venvDir, "docs.py"), []byte(`
+@app.get("/docs")
+async def docs():
+    return {}
+`), 0o644))
+
+	rootDirForPID = func(_ app.PID) string {
+		return tempDir
+	}
+	cwdForPID = func(_ app.PID) (string, error) {
+		return "/", nil
+	}
+
+	t.Run("script directory", func(t *testing.T) {
+		cmdlineForPID = func(_ app.PID) (string, []string, error) {
+			return "python3", []string{"-u", "/app/main.py"}, nil
+		}
+
+		result, err := ExtractPythonRoutes(12345)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, CompleteRoutes, result.Kind)
+		assert.ElementsMatch(t, []string{"/users/{user_id}", "/orders"}, result.Routes)
+	})
+
+	t.Run("launcher in working directory", func(t *testing.T) {
+		cmdlineForPID = func(_ app.PID) (string, []string, error) {
+			return "python3", []string{"/usr/local/bin/uvicorn", "main:app"}, nil
+		}
+		cwdForPID = func(_ app.PID) (string, error) {
+			return "/app/api", nil
+		}
+
+		result, err := ExtractPythonRoutes(12345)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, []string{"/orders"}, result.Routes)
+	})
+}
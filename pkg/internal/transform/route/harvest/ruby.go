@@ -0,0 +1,450 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest // import "go.opentelemetry.io/obi/pkg/internal/transform/route/harvest"
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+)
+
+// rubyAppDirs are the usual locations of the Rails applications in container images, which
+// are checked when the application root can't be found from the process command line
+// or working directory (e.g. Puma overrides its process title)
+var rubyAppDirs = []string{"/app", "/rails", "/usr/src/app", "/srv/app"}
+
+const railsRoutesFile = "config/routes.rb"
+
+// RailsPatterns holds regex patterns for the Rails routing DSL
+type RailsPatterns struct {
+	// namespace :admin do
+	Namespace *regexp.Regexp
+	// scope '/api' do, scope path: '/api', module: 'v1' do
+	Scope *regexp.Regexp
+	// resources :photos, only: [:index, :show] do
+	Resources *regexp.Regexp
+	// get 'photos/:id', to: 'photos#show', post :search
+	Verb *regexp.Regexp
+	// member do, collection do
+	MemberOrCollection *regexp.Regexp
+	// draw(:admin) loads config/routes/admin.rb
+	Draw *regexp.Regexp
+	// any other block, e.g. constraints(subdomain: 'api') do, or if Rails.env.development?
+	BlockStart *regexp.Regexp
+	Condition  *regexp.Regexp
+	BlockEnd   *regexp.Regexp
+
+	// options
+	ScopePath  *regexp.Regexp
+	Path       *regexp.Regexp
+	Param      *regexp.Regexp
+	On         *regexp.Regexp
+	OnlyExcept *regexp.Regexp
+	Word       *regexp.Regexp
+
+	// path cleanup
+	Optional             *regexp.Regexp
+	MultipleSlashPattern *regexp.Regexp
+}
+
+func newRailsPatterns() *RailsPatterns {
+	return &RailsPatterns{
+		Namespace:          regexp.MustCompile(`^namespace\s*\(?\s*:?['"]?(\w+)['"]?(.*?)\)?\s+do\b`),
+		Scope:              regexp.MustCompile(`^scope\b\s*\(?(.*?)\)?\s+do\b`),
+		Resources:          regexp.MustCompile(`^(resources?)\s*\(?\s*((?::\w+\s*,\s*)*:\w+)(.*)$`),
+		Verb:               regexp.MustCompile(`^(get|post|put|patch|delete|options|match)\s*\(?\s*(?:['"]([^'"]*)['"]|:(\w+))(.*)$`),
+		MemberOrCollection: regexp.MustCompile(`^(member|collection)\s+do\b`),
+		Draw:               regexp.MustCompile(`^draw\s*\(?\s*:?['"]?(\w+)`),
+		BlockStart:         regexp.MustCompile(`\bdo\s*(\|[^|]*\|)?\s*$`),
+		Condition:          regexp.MustCompile(`^(if|unless|case|begin|while|until)\b`),
+		BlockEnd:           regexp.MustCompile(`^end\b`),
+
+		ScopePath:  regexp.MustCompile(`^\s*['"]([^'"]*)['"]`),
+		Path:       regexp.MustCompile(`\bpath:\s*['"]([^'"]*)['"]`),
+		Param:      regexp.MustCompile(`\bparam:\s*:(\w+)`),
+		On:         regexp.MustCompile(`\bon:\s*:(member|collection)`),
+		OnlyExcept: regexp.MustCompile(`\b(only|except):\s*(\[[^\]]*\]|%i\[[^\]]*\]|:\w+)`),
+		Word:       regexp.MustCompile(`\w+`),
+
+		Optional:             regexp.MustCompile(`\([^()]*\)`),
+		MultipleSlashPattern: regexp.MustCompile(`//+`),
+	}
+}
+
+// railsScope is a block of the routes file, whose routes are nested in its path
+type railsScope struct {
+	path string
+	// resources blocks also define the paths of their member and collection routes
+	resources          bool
+	member, collection string
+}
+
+type RailsRouteExtractor struct {
+	log      *slog.Logger
+	appRoot  string
+	patterns *RailsPatterns
+	scopes   []railsScope
+	routes   []RoutePattern
+	// route files already drawn, to avoid recursion
+	drawn map[string]struct{}
+}
+
+func NewRailsRouteExtractor(appRoot string) *RailsRouteExtractor {
+	return &RailsRouteExtractor{
+		log:      slog.With("component", "route.harvester.ruby"),
+		appRoot:  appRoot,
+		patterns: newRailsPatterns(),
+		scopes:   []railsScope{{path: ""}},
+		routes:   []RoutePattern{},
+		drawn:    map[string]struct{}{},
+	}
+}
+
+func joinRoutePath(parts ...string) string {
+	var sb strings.Builder
+	for _, p := range parts {
+		p = strings.Trim(p, "/")
+		if p != "" {
+			sb.WriteByte('/')
+			sb.WriteString(p)
+		}
+	}
+	return sb.String()
+}
+
+// stripRubyComment removes the comment at the end of the line, if any
+func stripRubyComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func (e *RailsRouteExtractor) top() *railsScope {
+	return &e.scopes[len(e.scopes)-1]
+}
+
+func (e *RailsRouteExtractor) push(s railsScope) {
+	e.scopes = append(e.scopes, s)
+}
+
+func (e *RailsRouteExtractor) pop() {
+	// the outermost scope is never removed
+	if len(e.scopes) > 1 {
+		e.scopes = e.scopes[:len(e.scopes)-1]
+	}
+}
+
+func (e *RailsRouteExtractor) addRoute(method, path, file string, line int) {
+	e.routes = append(e.routes, RoutePattern{Method: method, Path: path, File: file, Line: line})
+}
+
+// resourceActions returns the actions of a resource, after applying the only and except options
+func (e *RailsRouteExtractor) resourceActions(options string, all []string) map[string]bool {
+	actions := map[string]bool{}
+	m := e.patterns.OnlyExcept.FindStringSubmatch(options)
+	if m == nil {
+		for _, a := range all {
+			actions[a] = true
+		}
+		return actions
+	}
+	listed := map[string]bool{}
+	for _, w := range e.patterns.Word.FindAllString(strings.TrimPrefix(m[2], "%i"), -1) {
+		listed[w] = true
+	}
+	for _, a := range all {
+		if (m[1] == "only") == listed[a] {
+			actions[a] = true
+		}
+	}
+	return actions
+}
+
+var (
+	railsPluralActions   = []string{"index", "create", "new", "show", "update", "destroy", "edit"}
+	railsSingularActions = []string{"create", "new", "show", "update", "destroy", "edit"}
+)
+
+// handleResources adds the RESTful routes of the resources and returns the scope of its
+// block, which is only pushed if the line opens a block
+func (e *RailsRouteExtractor) handleResources(file, line string, lineNum int) (railsScope, bool) {
+	m := e.patterns.Resources.FindStringSubmatch(line)
+	if m == nil {
+		return railsScope{}, false
+	}
+	plural := m[1] == "resources"
+	options := m[3]
+	base := e.top().path
+
+	var scope railsScope
+	for _, name := range e.patterns.Word.FindAllString(m[2], -1) {
+		segment := name
+		if p := e.patterns.Path.FindStringSubmatch(options); p != nil {
+			segment = p[1]
+		}
+		param := "id"
+		if p := e.patterns.Param.FindStringSubmatch(options); p != nil {
+			param = p[1]
+		}
+
+		collection := joinRoutePath(base, segment)
+		member, nested := collection, collection
+		actions := e.resourceActions(options, railsSingularActions)
+		if plural {
+			member = joinRoutePath(collection, ":"+param)
+			nested = joinRoutePath(collection, ":"+singularize(name)+"_id")
+			actions = e.resourceActions(options, railsPluralActions)
+		}
+
+		if actions["index"] || actions["create"] {
+			e.addRoute("ALL", collection, file, lineNum)
+		}
+		if actions["new"] {
+			e.addRoute("GET", joinRoutePath(collection, "new"), file, lineNum)
+		}
+		if actions["show"] || actions["update"] || actions["destroy"] {
+			e.addRoute("ALL", member, file, lineNum)
+		}
+		if actions["edit"] {
+			e.addRoute("GET", joinRoutePath(member, "edit"), file, lineNum)
+		}
+		scope = railsScope{path: nested, resources: true, member: member, collection: collection}
+	}
+	return scope, true
+}
+
+func (e *RailsRouteExtractor) handleVerb(file, line string, lineNum int) bool {
+	m := e.patterns.Verb.FindStringSubmatch(line)
+	if m == nil {
+		return false
+	}
+	path := m[2]
+	if path == "" {
+		path = m[3]
+	}
+	method := strings.ToUpper(m[1])
+	if method == "MATCH" {
+		method = "ALL"
+	}
+	base := e.top().path
+	if on := e.patterns.On.FindStringSubmatch(m[4]); on != nil && e.top().resources {
+		base = e.top().member
+		if on[1] == "collection" {
+			base = e.top().collection
+		}
+	}
+	e.addRoute(method, joinRoutePath(base, path), file, lineNum)
+	return true
+}
+
+func (e *RailsRouteExtractor) scopePath(args string) string {
+	if m := e.patterns.ScopePath.FindStringSubmatch(args); m != nil {
+		return m[1]
+	}
+	if m := e.patterns.Path.FindStringSubmatch(args); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func (e *RailsRouteExtractor) handleLine(file, line string, lineNum int) {
+	p := e.patterns
+	top := e.top()
+
+	if p.BlockEnd.MatchString(line) {
+		e.pop()
+		return
+	}
+	if m := p.MemberOrCollection.FindStringSubmatch(line); m != nil {
+		s := railsScope{path: top.path}
+		if top.resources {
+			s.path = top.member
+			if m[1] == "collection" {
+				s.path = top.collection
+			}
+		}
+		e.push(s)
+		return
+	}
+	if m := p.Namespace.FindStringSubmatch(line); m != nil {
+		segment := m[1]
+		if path := p.Path.FindStringSubmatch(m[2]); path != nil {
+			segment = path[1]
+		}
+		e.push(railsScope{path: joinRoutePath(top.path, segment)})
+		return
+	}
+	if m := p.Scope.FindStringSubmatch(line); m != nil {
+		e.push(railsScope{path: joinRoutePath(top.path, e.scopePath(m[1]))})
+		return
+	}
+	if scope, ok := e.handleResources(file, line, lineNum); ok {
+		if p.BlockStart.MatchString(line) {
+			e.push(scope)
+		}
+		return
+	}
+	if e.handleVerb(file, line, lineNum) {
+		if p.BlockStart.MatchString(line) {
+			e.push(railsScope{path: top.path})
+		}
+		return
+	}
+	if m := p.Draw.FindStringSubmatch(line); m != nil {
+		if err := e.scanFile(filepath.Join(e.appRoot, "config", "routes", m[1]+".rb")); err != nil {
+			e.log.Debug("error drawing routes file", "name", m[1], "error", err)
+		}
+		return
+	}
+	if p.BlockStart.MatchString(line) || p.Condition.MatchString(line) {
+		e.push(railsScope{path: top.path, resources: top.resources, member: top.member, collection: top.collection})
+	}
+}
+
+func (e *RailsRouteExtractor) scanFile(filePath string) error {
+	if _, ok := e.drawn[filePath]; ok {
+		return nil
+	}
+	e.drawn[filePath] = struct{}{}
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(stripRubyComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		e.handleLine(filePath, line, lineNum)
+	}
+	return scanner.Err()
+}
+
+// ScanRoutes parses the config/routes.rb file of the application, and the files drawn from it
+func (e *RailsRouteExtractor) ScanRoutes() error {
+	return e.scanFile(filepath.Join(e.appRoot, railsRoutesFile))
+}
+
+func (e *RailsRouteExtractor) GetRoutes() []RoutePattern {
+	return e.routes
+}
+
+// CleanupPath removes the optional segments from a Rails route and replaces the glob
+// parameters by the * wildcard.
+// Example: "/(:locale)/files/*path(.:format)" -> "/files/*"
+func (e *RailsRouteExtractor) CleanupPath(path string) string {
+	for {
+		cleaned := e.patterns.Optional.ReplaceAllString(path, "")
+		if cleaned == path {
+			break
+		}
+		path = cleaned
+	}
+	path = e.patterns.MultipleSlashPattern.ReplaceAllString(path, "/")
+
+	parts := strings.Split(path, "/")
+	keep := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p == "" {
+			continue
+		}
+		if p[0] == '*' {
+			keep = append(keep, "*")
+			break
+		}
+		keep = append(keep, p)
+	}
+	return "/" + strings.Join(keep, "/")
+}
+
+func (e *RailsRouteExtractor) GetHarvestedRoutes() []string {
+	dedup := map[string]struct{}{}
+	for _, r := range e.routes {
+		if route := e.CleanupPath(r.Path); route != "/" {
+			dedup[route] = struct{}{}
+		}
+	}
+
+	result := make([]string, 0, len(dedup))
+	for k := range dedup {
+		result = append(result, k)
+	}
+	return result
+}
+
+// appCandidateDirs returns the directories where an application root is looked for: the
+// directory of the script, the working directory and the provided usual locations
+func appCandidateDirs(pid app.PID, usual []string) ([]string, error) {
+	_, args, err := cmdlineForPID(pid)
+	if err != nil {
+		return nil, fmt.Errorf("error finding cmd line: %w", err)
+	}
+	workdir, err := cwdForPID(pid)
+	if err != nil {
+		return nil, fmt.Errorf("error finding cwd: %w", err)
+	}
+
+	var dirs []string
+	if script := FirstArg(args); strings.HasPrefix(script, "/") {
+		dirs = append(dirs, filepath.Dir(script))
+	}
+	dirs = append(dirs, workdir)
+	return append(dirs, usual...), nil
+}
+
+// FindRailsAppRoot locates the root directory of a Rails application, which contains
+// the config/routes.rb file
+func FindRailsAppRoot(pid app.PID) (string, error) {
+	dirs, err := appCandidateDirs(pid, rubyAppDirs)
+	if err != nil {
+		return "", err
+	}
+	root := FindAppRoot(rootDirForPID(pid), dirs, railsRoutesFile)
+	if root == "" {
+		return "", fmt.Errorf("failed to find a Rails application for pid %d, searched in %v", pid, dirs)
+	}
+	return root, nil
+}
+
+func ExtractRubyRoutes(pid app.PID) (*RouteHarvesterResult, error) {
+	root, err := FindRailsAppRoot(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	extractor := NewRailsRouteExtractor(root)
+	if err := extractor.ScanRoutes(); err != nil {
+		return nil, fmt.Errorf("error parsing Rails routes, error %w", err)
+	}
+
+	return &RouteHarvesterResult{
+		Routes: extractor.GetHarvestedRoutes(),
+		Kind:   CompleteRoutes,
+	}, nil
+}
@@ -0,0 +1,31 @@
+Rails.application.routes.draw do
+  # Health checks
+  get "up" => "rails/health#show", as: :rails_health_check
+  root "home#index"
+
+  resources :photos do
+    resources :comments, only: [:index, :show]
+    member do
+      post :publish
+    end
+    get :search, on: :collection
+  end
+
+  resource :profile, except: %i[destroy edit]
+
+  namespace :api do
+    namespace :v1 do
+      resources :categories, param: :slug, only: :show
+    end
+  end
+
+  scope "/(:locale)" do
+    get "files/*path(.:format)", to: "files#show"
+  end
+
+  if Rails.env.development?
+    get "debug/:id" => "debug#show" # "fake/:route"
+  end
+
+  draw(:admin)
+end
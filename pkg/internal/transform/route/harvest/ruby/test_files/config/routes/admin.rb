@@ -0,0 +1,6 @@
+namespace :admin, path: "backoffice" do
+  resources :users, only: [:index]
+  match "reports/:year", to: "reports#show", via: [:get, :post]
+end
+
+draw :admin
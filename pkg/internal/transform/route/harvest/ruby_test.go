@@ -0,0 +1,154 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package harvest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app"
+)
+
+func TestRailsRouteExtractor(t *testing.T) {
+	extractor := NewRailsRouteExtractor(filepath.Join("ruby", "test_files"))
+	require.NoError(t, extractor.ScanRoutes())
+
+	assert.Contains(t, extractor.GetRoutes(), RoutePattern{
+		Method: "POST", Path: "/photos/:id/publish",
+		File: filepath.Join("ruby", "test_files", "config", "routes.rb"), Line: 9,
+	})
+	assert.Contains(t, extractor.GetRoutes(), RoutePattern{
+		Method: "ALL", Path: "/backoffice/reports/:year",
+		File: filepath.Join("ruby", "test_files", "config", "routes", "admin.rb"), Line: 3,
+	})
+
+	assert.ElementsMatch(t, []string{
+		"/up",
+		"/photos",
+		"/photos/new",
+		"/photos/:id",
+		"/photos/:id/edit",
+		"/photos/:photo_id/comments",
+		"/photos/:photo_id/comments/:id",
+		"/photos/:id/publish",
+		"/photos/search",
+		"/profile",
+		"/profile/new",
+		"/api/v1/categories/:slug",
+		"/files/*",
+		"/debug/:id",
+		"/backoffice/users",
+		"/backoffice/reports/:year",
+	}, extractor.GetHarvestedRoutes())
+}
+
+func TestRailsCleanupPath(t *testing.T) {
+	extractor := NewRailsRouteExtractor("")
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/", expected: "/"},
+		{path: "/users/:id", expected: "/users/:id"},
+		{path: "/users/:id(.:format)", expected: "/users/:id"},
+		{path: "/(:locale)/posts(/:year(/:month))", expected: "/posts"},
+		{path: "/files/*path/info", expected: "/files/*"},
+		{path: "//double//slash", expected: "/double/slash"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractor.CleanupPath(tt.path))
+		})
+	}
+}
+
+func TestStripRubyComment(t *testing.T) {
+	assert.Equal(t, `get "a" `, stripRubyComment(`get "a" # comment`))
+	assert.Equal(t, `get "a#b"`, stripRubyComment(`get "a#b"`))
+	assert.Equal(t, `get 'it\'s#1' `, stripRubyComment(`get 'it\'s#1' # comment`))
+}
+
+func TestSingularize(t *testing.T) {
+	for plural, singular := range map[string]string{
+		"photos":     "photo",
+		"categories": "category",
+		"addresses":  "address",
+		"boxes":      "box",
+		"branches":   "branch",
+		"status":     "statu",
+		"access":     "access",
+		"news":       "new",
+		"data":       "data",
+	} {
+		assert.Equal(t, singular, singularize(plural), plural)
+	}
+}
+
+func TestFindAppRoot(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "srv", "shop", "config"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "srv", "shop", "bin"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "srv", "shop", "config", "routes.rb"), nil, 0o644))
+
+	expected := filepath.Join(root, "srv", "shop") + "/"
+	assert.Equal(t, expected, FindAppRoot(root, []string{"/srv/shop/bin"}, railsRoutesFile))
+	assert.Equal(t, expected, FindAppRoot(root, []string{"relative", "/other", "/srv/shop"}, railsRoutesFile))
+	assert.Empty(t, FindAppRoot(root, []string{"/srv/shop"}, railsRoutesFile, "Gemfile"))
+	assert.Empty(t, FindAppRoot(root, []string{"/other"}, railsRoutesFile))
+}
+
+func TestExtractRubyRoutes(t *testing.T) {
+	origRootDir := rootDirForPID
+	origCmdline := cmdlineForPID
+	origCwd := cwdForPID
+
+	defer func() {
+		rootDirForPID = origRootDir
+		cmdlineForPID = origCmdline
+		cwdForPID = origCwd
+	}()
+
+	tempDir := t.TempDir()
+	appDir := filepath.Join(tempDir, "rails", "config")
+	require.NoError(t, os.MkdirAll(appDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(appDir, "routes.rb"), []byte(`
+Rails.application.routes.draw do
+  resources :articles, only: [:index, :show]
+end
+`), 0o644))
+
+	rootDirForPID = func(_ app.PID) string {
+		return tempDir
+	}
+	cmdlineForPID = func(_ app.PID) (string, []string, error) {
+		return "ruby", []string{"puma 6.4.0 (tcp://0.0.0.0:3000) [rails]"}, nil
+	}
+
+	t.Run("usual location", func(t *testing.T) {
+		cwdForPID = func(_ app.PID) (string, error) {
+			return "/", nil
+		}
+
+		result, err := ExtractRubyRoutes(12345)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, CompleteRoutes, result.Kind)
+		assert.ElementsMatch(t, []string{"/articles", "/articles/:id"}, result.Routes)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		require.NoError(t, os.Rename(filepath.Join(tempDir, "rails"), filepath.Join(tempDir, "other")))
+		cwdForPID = func(_ app.PID) (string, error) {
+			return "/tmp", nil
+		}
+
+		result, err := ExtractRubyRoutes(12345)
+		require.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
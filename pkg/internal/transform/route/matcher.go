@@ -18,6 +18,42 @@ type Matcher interface {
 	Find(string) string
 }
 
+// MethodMatcher is a Matcher whose routes might depend on the HTTP method of the request
+type MethodMatcher interface {
+	Matcher
+	FindMethod(method, path string) string
+}
+
+// FindRoute returns the route that matches the URL path, taking into account the HTTP
+// method if the matcher supports it.
+func FindRoute(m Matcher, method, path string) string {
+	if mm, ok := m.(MethodMatcher); ok {
+		return mm.FindMethod(method, path)
+	}
+	return m.Find(path)
+}
+
+// Matchers returns the first route found by any of its matchers
+type Matchers []Matcher
+
+func (ms Matchers) Find(path string) string {
+	for _, m := range ms {
+		if route := m.Find(path); route != "" {
+			return route
+		}
+	}
+	return ""
+}
+
+func (ms Matchers) FindMethod(method, path string) string {
+	for _, m := range ms {
+		if route := FindRoute(m, method, path); route != "" {
+			return route
+		}
+	}
+	return ""
+}
+
 // Matcher allows matching a given URL path towards a set of framework-like provided
 // patterns.
 type CompleteRouteMatcher struct {
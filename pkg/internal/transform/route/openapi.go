@@ -0,0 +1,291 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package route // import "go.opentelemetry.io/obi/pkg/internal/transform/route"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultOpenAPIRefresh is the interval between two reloads of the OpenAPI documents,
+// when it is not explicitly configured
+const DefaultOpenAPIRefresh = 5 * time.Minute
+
+const (
+	openAPIFetchTimeout = 10 * time.Second
+	// maxOpenAPIDocumentSize protects the agent from loading huge remote documents
+	maxOpenAPIDocumentSize = 16 * 1024 * 1024
+)
+
+var openAPIMethods = map[string]struct{}{
+	http.MethodGet: {}, http.MethodPut: {}, http.MethodPost: {}, http.MethodDelete: {},
+	http.MethodOptions: {}, http.MethodHead: {}, http.MethodPatch: {}, http.MethodTrace: {},
+}
+
+// isTemplateParam returns whether a path segment contains any OpenAPI path template
+// parameter, e.g. {userId} or {user-id}.
+func isTemplateParam(segment string) bool {
+	open := strings.IndexByte(segment, '{')
+	return open >= 0 && strings.IndexByte(segment[open:], '}') > 0
+}
+
+// OpenAPIRoute is an operation defined in an OpenAPI document
+type OpenAPIRoute struct {
+	// Method is the upper-case HTTP method of the operation
+	Method string
+	// Template is the path template of the operation, prefixed by the base path of the API
+	Template string
+}
+
+// openAPIDocument contains the subset of the OpenAPI 3 and Swagger 2 documents that is required
+// to extract the routes. Since JSON is a subset of YAML, it parses both formats.
+type openAPIDocument struct {
+	OpenAPI string `yaml:"openapi"`
+	Swagger string `yaml:"swagger"`
+	// BasePath of the Swagger 2 documents
+	BasePath string `yaml:"basePath"`
+	// Servers of the OpenAPI 3 documents
+	Servers []struct {
+		URL string `yaml:"url"`
+	} `yaml:"servers"`
+	Paths map[string]map[string]any `yaml:"paths"`
+}
+
+// ParseOpenAPI returns the routes defined in an OpenAPI 3 or Swagger 2 document, in JSON or YAML format.
+func ParseOpenAPI(document []byte) ([]OpenAPIRoute, error) {
+	doc := openAPIDocument{}
+	if err := yaml.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+	if doc.OpenAPI == "" && doc.Swagger == "" {
+		return nil, errors.New("not an OpenAPI document: missing openapi or swagger version field")
+	}
+	basePath := doc.BasePath
+	if len(doc.Servers) > 0 {
+		basePath = serverBasePath(doc.Servers[0].URL)
+	}
+	basePath = strings.TrimSuffix(basePath, "/")
+
+	var routes []OpenAPIRoute
+	for template, item := range doc.Paths {
+		if !strings.HasPrefix(template, "/") {
+			continue
+		}
+		for method := range item {
+			method = strings.ToUpper(method)
+			if _, ok := openAPIMethods[method]; ok {
+				routes = append(routes, OpenAPIRoute{Method: method, Template: basePath + template})
+			}
+		}
+	}
+	return routes, nil
+}
+
+// serverBasePath returns the path of an OpenAPI server URL, which can be absolute or
+// relative to the document location. Server variables are not resolved, so the base path
+// is ignored if it contains any of them.
+func serverBasePath(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err != nil || strings.ContainsAny(u.Path, "{}") {
+		return ""
+	}
+	return path.Clean("/" + u.Path)
+}
+
+// openAPIRoutes is the immutable set of route matchers built from the loaded documents
+type openAPIRoutes struct {
+	byMethod map[string]*CompleteRouteMatcher
+	// anyMethod matches the requests whose method is not defined for the path
+	anyMethod *CompleteRouteMatcher
+}
+
+func newOpenAPIRoutes(routes []OpenAPIRoute) *openAPIRoutes {
+	byMethod := map[string][]OpenAPIRoute{}
+	for _, r := range routes {
+		byMethod[r.Method] = append(byMethod[r.Method], r)
+	}
+	oar := &openAPIRoutes{
+		byMethod:  make(map[string]*CompleteRouteMatcher, len(byMethod)),
+		anyMethod: newTemplateMatcher(routes),
+	}
+	for method, methodRoutes := range byMethod {
+		oar.byMethod[method] = newTemplateMatcher(methodRoutes)
+	}
+	return oar
+}
+
+// newTemplateMatcher differs from NewMatcher in that any segment containing a path
+// parameter is considered a wildcard, as OpenAPI parameter names might contain
+// non-word characters and segments might mix literals and parameters (e.g. /{file}.json)
+func newTemplateMatcher(routes []OpenAPIRoute) *CompleteRouteMatcher {
+	m := CompleteRouteMatcher{root: &node{Child: map[string]*node{}}}
+	for _, r := range routes {
+		tokens := tokenize(r.Template)
+		for i, t := range tokens {
+			if isTemplateParam(t) {
+				tokens[i] = "{}"
+			}
+		}
+		appendRoute(r.Template, tokens, m.root)
+	}
+	return &m
+}
+
+// OpenAPIMatcher matches the URL paths towards the path templates of a set of OpenAPI
+// documents, which are periodically reloaded from local files or HTTP URLs.
+type OpenAPIMatcher struct {
+	log       *slog.Logger
+	documents []string
+	refresh   time.Duration
+	client    *http.Client
+	routes    atomic.Pointer[openAPIRoutes]
+	// last successfully loaded routes of each document, to keep them if a reload fails
+	loaded map[string][]OpenAPIRoute
+}
+
+// NewOpenAPIMatcher creates an OpenAPIMatcher for the provided documents. It does not match
+// any path until its Reload method loads them.
+func NewOpenAPIMatcher(documents []string, refresh time.Duration) *OpenAPIMatcher {
+	if refresh <= 0 {
+		refresh = DefaultOpenAPIRefresh
+	}
+	return &OpenAPIMatcher{
+		log:       slog.With("component", "route.OpenAPIMatcher"),
+		documents: documents,
+		refresh:   refresh,
+		client:    &http.Client{Timeout: openAPIFetchTimeout},
+		loaded:    map[string][]OpenAPIRoute{},
+	}
+}
+
+// RefreshPeriodically reloads the documents after each refresh interval, until the
+// context is cancelled
+func (m *OpenAPIMatcher) RefreshPeriodically(ctx context.Context) {
+	ticker := time.NewTicker(m.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Reload(ctx)
+		}
+	}
+}
+
+// Reload loads all the documents. The documents that can't be loaded keep the routes
+// from their last successful load. It must not be invoked concurrently.
+func (m *OpenAPIMatcher) Reload(ctx context.Context) {
+	var all []OpenAPIRoute
+	for _, doc := range m.documents {
+		routes, err := m.load(ctx, doc)
+		if err != nil {
+			m.log.Warn("can't load OpenAPI document. Keeping previous routes, if any",
+				"document", doc, "error", err)
+		} else {
+			m.loaded[doc] = routes
+		}
+		all = append(all, m.loaded[doc]...)
+	}
+	m.log.Debug("loaded OpenAPI routes", "documents", m.documents, "routes", len(all))
+	m.routes.Store(newOpenAPIRoutes(all))
+}
+
+func (m *OpenAPIMatcher) load(ctx context.Context, document string) ([]OpenAPIRoute, error) {
+	var content []byte
+	var err error
+	if strings.HasPrefix(document, "http://") || strings.HasPrefix(document, "https://") {
+		content, err = m.fetch(ctx, document)
+	} else {
+		content, err = os.ReadFile(document)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ParseOpenAPI(content)
+}
+
+func (m *OpenAPIMatcher) fetch(ctx context.Context, documentURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxOpenAPIDocumentSize))
+}
+
+// Find the path template that matches the URL path, for any method
+func (m *OpenAPIMatcher) Find(path string) string {
+	routes := m.routes.Load()
+	if routes == nil {
+		return ""
+	}
+	return routes.anyMethod.Find(path)
+}
+
+// FindMethod returns the path template of the operation that matches the method and URL path.
+// If the method is not defined for the matching paths, the template is matched for any method.
+func (m *OpenAPIMatcher) FindMethod(method, path string) string {
+	routes := m.routes.Load()
+	if routes == nil {
+		return ""
+	}
+	if methodMatcher, ok := routes.byMethod[method]; ok {
+		if route := methodMatcher.Find(path); route != "" {
+			return route
+		}
+	}
+	return routes.anyMethod.Find(path)
+}
+
+var (
+	sharedOpenAPIMatchersMutex sync.Mutex
+	sharedOpenAPIMatchers      = map[string]*OpenAPIMatcher{}
+)
+
+// SharedOpenAPIMatcher returns a running OpenAPIMatcher for the provided documents, which is shared
+// by all the invocations with the same arguments (e.g. all the processes of the same service), so
+// the documents are loaded only once. As for the global routes, the first load is synchronous, so
+// the returned matcher already matches the first spans. The matcher is reloaded periodically until
+// the provided context, usually the pipeline context, is cancelled. Then it is not shared anymore.
+func SharedOpenAPIMatcher(ctx context.Context, documents []string, refresh time.Duration) *OpenAPIMatcher {
+	key := fmt.Sprintf("%s|%s", strings.Join(documents, ","), refresh)
+	sharedOpenAPIMatchersMutex.Lock()
+	defer sharedOpenAPIMatchersMutex.Unlock()
+	if m, ok := sharedOpenAPIMatchers[key]; ok {
+		return m
+	}
+	m := NewOpenAPIMatcher(documents, refresh)
+	m.Reload(ctx)
+	sharedOpenAPIMatchers[key] = m
+	go func() {
+		m.RefreshPeriodically(ctx)
+		sharedOpenAPIMatchersMutex.Lock()
+		defer sharedOpenAPIMatchersMutex.Unlock()
+		if sharedOpenAPIMatchers[key] == m {
+			delete(sharedOpenAPIMatchers, key)
+		}
+	}()
+	return m
+}
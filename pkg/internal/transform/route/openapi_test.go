@@ -0,0 +1,137 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package route
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const petstoreYAML = `
+openapi: 3.0.3
+servers:
+  - url: https://petstore.example.com/api/v1/
+paths:
+  /pets:
+    get: {}
+    post: {}
+  /pets/{pet-id}:
+    parameters:
+      - name: pet-id
+        in: path
+    get: {}
+    delete: {}
+  /pets/mine:
+    get: {}
+  /files/{name}.json:
+    get: {}
+`
+
+const swaggerJSON = `{
+  "swagger": "2.0",
+  "basePath": "/v2",
+  "paths": {
+    "/users/{userId}": {"get": {}, "put": {}},
+    "/users/{userId}/orders": {"get": {}}
+  }
+}`
+
+func TestParseOpenAPI(t *testing.T) {
+	routes, err := ParseOpenAPI([]byte(petstoreYAML))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []OpenAPIRoute{
+		{Method: "GET", Template: "/api/v1/pets"},
+		{Method: "POST", Template: "/api/v1/pets"},
+		{Method: "GET", Template: "/api/v1/pets/{pet-id}"},
+		{Method: "DELETE", Template: "/api/v1/pets/{pet-id}"},
+		{Method: "GET", Template: "/api/v1/pets/mine"},
+		{Method: "GET", Template: "/api/v1/files/{name}.json"},
+	}, routes)
+
+	routes, err = ParseOpenAPI([]byte(swaggerJSON))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []OpenAPIRoute{
+		{Method: "GET", Template: "/v2/users/{userId}"},
+		{Method: "PUT", Template: "/v2/users/{userId}"},
+		{Method: "GET", Template: "/v2/users/{userId}/orders"},
+	}, routes)
+
+	_, err = ParseOpenAPI([]byte(`paths: {}`))
+	require.Error(t, err)
+	_, err = ParseOpenAPI([]byte(`{{{`))
+	require.Error(t, err)
+}
+
+func TestOpenAPIMatcher(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "petstore.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(petstoreYAML), 0o600))
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte(swaggerJSON))
+	}))
+	defer server.Close()
+
+	m := NewOpenAPIMatcher([]string{file, server.URL + "/swagger.json"}, time.Minute)
+	assert.Empty(t, m.Find("/api/v1/pets/123"))
+
+	m.Reload(t.Context())
+	assert.Equal(t, "/api/v1/pets/{pet-id}", m.FindMethod("GET", "/api/v1/pets/123"))
+	assert.Equal(t, "/api/v1/pets/mine", m.FindMethod("GET", "/api/v1/pets/mine"))
+	// /pets/mine is only defined for GET
+	assert.Equal(t, "/api/v1/pets/{pet-id}", m.FindMethod("DELETE", "/api/v1/pets/mine"))
+	// undefined methods are matched against any path template
+	assert.Equal(t, "/api/v1/pets", m.FindMethod("PATCH", "/api/v1/pets"))
+	assert.Equal(t, "/api/v1/files/{name}.json", m.FindMethod("GET", "/api/v1/files/report.json"))
+	assert.Equal(t, "/v2/users/{userId}/orders", m.Find("/v2/users/42/orders"))
+	assert.Equal(t, "/v2/users/{userId}/orders", FindRoute(m, "GET", "/v2/users/42/orders"))
+	assert.Empty(t, m.FindMethod("GET", "/pets/123"))
+
+	// routes are kept if a document can't be reloaded
+	require.NoError(t, os.Remove(file))
+	m.Reload(t.Context())
+	assert.Equal(t, "/api/v1/pets/{pet-id}", m.FindMethod("GET", "/api/v1/pets/123"))
+}
+
+func TestSharedOpenAPIMatcher(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "petstore.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(petstoreYAML), 0o600))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	m := SharedOpenAPIMatcher(ctx, []string{file}, time.Minute)
+	// the first load is synchronous
+	assert.Equal(t, "/api/v1/pets/{pet-id}", m.FindMethod("GET", "/api/v1/pets/123"))
+	assert.Same(t, m, SharedOpenAPIMatcher(ctx, []string{file}, time.Minute))
+	assert.NotSame(t, m, SharedOpenAPIMatcher(ctx, []string{file}, time.Hour))
+
+	// once the context is cancelled, the matcher stops and is not shared anymore
+	cancel()
+	var m2 *OpenAPIMatcher
+	require.Eventually(t, func() bool {
+		m2 = SharedOpenAPIMatcher(t.Context(), []string{file}, time.Minute)
+		return m2 != m
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "/api/v1/pets/{pet-id}", m2.FindMethod("GET", "/api/v1/pets/123"))
+}
+
+func TestMatchers(t *testing.T) {
+	openAPI := NewOpenAPIMatcher(nil, 0)
+	openAPI.routes.Store(newOpenAPIRoutes([]OpenAPIRoute{
+		{Method: "GET", Template: "/users/{id}"},
+		{Method: "GET", Template: "/users/me"},
+	}))
+	m := Matchers{NewMatcher([]string{"/users/:name/details"}), openAPI}
+
+	assert.Equal(t, "/users/:name/details", FindRoute(m, "GET", "/users/me/details"))
+	assert.Equal(t, "/users/me", FindRoute(m, "GET", "/users/me"))
+	assert.Equal(t, "/users/{id}", m.Find("/users/123"))
+	assert.Empty(t, FindRoute(m, "GET", "/groups/123"))
+}
@@ -33,7 +33,9 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/export/otel/tailsampling"
 	"go.opentelemetry.io/obi/pkg/export/prom"
+	"go.opentelemetry.io/obi/pkg/export/slo"
 	"go.opentelemetry.io/obi/pkg/filter"
 	"go.opentelemetry.io/obi/pkg/kube"
 	"go.opentelemetry.io/obi/pkg/kube/kubeflags"
@@ -76,6 +78,8 @@ const (
 
 const (
 	defaultMetricsTTL = 5 * time.Minute
+	// how often the OTLP exporters reload the TLS certificates from disk
+	defaultTLSReloadInterval = 5 * time.Minute
 )
 
 // ExtraGroupAttributesMap defines additional attributes for attribute groups.
@@ -143,6 +147,7 @@ var DefaultConfig = Config{
 		PostgresPreparedStatementsCacheSize: 1024,
 		MongoRequestsCacheSize:              1024,
 		KafkaTopicUUIDCacheSize:             1024,
+		KafkaClientIDGroupCacheSize:         1024,
 		CouchbaseDBCacheSize:                1024,
 		OverrideBPFLoopEnabled:              false,
 		PayloadExtraction: config.PayloadExtraction{
@@ -210,7 +215,8 @@ var DefaultConfig = Config{
 		Instrumentations: []instrumentations.Instrumentation{
 			instrumentations.InstrumentationALL,
 		},
-		TTL: defaultMetricsTTL,
+		TTL:               defaultMetricsTTL,
+		TLSReloadInterval: defaultTLSReloadInterval,
 	},
 	Traces: otelcfg.TracesConfig{
 		Protocol:          otelcfg.ProtocolUnset,
@@ -218,6 +224,7 @@ var DefaultConfig = Config{
 		MaxQueueSize:      4096,
 		BatchTimeout:      15 * time.Second,
 		ReportersCacheLen: ReporterLRUSize,
+		TLSReloadInterval: defaultTLSReloadInterval,
 		Instrumentations: []instrumentations.Instrumentation{
 			instrumentations.InstrumentationHTTP,
 			instrumentations.InstrumentationGRPC,
@@ -230,6 +237,11 @@ var DefaultConfig = Config{
 			instrumentations.InstrumentationMemcached,
 			// no traces for DNS and GPU by default
 		},
+		TailSampling: tailsampling.Config{
+			DecisionWait: 10 * time.Second,
+			MaxTraces:    50_000,
+			Errors:       true,
+		},
 	},
 	Prometheus: prom.PrometheusConfig{
 		Path:    "/metrics",
@@ -274,6 +286,12 @@ var DefaultConfig = Config{
 	},
 	NetworkFlows: DefaultNetworkConfig,
 	Stats:        DefaultStatsConfig,
+	Processes: ProcessesConfig{
+		Interval: 5 * time.Second,
+	},
+	SLO: slo.Config{
+		ApdexWindow: 5 * time.Minute,
+	},
 	Discovery: services.DiscoveryConfig{
 		ExcludeOTelInstrumentedServices: true,
 		DefaultExcludeServices: services.RegexDefinitionCriteria{
@@ -316,9 +334,20 @@ type Config struct {
 	NetworkFlows NetworkConfig `yaml:"network"`
 	Stats        StatsConfig   `yaml:"stats"`
 
+	// Processes configuration for the process metrics feature
+	Processes ProcessesConfig `yaml:"processes"`
+
+	// SLO definitions, whose good/total events and Apdex score are reported as metrics
+	SLO slo.Config `yaml:"slo" envPrefix:"OTEL_EBPF_SLO_"`
+
 	Filters filter.AttributesConfig `yaml:"filter"`
 
 	Attributes Attributes `yaml:"attributes"`
+
+	// Redaction rules for the URL path segments, URL query parameters and captured headers
+	// of the HTTP spans. They can be overridden for each service in the discovery section.
+	Redaction services.RedactionConfig `yaml:"redaction"`
+
 	// Routes is an optional node. If not set, data will be directly forwarded to exporters.
 	Routes       *transform.RoutesConfig       `yaml:"routes"`
 	NameResolver *transform.NameResolverConfig `yaml:"name_resolver"`
@@ -573,6 +602,12 @@ type HostIDConfig struct {
 	Override string `yaml:"override" env:"OTEL_EBPF_HOST_ID"`
 }
 
+// ProcessesConfig for the metrics about the resource usage of the instrumented processes
+type ProcessesConfig struct {
+	// Interval between two samples of the /proc filesystem of the instrumented processes
+	Interval time.Duration `yaml:"interval" env:"OTEL_EBPF_PROCESSES_INTERVAL" validate:"gte=0"`
+}
+
 type NodeJSConfig struct {
 	Enabled bool `yaml:"enabled" env:"OTEL_EBPF_NODEJS_ENABLED"`
 }
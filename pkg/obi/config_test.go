@@ -28,7 +28,9 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/instrumentations"
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	"go.opentelemetry.io/obi/pkg/export/otel/perapp"
+	"go.opentelemetry.io/obi/pkg/export/otel/tailsampling"
 	"go.opentelemetry.io/obi/pkg/export/prom"
+	"go.opentelemetry.io/obi/pkg/export/slo"
 	"go.opentelemetry.io/obi/pkg/internal/pipe/cidr"
 	"go.opentelemetry.io/obi/pkg/kube"
 	"go.opentelemetry.io/obi/pkg/kube/kubeflags"
@@ -162,6 +164,7 @@ discovery:
 			PostgresPreparedStatementsCacheSize: 1024,
 			MongoRequestsCacheSize:              1024,
 			KafkaTopicUUIDCacheSize:             1024,
+			KafkaClientIDGroupCacheSize:         1024,
 			CouchbaseDBCacheSize:                1024,
 			PayloadExtraction: config.PayloadExtraction{
 				HTTP: config.HTTPConfig{
@@ -191,6 +194,12 @@ discovery:
 		},
 		NetworkFlows: nc,
 		Stats:        sc,
+		Processes: ProcessesConfig{
+			Interval: 5 * time.Second,
+		},
+		SLO: slo.Config{
+			ApdexWindow: 5 * time.Minute,
+		},
 		Metrics: perapp.MetricsConfig{
 			// after normalization, network feature is added from network > enable: true
 			Features: export.FeatureApplicationRED | export.FeatureNetwork,
@@ -213,6 +222,7 @@ discovery:
 			},
 			HistogramAggregation: "base2_exponential_bucket_histogram",
 			TTL:                  5 * time.Minute,
+			TLSReloadInterval:    5 * time.Minute,
 		},
 		Traces: otelcfg.TracesConfig{
 			Protocol:          otelcfg.ProtocolUnset,
@@ -221,6 +231,7 @@ discovery:
 			MaxQueueSize:      4096,
 			BatchTimeout:      15 * time.Second,
 			ReportersCacheLen: ReporterLRUSize,
+			TLSReloadInterval: 5 * time.Minute,
 			Instrumentations: []instrumentations.Instrumentation{
 				instrumentations.InstrumentationHTTP,
 				instrumentations.InstrumentationGRPC,
@@ -233,6 +244,11 @@ discovery:
 				instrumentations.InstrumentationMemcached,
 				// no traces for DNS and GPU by default
 			},
+			TailSampling: tailsampling.Config{
+				DecisionWait: 10 * time.Second,
+				MaxTraces:    50_000,
+				Errors:       true,
+			},
 		},
 		Prometheus: prom.PrometheusConfig{
 			Path: "/metrics",
@@ -361,6 +377,13 @@ func TestConfigValidate(t *testing.T) {
 		{"OTEL_EBPF_TRACE_PRINTER": "counter", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
 		{"OTEL_EBPF_PROMETHEUS_PORT": "8080", "OTEL_EBPF_EXECUTABLE_PATH": "foo", "INSTRUMENT_FUNC_NAME": "bar"},
 		{"OTEL_EBPF_INTERNAL_OTEL_METRICS": "true", "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT": "localhost:1234", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EBPF_TRACES_FILE_PATH": "/var/lib/obi/traces.jsonl", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EBPF_METRICS_FILE_PATH": "/var/lib/obi/metrics.jsonl", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT": "localhost:1234", "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE": "LowMemory", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "localhost:1234", "OTEL_EBPF_TRACES_TAIL_SAMPLING_ENABLED": "true", "OTEL_EBPF_TRACES_TAIL_SAMPLING_PROBABILISTIC": "0.1", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EXPORTER_OTLP_ENDPOINT": "localhost:1234", "OTEL_EBPF_TRACES_DISK_QUEUE_DIRECTORY": "/var/lib/obi/queue", "OTEL_EBPF_METRICS_DISK_QUEUE_DIRECTORY": "/var/lib/obi/queue", "OTEL_EBPF_METRICS_DISK_QUEUE_MAX_SIZE_MB": "1024", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EBPF_PROMETHEUS_PORT": "8080", "OTEL_EBPF_METRICS_FEATURES": "application,process", "OTEL_EBPF_PROCESSES_INTERVAL": "10s", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EBPF_PROMETHEUS_PORT": "8080", "OTEL_EBPF_SLO_APDEX_WINDOW": "1m", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
 	}
 	for n, tc := range testCases {
 		t.Run(fmt.Sprint("case", n), func(t *testing.T) {
@@ -375,6 +398,11 @@ func TestConfigValidate_error(t *testing.T) {
 		{"OTEL_EBPF_EXECUTABLE_PATH": "foo", "INSTRUMENT_FUNC_NAME": "bar", "OTEL_EBPF_TRACE_PRINTER": "disabled"},
 		{"OTEL_EBPF_EXECUTABLE_PATH": "foo", "INSTRUMENT_FUNC_NAME": "bar", "OTEL_EBPF_TRACE_PRINTER": ""},
 		{"OTEL_EBPF_EXECUTABLE_PATH": "foo", "INSTRUMENT_FUNC_NAME": "bar", "OTEL_EBPF_TRACE_PRINTER": "invalid"},
+		{"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT": "localhost:1234", "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE": "sometimes", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "localhost:1234", "OTEL_EBPF_TRACES_TAIL_SAMPLING_PROBABILISTIC": "2", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT": "localhost:1234", "OTEL_EBPF_METRICS_DISK_QUEUE_DIRECTORY": "/var/lib/obi/queue", "OTEL_EBPF_METRICS_DISK_QUEUE_MAX_SIZE_MB": "-1", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EBPF_PROMETHEUS_PORT": "8080", "OTEL_EBPF_METRICS_FEATURES": "process", "OTEL_EBPF_PROCESSES_INTERVAL": "-1s", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
+		{"OTEL_EBPF_PROMETHEUS_PORT": "8080", "OTEL_EBPF_SLO_APDEX_WINDOW": "-1m", "OTEL_EBPF_EXECUTABLE_PATH": "foo"},
 	}
 	for n, tc := range testCases {
 		t.Run(fmt.Sprint("case", n), func(t *testing.T) {
@@ -475,6 +503,50 @@ func TestConfigValidate_TracePrinterFallback(t *testing.T) {
 	assert.Equal(t, debug.TracePrinterText, cfg.TracePrinter)
 }
 
+func TestConfigValidateSLO(t *testing.T) {
+	userConfig := bytes.NewBufferString(`executable_path: foo
+prometheus_export:
+  port: 8080
+slo:
+  definitions:
+    - name: checkout
+      service: "checkout-*"
+      route: "/api/*"
+      latency_threshold: 300ms
+      success_status_classes: [2xx, 4xx]
+`)
+	cfg, err := LoadConfig(userConfig)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+	require.Len(t, cfg.SLO.Definitions, 1)
+	def := cfg.SLO.Definitions[0]
+	assert.Equal(t, "checkout", def.Name)
+	assert.True(t, def.Service.MatchString("checkout-svc"))
+	assert.True(t, def.Route.MatchString("/api/cart"))
+	assert.Equal(t, 300*time.Millisecond, def.LatencyThreshold)
+	assert.Equal(t, []string{"2xx", "4xx"}, def.SuccessStatusClasses)
+}
+
+func TestConfigValidateSLO_Errors(t *testing.T) {
+	for name, definition := range map[string]string{
+		"missing name":      "latency_threshold: 300ms",
+		"missing threshold": "name: checkout",
+		"invalid class":     "{name: checkout, latency_threshold: 300ms, success_status_classes: [200]}",
+	} {
+		t.Run(name, func(t *testing.T) {
+			userConfig := bytes.NewBufferString(`executable_path: foo
+prometheus_export:
+  port: 8080
+slo:
+  definitions:
+    - ` + definition + "\n")
+			cfg, err := LoadConfig(userConfig)
+			require.NoError(t, err)
+			require.Error(t, cfg.Validate())
+		})
+	}
+}
+
 func TestConfigValidateRoutes(t *testing.T) {
 	userConfig := bytes.NewBufferString(`executable_path: foo
 trace_printer: text
@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/obi/pkg/export/otel/otelcfg"
 	netebpf "go.opentelemetry.io/obi/pkg/internal/netolly/ebpf"
 	statsebpf "go.opentelemetry.io/obi/pkg/internal/statsolly/ebpf"
+	"go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
 	"go.opentelemetry.io/obi/pkg/kube"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
 )
@@ -75,4 +76,7 @@ type AppO11y struct {
 	// (e.g. discover.NewDynamicPIDSelector()), passes it via instrumenter.WithDynamicPIDSelector, and
 	// calls AddPIDs/RemovePIDs/GetPIDs on it directly. The instrumenter does not implement an updater interface.
 	DynamicPIDSelector any
+	// RouteTemplates, when set, stores the route templates learned for each service by the
+	// low-cardinality unmatch mode
+	RouteTemplates *clusterurl.TrieStore
 }
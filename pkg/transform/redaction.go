@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transform // import "go.opentelemetry.io/obi/pkg/transform"
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm"
+	"go.opentelemetry.io/obi/pkg/pipe/swarm/swarms"
+)
+
+const defaultRedactionMask = "*"
+
+// RedactionProvider redacts the URL path segments, the URL query parameter values, the captured
+// headers, the captured baggage values and the captured response body of the HTTP spans, according to the redaction rules of their service, or the global rules
+// if the service does not define them. It runs before the routes are matched, so the routes
+// are matched against the redacted paths.
+// perService must be true if any service defines its own redaction rules.
+func RedactionProvider(cfg *services.RedactionConfig, perService bool, input, output *msg.Queue[[]request.Span]) swarm.InstanceFunc {
+	return func(_ context.Context) (swarm.RunFunc, error) {
+		if !cfg.Enabled() && !perService {
+			return swarm.Bypass(input, output)
+		}
+		rn := &redactionNode{
+			global:    newRedactor(cfg),
+			redactors: map[*services.RedactionConfig]*redactor{},
+		}
+		in := input.Subscribe(msg.SubscriberName("transform.Redaction"))
+		return func(ctx context.Context) {
+			// output channel must be closed so later stages in the pipeline can finish in cascade
+			defer output.Close()
+
+			swarms.ForEachInput(ctx, in, nil, func(spans []request.Span) {
+				for i := range spans {
+					s := &spans[i]
+					if s.IsHTTPSpan() {
+						rn.redactorFor(s.Service.Redaction).redactSpan(s)
+					}
+				}
+				output.SendCtx(ctx, spans)
+			})
+		}, nil
+	}
+}
+
+type redactionNode struct {
+	global *redactor
+	// redactors of the services defining their own redaction rules
+	redactors map[*services.RedactionConfig]*redactor
+}
+
+func (rn *redactionNode) redactorFor(cfg *services.RedactionConfig) *redactor {
+	if cfg == nil {
+		return rn.global
+	}
+	r, ok := rn.redactors[cfg]
+	if !ok {
+		r = newRedactor(cfg)
+		rn.redactors[cfg] = r
+	}
+	return r
+}
+
+// redactor applies a redaction config, skipping the parts of the span that can't be redacted
+// by any of its rules
+type redactor struct {
+	cfg                            *services.RedactionConfig
+	mask                           string
+	paths, query, headers, baggage bool
+	// action for the captured response body, or zero if it is kept
+	body services.RedactionAction
+}
+
+func newRedactor(cfg *services.RedactionConfig) *redactor {
+	r := &redactor{cfg: cfg, mask: cfg.Policy.MaskString}
+	if r.mask == "" {
+		r.mask = defaultRedactionMask
+	}
+	if cfg.Policy.DefaultAction != 0 && cfg.Policy.DefaultAction != services.RedactionActionKeep {
+		r.paths, r.query, r.headers, r.baggage = true, true, true, true
+		r.body = cfg.Policy.DefaultAction
+		return r
+	}
+	for i := range cfg.Rules {
+		if cfg.Rules[i].Action == services.RedactionActionKeep {
+			continue
+		}
+		// the response body can't be matched against the rules, and it might contain any of
+		// the values that the rules redact, so it is masked
+		r.body = services.RedactionActionMask
+		switch cfg.Rules[i].Type {
+		case services.RedactionRuleTypePathSegment:
+			r.paths = true
+		case services.RedactionRuleTypeQuery:
+			r.query = true
+		case services.RedactionRuleTypeHeader:
+			r.headers = true
+		case services.RedactionRuleTypeBaggage:
+			r.baggage = true
+		}
+	}
+	return r
+}
+
+func (r *redactor) redactSpan(s *request.Span) {
+	if r.paths || r.query {
+		s.Path = r.redactURL(s.Path)
+		s.FullPath = r.redactURL(s.FullPath)
+	}
+	if r.headers {
+		r.redactHeaders(s.RequestHeaders)
+		r.redactHeaders(s.ResponseHeaders)
+	}
+	if r.baggage {
+		r.redactBaggage(s.Baggage)
+	}
+	if r.body != 0 && s.ResponseBodyPrefix != "" {
+		s.ResponseBodyPrefix = r.apply(r.body, s.ResponseBodyPrefix)
+	}
+}
+
+func (r *redactor) apply(action services.RedactionAction, value string) string {
+	switch action {
+	case services.RedactionActionMask:
+		return r.mask
+	case services.RedactionActionHash:
+		return redactionHash(value)
+	default:
+		return value
+	}
+}
+
+// redactionHash returns the first 8 bytes of the SHA-256 hash of the value, hex-encoded
+func redactionHash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:8])
+}
+
+// redactURL redacts the path and query of an absolute URL or a request target,
+// keeping the scheme, host and fragment
+func (r *redactor) redactURL(u string) string {
+	if u == "" {
+		return u
+	}
+	rest, fragment := u, ""
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		rest, fragment = rest[:i], rest[i:]
+	}
+	query, hasQuery := "", false
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		rest, query, hasQuery = rest[:i], rest[i+1:], true
+	}
+	prefix := ""
+	if i := strings.Index(rest, "://"); i >= 0 {
+		if j := strings.IndexByte(rest[i+3:], '/'); j >= 0 {
+			prefix, rest = rest[:i+3+j], rest[i+3+j:]
+		} else {
+			prefix, rest = rest, ""
+		}
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(u))
+	sb.WriteString(prefix)
+	if r.paths {
+		r.redactPath(&sb, rest)
+	} else {
+		sb.WriteString(rest)
+	}
+	if hasQuery {
+		sb.WriteByte('?')
+		if r.query {
+			r.redactQuery(&sb, query)
+		} else {
+			sb.WriteString(query)
+		}
+	}
+	sb.WriteString(fragment)
+	return sb.String()
+}
+
+func (r *redactor) redactPath(sb *strings.Builder, path string) {
+	for i, segment := range strings.Split(path, "/") {
+		if i > 0 {
+			sb.WriteByte('/')
+		}
+		if segment == "" {
+			continue
+		}
+		// the rules are matched against the unescaped segment, e.g. user%40example.com
+		match := segment
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			match = unescaped
+		}
+		sb.WriteString(r.apply(r.cfg.Resolve(services.RedactionRuleTypePathSegment, match), segment))
+	}
+}
+
+// redactQuery redacts the values of the query parameters. The keys are always kept.
+func (r *redactor) redactQuery(sb *strings.Builder, query string) {
+	for i, param := range strings.Split(query, "&") {
+		if i > 0 {
+			sb.WriteByte('&')
+		}
+		key, value, hasValue := strings.Cut(param, "=")
+		sb.WriteString(key)
+		if !hasValue {
+			continue
+		}
+		match := key
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			match = unescaped
+		}
+		sb.WriteByte('=')
+		sb.WriteString(r.apply(r.cfg.Resolve(services.RedactionRuleTypeQuery, match), value))
+	}
+}
+
+func (r *redactor) redactHeaders(headers map[string][]string) {
+	for name, values := range headers {
+		switch r.cfg.Resolve(services.RedactionRuleTypeHeader, name) {
+		case services.RedactionActionMask:
+			headers[name] = []string{r.mask}
+		case services.RedactionActionHash:
+			hashed := make([]string, len(values))
+			for i, v := range values {
+				hashed[i] = redactionHash(v)
+			}
+			headers[name] = hashed
+		}
+	}
+}
+
+func (r *redactor) redactBaggage(baggage map[string]string) {
+	for key, value := range baggage {
+		baggage[key] = r.apply(r.cfg.Resolve(services.RedactionRuleTypeBaggage, key), value)
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
+	"go.opentelemetry.io/obi/pkg/internal/testutil"
+	"go.opentelemetry.io/obi/pkg/pipe/msg"
+)
+
+func redactionConfig(t *testing.T, text string) *services.RedactionConfig {
+	t.Helper()
+	cfg := &services.RedactionConfig{}
+	require.NoError(t, yaml.Unmarshal([]byte(text), cfg))
+	return cfg
+}
+
+func runRedaction(t *testing.T, cfg *services.RedactionConfig, perService bool, spans []request.Span) []request.Span {
+	t.Helper()
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	redactor, err := RedactionProvider(cfg, perService, input, output)(t.Context())
+	require.NoError(t, err)
+	out := output.Subscribe()
+	defer input.Close()
+	go redactor(t.Context())
+	input.Send(spans)
+	return testutil.ReadChannel(t, out, testTimeout)
+}
+
+const testRedactionRules = `
+policy:
+  mask_string: "[REDACTED]"
+rules:
+  - action: mask
+    type: path_segment
+    match:
+      regexes: ["^[^@]+@[^@]+$"]
+  - action: hash
+    type: path_segment
+    match:
+      patterns: ["tok_*"]
+  - action: keep
+    type: query
+    match:
+      patterns: ["page"]
+  - action: mask
+    type: query
+    match:
+      patterns: ["*token*", "email"]
+  - action: mask
+    type: header
+    match:
+      patterns: ["authorization"]
+  - action: hash
+    type: header
+    match:
+      patterns: ["x-user-*"]
+  - action: mask
+    type: baggage
+    match:
+      patterns: ["session.*"]
+`
+
+func TestRedaction(t *testing.T) {
+	cfg := redactionConfig(t, testRedactionRules)
+	spans := runRedaction(t, cfg, false, []request.Span{{
+		Type:     request.EventTypeHTTP,
+		Path:     "/users/john%40example.com/keys/tok_1234?page=2&access_token=secret&Email=a%40b.c&flag#top",
+		FullPath: "https://example.com/users/john@example.com/keys/tok_1234?access_token=secret",
+		RequestHeaders: map[string][]string{
+			"Authorization": {"Bearer secret"},
+			"X-User-Id":     {"1234"},
+			"Accept":        {"*/*"},
+		},
+		ResponseHeaders: map[string][]string{
+			"Content-Type": {"application/json"},
+		},
+		Baggage: map[string]string{
+			"session.id": "abcd",
+			"tenant.id":  "acme",
+		},
+		ResponseBodyPrefix: `{"error":"invalid token secret"}`,
+	}, {
+		// non-HTTP spans are not redacted
+		Type: request.EventTypeGRPC,
+		Path: "/users/john@example.com",
+	}})
+	require.Len(t, spans, 2)
+
+	hashed := redactionHash("tok_1234")
+	assert.Len(t, hashed, 16)
+	assert.Equal(t, "/users/[REDACTED]/keys/"+hashed+"?page=2&access_token=[REDACTED]&Email=[REDACTED]&flag#top",
+		spans[0].Path)
+	assert.Equal(t, "https://example.com/users/[REDACTED]/keys/"+hashed+"?access_token=[REDACTED]",
+		spans[0].FullPath)
+	assert.Equal(t, map[string][]string{
+		"Authorization": {"[REDACTED]"},
+		"X-User-Id":     {redactionHash("1234")},
+		"Accept":        {"*/*"},
+	}, spans[0].RequestHeaders)
+	assert.Equal(t, map[string][]string{
+		"Content-Type": {"application/json"},
+	}, spans[0].ResponseHeaders)
+	assert.Equal(t, map[string]string{
+		"session.id": "[REDACTED]",
+		"tenant.id":  "acme",
+	}, spans[0].Baggage)
+	// the response body can't be matched against the rules, so it is masked
+	assert.Equal(t, "[REDACTED]", spans[0].ResponseBodyPrefix)
+
+	assert.Equal(t, "/users/john@example.com", spans[1].Path)
+}
+
+func TestRedaction_DefaultAction(t *testing.T) {
+	cfg := redactionConfig(t, `
+policy:
+  default_action: hash
+rules:
+  - action: keep
+    type: path_segment
+    match:
+      regexes: ["^[a-z]+$"]
+  - action: keep
+    type: query
+    match:
+      patterns: ["page"]
+  - action: keep
+    type: header
+    match:
+      patterns: ["*"]
+`)
+	spans := runRedaction(t, cfg, false, []request.Span{{
+		Type:           request.EventTypeHTTPClient,
+		Path:           "/orders/3f2a1b/items?page=1&id=33",
+		FullPath:       "http://orders:8080",
+		RequestHeaders: map[string][]string{"Accept": {"*/*"}},
+		Baggage:        map[string]string{"tenant.id": "acme"},
+
+		ResponseBodyPrefix: "internal error",
+	}})
+	require.Len(t, spans, 1)
+	assert.Equal(t, "/orders/"+redactionHash("3f2a1b")+"/items?page=1&id="+redactionHash("33"), spans[0].Path)
+	assert.Equal(t, "http://orders:8080", spans[0].FullPath)
+	assert.Equal(t, map[string][]string{"Accept": {"*/*"}}, spans[0].RequestHeaders)
+	assert.Equal(t, map[string]string{"tenant.id": redactionHash("acme")}, spans[0].Baggage)
+	assert.Equal(t, redactionHash("internal error"), spans[0].ResponseBodyPrefix)
+}
+
+func TestRedaction_PerService(t *testing.T) {
+	serviceCfg := redactionConfig(t, `
+rules:
+  - action: hash
+    type: query
+    match:
+      patterns: ["user"]
+`)
+	spans := runRedaction(t, &services.RedactionConfig{}, true, []request.Span{{
+		Type: request.EventTypeHTTP,
+		Path: "/search?user=john&q=shoes",
+	}, {
+		Type:    request.EventTypeHTTP,
+		Path:    "/search?user=john&q=shoes",
+		Service: svc.Attrs{Redaction: serviceCfg},
+	}})
+	require.Len(t, spans, 2)
+	assert.Equal(t, "/search?user=john&q=shoes", spans[0].Path)
+	assert.Equal(t, "/search?user="+redactionHash("john")+"&q=shoes", spans[1].Path)
+}
+
+func TestRedaction_CaseSensitive(t *testing.T) {
+	cfg := redactionConfig(t, `
+rules:
+  - action: mask
+    type: query
+    match:
+      patterns: ["Token"]
+      case_sensitive: true
+`)
+	assert.Equal(t, services.RedactionActionMask, cfg.Resolve(services.RedactionRuleTypeQuery, "Token"))
+	assert.Equal(t, services.RedactionActionKeep, cfg.Resolve(services.RedactionRuleTypeQuery, "token"))
+	assert.Equal(t, services.RedactionActionKeep, cfg.Resolve(services.RedactionRuleTypeHeader, "Token"))
+}
+
+func TestRedaction_InvalidConfig(t *testing.T) {
+	cfg := &services.RedactionConfig{}
+	require.Error(t, yaml.Unmarshal([]byte(`rules: [{action: remove, type: query}]`), cfg))
+	require.Error(t, yaml.Unmarshal([]byte(`rules: [{action: mask, type: body}]`), cfg))
+	require.Error(t, yaml.Unmarshal([]byte(`rules: [{action: mask, type: query, match: {regexes: ["("]}}]`), cfg))
+	require.Error(t, yaml.Unmarshal([]byte(`rules: [{action: mask, match: {patterns: ["token"]}}]`), cfg))
+	require.Error(t, yaml.Unmarshal([]byte(`rules: [{type: query, match: {patterns: ["token"]}}]`), cfg))
+	require.Error(t, yaml.Unmarshal([]byte(`policy: {match_order: last_match_wins}`), cfg))
+}
+
+func TestRedaction_Disabled(t *testing.T) {
+	assert.False(t, (*services.RedactionConfig)(nil).Enabled())
+	assert.False(t, redactionConfig(t, `{rules: [{action: keep, type: query}]}`).Enabled())
+	assert.True(t, redactionConfig(t, `{policy: {default_action: hash}}`).Enabled())
+
+	spans := runRedaction(t, &services.RedactionConfig{}, false, []request.Span{{
+		Type: request.EventTypeHTTP,
+		Path: "/users/john@example.com?token=1",
+
+		ResponseBodyPrefix: "internal error",
+	}})
+	assert.Equal(t, "/users/john@example.com?token=1", spans[0].Path)
+	assert.Equal(t, "internal error", spans[0].ResponseBodyPrefix)
+}
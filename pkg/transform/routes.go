@@ -8,8 +8,12 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
+	"strconv"
+	"time"
 
 	"go.opentelemetry.io/obi/pkg/appolly/app/request"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
 	"go.opentelemetry.io/obi/pkg/internal/transform/route"
 	"go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
@@ -65,6 +69,43 @@ type RoutesConfig struct {
 	WildcardChar string `yaml:"wildcard_char,omitempty" jsonschema:"maxLength=1"`
 	// Max allowed path segment cardinality (per service) for the heuristic matcher
 	MaxPathSegmentCardinality int `yaml:"max_path_segment_cardinality"`
+	// OpenAPI documents whose path templates are matched after the Patterns
+	OpenAPI services.OpenAPIConfig `yaml:"openapi"`
+	// Persistence of the route templates learned by the low-cardinality unmatch mode
+	Persistence RoutesPersistenceConfig `yaml:"persistence"`
+}
+
+// RoutesPersistenceConfig allows keeping the route templates learned by the low-cardinality
+// unmatch mode across restarts, so the route space is not learned again after each restart.
+// Only the route templates of the services whose name is set in the discovery section are stored.
+type RoutesPersistenceConfig struct {
+	// File where the learned route templates are stored
+	File string `yaml:"file"`
+	// BPFFS stores the learned route templates in a BPF map pinned under the bpf_fs_path
+	// directory, which does not require a writable volume. Ignored if File is set.
+	BPFFS bool `yaml:"bpffs"`
+	// FlushInterval between two writes of the learned route templates. Defaults to 1 minute.
+	FlushInterval time.Duration `yaml:"flush_interval"`
+	// Port of the HTTP endpoint to inspect and delete the learned route templates.
+	// 0 (default) disables it.
+	Port int `yaml:"port"`
+	// Address where the HTTP endpoint listens. Defaults to 127.0.0.1, so the learned route
+	// templates can only be inspected and deleted from the local host.
+	Address string `yaml:"address"`
+}
+
+// Enabled returns whether the learned route templates are persisted
+func (c *RoutesPersistenceConfig) Enabled() bool {
+	return c.File != "" || c.BPFFS
+}
+
+// EndpointAddr returns the listen address of the HTTP endpoint of the learned route templates
+func (c *RoutesPersistenceConfig) EndpointAddr() string {
+	host := c.Address
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, strconv.Itoa(c.Port))
 }
 
 func RoutesProvider(rc *RoutesConfig, input, output *msg.Queue[[]request.Span]) swarm.InstanceFunc {
@@ -93,9 +134,14 @@ func (rn *routerNode) provideRoutes(_ context.Context) (swarm.RunFunc, error) {
 	if err != nil {
 		return nil, err
 	}
-	matcher := route.NewMatcher(rc.Patterns)
+	var matcher route.Matcher = route.NewMatcher(rc.Patterns)
+	var openAPI *route.OpenAPIMatcher
+	if rc.OpenAPI.Enabled() {
+		openAPI = route.NewOpenAPIMatcher(rc.OpenAPI.Documents, rc.OpenAPI.RefreshInterval)
+		matcher = route.Matchers{matcher, openAPI}
+	}
 	discarder := route.NewMatcher(rc.IgnorePatterns)
-	routesEnabled := len(rc.Patterns) > 0
+	routesEnabled := len(rc.Patterns) > 0 || rc.OpenAPI.Enabled()
 	ignoreEnabled := len(rc.IgnorePatterns) > 0
 
 	ignoreMode := rc.IgnoredEvents
@@ -109,6 +155,12 @@ func (rn *routerNode) provideRoutes(_ context.Context) (swarm.RunFunc, error) {
 		// output channel must be closed so later stages in the pipeline can finish in cascade
 		defer rn.output.Close()
 
+		if openAPI != nil {
+			// the first load is synchronous, so the first spans are already matched
+			openAPI.Reload(ctx)
+			go openAPI.RefreshPeriodically(ctx)
+		}
+
 		swarms.ForEachInput(ctx, in, nil, func(spans []request.Span) {
 			for i := range spans {
 				s := &spans[i]
@@ -123,16 +175,16 @@ func (rn *routerNode) provideRoutes(_ context.Context) (swarm.RunFunc, error) {
 					}
 				}
 				if s.Route == "" && routesEnabled {
-					s.Route = matcher.Find(s.Path)
+					s.Route = route.FindRoute(matcher, s.Method, s.Path)
 				}
 				if s.Route == "" && s.IsHTTPSpan() {
 					if s.IsClientSpan() {
 						if s.Service.CustomOutRouteMatcher != nil {
-							s.Route = s.Service.CustomOutRouteMatcher.Find(s.Path)
+							s.Route = route.FindRoute(s.Service.CustomOutRouteMatcher, s.Method, s.Path)
 						}
 					} else {
 						if s.Service.CustomInRouteMatcher != nil {
-							s.Route = s.Service.CustomInRouteMatcher.Find(s.Path)
+							s.Route = route.FindRoute(s.Service.CustomInRouteMatcher, s.Method, s.Path)
 						}
 					}
 
@@ -169,7 +221,7 @@ func chooseUnmatchPolicy(rn *routerNode) (func(rn *routerNode, span *request.Spa
 	case UnmatchWildcard, "":
 		unmatchAction = setUnmatchToWildcard
 
-		if len(rc.Patterns) == 0 {
+		if len(rc.Patterns) == 0 && !rc.OpenAPI.Enabled() {
 			slog.With("component", "RoutesProvider").
 				Warn("No route match patterns configured. " +
 					"Without route definitions OBI will not be able to generate a low cardinality " +
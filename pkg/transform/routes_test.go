@@ -4,6 +4,8 @@
 package transform
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 
 	"go.opentelemetry.io/obi/pkg/appolly/app/request"
 	"go.opentelemetry.io/obi/pkg/appolly/app/svc"
+	"go.opentelemetry.io/obi/pkg/appolly/services"
 	"go.opentelemetry.io/obi/pkg/internal/testutil"
 	"go.opentelemetry.io/obi/pkg/internal/transform/route/clusterurl"
 	"go.opentelemetry.io/obi/pkg/pipe/msg"
@@ -44,6 +47,44 @@ func TestUnmatchedWildcard(t *testing.T) {
 	}
 }
 
+func TestOpenAPIRoutes(t *testing.T) {
+	document := filepath.Join(t.TempDir(), "openapi.yaml")
+	require.NoError(t, os.WriteFile(document, []byte(`
+openapi: 3.1.0
+paths:
+  /users/{userId}:
+    get: {}
+  /users/me:
+    get: {}
+`), 0o600))
+	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
+	router, err := RoutesProvider(&RoutesConfig{
+		Unmatch:  UnmatchPath,
+		Patterns: []string{"/user/:id"},
+		OpenAPI:  services.OpenAPIConfig{Documents: []string{document}},
+	}, input, output)(t.Context())
+	require.NoError(t, err)
+	out := output.Subscribe()
+	defer input.Close()
+	go router(t.Context())
+	input.Send([]request.Span{
+		{Type: request.EventTypeHTTP, Method: "GET", Path: "/user/1234"},
+		{Type: request.EventTypeHTTP, Method: "GET", Path: "/users/1234"},
+		{Type: request.EventTypeHTTP, Method: "GET", Path: "/users/me"},
+		{Type: request.EventTypeHTTP, Method: "DELETE", Path: "/users/me"},
+		{Type: request.EventTypeHTTP, Method: "GET", Path: "/some/path"},
+	})
+	spans := testutil.ReadChannel(t, out, testTimeout)
+	require.Len(t, spans, 5)
+	assert.Equal(t, "/user/:id", spans[0].Route)
+	assert.Equal(t, "/users/{userId}", spans[1].Route)
+	assert.Equal(t, "/users/me", spans[2].Route)
+	// DELETE is not defined for any path, so the template is matched for any method
+	assert.Equal(t, "/users/me", spans[3].Route)
+	assert.Equal(t, "/some/path", spans[4].Route)
+}
+
 func TestUnmatchedPath(t *testing.T) {
 	input := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
 	output := msg.NewQueue[[]request.Span](msg.ChannelBufferLen(10))
@@ -275,3 +316,10 @@ func filterIgnored(reader func() []request.Span) []request.Span {
 		}
 	}
 }
+
+func TestRoutesPersistenceConfig_EndpointAddr(t *testing.T) {
+	// the endpoint is only reachable from the local host by default
+	assert.Equal(t, "127.0.0.1:8999", (&RoutesPersistenceConfig{Port: 8999}).EndpointAddr())
+	assert.Equal(t, "0.0.0.0:8999", (&RoutesPersistenceConfig{Port: 8999, Address: "0.0.0.0"}).EndpointAddr())
+	assert.Equal(t, "[::1]:8999", (&RoutesPersistenceConfig{Port: 8999, Address: "::1"}).EndpointAddr())
+}